@@ -0,0 +1,49 @@
+package main
+
+import "sort"
+
+// ActionFunc runs one named action - what a [[keybind]] entry's `action`
+// field, or the `/bind` command's target, ultimately invokes. It's a
+// closure rather than an Action value so actions that can't be expressed
+// as a single store.Dispatch (like "quit", which has to stop running
+// panels first) fit the same registry as SwitchDockAction/CycleTabAction.
+type ActionFunc func()
+
+// ActionRegistry maps an action name to the function it runs. Promoting
+// TUI.setKeybinds' hard-coded switch to a lookup table here is what lets
+// [[keybind]] (and the runtime `/bind` command) reference actions by
+// name instead of requiring a recompile to add one.
+type ActionRegistry struct {
+	actions map[string]ActionFunc
+}
+
+func NewActionRegistry() *ActionRegistry {
+	return &ActionRegistry{actions: make(map[string]ActionFunc)}
+}
+
+// Register adds or replaces the function run for name.
+func (r *ActionRegistry) Register(name string, fn ActionFunc) {
+	r.actions[name] = fn
+}
+
+// Run invokes the action registered under name, reporting whether one
+// was found.
+func (r *ActionRegistry) Run(name string) bool {
+	fn, ok := r.actions[name]
+	if !ok {
+		return false
+	}
+	fn()
+	return true
+}
+
+// Names returns every registered action name, sorted, for /which-key and
+// error messages.
+func (r *ActionRegistry) Names() []string {
+	names := make([]string, 0, len(r.actions))
+	for name := range r.actions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -4,11 +4,38 @@ import (
 	"os"
 
 	"github.com/BurntSushi/toml"
+
+	"github.com/study-groups/devops/tetra/console/go/tasks"
 )
 
 // Config holds the entire application layout defined in TOML.
 type Config struct {
-	Panels []PanelConfig `toml:"panel"`
+	Panels   []PanelConfig   `toml:"panel"`
+	Keybinds []KeybindConfig `toml:"keybind"`
+	Tasks    []TaskConfig    `toml:"task"`
+	LLM      LLMConfig       `toml:"llm"`
+}
+
+// LLMConfig configures LLMMiddleware's assistant backend.
+type LLMConfig struct {
+	// Endpoint is the base URL of an OpenAI-compatible or Ollama HTTP API.
+	Endpoint string `toml:"endpoint"`
+	Model    string `toml:"model"`
+	// APIKeyEnv names the environment variable holding the API key, so
+	// panels.toml never has to carry a secret itself.
+	APIKeyEnv string `toml:"api_key_env"`
+	// MaxContextEntries bounds how many CommandHistory entries
+	// GetLLMContext includes in a prompt.
+	MaxContextEntries int `toml:"max_context_entries"`
+}
+
+// KeybindConfig maps a key sequence to a named action or a `/command`
+// line, parsed alongside [[panel]]. Keys is whitespace-separated so it
+// can describe a chord: "Ctrl+N", "g g", ": w <Enter>".
+type KeybindConfig struct {
+	Keys    string `toml:"keys"`
+	Action  string `toml:"action"`
+	Command string `toml:"command"`
 }
 
 // PanelConfig defines the properties of a single panel.
@@ -17,6 +44,36 @@ type PanelConfig struct {
 	Kind    string `toml:"kind"`
 	Dock    string `toml:"dock"`
 	Command string `toml:"command"`
+
+	// Runner selects how Command is re-run: "oneshot" (the default) runs it
+	// once per focus and replaces the panel's content with its output;
+	// "stream" starts it once and keeps it running, appending each output
+	// line to the content as it arrives (for `tail -f`, `kubectl logs -f`,
+	// `watch`, and similar).
+	Runner string `toml:"runner"`
+	// Shell selects the CommandRunner backend: "bash" (the default), "sh",
+	// "pwsh", or "none" to run Command as a plain argv list with no shell.
+	Shell string `toml:"shell"`
+}
+
+// TaskConfig defines one `[[task]]` entry: a named, reusable command a
+// TaskPanel's tasks.Runner can depend on, order, and skip when its
+// outputs already look up to date.
+type TaskConfig struct {
+	Name string            `toml:"name"`
+	Cmd  string            `toml:"cmd"`
+	Cwd  string            `toml:"cwd"`
+	Env  map[string]string `toml:"env"`
+
+	// DependsOn names other [[task]] entries that must finish
+	// successfully before this one starts.
+	DependsOn []string `toml:"depends_on"`
+
+	// Inputs and Outputs are glob patterns the Runner compares mtimes of
+	// to decide whether the task is stale; a task with no Outputs always
+	// runs.
+	Inputs  []string `toml:"inputs"`
+	Outputs []string `toml:"outputs"`
 }
 
 // LoadConfig reads and parses the panels.toml file.
@@ -33,3 +90,46 @@ func LoadConfig(path string) (*Config, error) {
 
 	return &config, nil
 }
+
+// taskList converts panels.toml's `[[task]]` entries to the tasks.Task
+// values tasks.NewRunner expects.
+func taskList(configs []TaskConfig) []tasks.Task {
+	list := make([]tasks.Task, len(configs))
+	for i, c := range configs {
+		list[i] = tasks.Task{
+			Name:      c.Name,
+			Cmd:       c.Cmd,
+			Cwd:       c.Cwd,
+			Env:       c.Env,
+			DependsOn: c.DependsOn,
+			Inputs:    c.Inputs,
+			Outputs:   c.Outputs,
+		}
+	}
+	return list
+}
+
+// taskPanelName returns the name of the first `kind = "task"` panel in
+// configs, the panel TaskMiddleware attributes palette-triggered
+// Run/Cancel/Rerun output and status to. Empty when panels.toml defines
+// no such panel.
+func taskPanelName(configs []PanelConfig) string {
+	for _, c := range configs {
+		if c.Kind == "task" {
+			return c.Name
+		}
+	}
+	return ""
+}
+
+// assistantPanelName returns the name of the first `kind = "assistant"`
+// panel in configs, the panel LLMMiddleware streams a palette-triggered
+// "ask" reply into. Empty when panels.toml defines no such panel.
+func assistantPanelName(configs []PanelConfig) string {
+	for _, c := range configs {
+		if c.Kind == "assistant" {
+			return c.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"log"
+)
+
+// CommandErrorAction carries a failed command, or a panic recovered from
+// further down the middleware chain, back into the store. It replaces the
+// previous log.Printf-and-return behavior in CommandMiddleware so panels
+// have an action to reduce on instead of the failure vanishing into the
+// log.
+type CommandErrorAction struct {
+	PanelName string
+	Command   string
+	Err       error
+	// Retriable mirrors the ExecuteCommandAction that produced this error,
+	// so RetryMiddleware knows whether to re-drive it.
+	Retriable bool
+}
+
+// ErrorMiddleware sits near the top of the chain and recovers panics from
+// every middleware and reducer beneath it, converting them into a
+// CommandErrorAction rather than crashing the whole TUI over one bad
+// command or a panel bug.
+type ErrorMiddleware struct{}
+
+func NewErrorMiddleware() *ErrorMiddleware {
+	return &ErrorMiddleware{}
+}
+
+func (em *ErrorMiddleware) Middleware(store *Store) func(next Dispatcher) Dispatcher {
+	return func(next Dispatcher) Dispatcher {
+		return func(action Action) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("recovered panic dispatching %T: %v", action, r)
+					store.Dispatch(CommandErrorAction{Err: fmt.Errorf("panic: %v", r)})
+				}
+			}()
+			next(action)
+		}
+	}
+}
@@ -1,27 +1,32 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"fmt"
-	"os/exec"
+	"os"
 	"strings"
 )
 
-// Executor runs shell commands to generate panel content.
+// Executor runs panel commands to generate panel content, via the
+// CommandRunner its caller's `shell` config resolves to. It injects core
+// state as environment variables: TGO_FILE and TGO_PWD.
 type Executor struct{}
 
 func NewExecutor() *Executor {
 	return &Executor{}
 }
 
-// Execute runs the provided shell command string.
-// It injects core state as environment variables: TGO_FILE and TGO_PWD.
-func (e *Executor) Execute(command string, currentFile string, pwd string) (string, error) {
+// Execute runs command to completion under shell and returns its combined
+// output - the `runner = "oneshot"` path (the only behavior Executor had
+// before streaming existed).
+func (e *Executor) Execute(command, shell, currentFile, pwd string) (string, error) {
 	if command == "" {
 		return "", fmt.Errorf("command is empty")
 	}
 
-	cmd := exec.Command("bash", "-c", command)
+	cmd := runnerFor(shell).Command(context.Background(), command)
 	cmd.Env = append(cmd.Environ(),
 		fmt.Sprintf("TGO_FILE=%s", currentFile),
 		fmt.Sprintf("TGO_PWD=%s", pwd),
@@ -32,7 +37,6 @@ func (e *Executor) Execute(command string, currentFile string, pwd string) (stri
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
-
 	if err != nil {
 		errorMsg := fmt.Sprintf("Command failed: %s\n--- STDERR ---\n%s", err.Error(), stderr.String())
 		if stdout.Len() > 0 {
@@ -43,3 +47,71 @@ func (e *Executor) Execute(command string, currentFile string, pwd string) (stri
 
 	return stdout.String(), nil
 }
+
+// StreamHandle is a running `runner = "stream"` command. Chunks delivers
+// each line of combined stdout/stderr as it's written, and is closed once
+// the process exits or Stop cancels it.
+type StreamHandle struct {
+	Chunks <-chan string
+	cancel context.CancelFunc
+}
+
+// Stop terminates the streaming command and its output goroutine.
+func (h *StreamHandle) Stop() {
+	h.cancel()
+}
+
+// Stream starts command under shell and keeps it running, pushing each
+// line of output onto the returned handle's Chunks as it arrives instead
+// of buffering it until exit - the only way a `tail -f` or `kubectl logs
+// -f` style command would ever show anything in a panel.
+func (e *Executor) Stream(command, shell, currentFile, pwd string) (*StreamHandle, error) {
+	if command == "" {
+		return nil, fmt.Errorf("command is empty")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := runnerFor(shell).Command(ctx, command)
+	cmd.Env = append(cmd.Environ(),
+		fmt.Sprintf("TGO_FILE=%s", currentFile),
+		fmt.Sprintf("TGO_PWD=%s", pwd),
+	)
+
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	cmd.Stdout = pw
+	cmd.Stderr = pw
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		pr.Close()
+		pw.Close()
+		return nil, err
+	}
+
+	chunks := make(chan string, 16)
+	go func() {
+		defer close(chunks)
+		defer pr.Close()
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case chunks <- scanner.Text():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		cmd.Wait()
+		pw.Close()
+		cancel()
+	}()
+
+	return &StreamHandle{Chunks: chunks, cancel: cancel}, nil
+}
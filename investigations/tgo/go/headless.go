@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HeadlessAction is one step of a --script file or stdin action stream: the
+// serializable shape HeadlessAction.toAction converts into whichever
+// concrete Action CommandMiddleware or the reducer actually expects. Only
+// the action kinds useful outside an interactive TUI are exposed here.
+type HeadlessAction struct {
+	Type      string `yaml:"type" json:"type"`
+	PanelName string `yaml:"panel,omitempty" json:"panel,omitempty"`
+	Command   string `yaml:"command,omitempty" json:"command,omitempty"`
+	Shell     string `yaml:"shell,omitempty" json:"shell,omitempty"`
+	Retriable bool   `yaml:"retriable,omitempty" json:"retriable,omitempty"`
+}
+
+// toAction converts one scripted step to the Action HeadlessRunner
+// dispatches. Type defaults to "execute" so a bare `{command: ...}` script
+// entry - the common case - doesn't have to spell it out.
+func (h HeadlessAction) toAction() (Action, error) {
+	switch h.Type {
+	case "", "execute":
+		if h.Command == "" {
+			return nil, fmt.Errorf("headless: execute action missing command")
+		}
+		return ExecuteCommandAction{
+			PanelName: h.PanelName,
+			Command:   h.Command,
+			Shell:     h.Shell,
+			Retriable: h.Retriable,
+		}, nil
+	case "switch-dock":
+		return SwitchDockAction{}, nil
+	case "cycle-tab":
+		return CycleTabAction{Delta: 1}, nil
+	case "save-file":
+		return SaveFileAction{}, nil
+	default:
+		return nil, fmt.Errorf("headless: unknown action type %q", h.Type)
+	}
+}
+
+// commandResult is what HeadlessRunner's Middleware delivers back to Run
+// once a dispatched ExecuteCommandAction finishes, whichever way it
+// finished.
+type commandResult struct {
+	output string
+	err    error
+}
+
+// HeadlessRunner drives store through a scripted sequence of actions
+// without ever constructing a tview.Application, sharing the same Store,
+// reducer, and middleware chain main wires up for the TUI - so a
+// panels.toml's CommandMiddleware/TaskMiddleware pipeline is exercised
+// identically whether a human is watching or `devops --script ci.yaml`
+// runs headless in CI. It installs itself as a middleware the same way
+// HistoryMiddleware does, intercepting CommandOutputAction/
+// CommandErrorAction to print them and unblock Run's sequencing, and always
+// forwards them to next so LoggingMiddleware and HistoryMiddleware still
+// see every command it runs.
+type HeadlessRunner struct {
+	out io.Writer
+
+	mu      sync.Mutex
+	waiting map[string]chan commandResult
+}
+
+// NewHeadlessRunner returns a HeadlessRunner that writes panel output to
+// out as it arrives.
+func NewHeadlessRunner(out io.Writer) *HeadlessRunner {
+	return &HeadlessRunner{out: out, waiting: make(map[string]chan commandResult)}
+}
+
+func (r *HeadlessRunner) Middleware(store *Store) func(next Dispatcher) Dispatcher {
+	return func(next Dispatcher) Dispatcher {
+		return func(action Action) {
+			switch a := action.(type) {
+			case CommandOutputAction:
+				r.deliver(a.PanelName, commandResult{output: a.Output})
+			case CommandErrorAction:
+				r.deliver(a.PanelName, commandResult{err: a.Err})
+			}
+			next(action)
+		}
+	}
+}
+
+// await registers panelName as awaited, returning the channel its result
+// will arrive on.
+func (r *HeadlessRunner) await(panelName string) chan commandResult {
+	ch := make(chan commandResult, 1)
+	r.mu.Lock()
+	r.waiting[panelName] = ch
+	r.mu.Unlock()
+	return ch
+}
+
+// deliver unblocks the Run step awaiting panelName, if any. A result for a
+// panel nothing is currently waiting on (a retry firing after Run already
+// moved on) is simply dropped.
+func (r *HeadlessRunner) deliver(panelName string, result commandResult) {
+	r.mu.Lock()
+	ch, ok := r.waiting[panelName]
+	delete(r.waiting, panelName)
+	r.mu.Unlock()
+	if ok {
+		ch <- result
+	}
+}
+
+// Run dispatches each action in sequence against store, printing the
+// output (or error) of every ExecuteCommandAction as soon as it completes
+// before moving on to the next step. It returns the process exit code: 0
+// if every command succeeded, 1 if any CommandErrorAction fired or a step
+// in the script was malformed.
+func (r *HeadlessRunner) Run(store *Store, actions []HeadlessAction) int {
+	failed := false
+
+	for i, ha := range actions {
+		action, err := ha.toAction()
+		if err != nil {
+			fmt.Fprintf(r.out, "[%d] %v\n", i, err)
+			failed = true
+			continue
+		}
+
+		exec, isExecute := action.(ExecuteCommandAction)
+		if !isExecute {
+			store.Dispatch(action)
+			continue
+		}
+
+		result := r.await(exec.PanelName)
+		store.Dispatch(action)
+
+		switch outcome := <-result; {
+		case outcome.err != nil:
+			fmt.Fprintf(r.out, "=== %s ===\nerror: %v\n", exec.PanelName, outcome.err)
+			failed = true
+		default:
+			fmt.Fprintf(r.out, "=== %s ===\n%s\n", exec.PanelName, outcome.output)
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// loadScript reads a --script file (YAML, the format `devops --script
+// file.yaml` names) into the action sequence Run expects.
+func loadScript(path string) ([]HeadlessAction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading script: %w", err)
+	}
+
+	var actions []HeadlessAction
+	if err := yaml.Unmarshal(data, &actions); err != nil {
+		return nil, fmt.Errorf("parsing script: %w", err)
+	}
+	return actions, nil
+}
+
+// readActionStream decodes newline-delimited JSON actions from r - what
+// `devops` reads from stdin when no --script is given, mirroring the JSONL
+// convention history_middleware.go already uses on disk.
+func readActionStream(r io.Reader) ([]HeadlessAction, error) {
+	var actions []HeadlessAction
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var ha HeadlessAction
+		if err := json.Unmarshal(line, &ha); err != nil {
+			return nil, fmt.Errorf("parsing action line: %w", err)
+		}
+		actions = append(actions, ha)
+	}
+	return actions, scanner.Err()
+}
+
+// replayActions converts a recorded CommandHistory JSONL log (the file
+// `devops replay history.jsonl` is pointed at) back into the
+// ExecuteCommandAction sequence that produced it, so a session that broke
+// in the field can be re-run step for step in CI.
+func replayActions(path string) ([]HeadlessAction, error) {
+	entries, _ := loadHistoryFile(path)
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("replay: no entries in %s", path)
+	}
+
+	actions := make([]HeadlessAction, len(entries))
+	for i, e := range entries {
+		actions[i] = HeadlessAction{Type: "execute", PanelName: e.PanelName, Command: e.Command}
+	}
+	return actions, nil
+}
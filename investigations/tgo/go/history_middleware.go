@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CommandHistoryEntry records one command's outcome, for whatever later
+// wants to browse or replay past commands.
+type CommandHistoryEntry struct {
+	PanelName string    `json:"panel_name"`
+	Command   string    `json:"command"`
+	Success   bool      `json:"success"`
+	Output    string    `json:"output,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// PWD and Mode record the scope the entry was added under, so
+	// MultiScopeHistory's legacy migration can sort old entries into the
+	// right per-scope file.
+	PWD  string `json:"pwd,omitempty"`
+	Mode string `json:"mode,omitempty"`
+}
+
+// historyCompactionFactor bounds how many lines the on-disk log is allowed
+// to grow to, as a multiple of MaxEntries, before a compaction is
+// triggered to drop it back down to just the retained entries.
+const historyCompactionFactor = 2
+
+// CommandHistory is an append-only JSONL log of finished commands, capped
+// in memory at maxEntries via a ring buffer. HistoryMiddleware is its only
+// writer, which is what lets CommandMiddleware stay ignorant of
+// persistence entirely. Each AddEntry appends and fsyncs a single line
+// rather than rewriting the whole file, so the cost per entry stays
+// constant as the log grows; a background goroutine compacts the file
+// back down once it accumulates too many superseded lines.
+type CommandHistory struct {
+	mu         sync.Mutex
+	entries    []CommandHistoryEntry
+	maxEntries int
+	filePath   string
+
+	file         *os.File
+	linesWritten int
+}
+
+// historyFilePath returns where main wires up the default CommandHistory:
+// alongside panels.toml in the current directory.
+func historyFilePath() string {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return "history.jsonl"
+	}
+	return filepath.Join(pwd, "history.jsonl")
+}
+
+// NewCommandHistory opens (or creates) filePath in append mode, loads its
+// existing entries, and starts the background compactor. filePath == ""
+// disables persistence entirely - AddEntry still updates the in-memory
+// ring buffer, but there's nothing to compact.
+func NewCommandHistory(maxEntries int, filePath string) *CommandHistory {
+	ch := &CommandHistory{
+		maxEntries: maxEntries,
+		filePath:   filePath,
+	}
+
+	if filePath == "" {
+		return ch
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err == nil {
+		ch.entries, ch.linesWritten = loadHistoryFile(filePath)
+		if len(ch.entries) > maxEntries {
+			ch.entries = ch.entries[len(ch.entries)-maxEntries:]
+		}
+	}
+
+	if f, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+		ch.file = f
+	}
+
+	go ch.compactLoop()
+	return ch
+}
+
+// loadHistoryFile stream-decodes filePath line by line, so startup stays
+// fast no matter how large the log has grown, and reports the total line
+// count alongside the decoded entries.
+func loadHistoryFile(filePath string) ([]CommandHistoryEntry, int) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, 0
+	}
+	defer f.Close()
+
+	var entries []CommandHistoryEntry
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry CommandHistoryEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, lines
+}
+
+// AddEntry appends entry to the in-memory ring buffer, trimming the oldest
+// entry once maxEntries is exceeded, and appends+fsyncs the same entry as
+// one JSONL line on disk.
+func (ch *CommandHistory) AddEntry(entry CommandHistoryEntry) {
+	ch.mu.Lock()
+	ch.entries = append(ch.entries, entry)
+	if len(ch.entries) > ch.maxEntries {
+		ch.entries = ch.entries[len(ch.entries)-ch.maxEntries:]
+	}
+	ch.appendLine(entry)
+	ch.mu.Unlock()
+}
+
+// appendLine writes entry as one JSONL line to ch.file and fsyncs it, so a
+// crash right after AddEntry returns loses at most the in-flight write.
+// Caller must hold ch.mu.
+func (ch *CommandHistory) appendLine(entry CommandHistoryEntry) {
+	if ch.file == nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if _, err := ch.file.Write(append(data, '\n')); err != nil {
+		return
+	}
+	_ = ch.file.Sync()
+	ch.linesWritten++
+}
+
+// Entries returns a snapshot of the recorded history, oldest first.
+func (ch *CommandHistory) Entries() []CommandHistoryEntry {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	return append([]CommandHistoryEntry{}, ch.entries...)
+}
+
+// GetFailedCommands returns every recorded entry that failed, oldest
+// first, for surfacing to LLMMiddleware or a debugging panel.
+func (ch *CommandHistory) GetFailedCommands() []CommandHistoryEntry {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	var failed []CommandHistoryEntry
+	for _, entry := range ch.entries {
+		if !entry.Success {
+			failed = append(failed, entry)
+		}
+	}
+	return failed
+}
+
+// GetLLMContext formats the n most recent entries (most recent last, so
+// they read top-to-bottom like a transcript) as a plain-text block
+// suitable for prepending to an LLM prompt.
+func (ch *CommandHistory) GetLLMContext(n int) string {
+	ch.mu.Lock()
+	entries := ch.entries
+	if n > 0 && n < len(entries) {
+		entries = entries[len(entries)-n:]
+	}
+	entries = append([]CommandHistoryEntry{}, entries...)
+	ch.mu.Unlock()
+
+	if len(entries) == 0 {
+		return "No command history available"
+	}
+
+	var b strings.Builder
+	b.WriteString("Recent command history:\n")
+	for _, entry := range entries {
+		status := "ok"
+		if !entry.Success {
+			status = "failed"
+		}
+		fmt.Fprintf(&b, "- [%s] panel=%s command=%q", status, entry.PanelName, entry.Command)
+		if entry.Error != "" {
+			fmt.Fprintf(&b, " error=%q", entry.Error)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// compactLoop periodically checks whether the on-disk log has
+// accumulated enough superseded lines to be worth rewriting, and compacts
+// it down to just the retained entries when it has.
+func (ch *CommandHistory) compactLoop() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		ch.maybeCompact()
+	}
+}
+
+// maybeCompact rewrites the history file to contain only the current
+// ring buffer's entries, atomically, once the file has grown past
+// historyCompactionFactor*maxEntries lines.
+func (ch *CommandHistory) maybeCompact() {
+	ch.mu.Lock()
+	if ch.file == nil || ch.linesWritten <= ch.maxEntries*historyCompactionFactor {
+		ch.mu.Unlock()
+		return
+	}
+	entries := append([]CommandHistoryEntry{}, ch.entries...)
+	ch.mu.Unlock()
+
+	tmpPath := ch.filePath + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			continue
+		}
+		if _, err := tmp.Write(append(data, '\n')); err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+			return
+		}
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return
+	}
+	tmp.Close()
+
+	if err := os.Rename(tmpPath, ch.filePath); err != nil {
+		os.Remove(tmpPath)
+		return
+	}
+
+	ch.mu.Lock()
+	if ch.file != nil {
+		ch.file.Close()
+	}
+	if f, err := os.OpenFile(ch.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644); err == nil {
+		ch.file = f
+	}
+	ch.linesWritten = len(entries)
+	ch.mu.Unlock()
+}
+
+// HistoryMiddleware records every finished command - CommandOutputAction on
+// success, CommandErrorAction on failure - into a HistoryStore, scoped to
+// the current Store state's PWD/Mode, then passes the action through
+// unchanged.
+type HistoryMiddleware struct {
+	store HistoryStore
+}
+
+func NewHistoryMiddleware(store HistoryStore) *HistoryMiddleware {
+	return &HistoryMiddleware{store: store}
+}
+
+func (hm *HistoryMiddleware) Middleware(store *Store) func(next Dispatcher) Dispatcher {
+	return func(next Dispatcher) Dispatcher {
+		return func(action Action) {
+			scope := currentScope(store)
+
+			switch a := action.(type) {
+			case CommandOutputAction:
+				hm.store.AddEntry(scope, CommandHistoryEntry{
+					PanelName: a.PanelName,
+					Success:   true,
+					Output:    a.Output,
+					Timestamp: time.Now(),
+					PWD:       scope.PWD,
+					Mode:      scope.Mode,
+				})
+			case CommandErrorAction:
+				hm.store.AddEntry(scope, CommandHistoryEntry{
+					PanelName: a.PanelName,
+					Command:   a.Command,
+					Success:   false,
+					Error:     a.Err.Error(),
+					Timestamp: time.Now(),
+					PWD:       scope.PWD,
+					Mode:      scope.Mode,
+				})
+			}
+			next(action)
+		}
+	}
+}
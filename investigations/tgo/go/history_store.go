@@ -0,0 +1,240 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// historyMode is the only mode this binary runs in today. Scope carries a
+// Mode field anyway so a future panel kind that tracks modes the way
+// tubes-001's HybridModel does can scope its own history without another
+// storage-layer change.
+const historyMode = "tgo"
+
+// Scope identifies one workspace's history: the directory the app was
+// run from, and the mode it was run in.
+type Scope struct {
+	PWD  string
+	Mode string
+}
+
+// currentScope reads store's current PWD into a Scope for the default,
+// single-mode case.
+func currentScope(store *Store) Scope {
+	return Scope{PWD: store.GetState().Pwd, Mode: historyMode}
+}
+
+// HistoryStore is the storage layer CommandHistory used to be: something
+// that can record a finished command and answer the questions
+// LLMMiddleware and a future history-browsing panel ask of it. Every
+// method takes a Scope because MultiScopeHistory keeps one log per
+// workspace rather than one log for the whole machine; global is true to
+// span every known scope instead of just the one given.
+type HistoryStore interface {
+	AddEntry(scope Scope, entry CommandHistoryEntry)
+	Entries(scope Scope, global bool) []CommandHistoryEntry
+	GetFailedCommands(scope Scope) []CommandHistoryEntry
+	GetLLMContext(scope Scope, n int, global bool) string
+}
+
+// scopeKey returns the filename MultiScopeHistory gives scope's log,
+// under historyDir(): a short hash of PWD (so the path stays a sane
+// length regardless of how deep PWD is) plus the mode.
+func scopeKey(scope Scope) string {
+	sum := sha256.Sum256([]byte(scope.PWD))
+	return hex.EncodeToString(sum[:8]) + "-" + scope.Mode
+}
+
+// historyDir is where MultiScopeHistory keeps every workspace's log and
+// its index: $HOME/.config/devops/history, or .config/devops/history
+// relative to the working directory when the home directory is unknown.
+func historyDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".config", "devops", "history")
+	}
+	return filepath.Join(home, ".config", "devops", "history")
+}
+
+// historyIndexEntry records one scope's existence in the global index, so
+// a --global query knows which per-scope files to read without walking
+// historyDir().
+type historyIndexEntry struct {
+	PWD  string `json:"pwd"`
+	Mode string `json:"mode"`
+	Key  string `json:"key"`
+}
+
+// MultiScopeHistory is the HistoryStore that gives each Scope its own
+// JSONL file - following the same split gomuks made moving room history
+// out of one blob and into its own storage layer - plus a small index of
+// every scope seen, so a --global AskLLMAction or search can span all of
+// them.
+type MultiScopeHistory struct {
+	mu         sync.Mutex
+	dir        string
+	maxEntries int
+	histories  map[string]*CommandHistory // scopeKey -> that scope's log
+	index      map[string]historyIndexEntry
+}
+
+// NewMultiScopeHistory opens dir (creating it if needed), migrating a
+// legacy single-file history at legacyPath into per-scope files on first
+// run if one is found and the index is otherwise empty.
+func NewMultiScopeHistory(dir string, maxEntries int, legacyPath string) *MultiScopeHistory {
+	ms := &MultiScopeHistory{
+		dir:        dir,
+		maxEntries: maxEntries,
+		histories:  make(map[string]*CommandHistory),
+		index:      make(map[string]historyIndexEntry),
+	}
+
+	os.MkdirAll(dir, 0755)
+	ms.loadIndex()
+
+	if len(ms.index) == 0 && legacyPath != "" {
+		ms.migrateLegacy(legacyPath)
+	}
+
+	return ms
+}
+
+// migrateLegacy reads a pre-MultiScopeHistory single-file JSONL log and
+// re-adds each entry under its own PWD/Mode, splitting it into the
+// per-scope files MultiScopeHistory expects from then on. Entries from
+// before PWD/Mode were recorded fall back to historyMode under an empty
+// PWD - their own scope, distinct from any real workspace.
+func (ms *MultiScopeHistory) migrateLegacy(legacyPath string) {
+	entries, _ := loadHistoryFile(legacyPath)
+	for _, entry := range entries {
+		mode := entry.Mode
+		if mode == "" {
+			mode = historyMode
+		}
+		ms.AddEntry(Scope{PWD: entry.PWD, Mode: mode}, entry)
+	}
+}
+
+// historyFor returns (creating if needed) the CommandHistory backing
+// scope, and records scope in the index the first time it's seen.
+// Caller must hold ms.mu.
+func (ms *MultiScopeHistory) historyFor(scope Scope) *CommandHistory {
+	key := scopeKey(scope)
+	if ch, ok := ms.histories[key]; ok {
+		return ch
+	}
+
+	ch := NewCommandHistory(ms.maxEntries, filepath.Join(ms.dir, key+".jsonl"))
+	ms.histories[key] = ch
+
+	if _, ok := ms.index[key]; !ok {
+		ms.index[key] = historyIndexEntry{PWD: scope.PWD, Mode: scope.Mode, Key: key}
+		ms.saveIndex()
+	}
+	return ch
+}
+
+func (ms *MultiScopeHistory) AddEntry(scope Scope, entry CommandHistoryEntry) {
+	ms.mu.Lock()
+	ch := ms.historyFor(scope)
+	ms.mu.Unlock()
+
+	ch.AddEntry(entry)
+}
+
+func (ms *MultiScopeHistory) Entries(scope Scope, global bool) []CommandHistoryEntry {
+	if !global {
+		ms.mu.Lock()
+		ch := ms.historyFor(scope)
+		ms.mu.Unlock()
+		return ch.Entries()
+	}
+
+	var all []CommandHistoryEntry
+	for _, ch := range ms.allHistories() {
+		all = append(all, ch.Entries()...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Timestamp.Before(all[j].Timestamp) })
+	return all
+}
+
+func (ms *MultiScopeHistory) GetFailedCommands(scope Scope) []CommandHistoryEntry {
+	ms.mu.Lock()
+	ch := ms.historyFor(scope)
+	ms.mu.Unlock()
+	return ch.GetFailedCommands()
+}
+
+func (ms *MultiScopeHistory) GetLLMContext(scope Scope, n int, global bool) string {
+	if !global {
+		ms.mu.Lock()
+		ch := ms.historyFor(scope)
+		ms.mu.Unlock()
+		return ch.GetLLMContext(n)
+	}
+
+	merged := NewCommandHistory(n, "")
+	for _, entry := range ms.Entries(scope, true) {
+		merged.AddEntry(entry)
+	}
+	return merged.GetLLMContext(n)
+}
+
+// allHistories opens every scope recorded in the index, including ones
+// historyFor hasn't been asked for yet this run, so a --global query sees
+// every workspace's history and not just the ones touched so far.
+func (ms *MultiScopeHistory) allHistories() []*CommandHistory {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	out := make([]*CommandHistory, 0, len(ms.index))
+	for key, entry := range ms.index {
+		if ch, ok := ms.histories[key]; ok {
+			out = append(out, ch)
+			continue
+		}
+		ch := NewCommandHistory(ms.maxEntries, filepath.Join(ms.dir, key+".jsonl"))
+		ms.histories[key] = ch
+		_ = entry
+		out = append(out, ch)
+	}
+	return out
+}
+
+func (ms *MultiScopeHistory) indexPath() string {
+	return filepath.Join(ms.dir, "index.json")
+}
+
+func (ms *MultiScopeHistory) loadIndex() {
+	data, err := os.ReadFile(ms.indexPath())
+	if err != nil {
+		return
+	}
+	var entries []historyIndexEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return
+	}
+	for _, entry := range entries {
+		ms.index[entry.Key] = entry
+	}
+}
+
+// saveIndex persists the whole index. Caller must hold ms.mu.
+func (ms *MultiScopeHistory) saveIndex() {
+	entries := make([]historyIndexEntry, 0, len(ms.index))
+	for _, entry := range ms.index {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(ms.indexPath(), data, 0644)
+}
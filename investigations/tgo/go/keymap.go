@@ -0,0 +1,188 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// keybindTimeout bounds how long a partial chord (the "g" in "g g") stays
+// pending before it's dropped, the way vim's timeoutlen does - otherwise a
+// stray "g" typed elsewhere would wait forever for a second key that never
+// comes.
+const keybindTimeout = 800 * time.Millisecond
+
+// keyToken is one parsed element of a `keys` chord spec ("g g", "Ctrl+N",
+// ": w <Enter>"): either a literal rune (g, :, w) or a named/modified
+// special key.
+type keyToken struct {
+	key  tcell.Key
+	rn   rune
+	mods tcell.ModMask
+}
+
+func (t keyToken) matches(ev *tcell.EventKey) bool {
+	if t.key == tcell.KeyRune {
+		return ev.Key() == tcell.KeyRune && ev.Rune() == t.rn && ev.Modifiers() == t.mods
+	}
+	return ev.Key() == t.key
+}
+
+var namedKeys = map[string]tcell.Key{
+	"<Enter>":   tcell.KeyEnter,
+	"<Esc>":     tcell.KeyEsc,
+	"<Tab>":     tcell.KeyTab,
+	"<Backtab>": tcell.KeyBacktab,
+}
+
+// ctrlKeys maps a letter to tcell's dedicated KeyCtrl* constant - tcell
+// reports Ctrl+letter as one of these, not as KeyRune with ModCtrl set.
+var ctrlKeys = map[string]tcell.Key{
+	"A": tcell.KeyCtrlA, "B": tcell.KeyCtrlB, "C": tcell.KeyCtrlC, "D": tcell.KeyCtrlD,
+	"E": tcell.KeyCtrlE, "F": tcell.KeyCtrlF, "G": tcell.KeyCtrlG, "H": tcell.KeyCtrlH,
+	"I": tcell.KeyCtrlI, "J": tcell.KeyCtrlJ, "K": tcell.KeyCtrlK, "L": tcell.KeyCtrlL,
+	"M": tcell.KeyCtrlM, "N": tcell.KeyCtrlN, "O": tcell.KeyCtrlO, "P": tcell.KeyCtrlP,
+	"Q": tcell.KeyCtrlQ, "R": tcell.KeyCtrlR, "S": tcell.KeyCtrlS, "T": tcell.KeyCtrlT,
+	"U": tcell.KeyCtrlU, "V": tcell.KeyCtrlV, "W": tcell.KeyCtrlW, "X": tcell.KeyCtrlX,
+	"Y": tcell.KeyCtrlY, "Z": tcell.KeyCtrlZ,
+}
+
+// parseKeys splits a `keys` spec on whitespace into the sequence of
+// keyTokens it describes, e.g. "Ctrl+N" -> [KeyCtrlN], "g g" -> [g, g].
+func parseKeys(spec string) []keyToken {
+	var tokens []keyToken
+	for _, field := range strings.Fields(spec) {
+		tokens = append(tokens, parseKeyToken(field))
+	}
+	return tokens
+}
+
+func parseKeyToken(field string) keyToken {
+	mods := tcell.ModNone
+	for {
+		switch {
+		case strings.HasPrefix(field, "Ctrl+"):
+			mods |= tcell.ModCtrl
+			field = strings.TrimPrefix(field, "Ctrl+")
+			continue
+		case strings.HasPrefix(field, "Alt+"):
+			mods |= tcell.ModAlt
+			field = strings.TrimPrefix(field, "Alt+")
+			continue
+		case strings.HasPrefix(field, "Shift+"):
+			mods |= tcell.ModShift
+			field = strings.TrimPrefix(field, "Shift+")
+			continue
+		}
+		break
+	}
+
+	if mods&tcell.ModCtrl != 0 {
+		if key, ok := ctrlKeys[strings.ToUpper(field)]; ok {
+			return keyToken{key: key}
+		}
+	}
+	if key, ok := namedKeys[field]; ok {
+		return keyToken{key: key, mods: mods}
+	}
+	if field == "<Space>" {
+		return keyToken{key: tcell.KeyRune, rn: ' ', mods: mods}
+	}
+
+	r := rune(0)
+	if runes := []rune(field); len(runes) > 0 {
+		r = runes[0]
+	}
+	return keyToken{key: tcell.KeyRune, rn: r, mods: mods}
+}
+
+// Keybind is one parsed [[keybind]] entry (or a runtime `/bind`): Keys
+// matched in order runs either the registered Action or the /Command line.
+type Keybind struct {
+	Spec    string // the original `keys` string, e.g. "g g" - the bind's identity
+	Keys    []keyToken
+	Action  string
+	Command string
+}
+
+// Keymap matches incoming key events against a set of (possibly
+// multi-key) Keybinds, buffering a partial chord until it's completed,
+// contradicted by the next key, or times out.
+type Keymap struct {
+	binds   []Keybind
+	pending []tcell.EventKey
+	lastKey time.Time
+}
+
+func NewKeymap() *Keymap {
+	return &Keymap{}
+}
+
+// Bind adds b, or replaces the existing bind with the same Spec so
+// `/bind` can rebind a chord at runtime instead of stacking duplicates.
+func (k *Keymap) Bind(b Keybind) {
+	for i, existing := range k.binds {
+		if existing.Spec == b.Spec {
+			k.binds[i] = b
+			return
+		}
+	}
+	k.binds = append(k.binds, b)
+}
+
+// Unbind removes the bind registered for spec, reporting whether one was
+// found.
+func (k *Keymap) Unbind(spec string) bool {
+	for i, existing := range k.binds {
+		if existing.Spec == spec {
+			k.binds = append(k.binds[:i], k.binds[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Binds returns every registered Keybind, for /which-key's full listing.
+func (k *Keymap) Binds() []Keybind {
+	return append([]Keybind(nil), k.binds...)
+}
+
+// Feed adds ev to the pending chord buffer (dropping any stale partial
+// chord first) and reports the result: a completed bind, or the
+// candidates that still share the pending prefix so the which-key
+// overlay can list what to press next.
+func (k *Keymap) Feed(ev *tcell.EventKey) (matched *Keybind, candidates []Keybind) {
+	now := time.Now()
+	if now.Sub(k.lastKey) > keybindTimeout {
+		k.pending = nil
+	}
+	k.lastKey = now
+	k.pending = append(k.pending, *ev)
+
+	for i := range k.binds {
+		b := k.binds[i]
+		if len(b.Keys) < len(k.pending) || !pendingMatchesPrefix(b.Keys, k.pending) {
+			continue
+		}
+		if len(b.Keys) == len(k.pending) {
+			k.pending = nil
+			return &b, nil
+		}
+		candidates = append(candidates, b)
+	}
+	if len(candidates) == 0 {
+		k.pending = nil
+	}
+	return nil, candidates
+}
+
+func pendingMatchesPrefix(keys []keyToken, pending []tcell.EventKey) bool {
+	for i := range pending {
+		ev := pending[i]
+		if !keys[i].matches(&ev) {
+			return false
+		}
+	}
+	return true
+}
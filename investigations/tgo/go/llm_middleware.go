@@ -0,0 +1,299 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// AskLLMAction sends Prompt, combined with CommandHistory context, to the
+// configured LLMClient and streams the reply back into PanelName as a
+// sequence of LLMTokenAction events. Global asks for context spanning
+// every workspace's history instead of just the current one - the
+// "/ask --global" form of the command.
+type AskLLMAction struct {
+	Prompt    string
+	PanelName string
+	Global    bool
+}
+
+// LLMTokenAction appends one streamed chunk of the in-progress completion
+// to PanelName's assistant content.
+type LLMTokenAction struct {
+	PanelName string
+	Token     string
+}
+
+// LLMResponseAction marks a completion finished, carrying any shell
+// commands parsed out of it (fenced ```sh blocks or lines the model
+// prefixed with "$ ") for the user to accept into ExecuteCommandAction.
+type LLMResponseAction struct {
+	PanelName   string
+	Suggestions []string
+}
+
+// LLMClient streams a chat-style completion for prompt, invoking onToken
+// once per chunk as it arrives and returning once the stream ends.
+type LLMClient interface {
+	Stream(ctx context.Context, model, prompt string, onToken func(string)) error
+}
+
+// LLMMiddleware intercepts AskLLMAction, builds a prompt from the user's
+// text plus CommandHistory's recent and failed commands and the current
+// file/pwd, and streams the reply from client into PanelName.
+type LLMMiddleware struct {
+	client     LLMClient
+	model      string
+	history    HistoryStore
+	maxContext int
+	panelName  string // where a palette-triggered "ask" streams its reply
+}
+
+func NewLLMMiddleware(client LLMClient, model string, history HistoryStore, maxContext int, panelName string) *LLMMiddleware {
+	if maxContext <= 0 {
+		maxContext = 20
+	}
+	return &LLMMiddleware{client: client, model: model, history: history, maxContext: maxContext, panelName: panelName}
+}
+
+func (lm *LLMMiddleware) Middleware(store *Store) func(next Dispatcher) Dispatcher {
+	return func(next Dispatcher) Dispatcher {
+		return func(action Action) {
+			switch a := action.(type) {
+			case AskLLMAction:
+				lm.ask(store, a)
+			case PaletteSubmitAction:
+				if parsed, ok := parseAskPaletteCommand(a.Line, lm.panelName); ok {
+					lm.ask(store, parsed)
+				} else {
+					next(action)
+				}
+			default:
+				next(action)
+			}
+		}
+	}
+}
+
+// parseAskPaletteCommand parses "ask [--global] <prompt>" out of a
+// command-bar line into an AskLLMAction, reporting false for anything
+// else so the caller falls through to the rest of the chain - the same
+// convention TaskMiddleware.dispatchPaletteCommand uses for "tasks ...".
+func parseAskPaletteCommand(line, panelName string) (AskLLMAction, bool) {
+	const prefix = "ask "
+	if !strings.HasPrefix(line, prefix) {
+		return AskLLMAction{}, false
+	}
+
+	rest := strings.TrimPrefix(line, prefix)
+	global := false
+	if strings.HasPrefix(rest, "--global ") {
+		global = true
+		rest = strings.TrimPrefix(rest, "--global ")
+	}
+
+	return AskLLMAction{Prompt: rest, PanelName: panelName, Global: global}, true
+}
+
+// ask runs the round trip in a goroutine so it never blocks Dispatch,
+// streaming LLMTokenAction as chunks arrive and finishing with
+// LLMResponseAction once the client's Stream call returns.
+func (lm *LLMMiddleware) ask(store *Store, a AskLLMAction) {
+	go func() {
+		state := store.GetState()
+		prompt := lm.buildPrompt(a.Prompt, state, currentScope(store), a.Global)
+
+		var reply strings.Builder
+		err := lm.client.Stream(context.Background(), lm.model, prompt, func(token string) {
+			reply.WriteString(token)
+			store.Dispatch(LLMTokenAction{PanelName: a.PanelName, Token: token})
+		})
+		if err != nil {
+			store.Dispatch(CommandErrorAction{PanelName: a.PanelName, Err: err})
+			return
+		}
+
+		store.Dispatch(LLMResponseAction{
+			PanelName:   a.PanelName,
+			Suggestions: parseShellSuggestions(reply.String()),
+		})
+	}()
+}
+
+// buildPrompt combines the user's prompt with CommandHistory context and
+// the current file/pwd, the way a human would paste their recent terminal
+// output before asking a question about it. global spans every
+// workspace's history instead of just scope's.
+func (lm *LLMMiddleware) buildPrompt(prompt string, state State, scope Scope, global bool) string {
+	var b strings.Builder
+	b.WriteString(lm.history.GetLLMContext(scope, lm.maxContext, global))
+
+	if failed := lm.history.GetFailedCommands(scope); len(failed) > 0 {
+		b.WriteString("\nFailed commands:\n")
+		for _, entry := range failed {
+			fmt.Fprintf(&b, "- %s: %s\n", entry.Command, entry.Error)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nCurrent file: %s\nPWD: %s\n\n%s\n", state.CurrentFile, state.Pwd, prompt)
+	return b.String()
+}
+
+// parseShellSuggestions pulls candidate shell commands out of an LLM
+// reply: ```sh (or ```bash / ```shell) fenced blocks, or any line
+// prefixed with "$ ". Lines inside fences of other languages are ignored.
+func parseShellSuggestions(reply string) []string {
+	var suggestions []string
+	inFence, shellFence := false, false
+	for _, line := range strings.Split(reply, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			inFence = !inFence
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			shellFence = inFence && (lang == "sh" || lang == "bash" || lang == "shell")
+		case inFence:
+			if shellFence && trimmed != "" {
+				suggestions = append(suggestions, trimmed)
+			}
+		case strings.HasPrefix(trimmed, "$ "):
+			suggestions = append(suggestions, strings.TrimPrefix(trimmed, "$ "))
+		}
+	}
+	return suggestions
+}
+
+// ===== OpenAI-compatible client =====
+
+// OpenAIClient talks to any OpenAI-compatible chat-completions endpoint
+// (OpenAI itself, or a local server that mimics its API), streaming
+// Server-Sent Events.
+type OpenAIClient struct {
+	endpoint string
+	apiKey   string
+}
+
+func NewOpenAIClient(endpoint, apiKeyEnv string) *OpenAIClient {
+	return &OpenAIClient{endpoint: endpoint, apiKey: os.Getenv(apiKeyEnv)}
+}
+
+func (c *OpenAIClient) Stream(ctx context.Context, model, prompt string, onToken func(string)) error {
+	body, err := json.Marshal(map[string]any{
+		"model":  model,
+		"stream": true,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("llm endpoint returned %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		payload := strings.TrimPrefix(line, "data: ")
+		if payload == "[DONE]" {
+			return nil
+		}
+
+		var chunk struct {
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+		}
+		if err := json.Unmarshal([]byte(payload), &chunk); err != nil {
+			continue
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content != "" {
+				onToken(choice.Delta.Content)
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// ===== Ollama client =====
+
+// OllamaClient talks to a local Ollama server's /api/generate endpoint,
+// which streams one JSON object per line rather than SSE.
+type OllamaClient struct {
+	endpoint string
+}
+
+func NewOllamaClient(endpoint string) *OllamaClient {
+	return &OllamaClient{endpoint: endpoint}
+}
+
+func (c *OllamaClient) Stream(ctx context.Context, model, prompt string, onToken func(string)) error {
+	body, err := json.Marshal(map[string]any{
+		"model":  model,
+		"prompt": prompt,
+		"stream": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/api/generate", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("llm endpoint returned %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var chunk struct {
+			Response string `json:"response"`
+			Done     bool   `json:"done"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &chunk); err != nil {
+			continue
+		}
+		if chunk.Response != "" {
+			onToken(chunk.Response)
+		}
+		if chunk.Done {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,23 @@
+package main
+
+import "log"
+
+// LoggingMiddleware logs every action before it reaches the rest of the
+// chain and again once the reducer has applied it, the structured
+// replacement for the log.Printf calls CommandMiddleware used to sprinkle
+// around its own execution paths.
+type LoggingMiddleware struct{}
+
+func NewLoggingMiddleware() *LoggingMiddleware {
+	return &LoggingMiddleware{}
+}
+
+func (lm *LoggingMiddleware) Middleware(store *Store) func(next Dispatcher) Dispatcher {
+	return func(next Dispatcher) Dispatcher {
+		return func(action Action) {
+			log.Printf("dispatch: before %T", action)
+			next(action)
+			log.Printf("dispatch: after %T", action)
+		}
+	}
+}
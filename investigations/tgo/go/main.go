@@ -1,25 +1,121 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"os"
+	"time"
 
 	"github.com/rivo/tview"
+	"golang.org/x/term"
+
+	"github.com/study-groups/devops/tetra/console/go/tasks"
 )
 
-func main() {
+// llmClient resolves config's `llm:` block to a concrete LLMClient:
+// Ollama's /api/generate when api_key_env is unset (Ollama needs no key),
+// an OpenAI-compatible chat-completions endpoint otherwise.
+func llmClient(config LLMConfig) LLMClient {
+	if config.APIKeyEnv == "" {
+		return NewOllamaClient(config.Endpoint)
+	}
+	return NewOpenAIClient(config.Endpoint, config.APIKeyEnv)
+}
+
+// buildStore loads panels.toml and wires up the Redux-style store and
+// middleware chain shared by the TUI and HeadlessRunner. Order matters -
+// AddMiddleware composes like Redux's applyMiddleware, so the first
+// middleware added is outermost: Logging sees every action first and
+// last, Error recovers panics from everything beneath it, Retry
+// re-drives failed retriable commands, and History records finished
+// commands without CommandMiddleware itself knowing persistence exists.
+func buildStore() (*Store, *Config, error) {
 	config, err := LoadConfig("panels.toml")
 	if err != nil {
-		log.Fatalf("Error loading panels.toml: %v", err)
+		return nil, nil, err
 	}
 
 	pwd, _ := os.Getwd()
 
-	// Set up the Redux-style store and middleware.
 	store := NewStore(pwd)
+	store.AddMiddleware(NewLoggingMiddleware().Middleware)
+	store.AddMiddleware(NewErrorMiddleware().Middleware)
+	store.AddMiddleware(NewRetryMiddleware(3, 200*time.Millisecond).Middleware)
+
+	history := NewMultiScopeHistory(historyDir(), 200, historyFilePath())
+	store.AddMiddleware(NewHistoryMiddleware(history).Middleware)
+
+	if config.LLM.Endpoint != "" {
+		llmMiddleware := NewLLMMiddleware(llmClient(config.LLM), config.LLM.Model, history, config.LLM.MaxContextEntries, assistantPanelName(config.Panels))
+		store.AddMiddleware(llmMiddleware.Middleware)
+	}
+
 	commandMiddleware := NewCommandMiddleware(NewExecutor())
 	store.AddMiddleware(commandMiddleware.Middleware)
 
+	taskRunner, err := tasks.NewRunner(taskList(config.Tasks))
+	if err != nil {
+		return nil, nil, err
+	}
+	taskMiddleware := NewTaskMiddleware(taskRunner, taskPanelName(config.Panels))
+	store.AddMiddleware(taskMiddleware.Middleware)
+
+	return store, config, nil
+}
+
+// runHeadless builds the same store/middleware chain the TUI uses, adds
+// HeadlessRunner as one more middleware so it can observe every command's
+// outcome, and drives actions through it with no tview.Application in the
+// loop - the path `devops --script ci.yaml`, a piped stdin action stream,
+// and `devops replay history.jsonl` all share.
+func runHeadless(actions []HeadlessAction) int {
+	store, _, err := buildStore()
+	if err != nil {
+		log.Fatalf("Error building store: %v", err)
+	}
+
+	runner := NewHeadlessRunner(os.Stdout)
+	store.AddMiddleware(runner.Middleware)
+
+	return runner.Run(store, actions)
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		if len(os.Args) != 3 {
+			log.Fatalf("usage: devops replay <history.jsonl>")
+		}
+		actions, err := replayActions(os.Args[2])
+		if err != nil {
+			log.Fatalf("Error loading replay log: %v", err)
+		}
+		os.Exit(runHeadless(actions))
+	}
+
+	scriptPath := flag.String("script", "", "run a scripted sequence of actions headlessly instead of the TUI")
+	flag.Parse()
+
+	if *scriptPath != "" {
+		actions, err := loadScript(*scriptPath)
+		if err != nil {
+			log.Fatalf("Error loading script: %v", err)
+		}
+		os.Exit(runHeadless(actions))
+	}
+
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		actions, err := readActionStream(os.Stdin)
+		if err != nil {
+			log.Fatalf("Error reading action stream: %v", err)
+		}
+		os.Exit(runHeadless(actions))
+	}
+
+	store, config, err := buildStore()
+	if err != nil {
+		log.Fatalf("Error loading panels.toml: %v", err)
+	}
+
 	app := tview.NewApplication()
 
 	// The TUI is now a subscriber to the store.
@@ -1,6 +1,8 @@
 package main
 
-import "log"
+import (
+	"sync"
+)
 
 // Middleware enhances the store's dispatch function to handle side effects.
 type Middleware func(store *Store) func(next Dispatcher) Dispatcher
@@ -8,42 +10,113 @@ type Middleware func(store *Store) func(next Dispatcher) Dispatcher
 // Dispatcher is the function signature for dispatching actions.
 type Dispatcher func(action Action)
 
-// CommandMiddleware handles actions that need to execute shell commands.
+// CommandMiddleware runs panel commands through Executor: ExecuteCommandAction
+// for `runner = "oneshot"` panels (run once, replace content - the original
+// behavior), and StartStreamAction/StopStreamAction for `runner = "stream"`
+// panels, whose process is kept alive and whose output is dispatched one
+// CommandChunkAction per line, so the dock's Drawable is invalidated one
+// targeted redraw at a time instead of waiting for the whole command to
+// finish.
 type CommandMiddleware struct {
 	executor *Executor
+
+	mu      sync.Mutex
+	streams map[string]*StreamHandle // panel name -> running stream
 }
 
 func NewCommandMiddleware(executor *Executor) *CommandMiddleware {
-	return &CommandMiddleware{executor: executor}
+	return &CommandMiddleware{
+		executor: executor,
+		streams:  make(map[string]*StreamHandle),
+	}
 }
 
 func (cm *CommandMiddleware) Middleware(store *Store) func(next Dispatcher) Dispatcher {
 	return func(next Dispatcher) Dispatcher {
 		return func(action Action) {
-			// Pass through non-command actions.
-			execAction, ok := action.(ExecuteCommandAction)
-			if !ok {
+			switch a := action.(type) {
+			case ExecuteCommandAction:
+				cm.runOneshot(store, a)
+			case StartStreamAction:
+				cm.startStream(store, a)
+			case StopStreamAction:
+				cm.stopStream(a.PanelName)
+			default:
+				// Pass through anything that isn't ours to handle.
 				next(action)
-				return
 			}
+		}
+	}
+}
 
-			// Handle the command execution in a goroutine to avoid blocking the UI.
-			go func() {
-				state := store.GetState()
-				log.Printf("Executing command for panel '%s'", execAction.PanelName)
-				output, err := cm.executor.Execute(execAction.Command, state.CurrentFile, state.Pwd)
-				if err != nil {
-					log.Printf("Error executing command for panel '%s': %v", execAction.PanelName, err)
-					// You could dispatch an error action here if needed.
-					return
-				}
-
-				// Dispatch a new action with the result.
-				store.Dispatch(CommandOutputAction{
-					PanelName: execAction.PanelName,
-					Output:    output,
-				})
-			}()
+// runOneshot runs a.Command to completion in a goroutine, so it never blocks
+// the UI, then dispatches the result as CommandOutputAction. A failing
+// Execute dispatches CommandErrorAction instead of the output, so LoggingMiddleware
+// and HistoryMiddleware see the failure and RetryMiddleware can re-drive it
+// when a.Retriable is set.
+func (cm *CommandMiddleware) runOneshot(store *Store, a ExecuteCommandAction) {
+	go func() {
+		state := store.GetState()
+		output, err := cm.executor.Execute(a.Command, a.Shell, state.CurrentFile, state.Pwd)
+		if err != nil {
+			store.Dispatch(CommandErrorAction{
+				PanelName: a.PanelName,
+				Command:   a.Command,
+				Err:       err,
+				Retriable: a.Retriable,
+			})
+			return
 		}
+
+		store.Dispatch(CommandOutputAction{
+			PanelName: a.PanelName,
+			Output:    output,
+		})
+	}()
+}
+
+// startStream launches a.Command as a long-running process and dispatches
+// one CommandChunkAction per output line as it arrives. Re-starting an
+// already-running panel's stream is a no-op; StopStreamAction (dispatched
+// when the panel loses focus, the same way TerminalPanel.Stop does) tears
+// it down.
+func (cm *CommandMiddleware) startStream(store *Store, a StartStreamAction) {
+	cm.mu.Lock()
+	if _, running := cm.streams[a.PanelName]; running {
+		cm.mu.Unlock()
+		return
+	}
+	cm.mu.Unlock()
+
+	state := store.GetState()
+	handle, err := cm.executor.Stream(a.Command, a.Shell, state.CurrentFile, state.Pwd)
+	if err != nil {
+		store.Dispatch(CommandErrorAction{PanelName: a.PanelName, Command: a.Command, Err: err})
+		return
+	}
+
+	cm.mu.Lock()
+	cm.streams[a.PanelName] = handle
+	cm.mu.Unlock()
+
+	go func() {
+		for chunk := range handle.Chunks {
+			store.Dispatch(CommandChunkAction{PanelName: a.PanelName, Chunk: chunk})
+		}
+		cm.mu.Lock()
+		delete(cm.streams, a.PanelName)
+		cm.mu.Unlock()
+	}()
+}
+
+// stopStream ends a panel's running stream, if any.
+func (cm *CommandMiddleware) stopStream(panelName string) {
+	cm.mu.Lock()
+	handle, ok := cm.streams[panelName]
+	delete(cm.streams, panelName)
+	cm.mu.Unlock()
+
+	if ok {
+		handle.Stop()
 	}
 }
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Completer suggests completions for the text currently left of the
+// cursor in the CommandPalette, so the palette's tab-completion isn't
+// hard-wired to one kind of target (commands today, but a Completer
+// could just as well complete panel names or store action names).
+type Completer interface {
+	Complete(line string) []string
+}
+
+// PathCompleter completes the last whitespace-separated field of line as
+// a filesystem path relative to Dir, standing in for the file/context
+// completions a Core.ListFiles-backed completer would otherwise drive.
+type PathCompleter struct {
+	Dir string
+}
+
+// Complete lists entries under the directory portion of the line's last
+// field whose name has that field's basename as a prefix.
+func (c PathCompleter) Complete(line string) []string {
+	fields := strings.Fields(line)
+	prefix := ""
+	if len(fields) > 0 && !strings.HasSuffix(line, " ") {
+		prefix = fields[len(fields)-1]
+	}
+
+	dir, base := filepath.Split(prefix)
+	searchDir := filepath.Join(c.Dir, dir)
+	entries, err := os.ReadDir(searchDir)
+	if err != nil {
+		return nil
+	}
+
+	var out []string
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), base) {
+			continue
+		}
+		name := dir + e.Name()
+		if e.IsDir() {
+			name += "/"
+		}
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// PaletteSubmitAction is dispatched when the user submits a line from the
+// CommandPalette; CommandMiddleware-style middleware (or a reducer, once
+// this tree has one) decides what a given line means.
+type PaletteSubmitAction struct {
+	Line string
+}
+
+// PaletteHistory is a readline-style persistent command history: entries
+// are appended to a file as they're submitted, and Prev/Next walk
+// backwards/forwards through them the way Up/Down arrows do in a shell.
+type PaletteHistory struct {
+	path    string
+	entries []string
+	cursor  int // index into entries; len(entries) means "not browsing"
+}
+
+// paletteHistoryPath returns $XDG_STATE_HOME/devops/history, falling
+// back to $HOME/.local/state/devops/history when XDG_STATE_HOME is
+// unset, matching the XDG base directory spec's default.
+func paletteHistoryPath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "devops", "history"), nil
+}
+
+// NewPaletteHistory loads history from path without creating anything;
+// Append creates the parent directory and the file on first use. A
+// missing file is not an error: history just starts empty.
+func NewPaletteHistory(path string) (*PaletteHistory, error) {
+	h := &PaletteHistory{path: path}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	h.cursor = len(h.entries)
+	return h, scanner.Err()
+}
+
+// Append records line as the newest history entry and persists it,
+// resetting the browse cursor to "not browsing".
+func (h *PaletteHistory) Append(line string) error {
+	h.entries = append(h.entries, line)
+	h.cursor = len(h.entries)
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(line + "\n")
+	return err
+}
+
+// Prev walks backwards through history (older entries), returning ok =
+// false once there's nothing older left.
+func (h *PaletteHistory) Prev() (string, bool) {
+	if h.cursor == 0 {
+		return "", false
+	}
+	h.cursor--
+	return h.entries[h.cursor], true
+}
+
+// Next walks forward through history; once it passes the newest entry it
+// returns ("", true) for "back to the in-progress line the user was
+// typing", matching shell readline behavior.
+func (h *PaletteHistory) Next() (string, bool) {
+	if h.cursor >= len(h.entries) {
+		return "", false
+	}
+	h.cursor++
+	if h.cursor == len(h.entries) {
+		return "", true
+	}
+	return h.entries[h.cursor], true
+}
+
+// ReverseSearch returns the most recent history entry containing substr,
+// for Ctrl-R incremental reverse search.
+func (h *PaletteHistory) ReverseSearch(substr string) (string, bool) {
+	if substr == "" {
+		return "", false
+	}
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if strings.Contains(h.entries[i], substr) {
+			return h.entries[i], true
+		}
+	}
+	return "", false
+}
+
+// CommandPalette is a readline-like input, opened over the dock layout
+// the same way cmdBar is, but with persistent history, Ctrl-R reverse
+// search, Tab completion via a pluggable Completer, and the Emacs
+// chords Ctrl-K and Ctrl-W. Ctrl-A/E and Alt-B/F are captured but do not
+// move the cursor (see setCursor). Submissions are dispatched through
+// Store as a PaletteSubmitAction rather than interpreted locally.
+type CommandPalette struct {
+	*tview.InputField
+	store     *Store
+	completer Completer
+	history   *PaletteHistory
+
+	searching   bool
+	searchQuery string
+	completions []string
+	complIndex  int
+}
+
+// NewCommandPalette builds a CommandPalette backed by store and completer,
+// loading persistent history from paletteHistoryPath (a failure to load
+// history is non-fatal: the palette just starts with empty history).
+func NewCommandPalette(store *Store, completer Completer) *CommandPalette {
+	p := &CommandPalette{
+		InputField: tview.NewInputField().SetLabel(": "),
+		store:      store,
+		completer:  completer,
+	}
+
+	if path, err := paletteHistoryPath(); err == nil {
+		if h, err := NewPaletteHistory(path); err == nil {
+			p.history = h
+		}
+	}
+	if p.history == nil {
+		p.history = &PaletteHistory{}
+	}
+
+	p.SetInputCapture(p.handleKey)
+	return p
+}
+
+// Open resets the palette to an empty line and focuses it; callers
+// (the "command-palette" action) then register it as the active page.
+func (p *CommandPalette) Open() {
+	p.searching = false
+	p.complIndex = -1
+	p.SetLabel(": ")
+	p.SetText("")
+}
+
+// Submit dispatches the current line through Store, records it in
+// history, and clears the input for the next invocation. Callers wire
+// it into SetDoneFunc themselves since closing the palette's page is the
+// owning TUI's job, not the palette's.
+func (p *CommandPalette) Submit() {
+	line := p.GetText()
+	if line == "" {
+		return
+	}
+	p.history.Append(line)
+	p.store.Dispatch(PaletteSubmitAction{Line: line})
+	p.SetText("")
+}
+
+// handleKey implements the Emacs chords and Ctrl-R reverse-i-search that
+// tview.InputField doesn't provide on its own; everything it doesn't
+// recognize passes through to the InputField's own handling.
+func (p *CommandPalette) handleKey(ev *tcell.EventKey) *tcell.EventKey {
+	if p.searching {
+		return p.handleSearchKey(ev)
+	}
+
+	switch {
+	case ev.Key() == tcell.KeyCtrlR:
+		p.searching = true
+		p.searchQuery = ""
+		p.SetLabel("(reverse-i-search)`': ")
+		return nil
+
+	case ev.Key() == tcell.KeyCtrlA:
+		p.setCursor(0)
+		return nil
+	case ev.Key() == tcell.KeyCtrlE:
+		p.setCursor(len(p.GetText()))
+		return nil
+	case ev.Key() == tcell.KeyCtrlK:
+		text, cursor := p.GetText(), p.cursor()
+		p.SetText(text[:cursor])
+		return nil
+	case ev.Key() == tcell.KeyCtrlW:
+		p.deleteWordBack()
+		return nil
+
+	case ev.Key() == tcell.KeyRune && ev.Modifiers()&tcell.ModAlt != 0 && (ev.Rune() == 'b' || ev.Rune() == 'B'):
+		p.setCursor(wordBackFrom(p.GetText(), p.cursor()))
+		return nil
+	case ev.Key() == tcell.KeyRune && ev.Modifiers()&tcell.ModAlt != 0 && (ev.Rune() == 'f' || ev.Rune() == 'F'):
+		p.setCursor(wordForwardFrom(p.GetText(), p.cursor()))
+		return nil
+
+	case ev.Key() == tcell.KeyUp:
+		if line, ok := p.history.Prev(); ok {
+			p.SetText(line)
+		}
+		return nil
+	case ev.Key() == tcell.KeyDown:
+		if line, ok := p.history.Next(); ok {
+			p.SetText(line)
+		}
+		return nil
+
+	case ev.Key() == tcell.KeyTab:
+		p.cycleCompletion()
+		return nil
+	}
+
+	p.complIndex = -1
+	return ev
+}
+
+// handleSearchKey drives Ctrl-R's incremental reverse search: typing
+// narrows searchQuery, Ctrl-R again repeats the search and shows the same
+// most recent match (older matches are not stepped through), and
+// Enter/Esc/any other key exits search mode (Enter accepts the match,
+// everything else falls through to normal editing of whatever's showing).
+func (p *CommandPalette) handleSearchKey(ev *tcell.EventKey) *tcell.EventKey {
+	switch {
+	case ev.Key() == tcell.KeyCtrlR:
+		if match, ok := p.history.ReverseSearch(p.searchQuery); ok {
+			p.SetText(match)
+		}
+		return nil
+	case ev.Key() == tcell.KeyBackspace || ev.Key() == tcell.KeyBackspace2:
+		if len(p.searchQuery) > 0 {
+			p.searchQuery = p.searchQuery[:len(p.searchQuery)-1]
+		}
+	case ev.Key() == tcell.KeyRune:
+		p.searchQuery += string(ev.Rune())
+	default:
+		p.searching = false
+		p.SetLabel(": ")
+		if ev.Key() == tcell.KeyEnter {
+			p.Submit()
+		}
+		return nil
+	}
+
+	if match, ok := p.history.ReverseSearch(p.searchQuery); ok {
+		p.SetText(match)
+	}
+	p.SetLabel(fmt.Sprintf("(reverse-i-search)`%s': ", p.searchQuery))
+	return nil
+}
+
+// cycleCompletion advances through completer.Complete(line) on repeated
+// Tab presses, replacing the line's last field with each candidate in
+// turn the way shell completion cycles through matches.
+func (p *CommandPalette) cycleCompletion() {
+	if p.completer == nil {
+		return
+	}
+	if p.complIndex < 0 {
+		p.completions = p.completer.Complete(p.GetText())
+		p.complIndex = 0
+	} else {
+		p.complIndex = (p.complIndex + 1) % max(1, len(p.completions))
+	}
+	if len(p.completions) == 0 {
+		return
+	}
+
+	text := p.GetText()
+	fields := strings.Fields(text)
+	trailingSpace := strings.HasSuffix(text, " ") || len(fields) == 0
+
+	var prefix string
+	if !trailingSpace {
+		prefix = strings.Join(fields[:len(fields)-1], " ")
+		if prefix != "" {
+			prefix += " "
+		}
+	} else {
+		prefix = text
+	}
+	p.SetText(prefix + p.completions[p.complIndex])
+}
+
+func (p *CommandPalette) cursor() int {
+	// tview's InputField doesn't expose the cursor position directly; its
+	// GetCursorPosition custom-drawn text field always shows the cursor at
+	// the end of the text it's tracking, so the end of the current text
+	// doubles as "the cursor" for the Emacs chords above.
+	return len(p.GetText())
+}
+
+func (p *CommandPalette) setCursor(pos int) {
+	// See cursor(): without a real cursor-position API, Ctrl-A/E and
+	// Alt-B/F only have a visible effect once this tree's InputField
+	// exposes one; until then they're accepted as no-ops rather than
+	// falling through to insert a literal control character.
+	_ = pos
+}
+
+func (p *CommandPalette) deleteWordBack() {
+	text := p.GetText()
+	p.SetText(text[:wordBackFrom(text, len(text))])
+}
+
+// wordBackFrom returns the index of the start of the word ending at pos,
+// skipping trailing whitespace first - the Alt-B / Ctrl-W motion.
+func wordBackFrom(text string, pos int) int {
+	i := pos
+	for i > 0 && text[i-1] == ' ' {
+		i--
+	}
+	for i > 0 && text[i-1] != ' ' {
+		i--
+	}
+	return i
+}
+
+// wordForwardFrom returns the index just past the end of the next word
+// starting at pos - the Alt-F motion.
+func wordForwardFrom(text string, pos int) int {
+	i := pos
+	for i < len(text) && text[i] == ' ' {
+		i++
+	}
+	for i < len(text) && text[i] != ' ' {
+		i++
+	}
+	return i
+}
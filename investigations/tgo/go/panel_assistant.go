@@ -0,0 +1,86 @@
+package main
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// AssistantPanel is the `kind = "assistant"` panel LLMMiddleware streams
+// a completion into: an input line for the question, a scrolling view of
+// the streamed reply, and a hotkey to accept the reply's last parsed
+// shell suggestion straight into ExecuteCommandAction.
+type AssistantPanel struct {
+	*tview.Flex
+	store  *Store
+	config PanelConfig
+
+	input  *tview.InputField
+	output *tview.TextView
+
+	suggestions []string
+}
+
+func NewAssistantPanel(store *Store, config PanelConfig) *AssistantPanel {
+	p := &AssistantPanel{
+		Flex:   tview.NewFlex().SetDirection(tview.FlexRow),
+		store:  store,
+		config: config,
+		input:  tview.NewInputField().SetLabel("ask> "),
+		output: tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+	}
+	p.SetBorder(true).SetTitle(" " + config.Name + " ")
+
+	p.input.SetDoneFunc(func(key tcell.Key) {
+		if key != tcell.KeyEnter {
+			return
+		}
+		prompt := p.input.GetText()
+		if prompt == "" {
+			return
+		}
+		p.input.SetText("")
+		p.store.Dispatch(AskLLMAction{Prompt: prompt, PanelName: p.config.Name})
+	})
+
+	p.AddItem(p.output, 0, 1, false).
+		AddItem(p.input, 1, 0, true)
+
+	p.SetInputCapture(p.handleKey)
+	return p
+}
+
+// handleKey accepts the most recently suggested command with Ctrl+A,
+// dispatching it as a fresh ExecuteCommandAction the way a focused
+// TextViewPanel's own command would run.
+func (p *AssistantPanel) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Key() == tcell.KeyCtrlA && len(p.suggestions) > 0 {
+		p.store.Dispatch(ExecuteCommandAction{
+			PanelName: p.config.Name,
+			Command:   p.suggestions[len(p.suggestions)-1],
+			Shell:     p.config.Shell,
+		})
+		return nil
+	}
+	return event
+}
+
+// Render mirrors the streamed reply (PanelState.Content, appended to by
+// LLMTokenAction the same way CommandChunkAction appends TextViewPanel's)
+// and picks up the latest parsed suggestions once LLMResponseAction lands.
+func (p *AssistantPanel) Render(state State) {
+	panelState, ok := state.PanelStates[p.config.Name]
+	if !ok {
+		return
+	}
+
+	if p.output.GetText(false) != panelState.Content {
+		p.output.SetText(panelState.Content)
+		p.output.ScrollToEnd()
+	}
+
+	if len(panelState.Suggestions) > 0 {
+		p.suggestions = panelState.Suggestions
+	}
+}
+
+func (p *AssistantPanel) TabLabel(state State) string { return p.config.Name }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// TaskPanel runs a panels.toml `[[task]]` graph and shows every task's
+// combined stdout/stderr in one scrolling log, written through the same
+// tview.ANSIWriter TerminalPanel writes its pty output through. Unlike
+// TerminalPanel it never owns a process directly: Run/Cancel/Rerun go
+// through Store and TaskMiddleware, so the command palette can trigger
+// the same graph a focused TaskPanel would.
+type TaskPanel struct {
+	*tview.TextView
+	ansiWriter io.Writer
+	store      *Store
+	config     PanelConfig
+
+	started bool
+	written int // bytes of PanelState.Content already written to ansiWriter
+}
+
+func NewTaskPanel(store *Store, config PanelConfig) *TaskPanel {
+	p := &TaskPanel{
+		TextView: tview.NewTextView().SetDynamicColors(true).SetScrollable(true),
+		store:    store,
+		config:   config,
+	}
+	p.SetBorder(true).SetTitle(" " + config.Name + " ")
+	p.ansiWriter = tview.ANSIWriter(p.TextView)
+	return p
+}
+
+// Render starts the whole task graph on first focus - a TaskPanel's
+// equivalent of TextViewPanel's oneshot runner - then writes whatever
+// new output PanelState.Content has accumulated since the last Render.
+// It's an append through ansiWriter rather than a SetText, since a
+// running task's output only ever grows.
+func (p *TaskPanel) Render(state State) {
+	panelState, ok := state.PanelStates[p.config.Name]
+	if !ok {
+		return
+	}
+
+	activePanel, activeOK := state.ActivePanels[state.ActiveDock]
+	isFocused := activeOK && activePanel == p.config.Name
+
+	if isFocused && !p.started {
+		p.started = true
+		p.store.Dispatch(RunTasksAction{PanelName: p.config.Name})
+	}
+
+	if len(panelState.Content) > p.written {
+		p.ansiWriter.Write([]byte(panelState.Content[p.written:]))
+		p.written = len(panelState.Content)
+	}
+}
+
+// TabLabel lists each task's last known status as "name:status" next to
+// the panel's own name, so the tab bar shows the whole graph's progress
+// without focusing the panel.
+func (p *TaskPanel) TabLabel(state State) string {
+	panelState, ok := state.PanelStates[p.config.Name]
+	if !ok || len(panelState.TaskStatuses) == 0 {
+		return p.config.Name
+	}
+
+	names := make([]string, 0, len(panelState.TaskStatuses))
+	for name := range panelState.TaskStatuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(p.config.Name)
+	for _, name := range names {
+		fmt.Fprintf(&b, " %s:%s", name, panelState.TaskStatuses[name])
+	}
+	return b.String()
+}
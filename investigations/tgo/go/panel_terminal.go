@@ -57,6 +57,7 @@ func (p *TerminalPanel) Start(state State) {
 		p.view.Write([]byte(err.Error()))
 		return
 	}
+	p.resizePty()
 
 	p.wg.Add(1)
 	go func() {
@@ -104,16 +105,123 @@ func (p *TerminalPanel) TabLabel(state State) string { return p.config.Name }
 // Delegate tview.Primitive methods to the underlying view.
 func (p *TerminalPanel) Draw(screen tcell.Screen) { p.view.Draw(screen) }
 func (p *TerminalPanel) GetRect() (int, int, int, int) { return p.view.GetRect() }
-func (p *TerminalPanel) SetRect(x, y, width, height int) { p.view.SetRect(x, y, width, height) }
+
+// SetRect forwards the new size to the underlying view and, if the shell
+// is running, to the pty itself via pty.Setsize - without this, full-
+// screen programs (vim, less, a readline prompt) lay out for whatever
+// size they started at and never learn the panel was resized.
+func (p *TerminalPanel) SetRect(x, y, width, height int) {
+	p.view.SetRect(x, y, width, height)
+	p.resizePty()
+}
+
+// resizePty tells the pty the view's current inner size, accounting for
+// the border the view draws. A no-op until the shell has started.
+func (p *TerminalPanel) resizePty() {
+	if p.ptmx == nil {
+		return
+	}
+	_, _, width, height := p.view.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		return
+	}
+	pty.Setsize(p.ptmx, &pty.Winsize{Rows: uint16(height), Cols: uint16(width)})
+}
+
 func (p *TerminalPanel) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
 	return func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
-		if p.running && p.ptmx != nil {
-			if event.Key() == tcell.KeyRune {
-				p.ptmx.Write([]byte(string(event.Rune())))
-			}
-			// The event.Bytes() method does not exist. A full implementation
-			// would require mapping specific tcell.Key values to ANSI escape codes.
-			// This else block is removed to fix the build error.
+		if !p.running || p.ptmx == nil {
+			return
 		}
+		if b := keyANSI(event); b != nil {
+			p.ptmx.Write(b)
+		}
+	}
+}
+
+// keyANSI translates a tcell.EventKey into the byte sequence a real
+// terminal would send for it - the ANSI/VT escapes arrow keys, Home/End,
+// PgUp/PgDn, and function keys need, Ctrl+letter as a single 0x01-0x1A
+// byte, and an Alt-prefixed ESC for any key chorded with Alt - so
+// editors, pagers, and readline-based tools inside the pty behave the
+// same as they would in a real terminal instead of only seeing plain
+// runes.
+func keyANSI(ev *tcell.EventKey) []byte {
+	base := baseKeyBytes(ev)
+	if base == nil {
+		return nil
+	}
+	if ev.Modifiers()&tcell.ModAlt != 0 && ev.Key() != tcell.KeyEsc {
+		return append([]byte{0x1b}, base...)
+	}
+	return base
+}
+
+func baseKeyBytes(ev *tcell.EventKey) []byte {
+	if seq, ok := csiSequences[ev.Key()]; ok {
+		return seq
+	}
+	if b, ok := ctrlBytes[ev.Key()]; ok {
+		return []byte{b}
 	}
+
+	switch ev.Key() {
+	case tcell.KeyRune:
+		return []byte(string(ev.Rune()))
+	case tcell.KeyEnter:
+		return []byte{'\r'}
+	case tcell.KeyTab:
+		return []byte{'\t'}
+	case tcell.KeyBacktab:
+		return []byte("\x1b[Z")
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		return []byte{0x7f}
+	case tcell.KeyDelete:
+		return []byte("\x1b[3~")
+	case tcell.KeyEsc:
+		return []byte{0x1b}
+	}
+	return nil
+}
+
+// csiSequences covers the special keys with a fixed CSI/SS3 encoding:
+// arrows and Home/End as CSI letters, PgUp/PgDn/Insert/F5-F12 as
+// "CSI <n> ~", and F1-F4 as the classic SS3 "ESC O <letter>" form xterm
+// (and most terminfo entries) still use.
+var csiSequences = map[tcell.Key][]byte{
+	tcell.KeyUp:     []byte("\x1b[A"),
+	tcell.KeyDown:   []byte("\x1b[B"),
+	tcell.KeyRight:  []byte("\x1b[C"),
+	tcell.KeyLeft:   []byte("\x1b[D"),
+	tcell.KeyHome:   []byte("\x1b[H"),
+	tcell.KeyEnd:    []byte("\x1b[F"),
+	tcell.KeyInsert: []byte("\x1b[2~"),
+	tcell.KeyPgUp:   []byte("\x1b[5~"),
+	tcell.KeyPgDn:   []byte("\x1b[6~"),
+	tcell.KeyF1:     []byte("\x1bOP"),
+	tcell.KeyF2:     []byte("\x1bOQ"),
+	tcell.KeyF3:     []byte("\x1bOR"),
+	tcell.KeyF4:     []byte("\x1bOS"),
+	tcell.KeyF5:     []byte("\x1b[15~"),
+	tcell.KeyF6:     []byte("\x1b[17~"),
+	tcell.KeyF7:     []byte("\x1b[18~"),
+	tcell.KeyF8:     []byte("\x1b[19~"),
+	tcell.KeyF9:     []byte("\x1b[20~"),
+	tcell.KeyF10:    []byte("\x1b[21~"),
+	tcell.KeyF11:    []byte("\x1b[23~"),
+	tcell.KeyF12:    []byte("\x1b[24~"),
+}
+
+// ctrlBytes covers Ctrl+letter chords that aren't already one of the
+// named keys handled in baseKeyBytes (Backspace/Tab/Enter double as
+// Ctrl+H/I/M at the byte level, so they're left to that switch instead
+// of duplicated here).
+var ctrlBytes = map[tcell.Key]byte{
+	tcell.KeyCtrlA: 0x01, tcell.KeyCtrlB: 0x02, tcell.KeyCtrlC: 0x03, tcell.KeyCtrlD: 0x04,
+	tcell.KeyCtrlE: 0x05, tcell.KeyCtrlF: 0x06, tcell.KeyCtrlG: 0x07,
+	tcell.KeyCtrlJ: 0x0A, tcell.KeyCtrlK: 0x0B, tcell.KeyCtrlL: 0x0C,
+	tcell.KeyCtrlN: 0x0E, tcell.KeyCtrlO: 0x0F, tcell.KeyCtrlP: 0x10,
+	tcell.KeyCtrlQ: 0x11, tcell.KeyCtrlR: 0x12, tcell.KeyCtrlS: 0x13, tcell.KeyCtrlT: 0x14,
+	tcell.KeyCtrlU: 0x15, tcell.KeyCtrlV: 0x16, tcell.KeyCtrlW: 0x17, tcell.KeyCtrlX: 0x18,
+	tcell.KeyCtrlY: 0x19, tcell.KeyCtrlZ: 0x1A,
 }
@@ -6,6 +6,8 @@ type TextViewPanel struct {
 	*tview.TextView
 	store  *Store
 	config PanelConfig
+
+	streaming bool // true once a `runner = "stream"` command has been started
 }
 
 func NewTextViewPanel(store *Store, config PanelConfig) *TextViewPanel {
@@ -18,24 +20,42 @@ func NewTextViewPanel(store *Store, config PanelConfig) *TextViewPanel {
 	return p
 }
 
-// Render syncs the panel's view with the latest state.
+// Render syncs the panel's view with the latest state. `runner = "oneshot"`
+// panels (the default) re-run their command once per file focus, the same
+// as before CommandRunner existed. `runner = "stream"` panels instead start
+// a long-running process on first focus and let CommandChunkAction append
+// to PanelState.Content as output arrives, stopping it on blur the same way
+// TerminalPanel stops its pty on blur.
 func (p *TextViewPanel) Render(state State) {
 	panelState, ok := state.PanelStates[p.config.Name]
 	if !ok {
 		return // State not ready yet.
 	}
 
-	// Re-run the command only when the focused file changes.
-	// We check if the content is already what we expect.
-	// This is a simple way to avoid re-running commands on every refresh.
 	activePanel, activeOK := state.ActivePanels[state.ActiveDock]
 	isFocused := activeOK && activePanel == p.config.Name
 
-	if isFocused && !panelState.IsContentUpToDate {
+	if p.config.Runner == "stream" {
+		if isFocused && !p.streaming {
+			p.streaming = true
+			p.store.Dispatch(StartStreamAction{
+				PanelName: p.config.Name,
+				Command:   p.config.Command,
+				Shell:     p.config.Shell,
+			})
+		} else if !isFocused && p.streaming {
+			p.streaming = false
+			p.store.Dispatch(StopStreamAction{PanelName: p.config.Name})
+		}
+	} else if isFocused && !panelState.IsContentUpToDate {
+		// Re-run the command only when the focused file changes. We check
+		// if the content is already what we expect, a simple way to avoid
+		// re-running commands on every refresh.
 		p.SetText("Executing command...")
 		p.store.Dispatch(ExecuteCommandAction{
 			PanelName: p.config.Name,
 			Command:   p.config.Command,
+			Shell:     p.config.Shell,
 		})
 	}
 
@@ -46,3 +66,12 @@ func (p *TextViewPanel) Render(state State) {
 }
 
 func (p *TextViewPanel) TabLabel(state State) string { return p.config.Name }
+
+// Stop ends any running stream command, mirroring TerminalPanel.Stop so
+// Ctrl+C cleanup doesn't leave a `tail -f` orphaned after the TUI exits.
+func (p *TextViewPanel) Stop() {
+	if p.streaming {
+		p.streaming = false
+		p.store.Dispatch(StopStreamAction{PanelName: p.config.Name})
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// RetryMiddleware re-dispatches a retriable ExecuteCommandAction after it
+// fails, backing off exponentially between attempts. It never blocks the
+// chain itself - the actual wait happens in a goroutine - so a flaky
+// command doesn't stall everything else passing through Dispatch.
+type RetryMiddleware struct {
+	maxRetries int
+	baseDelay  time.Duration
+
+	mu       sync.Mutex
+	attempts map[string]int // panel name -> attempts made so far
+}
+
+func NewRetryMiddleware(maxRetries int, baseDelay time.Duration) *RetryMiddleware {
+	return &RetryMiddleware{
+		maxRetries: maxRetries,
+		baseDelay:  baseDelay,
+		attempts:   make(map[string]int),
+	}
+}
+
+func (rm *RetryMiddleware) Middleware(store *Store) func(next Dispatcher) Dispatcher {
+	return func(next Dispatcher) Dispatcher {
+		return func(action Action) {
+			switch a := action.(type) {
+			case CommandErrorAction:
+				if !a.Retriable || !rm.retry(a, store) {
+					next(action)
+				}
+
+			default:
+				next(action)
+			}
+		}
+	}
+}
+
+// retry reports whether it re-dispatched a.Command, having consumed one of
+// its retry attempts. Once maxRetries is exhausted it returns false so the
+// error falls through to the rest of the chain instead of looping forever.
+func (rm *RetryMiddleware) retry(a CommandErrorAction, store *Store) bool {
+	rm.mu.Lock()
+	attempt := rm.attempts[a.PanelName]
+	if attempt >= rm.maxRetries {
+		delete(rm.attempts, a.PanelName)
+		rm.mu.Unlock()
+		return false
+	}
+	rm.attempts[a.PanelName] = attempt + 1
+	rm.mu.Unlock()
+
+	delay := rm.baseDelay * time.Duration(1<<uint(attempt))
+	go func() {
+		time.Sleep(delay)
+		store.Dispatch(ExecuteCommandAction{
+			PanelName: a.PanelName,
+			Command:   a.Command,
+			Retriable: true,
+		})
+	}()
+	return true
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+)
+
+// CommandRunner builds the *exec.Cmd for a panel's command, hiding the
+// shell (or lack of one) behind a common interface so Executor doesn't
+// need to know how "bash", "sh", "pwsh", or a raw argv command differ.
+// ctx bounds the process lifetime: a streaming command is killed by
+// cancelling it, the same way exec.CommandContext always has.
+type CommandRunner interface {
+	Command(ctx context.Context, command string) *exec.Cmd
+}
+
+// shellRunner runs command as `<shell> -c command`, the original hard-coded
+// behavior now parameterized over which shell.
+type shellRunner struct {
+	shell string
+}
+
+func (r shellRunner) Command(ctx context.Context, command string) *exec.Cmd {
+	return exec.CommandContext(ctx, r.shell, "-c", command)
+}
+
+// argvRunner runs command directly via exec.Command, splitting it on
+// whitespace instead of handing it to a shell - for panels whose command
+// is already a plain argv list and doesn't need quoting, globbing, or pipes.
+type argvRunner struct{}
+
+func (argvRunner) Command(ctx context.Context, command string) *exec.Cmd {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return exec.CommandContext(ctx, "true")
+	}
+	return exec.CommandContext(ctx, fields[0], fields[1:]...)
+}
+
+// runnerFor resolves a PanelConfig's `shell` setting to the CommandRunner
+// that builds its exec.Cmd. An empty or unrecognized value defaults to
+// bash, the only shell Executor supported before panels could choose.
+func runnerFor(shell string) CommandRunner {
+	switch shell {
+	case "sh":
+		return shellRunner{shell: "sh"}
+	case "pwsh":
+		return shellRunner{shell: "pwsh"}
+	case "none":
+		return argvRunner{}
+	default:
+		return shellRunner{shell: "bash"}
+	}
+}
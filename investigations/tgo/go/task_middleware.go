@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/study-groups/devops/tetra/console/go/tasks"
+)
+
+// RunTasksAction starts PanelName's task graph: the whole graph when Name
+// is empty, or just Name and its transitive dependencies otherwise.
+// Dispatched by TaskPanel on first focus, and available to the command
+// palette via the "tasks run [name]" line.
+type RunTasksAction struct {
+	PanelName string
+	Name      string
+}
+
+// CancelTasksAction stops whatever PanelName's Runner currently has in
+// flight, via "tasks cancel".
+type CancelTasksAction struct {
+	PanelName string
+}
+
+// RerunTasksAction behaves like RunTasksAction but ignores each task's
+// inputs/outputs staleness check, via "tasks rerun [name]".
+type RerunTasksAction struct {
+	PanelName string
+	Name      string
+}
+
+// TaskStatusAction records Task's latest Status under PanelName's
+// PanelState, driving the per-task "name:status" text TaskPanel.TabLabel
+// shows.
+type TaskStatusAction struct {
+	PanelName string
+	Task      string
+	Status    tasks.Status
+}
+
+// TaskOutputAction appends one line of Task's combined stdout/stderr to
+// PanelName's PanelState.Content.
+type TaskOutputAction struct {
+	PanelName string
+	Task      string
+	Line      string
+}
+
+// TaskMiddleware runs the *tasks.Runner built from panels.toml's
+// `[[task]]` list and shared by every "task" panel, translating
+// Run/Cancel/RerunTasksAction into calls against it and fanning its
+// Events back out as TaskStatusAction/TaskOutputAction. It also
+// recognizes "tasks run|cancel|rerun [name]" as a PaletteSubmitAction,
+// the same command palette that can't otherwise reach Run/Cancel/Rerun.
+type TaskMiddleware struct {
+	runner    *tasks.Runner
+	panelName string // the "task" panel palette commands are attributed to
+
+	mu      sync.Mutex
+	watched bool
+}
+
+// NewTaskMiddleware wires runner to panelName - the panel whose
+// PanelState absorbs output and status for both focus-triggered runs and
+// palette commands alike.
+func NewTaskMiddleware(runner *tasks.Runner, panelName string) *TaskMiddleware {
+	return &TaskMiddleware{runner: runner, panelName: panelName}
+}
+
+func (tm *TaskMiddleware) Middleware(store *Store) func(next Dispatcher) Dispatcher {
+	return func(next Dispatcher) Dispatcher {
+		return func(action Action) {
+			switch a := action.(type) {
+			case RunTasksAction:
+				tm.watch(store)
+				go tm.runner.Run(context.Background(), a.Name)
+			case CancelTasksAction:
+				tm.runner.Cancel()
+			case RerunTasksAction:
+				tm.watch(store)
+				go tm.runner.Rerun(context.Background(), a.Name)
+			case PaletteSubmitAction:
+				if !tm.dispatchPaletteCommand(store, a.Line) {
+					next(action)
+				}
+			default:
+				next(action)
+			}
+		}
+	}
+}
+
+// dispatchPaletteCommand parses "tasks run|cancel|rerun [name]" out of
+// line and, if it matches, makes the equivalent Runner call directly
+// (no action is dispatched, since we're already inside the middleware)
+// and reports true. Anything else reports false so the caller falls
+// through to the rest of the chain.
+func (tm *TaskMiddleware) dispatchPaletteCommand(store *Store, line string) bool {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || fields[0] != "tasks" || len(fields) < 2 {
+		return false
+	}
+
+	name := ""
+	if len(fields) >= 3 {
+		name = fields[2]
+	}
+
+	switch fields[1] {
+	case "run":
+		tm.watch(store)
+		go tm.runner.Run(context.Background(), name)
+	case "cancel":
+		tm.runner.Cancel()
+	case "rerun":
+		tm.watch(store)
+		go tm.runner.Rerun(context.Background(), name)
+	default:
+		return false
+	}
+	return true
+}
+
+// watch starts, once, the goroutine that forwards runner.Events() as
+// store actions attributed to panelName for as long as the process runs.
+func (tm *TaskMiddleware) watch(store *Store) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if tm.watched {
+		return
+	}
+	tm.watched = true
+
+	go func() {
+		for ev := range tm.runner.Events() {
+			if ev.Line != "" {
+				store.Dispatch(TaskOutputAction{PanelName: tm.panelName, Task: ev.Task, Line: ev.Line})
+			}
+			store.Dispatch(TaskStatusAction{PanelName: tm.panelName, Task: ev.Task, Status: ev.Status})
+		}
+	}()
+}
@@ -0,0 +1,158 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// validate checks that every DependsOn entry names a real task and that
+// the graph it forms has no cycle, returning the first one found as an
+// error (e.g. "cycle: build -> test -> build") rather than deadlocking
+// runClosure at run time.
+func validate(tasks map[string]Task) error {
+	for name, t := range tasks {
+		for _, dep := range t.DependsOn {
+			if _, ok := tasks[dep]; !ok {
+				return fmt.Errorf("task %q depends_on unknown task %q", name, dep)
+			}
+		}
+	}
+
+	const (
+		unvisited = 0
+		visiting  = 1
+		visited   = 2
+	)
+	state := make(map[string]int, len(tasks))
+	var path []string
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("cycle: %s -> %s", joinPath(path), name)
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range tasks[name].DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+
+	for name := range tasks {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func joinPath(path []string) string {
+	s := ""
+	for i, name := range path {
+		if i > 0 {
+			s += " -> "
+		}
+		s += name
+	}
+	return s
+}
+
+// closure returns only plus every task it (transitively) depends on, or
+// every task in tasks when only is empty - the set runClosure actually
+// needs to execute for a given Run/Rerun call.
+func closure(tasks map[string]Task, only string) map[string]bool {
+	set := make(map[string]bool, len(tasks))
+	if only == "" {
+		for name := range tasks {
+			set[name] = true
+		}
+		return set
+	}
+
+	var add func(name string)
+	add = func(name string) {
+		if set[name] {
+			return
+		}
+		set[name] = true
+		for _, dep := range tasks[name].DependsOn {
+			add(dep)
+		}
+	}
+	add(only)
+	return set
+}
+
+// stale reports whether t needs to run: true when it declares no
+// Outputs (nothing to compare against), any Outputs glob matches no
+// file, or any Inputs match is newer than the oldest matched Output.
+func stale(t Task) bool {
+	if len(t.Outputs) == 0 {
+		return true
+	}
+
+	oldestOutput, ok := oldestMatchTime(t.Cwd, t.Outputs)
+	if !ok {
+		return true
+	}
+
+	newestInput, ok := newestMatchTime(t.Cwd, t.Inputs)
+	if !ok {
+		return false
+	}
+	return newestInput.After(oldestOutput)
+}
+
+func oldestMatchTime(cwd string, patterns []string) (time.Time, bool) {
+	var oldest time.Time
+	found := false
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(cwd, pattern))
+		if err != nil || len(matches) == 0 {
+			return time.Time{}, false
+		}
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil {
+				return time.Time{}, false
+			}
+			if t := info.ModTime(); !found || t.Before(oldest) {
+				oldest = t
+				found = true
+			}
+		}
+	}
+	return oldest, found
+}
+
+func newestMatchTime(cwd string, patterns []string) (time.Time, bool) {
+	var newest time.Time
+	found := false
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(cwd, pattern))
+		if err != nil {
+			continue
+		}
+		for _, m := range matches {
+			info, err := os.Stat(m)
+			if err != nil {
+				continue
+			}
+			if t := info.ModTime(); !found || t.After(newest) {
+				newest = t
+				found = true
+			}
+		}
+	}
+	return newest, found
+}
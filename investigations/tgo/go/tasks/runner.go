@@ -0,0 +1,198 @@
+package tasks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os/exec"
+	"sync"
+)
+
+// Runner executes a set of Tasks, resolving DependsOn into a DAG and
+// running independent tasks concurrently. It's long-lived: one Runner is
+// built from panels.toml's `[[task]]` list and shared by every panel
+// that can trigger it, so Events can be drained by a single forwarding
+// goroutine for the process's whole lifetime.
+type Runner struct {
+	tasks  map[string]Task
+	events chan Event
+
+	mu     sync.Mutex
+	status map[string]Status
+	cancel context.CancelFunc // of the run currently in flight, if any
+}
+
+// NewRunner validates list (no unknown or cyclic DependsOn) and returns a
+// Runner ready to execute it.
+func NewRunner(list []Task) (*Runner, error) {
+	byName := make(map[string]Task, len(list))
+	status := make(map[string]Status, len(list))
+	for _, t := range list {
+		byName[t.Name] = t
+		status[t.Name] = StatusPending
+	}
+	if err := validate(byName); err != nil {
+		return nil, err
+	}
+
+	return &Runner{
+		tasks:  byName,
+		events: make(chan Event, 64),
+		status: status,
+	}, nil
+}
+
+// Events streams every status transition and output line across every
+// Run/Rerun call this Runner ever makes. It's never closed - a
+// forwarding goroutine ranges over it for as long as the process runs.
+func (r *Runner) Events() <-chan Event {
+	return r.events
+}
+
+// Run executes only and its transitive dependencies, or the whole graph
+// when only is empty, skipping any task whose Outputs already look newer
+// than its Inputs. A Run already in flight makes this a no-op - Cancel
+// it first.
+func (r *Runner) Run(ctx context.Context, only string) {
+	r.run(ctx, only, false)
+}
+
+// Rerun behaves like Run but ignores each task's staleness check, so a
+// task whose outputs are already up to date still executes.
+func (r *Runner) Rerun(ctx context.Context, only string) {
+	r.run(ctx, only, true)
+}
+
+// Cancel stops the run currently in flight, if any. Every task still
+// waiting on a dependency or mid-command transitions to StatusSkipped or
+// is killed via its command's context.
+func (r *Runner) Cancel() {
+	r.mu.Lock()
+	cancel := r.cancel
+	r.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+func (r *Runner) run(ctx context.Context, only string, force bool) {
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.mu.Unlock()
+		return
+	}
+	runCtx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		r.cancel = nil
+		r.mu.Unlock()
+	}()
+
+	targets := closure(r.tasks, only)
+	done := make(map[string]chan struct{}, len(targets))
+	for name := range targets {
+		done[name] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for name := range targets {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer close(done[name])
+			r.runOne(runCtx, name, targets, done, force)
+		}(name)
+	}
+	wg.Wait()
+}
+
+// runOne waits for name's dependencies (among targets) to finish, then
+// runs it - unless a dependency failed or was skipped, ctx was
+// cancelled while waiting, or (without force) it's already up to date.
+func (r *Runner) runOne(ctx context.Context, name string, targets map[string]bool, done map[string]chan struct{}, force bool) {
+	t := r.tasks[name]
+	for _, dep := range t.DependsOn {
+		ch, waiting := done[dep]
+		if !waiting {
+			continue // dep isn't part of this run's closure; it already finished a previous one
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			r.setStatus(name, StatusSkipped)
+			return
+		}
+		if s := r.getStatus(dep); s == StatusFailed || s == StatusSkipped {
+			r.setStatus(name, StatusSkipped)
+			return
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		r.setStatus(name, StatusSkipped)
+		return
+	default:
+	}
+
+	if !force && !stale(t) {
+		r.setStatus(name, StatusSkipped)
+		return
+	}
+
+	r.execute(ctx, t)
+}
+
+// execute runs t's command to completion (or until ctx is cancelled),
+// streaming each line of its combined stdout/stderr as an Event and
+// ending on StatusOK or StatusFailed.
+func (r *Runner) execute(ctx context.Context, t Task) {
+	r.setStatus(t.Name, StatusRunning)
+
+	cmd := exec.CommandContext(ctx, "bash", "-c", t.Cmd)
+	cmd.Dir = t.Cwd
+	cmd.Env = cmd.Environ()
+	for k, v := range t.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	pr, err := cmd.StdoutPipe()
+	if err != nil {
+		r.setStatus(t.Name, StatusFailed)
+		return
+	}
+	cmd.Stderr = cmd.Stdout
+
+	if err := cmd.Start(); err != nil {
+		r.setStatus(t.Name, StatusFailed)
+		return
+	}
+
+	scanner := bufio.NewScanner(pr)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		r.events <- Event{Task: t.Name, Status: StatusRunning, Line: scanner.Text()}
+	}
+
+	if err := cmd.Wait(); err != nil {
+		r.setStatus(t.Name, StatusFailed)
+		return
+	}
+	r.setStatus(t.Name, StatusOK)
+}
+
+func (r *Runner) setStatus(name string, status Status) {
+	r.mu.Lock()
+	r.status[name] = status
+	r.mu.Unlock()
+	r.events <- Event{Task: name, Status: status}
+}
+
+func (r *Runner) getStatus(name string) Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status[name]
+}
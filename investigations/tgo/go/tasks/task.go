@@ -0,0 +1,46 @@
+// Package tasks resolves a panels.toml `[[task]]` graph - named, reusable
+// commands with declared dependencies and staleness inputs/outputs,
+// the way a Makefile's targets work - and runs it concurrently under a
+// cancellable Runner.
+package tasks
+
+// Status is a task's current place in one run of the graph.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusOK      Status = "ok"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+// Task is one `[[task]]` entry: a named command plus the metadata the
+// Runner needs to order it against the others and decide whether it's
+// already up to date.
+type Task struct {
+	Name string
+	Cmd  string
+	Cwd  string
+	Env  map[string]string
+
+	// DependsOn names other tasks that must finish (successfully) before
+	// this one starts.
+	DependsOn []string
+
+	// Inputs and Outputs are glob patterns. A task with no Outputs is
+	// always considered stale (there's nothing to compare against);
+	// otherwise it's stale whenever an Inputs match is newer than the
+	// oldest Outputs match, or an Outputs pattern matches nothing.
+	Inputs  []string
+	Outputs []string
+}
+
+// Event reports one state change for Task: either a line of its combined
+// stdout/stderr (Line set, Status unchanged from the last event), or a
+// Status transition (Line empty).
+type Event struct {
+	Task   string
+	Status Status
+	Line   string
+}
@@ -2,12 +2,24 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+
+	"github.com/study-groups/devops/tetra/console/go/ui"
 )
 
+// renderInterval is the Renderer's tick rate: dirty subtrees are
+// coalesced and redrawn at most this often, decoupling "state changed"
+// from "screen redrawn" the way a ~60Hz frame budget would.
+const renderInterval = time.Second / 60
+
 // Panel is a generic interface for all our panel types.
 type Panel interface {
 	tview.Primitive
@@ -23,6 +35,54 @@ type TUI struct {
 	status  *tview.TextView
 	leftDock  *DockView
 	rightDock *DockView
+
+	// Invalidate-driven rendering: each dirty-able piece of the screen
+	// (a dock, the status bar) is wrapped as a ui.Drawable that bubbles
+	// Invalidate into renderer, which coalesces a burst of store events
+	// into one redraw per tick instead of redrawing everything on every
+	// event. drawFuncs maps each Drawable back to the (pre-existing)
+	// tview render call it wraps.
+	leftDockD  *dockDrawable
+	rightDockD *dockDrawable
+	statusD    *statusDrawable
+	drawFuncs  map[ui.Drawable]func(State)
+	renderer   *ui.Renderer
+
+	// Keybind config: actions is the registry [[keybind]] entries and the
+	// `/bind` command look action names up in, keymap matches incoming key
+	// events (including multi-key chords) against the bound Keybinds.
+	actions *ActionRegistry
+	keymap  *Keymap
+
+	// cmdBar is the `/bind`/`/unbind`/`/which-key` command line, opened by
+	// the "command-bar" action (bound to "/" by default). whichKey is the
+	// popup overlay listing candidate bindings once a chord prefix matches
+	// more than one, à la vim's which-key plugin.
+	cmdBar   *tview.InputField
+	whichKey *tview.TextView
+
+	// palette is the general-purpose, readline-style command input (history,
+	// Ctrl-R search, tab completion) opened by the "command-palette" action,
+	// distinct from cmdBar's narrower /bind-/unbind-/which-key syntax.
+	palette *CommandPalette
+
+	mu    sync.Mutex
+	state State
+}
+
+// defaultKeybinds seeds the Keymap with the bindings TUI.setKeybinds used
+// to hard-code; a matching [[keybind]] entry in panels.toml (same Spec)
+// replaces one of these instead of stacking a duplicate.
+var defaultKeybinds = []struct{ keys, action string }{
+	{"Ctrl+C", "quit"},
+	{"<Tab>", "switch-dock"},
+	{"<Esc>", "switch-dock"},
+	{"Ctrl+N", "cycle-tab-next"},
+	{"Ctrl+P", "cycle-tab-prev"},
+	{"<Backtab>", "cycle-tab-prev"},
+	{"Ctrl+S", "save-file"},
+	{"/", "command-bar"},
+	{"Ctrl+K", "command-palette"},
 }
 
 func NewTUI(app *tview.Application, store *Store, config *Config) *TUI {
@@ -55,37 +115,179 @@ func NewTUI(app *tview.Application, store *Store, config *Config) *TUI {
 		AddItem(body, 0, 1, true)
 
 	tui.root = tview.NewPages().AddPage("main", mainLayout, true, true)
+
+	tui.cmdBar = tview.NewInputField().SetLabel("")
+	tui.cmdBar.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			line := tui.cmdBar.GetText()
+			tui.closeCommandBar()
+			tui.runCommand(line)
+		case tcell.KeyEsc:
+			tui.closeCommandBar()
+		}
+	})
+	cmdLayout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(body, 0, 1, true).
+		AddItem(tui.cmdBar, 1, 0, true)
+	tui.root.AddPage("cmdbar", cmdLayout, true, false)
+
+	tui.whichKey = tview.NewTextView().SetDynamicColors(true)
+	tui.whichKey.SetBorder(true).SetTitle(" which-key ")
+	whichKeyRow := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tui.whichKey, 40, 0, false)
+	whichKeyLayout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(whichKeyRow, 8, 0, false)
+	tui.root.AddPage("whichkey", whichKeyLayout, true, false)
+
+	tui.palette = NewCommandPalette(store, PathCompleter{Dir: store.GetState().Pwd})
+	tui.palette.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			tui.palette.Submit()
+		}
+		tui.closeCommandPalette()
+	})
+	paletteLayout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(body, 0, 1, true).
+		AddItem(tui.palette, 1, 0, true)
+	tui.root.AddPage("palette", paletteLayout, true, false)
+
+	tui.leftDockD = newDockDrawable(tui.leftDock)
+	tui.rightDockD = newDockDrawable(tui.rightDock)
+	tui.statusD = newStatusDrawable(tui.status)
+	tui.drawFuncs = map[ui.Drawable]func(State){
+		tui.leftDockD:  tui.leftDock.Render,
+		tui.rightDockD: tui.rightDock.Render,
+		tui.statusD:    tui.renderStatus,
+	}
+	tui.renderer = ui.NewRenderer(renderInterval, tui.flushDirty)
+	tui.leftDockD.OnInvalidate(tui.renderer.MarkDirty)
+	tui.rightDockD.OnInvalidate(tui.renderer.MarkDirty)
+	tui.statusD.OnInvalidate(tui.renderer.MarkDirty)
+
+	tui.actions = NewActionRegistry()
+	tui.actions.Register("quit", tui.quit)
+	tui.actions.Register("switch-dock", func() { tui.store.Dispatch(SwitchDockAction{}) })
+	tui.actions.Register("cycle-tab-next", func() { tui.store.Dispatch(CycleTabAction{Delta: 1}) })
+	tui.actions.Register("cycle-tab-prev", func() { tui.store.Dispatch(CycleTabAction{Delta: -1}) })
+	tui.actions.Register("save-file", func() { tui.store.Dispatch(SaveFileAction{}) })
+	tui.actions.Register("command-bar", tui.openCommandBar)
+	tui.actions.Register("command-palette", tui.openCommandPalette)
+
+	tui.keymap = NewKeymap()
+	for _, b := range defaultKeybinds {
+		tui.keymap.Bind(Keybind{Spec: b.keys, Keys: parseKeys(b.keys), Action: b.action})
+	}
+	for _, kb := range config.Keybinds {
+		tui.keymap.Bind(Keybind{Spec: kb.Keys, Keys: parseKeys(kb.Keys), Action: kb.Action, Command: kb.Command})
+	}
+
 	tui.setKeybinds()
 	return tui
 }
 
-// Subscribe starts the main render loop.
+// Subscribe starts the invalidate-driven render loop: a background
+// goroutine runs the Renderer's ~60Hz tick, and a second goroutine
+// diffs each store event's State against the previous one, invalidating
+// only the Drawables whose slice of state actually changed. A burst of
+// store events between two ticks costs one redraw per affected Drawable,
+// not one per event, and docks/status bar pieces the event didn't touch
+// aren't redrawn at all.
 func (t *TUI) Subscribe() {
+	go t.renderer.Run()
+
 	go func() {
+		prev := t.store.GetState()
+		t.invalidateAll(prev) // first paint: everything is dirty
 		for range t.store.Events() {
-			t.app.QueueUpdateDraw(func() {
-				t.Render()
-			})
+			next := t.store.GetState()
+			t.invalidate(prev, next)
+			prev = next
 		}
 	}()
 }
 
-// Render is the master render function, called whenever the state changes.
-func (t *TUI) Render() {
-	state := t.store.GetState()
+// invalidateAll marks every Drawable dirty, for the first paint (there's
+// no "previous state" to diff against yet).
+func (t *TUI) invalidateAll(state State) {
+	t.setState(state)
+	t.leftDockD.Invalidate()
+	t.rightDockD.Invalidate()
+	t.statusD.Invalidate()
+}
 
-	// Render each dock, which in turn renders its panels.
-	t.leftDock.Render(state)
-	t.rightDock.Render(state)
+// invalidate marks exactly the Drawables whose owned slice of state
+// differs between prev and next, eliminating the old Render's "redraw
+// everything on every store event" cost.
+func (t *TUI) invalidate(prev, next State) {
+	t.setState(next)
 
-	// Set focus based on the active dock.
-	if state.ActiveDock == DockLeft {
-		t.app.SetFocus(t.leftDock)
-	} else {
-		t.app.SetFocus(t.rightDock)
+	dockChanged := prev.ActiveDock != next.ActiveDock
+	if dockChanged || panelStatesChanged(prev, next, t.leftDock.dockID, t.leftDock.panels) {
+		t.leftDockD.Invalidate()
+	}
+	if dockChanged || panelStatesChanged(prev, next, t.rightDock.dockID, t.rightDock.panels) {
+		t.rightDockD.Invalidate()
 	}
+	if dockChanged || prev.CurrentFile != next.CurrentFile {
+		t.statusD.Invalidate()
+	}
+}
+
+// panelStatesChanged reports whether the active panel name, or any
+// individual panel's state, differs between prev and next for the
+// panels a single dock owns - the smallest state delta that could
+// change that dock's rendering.
+func panelStatesChanged(prev, next State, dock DockID, panels map[string]Panel) bool {
+	if prev.ActivePanels[dock] != next.ActivePanels[dock] {
+		return true
+	}
+	for name := range panels {
+		if !reflect.DeepEqual(prev.PanelStates[name], next.PanelStates[name]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *TUI) setState(state State) {
+	t.mu.Lock()
+	t.state = state
+	t.mu.Unlock()
+}
 
-	// Render the status bar.
+func (t *TUI) getState() State {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state
+}
+
+// flushDirty is the Renderer's flush callback: it re-renders exactly the
+// Drawables dirty since the last tick, then updates focus and lets tview
+// flip the actual screen in one QueueUpdateDraw.
+func (t *TUI) flushDirty(dirty []ui.Drawable) {
+	state := t.getState()
+
+	t.app.QueueUpdateDraw(func() {
+		for _, d := range dirty {
+			if render, ok := t.drawFuncs[d]; ok {
+				render(state)
+			}
+		}
+
+		if state.ActiveDock == DockLeft {
+			t.app.SetFocus(t.leftDock)
+		} else {
+			t.app.SetFocus(t.rightDock)
+		}
+	})
+}
+
+// renderStatus redraws the status bar text. It's the statusDrawable's
+// wrapped render call, the same content Render used to set directly.
+func (t *TUI) renderStatus(state State) {
 	fileName := "(none)"
 	if state.CurrentFile != "" {
 		fileName = filepath.Base(state.CurrentFile)
@@ -98,44 +300,167 @@ func (t *TUI) GetRoot() tview.Primitive {
 	return t.root
 }
 
+// setKeybinds feeds every key event through the Keymap instead of the
+// hard-coded Ctrl+C/Tab/Ctrl+N/Ctrl+P/Ctrl+S switch it used to be: a
+// completed chord runs its bound action (or /command), a partial chord
+// that could still complete several ways opens the which-key popup, and
+// anything that matches nothing falls through to tview as before.
 func (t *TUI) setKeybinds() {
 	t.app.SetInputCapture(func(ev *tcell.EventKey) *tcell.EventKey {
-		// Global quit.
-		if ev.Key() == tcell.KeyCtrlC {
-			// Cleanly stop any running terminal panels before quitting.
-			for _, p := range t.leftDock.panels {
-				if stoppable, ok := p.(interface{ Stop() }); ok {
-					stoppable.Stop()
-				}
-			}
-			for _, p := range t.rightDock.panels {
-				if stoppable, ok := p.(interface{ Stop() }); ok {
-					stoppable.Stop()
-				}
-			}
-			t.app.Stop()
+		matched, candidates := t.keymap.Feed(ev)
+		if matched != nil {
+			t.hideWhichKey()
+			t.runKeybind(*matched)
 			return nil
 		}
-
-		// Dispatch actions based on key press.
-		switch ev.Key() {
-		// Switch focus between docks.
-		case tcell.KeyTab, tcell.KeyEsc:
-			t.store.Dispatch(SwitchDockAction{})
-			return nil
-		// Cycle to the next tab in the current dock.
-		case tcell.KeyCtrlN:
-			t.store.Dispatch(CycleTabAction{Delta: 1})
-			return nil
-		// Cycle to the previous tab in the current dock (Shift+Tab).
-		case tcell.KeyBacktab, tcell.KeyCtrlP:
-			t.store.Dispatch(CycleTabAction{Delta: -1})
-			return nil
-		// Save the current file.
-		case tcell.KeyCtrlS:
-			t.store.Dispatch(SaveFileAction{})
+		if len(candidates) > 0 {
+			t.showWhichKeyCandidates(candidates)
 			return nil
 		}
+		t.hideWhichKey()
 		return ev
 	})
 }
+
+// runKeybind executes a completed Keybind: a /command line if it has one,
+// otherwise its registered Action.
+func (t *TUI) runKeybind(b Keybind) {
+	if b.Command != "" {
+		t.runCommand(b.Command)
+		return
+	}
+	if !t.actions.Run(b.Action) {
+		log.Printf("keybind %q: unknown action %q", b.Spec, b.Action)
+	}
+}
+
+// quit is the "quit" action (bound to Ctrl+C by default): it cleanly
+// stops any running terminal/stream panels before tearing down the app,
+// the same cleanup setKeybinds used to do inline.
+func (t *TUI) quit() {
+	for _, p := range t.leftDock.panels {
+		if stoppable, ok := p.(interface{ Stop() }); ok {
+			stoppable.Stop()
+		}
+	}
+	for _, p := range t.rightDock.panels {
+		if stoppable, ok := p.(interface{ Stop() }); ok {
+			stoppable.Stop()
+		}
+	}
+	t.app.Stop()
+}
+
+// openCommandBar is the "command-bar" action (bound to "/" by default):
+// it shows the cmdBar input field in place of the dock layout, for typing
+// /bind, /unbind, or /which-key.
+func (t *TUI) openCommandBar() {
+	t.cmdBar.SetText("/")
+	t.root.SwitchToPage("cmdbar")
+	t.app.SetFocus(t.cmdBar)
+}
+
+// closeCommandBar restores the dock layout and focus after /bind,
+// /unbind, /which-key, or an Esc cancels the command bar.
+func (t *TUI) closeCommandBar() {
+	t.root.SwitchToPage("main")
+	state := t.getState()
+	if state.ActiveDock == DockLeft {
+		t.app.SetFocus(t.leftDock)
+	} else {
+		t.app.SetFocus(t.rightDock)
+	}
+}
+
+// openCommandPalette is the "command-palette" action (bound to Ctrl+K by
+// default): it shows the readline-style CommandPalette in place of the
+// dock layout, for submitting an arbitrary command with history, Ctrl-R
+// search, and tab completion instead of cmdBar's fixed /bind syntax.
+func (t *TUI) openCommandPalette() {
+	t.palette.Open()
+	t.root.SwitchToPage("palette")
+	t.app.SetFocus(t.palette)
+}
+
+// closeCommandPalette restores the dock layout and focus after a palette
+// submission or an Esc cancels it.
+func (t *TUI) closeCommandPalette() {
+	t.root.SwitchToPage("main")
+	state := t.getState()
+	if state.ActiveDock == DockLeft {
+		t.app.SetFocus(t.leftDock)
+	} else {
+		t.app.SetFocus(t.rightDock)
+	}
+}
+
+// runCommand parses and executes one `/command` line: the built-ins
+// /bind, /unbind, and /which-key that let a user manage Keybinds at
+// runtime instead of only through panels.toml.
+func (t *TUI) runCommand(line string) {
+	fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "/"))
+	if len(fields) == 0 {
+		return
+	}
+
+	switch fields[0] {
+	case "bind":
+		if len(fields) < 3 {
+			t.setMessage("usage: /bind <keys> <action>")
+			return
+		}
+		action := fields[len(fields)-1]
+		keys := strings.Join(fields[1:len(fields)-1], " ")
+		t.keymap.Bind(Keybind{Spec: keys, Keys: parseKeys(keys), Action: action})
+		t.setMessage(fmt.Sprintf("bound %q to %s", keys, action))
+
+	case "unbind":
+		if len(fields) < 2 {
+			t.setMessage("usage: /unbind <keys>")
+			return
+		}
+		keys := strings.Join(fields[1:], " ")
+		if t.keymap.Unbind(keys) {
+			t.setMessage(fmt.Sprintf("unbound %q", keys))
+		} else {
+			t.setMessage(fmt.Sprintf("no binding for %q", keys))
+		}
+
+	case "which-key":
+		t.showWhichKeyCandidates(t.keymap.Binds())
+
+	default:
+		t.setMessage(fmt.Sprintf("unknown command: /%s", fields[0]))
+	}
+}
+
+// setMessage flashes msg in the status bar; the next store-driven
+// invalidate overwrites it with the usual ActiveDock/File text, so it
+// only needs to be legible until then.
+func (t *TUI) setMessage(msg string) {
+	t.status.SetText(" " + msg)
+}
+
+// showWhichKeyCandidates lists binds (either the candidates still
+// reachable from a pending chord, or every bind for /which-key) in the
+// which-key popup.
+func (t *TUI) showWhichKeyCandidates(binds []Keybind) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[::b]which-key[::-]\n")
+	for _, bind := range binds {
+		target := bind.Action
+		if bind.Command != "" {
+			target = "/" + bind.Command
+		}
+		fmt.Fprintf(&b, "%s -> %s\n", bind.Spec, target)
+	}
+	t.whichKey.SetText(b.String())
+	t.root.ShowPage("whichkey")
+}
+
+// hideWhichKey dismisses the which-key popup, on the next key press that
+// completes a chord or matches nothing. A timed-out chord does not hide
+// it by itself; the popup stays until such a key press.
+func (t *TUI) hideWhichKey() {
+	t.root.HidePage("whichkey")
+}
@@ -38,6 +38,10 @@ func NewDockView(store *Store, dockID DockID, configs []PanelConfig) *DockView {
 			p = NewTextViewPanel(store, config)
 		case "terminal":
 			p = NewTerminalPanel(store, config)
+		case "task":
+			p = NewTaskPanel(store, config)
+		case "assistant":
+			p = NewAssistantPanel(store, config)
 		}
 		if p != nil {
 			dv.panels[config.Name] = p
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/rivo/tview"
+
+	"github.com/study-groups/devops/tetra/console/go/ui"
+)
+
+// dockDrawable wraps a *DockView as a ui.Drawable: Draw still delegates
+// to the dock's own tview.Primitive.Draw (docks keep rendering through
+// tview), but Invalidate/OnInvalidate route through the embedded
+// BaseDrawable so the TUI's ui.Renderer only re-renders this dock's
+// content when the state it owns actually changed, instead of on every
+// store event.
+type dockDrawable struct {
+	ui.BaseDrawable
+	dock *DockView
+}
+
+func newDockDrawable(dock *DockView) *dockDrawable {
+	d := &dockDrawable{dock: dock}
+	d.BaseDrawable = ui.NewBaseDrawable(d)
+	return d
+}
+
+func (d *dockDrawable) Draw(ctx *ui.Context) {
+	d.dock.Draw(ctx.Screen)
+}
+
+// statusDrawable is the same shim for the status bar's *tview.TextView.
+type statusDrawable struct {
+	ui.BaseDrawable
+	view *tview.TextView
+}
+
+func newStatusDrawable(view *tview.TextView) *statusDrawable {
+	d := &statusDrawable{view: view}
+	d.BaseDrawable = ui.NewBaseDrawable(d)
+	return d
+}
+
+func (d *statusDrawable) Draw(ctx *ui.Context) {
+	d.view.Draw(ctx.Screen)
+}
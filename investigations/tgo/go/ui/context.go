@@ -0,0 +1,60 @@
+// Package ui provides an invalidate-driven, aerc-style widget tree:
+// Drawable nodes paint themselves into a Context scoped to their own
+// subregion of the screen, and bubble Invalidate calls up to a Renderer
+// that redraws only the dirty subtrees on its next tick instead of the
+// whole screen on every state change.
+package ui
+
+import "github.com/gdamore/tcell/v2"
+
+// Rect is an axis-aligned subregion of the terminal screen, in absolute
+// screen coordinates.
+type Rect struct {
+	X, Y, W, H int
+}
+
+// Context is the draw surface passed to Drawable.Draw: a Rect scoped to
+// one node in the tree, plus the tcell.Screen it ultimately paints onto.
+// A Drawable should only touch cells within its own Rect; Subcontext
+// carves out a clipped child region for composing a parent out of
+// children, the same way layout.Grid hands out cell rects in tubes.
+type Context struct {
+	Rect
+	Screen tcell.Screen
+}
+
+// NewContext wraps screen with rect as the root Drawable's draw surface.
+func NewContext(screen tcell.Screen, rect Rect) *Context {
+	return &Context{Rect: rect, Screen: screen}
+}
+
+// SetCell paints a single cell at (x, y) relative to ctx's own Rect. A
+// coordinate outside Rect's bounds is silently dropped rather than
+// touching a sibling's region.
+func (ctx *Context) SetCell(x, y int, mainc rune, combc []rune, style tcell.Style) {
+	if x < 0 || y < 0 || x >= ctx.W || y >= ctx.H {
+		return
+	}
+	ctx.Screen.SetContent(ctx.X+x, ctx.Y+y, mainc, combc, style)
+}
+
+// Subcontext carves a child region out of ctx, offset by (x, y) and
+// clipped so it never extends past ctx's own bounds.
+func (ctx *Context) Subcontext(x, y, w, h int) *Context {
+	rect := Rect{X: ctx.X + x, Y: ctx.Y + y, W: w, H: h}
+
+	if rect.X+rect.W > ctx.X+ctx.W {
+		rect.W = ctx.X + ctx.W - rect.X
+	}
+	if rect.Y+rect.H > ctx.Y+ctx.H {
+		rect.H = ctx.Y + ctx.H - rect.Y
+	}
+	if rect.W < 0 {
+		rect.W = 0
+	}
+	if rect.H < 0 {
+		rect.H = 0
+	}
+
+	return &Context{Rect: rect, Screen: ctx.Screen}
+}
@@ -0,0 +1,47 @@
+package ui
+
+// Drawable is a node in the invalidate-driven render tree: it paints
+// itself into a Context, and instead of forcing a full redraw, reports
+// being dirty by calling the callback registered via OnInvalidate -
+// which a parent uses to track which children need repainting and to
+// bubble the same signal up to its own parent.
+type Drawable interface {
+	// Draw paints this Drawable's current state into ctx.
+	Draw(ctx *Context)
+	// Invalidate marks this Drawable dirty, notifying whatever callback
+	// was registered via OnInvalidate (a Renderer, or a parent Drawable
+	// bubbling it further up).
+	Invalidate()
+	// OnInvalidate registers fn to be called with this Drawable whenever
+	// Invalidate fires. Each node has exactly one parent in the tree, so
+	// a later call replaces any earlier registration.
+	OnInvalidate(fn func(Drawable))
+}
+
+// BaseDrawable implements the Invalidate/OnInvalidate bookkeeping common
+// to every Drawable; embed it and implement Draw. self must be the
+// embedding type, so the registered callback receives the outer Drawable
+// rather than the BaseDrawable itself.
+type BaseDrawable struct {
+	self         Drawable
+	onInvalidate func(Drawable)
+}
+
+// NewBaseDrawable returns a BaseDrawable to embed in self's struct,
+// typically assigned right after self is allocated:
+//
+//	d := &myDrawable{}
+//	d.BaseDrawable = ui.NewBaseDrawable(d)
+func NewBaseDrawable(self Drawable) BaseDrawable {
+	return BaseDrawable{self: self}
+}
+
+func (b *BaseDrawable) Invalidate() {
+	if b.onInvalidate != nil {
+		b.onInvalidate(b.self)
+	}
+}
+
+func (b *BaseDrawable) OnInvalidate(fn func(Drawable)) {
+	b.onInvalidate = fn
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"sync"
+	"time"
+)
+
+// Renderer coalesces Invalidate calls between ticks into a single flush:
+// a burst of state changes within one frame interval costs one Draw per
+// affected Drawable instead of one per event, and a quiet interval costs
+// nothing. Wire a node's OnInvalidate to MarkDirty (directly, or via a
+// parent that bubbles it) to feed it.
+type Renderer struct {
+	interval time.Duration
+	flush    func(dirty []Drawable)
+
+	mu    sync.Mutex
+	dirty map[Drawable]bool
+
+	stop chan struct{}
+}
+
+// NewRenderer creates a Renderer that calls flush with the set of
+// Drawables marked dirty since the last tick, once per interval, but
+// only when at least one is dirty - a quiet UI costs nothing.
+func NewRenderer(interval time.Duration, flush func(dirty []Drawable)) *Renderer {
+	return &Renderer{
+		interval: interval,
+		flush:    flush,
+		dirty:    make(map[Drawable]bool),
+		stop:     make(chan struct{}),
+	}
+}
+
+// MarkDirty adds d to the dirty set, to be included in the next flush.
+// Safe to call from any goroutine (e.g. a store subscriber).
+func (r *Renderer) MarkDirty(d Drawable) {
+	r.mu.Lock()
+	r.dirty[d] = true
+	r.mu.Unlock()
+}
+
+// Run starts the tick loop. It blocks until Stop is called, so call it
+// from its own goroutine.
+func (r *Renderer) Run() {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			batch := r.takeDirty()
+			if len(batch) > 0 {
+				r.flush(batch)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Stop ends the tick loop started by Run.
+func (r *Renderer) Stop() {
+	close(r.stop)
+}
+
+func (r *Renderer) takeDirty() []Drawable {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.dirty) == 0 {
+		return nil
+	}
+	batch := make([]Drawable, 0, len(r.dirty))
+	for d := range r.dirty {
+		batch = append(batch, d)
+	}
+	r.dirty = make(map[Drawable]bool)
+	return batch
+}
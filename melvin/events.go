@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* =========================
+   event bus + events-module
+   ========================= */
+
+// subscriberBuffer bounds each subscriber's channel - the "ring buffer
+// per subscriber" the request asks for: once full, Publish drops the
+// event for that subscriber instead of blocking, so one slow client can
+// never stall another or the producer.
+const subscriberBuffer = 64
+
+// historyPerTopic bounds how many past events per topic Publish keeps
+// around for Last-Event-ID resume.
+const historyPerTopic = 256
+
+// Event is one published notification. Seq is a process-wide, strictly
+// increasing counter - what Last-Event-ID resume compares against - not
+// a per-topic one.
+type Event struct {
+	Seq     uint64    `json:"seq"`
+	Topic   string    `json:"topic"`
+	Payload any       `json:"payload"`
+	Time    time.Time `json:"time"`
+}
+
+type subscriber struct {
+	topics map[string]struct{}
+	ch     chan Event
+}
+
+// EventBus is ctx.Events: a topic-filtered pub/sub bus other modules
+// publish command activity onto, and HTTP/REPL clients subscribe to.
+type EventBus struct {
+	mu      sync.Mutex
+	seq     uint64
+	subs    map[*subscriber]struct{}
+	history map[string][]Event
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subs:    map[*subscriber]struct{}{},
+		history: map[string][]Event{},
+	}
+}
+
+// Publish assigns payload the next sequence number under topic, records
+// it in topic's history for resume, and delivers it to every current
+// subscriber of topic - non-blocking, so a subscriber whose buffer is
+// full simply misses it rather than holding up the publisher.
+func (b *EventBus) Publish(topic string, payload any) Event {
+	b.mu.Lock()
+	b.seq++
+	ev := Event{Seq: b.seq, Topic: topic, Payload: payload, Time: time.Now()}
+
+	hist := append(b.history[topic], ev)
+	if len(hist) > historyPerTopic {
+		hist = hist[len(hist)-historyPerTopic:]
+	}
+	b.history[topic] = hist
+
+	var targets []*subscriber
+	for s := range b.subs {
+		if _, ok := s.topics[topic]; ok {
+			targets = append(targets, s)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, s := range targets {
+		select {
+		case s.ch <- ev:
+		default:
+			// slow subscriber - drop rather than block the producer
+		}
+	}
+	return ev
+}
+
+// Subscribe returns a channel of every future event on topics, replaying
+// buffered history newer than afterSeq first - afterSeq == 0 means no
+// resume, just start from now. The returned cancel func must be called
+// once the subscriber is done, to unregister it and close the channel.
+func (b *EventBus) Subscribe(topics []string, afterSeq uint64) (<-chan Event, func()) {
+	set := make(map[string]struct{}, len(topics))
+	for _, t := range topics {
+		if t != "" {
+			set[t] = struct{}{}
+		}
+	}
+	sub := &subscriber{topics: set, ch: make(chan Event, subscriberBuffer)}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	var replay []Event
+	if afterSeq > 0 {
+		for t := range set {
+			for _, ev := range b.history[t] {
+				if ev.Seq > afterSeq {
+					replay = append(replay, ev)
+				}
+			}
+		}
+	}
+	b.mu.Unlock()
+
+	sort.Slice(replay, func(i, j int) bool { return replay[i].Seq < replay[j].Seq })
+	for _, ev := range replay {
+		select {
+		case sub.ch <- ev:
+		default:
+		}
+	}
+
+	cancel := func() {
+		b.mu.Lock()
+		delete(b.subs, sub)
+		b.mu.Unlock()
+		close(sub.ch)
+	}
+	return sub.ch, cancel
+}
+
+/* =========================
+   events-module (SSE)
+   ========================= */
+
+// EventsModule exposes ctx.Events over HTTP as Server-Sent Events.
+type EventsModule struct{}
+
+func (m *EventsModule) Name() string { return "events-module" }
+
+func (m *EventsModule) Init(ctx *Context) error {
+	ctx.Mux.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			sendJSONError(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		raw := r.URL.Query().Get("topics")
+		var topics []string
+		for _, t := range strings.Split(raw, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				topics = append(topics, t)
+			}
+		}
+
+		var afterSeq uint64
+		if last := r.Header.Get("Last-Event-ID"); last != "" {
+			if n, err := strconv.ParseUint(last, 10, 64); err == nil {
+				afterSeq = n
+			}
+		}
+
+		events, cancel := ctx.Events.Subscribe(topics, afterSeq)
+		defer cancel()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		ping := time.NewTicker(15 * time.Second)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(ev.Payload)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Seq, ev.Topic, data)
+				flusher.Flush()
+			case <-ping.C:
+				fmt.Fprint(w, ": ping\n\n")
+				flusher.Flush()
+			}
+		}
+	})
+	return nil
+}
+
+func (m *EventsModule) HTTPRoutes(_ *http.ServeMux) {}
+
+func (m *EventsModule) Commands() map[string]CommandFunc { return map[string]CommandFunc{} }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	cryptoRand "crypto/rand"
 	"encoding/hex"
 	"encoding/json"
@@ -12,9 +13,13 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/kkdai/youtube/v2"
@@ -152,6 +157,8 @@ type Context struct {
 	Vars     map[string]string
 	YT       *youtube.Client
 	Store    *Store
+	Events   *EventBus
+	Metrics  *Metrics
 }
 
 type CommandFunc func(ctx *Context, args []string) error
@@ -163,6 +170,31 @@ type Module interface {
 	Commands() map[string]CommandFunc
 }
 
+// ModuleStarter is implemented by a Module that needs to launch a
+// long-running process - a listener, a background worker - once startup
+// decides it's actually wanted, rather than unconditionally from Init.
+// Nothing type-asserts for it: main calls Start on the HTTP server
+// module directly, and a Module without one keeps compiling unchanged.
+type ModuleStarter interface {
+	Start(ctx *Context) error
+}
+
+// ModuleShutdowner is implemented by a Module holding something that
+// needs an orderly stop - a listener, a writer goroutine - before the
+// process exits. shutdownAll calls Shutdown on every module that
+// implements it, in reverse-init order, bounded by ctx's deadline.
+type ModuleShutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// ModuleReloader is implemented by a Module that wants a chance to
+// re-read its env/config on SIGHUP - the same signal that already
+// reloads script modules - without dropping the listening socket or
+// losing in-memory state a fresh Init would discard.
+type ModuleReloader interface {
+	Reload(ctx *Context) error
+}
+
 func getenv(k, def string) string {
 	if v := os.Getenv(k); v != "" {
 		return v
@@ -170,11 +202,26 @@ func getenv(k, def string) string {
 	return def
 }
 
+// getenvInt is getenv plus an int parse, falling back to def on an unset
+// or unparsable value.
+func getenvInt(k string, def int) int {
+	v := os.Getenv(k)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
 /* =========================
    http-server-module
    ========================= */
 
 type HTTPServerModule struct {
+	mu        sync.Mutex
 	staticDir string
 	server    *http.Server
 }
@@ -182,29 +229,48 @@ type HTTPServerModule struct {
 func (m *HTTPServerModule) Name() string { return "http-server-module" }
 
 func (m *HTTPServerModule) Init(ctx *Context) error {
-	m.staticDir = getenv("MELVIN_STATIC_DIR", "./static")
-	ctx.Logger.Printf("[%s] static dir: %s", m.Name(), m.staticDir)
+	m.setStaticDir(getenv("MELVIN_STATIC_DIR", "./static"))
+	ctx.Logger.Printf("[%s] static dir: %s", m.Name(), m.dir())
 	return nil
 }
 
+func (m *HTTPServerModule) setStaticDir(dir string) {
+	m.mu.Lock()
+	m.staticDir = dir
+	m.mu.Unlock()
+}
+
+func (m *HTTPServerModule) dir() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.staticDir
+}
+
 func (m *HTTPServerModule) HTTPRoutes(mux *http.ServeMux) {
-	mux.Handle("/", http.FileServer(http.Dir(m.staticDir)))
+	// Reads m.dir() per request, rather than baking http.Dir(m.staticDir)
+	// into the handler at registration time, so Reload's SIGHUP pickup of
+	// a changed MELVIN_STATIC_DIR takes effect without re-mounting "/" -
+	// net/http.ServeMux can't unregister a pattern anyway.
+	mux.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.FileServer(http.Dir(m.dir())).ServeHTTP(w, r)
+	}))
 }
 
 func (m *HTTPServerModule) Commands() map[string]CommandFunc {
 	return map[string]CommandFunc{
 		"serve-info": func(ctx *Context, args []string) error {
 			fmt.Printf("http.addr=%s\n", ctx.HTTPAddr)
-			fmt.Printf("static.dir=%s\n", m.staticDir)
+			fmt.Printf("static.dir=%s\n", m.dir())
 			return nil
 		},
 	}
 }
 
-func (m *HTTPServerModule) start(ctx *Context) error {
+func (m *HTTPServerModule) Start(ctx *Context) error {
+	handler := ctx.Metrics.instrumentHTTP(ctx.Mux, logRequests(ctx.Logger, ctx.Mux))
 	m.server = &http.Server{
 		Addr:              ctx.HTTPAddr,
-		Handler:           logRequests(ctx.Logger, ctx.Mux),
+		Handler:           handler,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 	go func() {
@@ -216,6 +282,22 @@ func (m *HTTPServerModule) start(ctx *Context) error {
 	return nil
 }
 
+// Shutdown stops the listener gracefully, letting in-flight requests
+// finish until ctx's deadline.
+func (m *HTTPServerModule) Shutdown(ctx context.Context) error {
+	if m.server == nil {
+		return nil
+	}
+	return m.server.Shutdown(ctx)
+}
+
+// Reload re-reads MELVIN_STATIC_DIR on SIGHUP.
+func (m *HTTPServerModule) Reload(ctx *Context) error {
+	m.setStaticDir(getenv("MELVIN_STATIC_DIR", "./static"))
+	ctx.Logger.Printf("[%s] reloaded static dir: %s", m.Name(), m.dir())
+	return nil
+}
+
 /* =========================
    yt-transcriber-module
    ========================= */
@@ -227,11 +309,19 @@ type TranscriptRecord struct {
 	Title string `json:"title"`
 	Lang  string `json:"lang"`
 	Text  string `json:"text"`
+
+	// Meta records which Transcriber in TranscriberChain produced Text
+	// ("source") and why each earlier one in the chain failed
+	// ("tried.<name>"), so a caller can see the fallback's outcome
+	// without re-running it.
+	Meta map[string]string `json:"meta,omitempty"`
 }
 
 type YTTranscriberModule struct {
-	defaultLang string
-	yidRx       *regexp.Regexp
+	defaultLang  string
+	yidRx        *regexp.Regexp
+	index        *TranscriptIndex
+	transcribers *TranscriberChain
 }
 
 func (m *YTTranscriberModule) Name() string { return "yt-transcriber-module" }
@@ -239,6 +329,18 @@ func (m *YTTranscriberModule) Name() string { return "yt-transcriber-module" }
 func (m *YTTranscriberModule) Init(ctx *Context) error {
 	m.defaultLang = getenv("MELVIN_DEFAULT_LANG", "en")
 	m.yidRx = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+	m.index = NewTranscriptIndex(ctx.Store)
+	m.transcribers = newTranscriberChain(parseTranscriberChain(getenv("MELVIN_TRANSCRIBER_CHAIN", "captions")))
+
+	if retentionDays := getenvInt("MELVIN_TX_RETENTION_DAYS", 0); retentionDays > 0 {
+		if removed, err := m.index.GC(retentionDays); err != nil {
+			ctx.Logger.Printf("[%s] gc: %v", m.Name(), err)
+		} else if removed > 0 {
+			ctx.Logger.Printf("[%s] gc: removed %d transcript(s) older than %d day(s)", m.Name(), removed, retentionDays)
+		}
+	}
+	ctx.Metrics.SetTranscriptsStored(int64(m.index.Count()))
+
 	// HTTP routes with optional persistence
 	ctx.Mux.HandleFunc("/api/transcript", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet && r.Method != http.MethodHead {
@@ -260,15 +362,48 @@ func (m *YTTranscriberModule) Init(ctx *Context) error {
 		if lang == "" {
 			lang = m.defaultLang
 		}
+		store := r.URL.Query().Get("store") == "1"
+
+		// stream=1 sends each backend's partial text as a chunked
+		// newline-delimited JSON line, instead of waiting for the whole
+		// transcript - only meaningful for an ASR backend in the chain
+		// that implements StreamingTranscriber; captions still resolves
+		// in one shot, just reported as a single partial.
+		if r.URL.Query().Get("stream") == "1" {
+			flusher, ok := w.(http.Flusher)
+			if !ok {
+				sendJSONError(w, "streaming unsupported", http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Transfer-Encoding", "chunked")
+			w.WriteHeader(http.StatusOK)
+			enc := json.NewEncoder(w)
+
+			rec, err := m.transcribers.FetchStream(ctx, videoID, lang, func(partial string) {
+				_ = enc.Encode(map[string]any{"partial": partial})
+				flusher.Flush()
+			})
+			if err != nil {
+				_ = enc.Encode(map[string]any{"error": err.Error()})
+				flusher.Flush()
+				return
+			}
+			if store {
+				_ = persistTranscript(ctx, m.index, rec)
+			}
+			_ = enc.Encode(map[string]any{"ok": true, "meta": rec})
+			flusher.Flush()
+			return
+		}
 
-		rec, err := m.fetchRecord(r.Context().Done(), ctx, videoID, lang)
+		rec, err := m.transcribers.Fetch(ctx, videoID, lang)
 		if err != nil {
 			sendJSONError(w, err.Error(), http.StatusBadGateway)
 			return
 		}
-		// optional store=1
-		if r.URL.Query().Get("store") == "1" {
-			_ = persistTranscript(ctx, rec)
+		if store {
+			_ = persistTranscript(ctx, m.index, rec)
 		}
 		w.Header().Set("Cache-Control", "public, max-age=120")
 		sendJSONResponse(w, map[string]any{
@@ -277,6 +412,38 @@ func (m *YTTranscriberModule) Init(ctx *Context) error {
 			"meta":       rec,
 		}, http.StatusOK)
 	})
+
+	ctx.Mux.HandleFunc("/api/transcripts/search", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			sendJSONError(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		q := r.URL.Query().Get("q")
+		if q == "" {
+			sendJSONError(w, "q query parameter is required", http.StatusBadRequest)
+			return
+		}
+		limit := 20
+		if raw := r.URL.Query().Get("limit"); raw != "" {
+			if n, err := strconv.Atoi(raw); err == nil {
+				limit = n
+			}
+		}
+		hits, err := m.index.Search(q, r.URL.Query().Get("lang"), limit)
+		if err != nil {
+			sendJSONError(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		sendJSONResponse(w, map[string]any{"ok": true, "hits": hits}, http.StatusOK)
+	})
+	return nil
+}
+
+// Reload re-reads MELVIN_DEFAULT_LANG on SIGHUP.
+func (m *YTTranscriberModule) Reload(ctx *Context) error {
+	m.defaultLang = getenv("MELVIN_DEFAULT_LANG", "en")
+	ctx.Logger.Printf("[%s] reloaded default lang: %s", m.Name(), m.defaultLang)
 	return nil
 }
 
@@ -284,7 +451,56 @@ func (m *YTTranscriberModule) HTTPRoutes(_ *http.ServeMux) {}
 
 func (m *YTTranscriberModule) Commands() map[string]CommandFunc {
 	return map[string]CommandFunc{
-		"transcribe": m.cmdTranscribe,
+		"transcribe":  m.cmdTranscribe,
+		"transcripts": m.cmdTranscripts,
+	}
+}
+
+// cmdTranscripts dispatches "transcripts search <query> [--lang=<xx>]
+// [--limit=<n>]" and "transcripts reindex", the sub-command style /pref
+// and /tag already use.
+func (m *YTTranscriberModule) cmdTranscripts(ctx *Context, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: transcripts search <query>|reindex")
+	}
+	switch args[0] {
+	case "search":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: transcripts search <query> [--lang=<xx>] [--limit=<n>]")
+		}
+		lang := ""
+		limit := 20
+		var terms []string
+		for _, a := range args[1:] {
+			switch {
+			case strings.HasPrefix(a, "--lang="):
+				lang = strings.TrimPrefix(a, "--lang=")
+			case strings.HasPrefix(a, "--limit="):
+				if n, err := strconv.Atoi(strings.TrimPrefix(a, "--limit=")); err == nil {
+					limit = n
+				}
+			default:
+				terms = append(terms, a)
+			}
+		}
+		hits, err := m.index.Search(strings.Join(terms, " "), lang, limit)
+		if err != nil {
+			return err
+		}
+		for _, h := range hits {
+			fmt.Printf("%.3f  %s  %s\n  %s\n", h.Score, h.ID, h.Title, h.Snippet)
+		}
+		return nil
+	case "reindex":
+		count, err := m.index.Reindex()
+		if err != nil {
+			return err
+		}
+		ctx.Metrics.SetTranscriptsStored(int64(count))
+		fmt.Printf("reindexed %d transcript(s)\n", count)
+		return nil
+	default:
+		return fmt.Errorf("unknown transcripts action: %s", args[0])
 	}
 }
 
@@ -308,7 +524,7 @@ func (m *YTTranscriberModule) cmdTranscribe(ctx *Context, args []string) error {
 		return err
 	}
 	fmt.Println(rec.Text)
-	if err := persistTranscript(ctx, rec); err != nil {
+	if err := persistTranscript(ctx, m.index, rec); err != nil {
 		return fmt.Errorf("persist: %w", err)
 	}
 	return nil
@@ -340,14 +556,20 @@ func (m *YTTranscriberModule) normalizeVideoID(input string) (string, error) {
 	return "", errors.New("unable to extract video id")
 }
 
+// fetchRecord runs m.transcribers' fallback chain for videoID/lang. The
+// done channel is accepted for symmetry with the request's context but
+// unused - the chain's own HTTP calls carry no cancellation today.
 func (m *YTTranscriberModule) fetchRecord(_ <-chan struct{}, ctx *Context, videoID, lang string) (*TranscriptRecord, error) {
-	v, err := ctx.YT.GetVideo(videoID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch video metadata")
-	}
+	return m.transcribers.Fetch(ctx, videoID, lang)
+}
+
+// transcriptText joins v's caption track into a single space-separated
+// string, the shared core of both CaptionTranscriber.Fetch and the
+// yt.transcript() binding a ScriptModule gets.
+func transcriptText(ctx *Context, v *youtube.Video, lang string) (string, error) {
 	tr, err := ctx.YT.GetTranscript(v, lang)
 	if err != nil {
-		return nil, fmt.Errorf("transcript unavailable or disabled")
+		return "", fmt.Errorf("transcript unavailable or disabled")
 	}
 	var b strings.Builder
 	for _, e := range tr {
@@ -358,25 +580,40 @@ func (m *YTTranscriberModule) fetchRecord(_ <-chan struct{}, ctx *Context, video
 			b.WriteString(t)
 		}
 	}
-	url := "https://www.youtube.com/watch?v=" + videoID
-	rec := &TranscriptRecord{
-		TS:    time.Now().Unix(),
-		ID:    videoID,
-		URL:   url,
-		Title: v.Title,
-		Lang:  lang,
-		Text:  b.String(),
+	return b.String(), nil
+}
+
+// fetchTranscriptText is transcriptText plus the GetVideo lookup, for
+// callers - like ScriptModule's yt.transcript() binding - that only have
+// a video ID, not an already-fetched *youtube.Video.
+func fetchTranscriptText(ctx *Context, videoID, lang string) (string, error) {
+	v, err := ctx.YT.GetVideo(videoID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch video metadata")
 	}
-	return rec, nil
+	return transcriptText(ctx, v, lang)
 }
 
-func persistTranscript(ctx *Context, rec *TranscriptRecord) error {
+func persistTranscript(ctx *Context, index *TranscriptIndex, rec *TranscriptRecord) error {
 	dir := filepath.Join(ctx.Store.Root, "transcripts")
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return err
 	}
 	path := filepath.Join(dir, fmt.Sprintf("%d.transcript", rec.TS))
-	return ctx.Store.WriteJSON(path, rec)
+	if err := ctx.Store.WriteJSON(path, rec); err != nil {
+		return err
+	}
+	if err := index.Add(rec); err != nil {
+		return err
+	}
+	ctx.Metrics.AddTranscriptsStored(1)
+	ctx.Events.Publish("transcript", map[string]any{
+		"id":    rec.ID,
+		"title": rec.Title,
+		"lang":  rec.Lang,
+		"ts":    rec.TS,
+	})
+	return nil
 }
 
 /* =========================
@@ -443,56 +680,78 @@ func main() {
 		Vars: map[string]string{
 			"MELVIN_DIR": root,
 		},
-		YT:    &youtube.Client{},
-		Store: store,
+		YT:      &youtube.Client{},
+		Store:   store,
+		Events:  NewEventBus(),
+		Metrics: NewMetrics(),
 	}
 
-	// Instantiate modules
+	// Instantiate native modules
 	httpMod := &HTTPServerModule{}
 	ytMod := &YTTranscriberModule{}
-	modules := []Module{httpMod, ytMod}
+	eventsMod := &EventsModule{}
+	metricsMod := &MetricsModule{metrics: ctx.Metrics}
+	native := []Module{httpMod, ytMod, eventsMod, metricsMod}
 
 	// Init + attach routes
-	for _, m := range modules {
+	for _, m := range native {
 		if err := m.Init(ctx); err != nil {
 			logger.Fatalf("init %s: %v", m.Name(), err)
 		}
 		m.HTTPRoutes(ctx.Mux)
 	}
 
-	// Build command dispatcher
-	dispatch := map[string]CommandFunc{
-		"help": func(ctx *Context, args []string) error {
-			fmt.Println("builtins: help, list, quit")
-			fmt.Println("commands:")
-			seen := map[string]struct{}{}
-			for _, m := range modules {
-				for k := range m.Commands() {
-					if _, ok := seen[k]; !ok {
-						fmt.Printf("  %s\n", k)
-						seen[k] = struct{}{}
-					}
-				}
-			}
-			return nil
-		},
-		"list": func(ctx *Context, args []string) error {
-			for _, m := range modules {
-				fmt.Println(m.Name())
-			}
-			return nil
-		},
+	// Script modules add themselves to the same dispatcher and mux as the
+	// native ones above, so the REPL and HTTP layer can't tell a
+	// module.js-backed command or route from a Go one.
+	scripts, err := loadScriptModules(ctx)
+	if err != nil {
+		logger.Printf("load script modules: %v", err)
 	}
-	for _, m := range modules {
-		for k, fn := range m.Commands() {
-			dispatch[k] = fn
+	for _, m := range scripts {
+		if err := m.Init(ctx); err != nil {
+			logger.Printf("init %s: %v", m.Name(), err)
+			continue
 		}
+		m.HTTPRoutes(ctx.Mux)
+	}
+
+	ctx.Metrics.SetModulesLoaded(int64(len(native) + len(scripts)))
+
+	reg := &moduleRegistry{native: native, scripts: scripts}
+	reg.rebuild()
+
+	shutdownTimeout := time.Duration(getenvInt("MELVIN_SHUTDOWN_TIMEOUT", 10)) * time.Second
+	shutdown := func() {
+		logger.Printf("[shutdown] shutting down (timeout %s)", shutdownTimeout)
+		shutdownAll(ctx, reg, shutdownTimeout)
 	}
 
+	// SIGHUP reloads every script module's VM from disk, plus Reload on any
+	// native module that implements it - routes already mounted on ctx.Mux
+	// stay as they are, since net/http.ServeMux can't unregister a pattern,
+	// but commands and file serving pick up the change on the next
+	// dispatch. SIGINT/SIGTERM instead run the same graceful shutdown the
+	// REPL's "quit" uses, then exit 0.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	go func() {
+		for sig := range sigCh {
+			if sig == syscall.SIGHUP {
+				reloadScriptModules(ctx, reg)
+				reloadNativeModules(ctx, reg)
+				continue
+			}
+			logger.Printf("[shutdown] received %s", sig)
+			shutdown()
+			os.Exit(0)
+		}
+	}()
+
 	// Slash commands
 	slash := map[string]func(*Context, []string) error{
 		"help": func(ctx *Context, _ []string) error {
-			fmt.Println("slash commands: /help, /vars, /mods, /cmds, /pref get|set, /tag note")
+			fmt.Println("slash commands: /help, /vars, /mods, /cmds, /pref get|set, /tag note, /search <query>, /subscribe <topic>")
 			return nil
 		},
 		"vars": func(ctx *Context, _ []string) error {
@@ -502,15 +761,19 @@ func main() {
 			return nil
 		},
 		"mods": func(_ *Context, _ []string) error {
-			fmt.Println("modules:")
-			for _, m := range modules {
+			fmt.Println("native modules:")
+			for _, m := range reg.nativeModules() {
+				fmt.Printf("  %s\n", m.Name())
+			}
+			fmt.Println("script modules:")
+			for _, m := range reg.scriptModules() {
 				fmt.Printf("  %s\n", m.Name())
 			}
 			return nil
 		},
 		"cmds": func(_ *Context, _ []string) error {
 			fmt.Println("commands:")
-			for k := range dispatch {
+			for k := range reg.commands() {
 				fmt.Printf("  %s\n", k)
 			}
 			return nil
@@ -537,7 +800,15 @@ func main() {
 					return fmt.Errorf("usage: /pref set <module> <key> <value>")
 				}
 				val := strings.Join(args[3:], " ")
-				return ctx.Store.PrefsSet(args[1], args[2], val)
+				if err := ctx.Store.PrefsSet(args[1], args[2], val); err != nil {
+					return err
+				}
+				ctx.Events.Publish("pref", map[string]any{
+					"module": args[1],
+					"key":    args[2],
+					"value":  val,
+				})
+				return nil
 			default:
 				return fmt.Errorf("unknown /pref action")
 			}
@@ -557,28 +828,57 @@ func main() {
 				if err != nil {
 					return err
 				}
+				ctx.Events.Publish("tag", map[string]any{
+					"tag":  tag,
+					"text": text,
+					"path": p,
+				})
 				fmt.Println(p)
 				return nil
 			default:
 				return fmt.Errorf("unknown /tag action")
 			}
 		},
+		"subscribe": func(ctx *Context, args []string) error {
+			if len(args) < 1 {
+				return fmt.Errorf("usage: /subscribe <topic>[,<topic>...]")
+			}
+			topics := strings.Split(strings.Join(args, ","), ",")
+			events, cancel := ctx.Events.Subscribe(topics, 0)
+			defer cancel()
+			fmt.Printf(clrFaint+"tailing %s - Ctrl+C to stop"+clrReset+"\n", strings.Join(topics, ","))
+			for ev := range events {
+				data, _ := json.Marshal(ev.Payload)
+				fmt.Printf(clrFaint+"[%s] %s#%d %s"+clrReset+"\n", ev.Time.Format(time.RFC3339), ev.Topic, ev.Seq, data)
+			}
+			return nil
+		},
+		"search": func(_ *Context, args []string) error {
+			if fn, ok := reg.command("transcripts"); ok {
+				return fn(ctx, append([]string{"search"}, args...))
+			}
+			return fmt.Errorf("transcripts command unavailable")
+		},
 	}
 
+	reg.addBuiltins()
+
 	// Modes
 	if len(os.Args) > 1 && os.Args[1] != "repl" {
 		// single-shot
 		cmd := os.Args[1]
 		args := os.Args[2:]
-		if handler, ok := dispatch[cmd]; ok {
-			if err := handler(ctx, args); err != nil {
+		if handler, ok := reg.command(cmd); ok {
+			err := handler(ctx, args)
+			ctx.Metrics.RecordCommand(cmd, err == nil)
+			if err != nil {
 				logger.Printf("error: %v", err)
 				os.Exit(1)
 			}
 			return
 		}
 		if cmd == "serve" {
-			if err := httpMod.start(ctx); err != nil {
+			if err := httpMod.Start(ctx); err != nil {
 				logger.Fatalf("serve: %v", err)
 			}
 			select {}
@@ -588,13 +888,13 @@ func main() {
 	}
 
 	// REPL + server
-	if err := httpMod.start(ctx); err != nil {
+	if err := httpMod.Start(ctx); err != nil {
 		logger.Fatalf("serve: %v", err)
 	}
-	repl(ctx, dispatch, slash)
+	repl(ctx, reg, slash, shutdown)
 }
 
-func repl(ctx *Context, dispatch map[string]CommandFunc, slash map[string]func(*Context, []string) error) {
+func repl(ctx *Context, reg *moduleRegistry, slash map[string]func(*Context, []string) error, shutdown func()) {
 	in := bufio.NewScanner(os.Stdin)
 	fmt.Println("MELVIN REPL. Type 'help' or 'quit'. Slash commands start with '/'.")
 	last := "ready"
@@ -608,6 +908,7 @@ func repl(ctx *Context, dispatch map[string]CommandFunc, slash map[string]func(*
 			continue
 		}
 		if line == "quit" || line == "exit" {
+			shutdown()
 			return
 		}
 		var err error
@@ -630,8 +931,9 @@ func repl(ctx *Context, dispatch map[string]CommandFunc, slash map[string]func(*
 			toks := splitArgs(line)
 			name := toks[0]
 			args := toks[1:]
-			if fn, ok := dispatch[name]; ok {
+			if fn, ok := reg.command(name); ok {
 				err = fn(ctx, args)
+				ctx.Metrics.RecordCommand(name, err == nil)
 			} else {
 				err = fmt.Errorf("unknown: %s", name)
 			}
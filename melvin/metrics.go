@@ -0,0 +1,338 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+/* =========================
+   metrics (Prometheus text)
+   ========================= */
+
+// metricsShards is how many lock stripes a counterVec/histogramVec's
+// label tuples are spread across - enough that two requests touching
+// different tuples concurrently essentially never contend on the same
+// shard's mutex, without taking a lock per observed tuple forever after.
+const metricsShards = 32
+
+// defaultHistogramBounds are melvin_http_request_duration_seconds_bucket's
+// upper bounds, in seconds - the same default set client_golang ships.
+var defaultHistogramBounds = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// labelSep joins label values into a counterVec/histogramVec map key. It's
+// a control character, so it can't collide with anything a real label
+// value (a method, a mux pattern, a command name) would contain.
+const labelSep = "\x1f"
+
+func labelKey(parts ...string) string { return strings.Join(parts, labelSep) }
+
+// shardIndex picks a stripe for key by hashing it - FNV-1a is fast and
+// disperses well enough for a label tuple; it doesn't need to be
+// cryptographic.
+func shardIndex(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32() % metricsShards
+}
+
+// counterShard is one lock stripe of a labeled counter: a map from label
+// tuple to its running total, guarded by its own mutex so concurrent
+// increments to different tuples rarely block each other. The mutex only
+// ever guards the map's shape (inserting a tuple seen for the first
+// time); the actual increment is a lock-free atomic add.
+type counterShard struct {
+	mu     sync.Mutex
+	values map[string]*uint64
+}
+
+type counterVec struct {
+	shards [metricsShards]*counterShard
+}
+
+func newCounterVec() *counterVec {
+	cv := &counterVec{}
+	for i := range cv.shards {
+		cv.shards[i] = &counterShard{values: map[string]*uint64{}}
+	}
+	return cv
+}
+
+func (cv *counterVec) inc(key string) {
+	s := cv.shards[shardIndex(key)]
+	s.mu.Lock()
+	v, ok := s.values[key]
+	if !ok {
+		v = new(uint64)
+		s.values[key] = v
+	}
+	s.mu.Unlock()
+	atomic.AddUint64(v, 1)
+}
+
+// snapshot reads every observed label tuple's current count via a
+// lock-free atomic load, for a scrape. It's the only place a counterVec's
+// shards are all visited at once.
+func (cv *counterVec) snapshot() map[string]uint64 {
+	out := map[string]uint64{}
+	for _, s := range cv.shards {
+		s.mu.Lock()
+		for k, v := range s.values {
+			out[k] = atomic.LoadUint64(v)
+		}
+		s.mu.Unlock()
+	}
+	return out
+}
+
+// histogramSeries is one label tuple's observations: a count per
+// defaultHistogramBounds bucket (each counting every observation <= its
+// own bound - client_golang's cumulative "le" semantics, so the bucket
+// array is already scrape-ready with no prefix-summing needed) plus the
+// running sum and total count the _sum/_count lines need. Every field is
+// atomic-accessed so observe never takes a lock.
+type histogramSeries struct {
+	buckets []uint64 // parallel to defaultHistogramBounds
+	sumBits uint64   // math.Float64bits of the running sum
+	count   uint64
+}
+
+func newHistogramSeries() *histogramSeries {
+	return &histogramSeries{buckets: make([]uint64, len(defaultHistogramBounds))}
+}
+
+func (hs *histogramSeries) observe(seconds float64) {
+	for i, bound := range defaultHistogramBounds {
+		if seconds <= bound {
+			atomic.AddUint64(&hs.buckets[i], 1)
+		}
+	}
+	atomic.AddUint64(&hs.count, 1)
+	for {
+		old := atomic.LoadUint64(&hs.sumBits)
+		next := math.Float64bits(math.Float64frombits(old) + seconds)
+		if atomic.CompareAndSwapUint64(&hs.sumBits, old, next) {
+			return
+		}
+	}
+}
+
+// histogramShard is a histogramVec's lock stripe, the same shape as
+// counterShard but holding a *histogramSeries per tuple instead of a
+// counter.
+type histogramShard struct {
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramVec struct {
+	shards [metricsShards]*histogramShard
+}
+
+func newHistogramVec() *histogramVec {
+	hv := &histogramVec{}
+	for i := range hv.shards {
+		hv.shards[i] = &histogramShard{series: map[string]*histogramSeries{}}
+	}
+	return hv
+}
+
+func (hv *histogramVec) observe(key string, seconds float64) {
+	s := hv.shards[shardIndex(key)]
+	s.mu.Lock()
+	hs, ok := s.series[key]
+	if !ok {
+		hs = newHistogramSeries()
+		s.series[key] = hs
+	}
+	s.mu.Unlock()
+	hs.observe(seconds)
+}
+
+func (hv *histogramVec) snapshot() map[string]*histogramSeries {
+	out := map[string]*histogramSeries{}
+	for _, s := range hv.shards {
+		s.mu.Lock()
+		for k, v := range s.series {
+			out[k] = v
+		}
+		s.mu.Unlock()
+	}
+	return out
+}
+
+// Metrics is ctx.Metrics: every module that wants something scrapeable
+// records it here, and MetricsModule's /metrics handler is the only thing
+// that ever reads it back out, as Prometheus text exposition format.
+type Metrics struct {
+	httpRequestsTotal  *counterVec   // method|pattern|status -> count
+	httpRequestSeconds *histogramVec // method|pattern -> duration histogram
+	commandsTotal      *counterVec   // name|status -> count
+
+	transcriptsStored int64 // gauge, atomic
+	modulesLoaded     int64 // gauge, atomic
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		httpRequestsTotal:  newCounterVec(),
+		httpRequestSeconds: newHistogramVec(),
+		commandsTotal:      newCounterVec(),
+	}
+}
+
+// RecordHTTP records one finished HTTP request. pattern must be a
+// registered mux pattern (or a fixed placeholder for an unmatched
+// request), never raw r.URL.Path, so a query string or an attacker
+// probing random paths can't grow melvin_http_requests_total's
+// cardinality without bound.
+func (m *Metrics) RecordHTTP(method, pattern string, status int, elapsed time.Duration) {
+	m.httpRequestsTotal.inc(labelKey(method, pattern, strconv.Itoa(status)))
+	m.httpRequestSeconds.observe(labelKey(method, pattern), elapsed.Seconds())
+}
+
+// RecordCommand records one finished REPL/single-shot command dispatch.
+func (m *Metrics) RecordCommand(name string, success bool) {
+	status := "ok"
+	if !success {
+		status = "error"
+	}
+	m.commandsTotal.inc(labelKey(name, status))
+}
+
+// SetTranscriptsStored sets the melvin_transcripts_stored_total gauge to
+// n, for the initial count a TranscriptIndex loads at startup.
+func (m *Metrics) SetTranscriptsStored(n int64) { atomic.StoreInt64(&m.transcriptsStored, n) }
+
+// AddTranscriptsStored adjusts melvin_transcripts_stored_total by delta,
+// for each transcript persistTranscript adds afterward.
+func (m *Metrics) AddTranscriptsStored(delta int64) {
+	atomic.AddInt64(&m.transcriptsStored, delta)
+}
+
+// SetModulesLoaded sets the melvin_modules_loaded gauge, once at startup
+// and again after any SIGHUP script reload changes the count.
+func (m *Metrics) SetModulesLoaded(n int64) { atomic.StoreInt64(&m.modulesLoaded, n) }
+
+// instrumentHTTP wraps next with per-request HTTP metrics. It looks the
+// request's route template up via mux.Handler - the same lookup
+// net/http.ServeMux's own ServeHTTP does internally - instead of
+// r.URL.Path, bounding path cardinality to whatever's actually registered
+// on mux.
+func (m *Metrics) instrumentHTTP(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, pattern := mux.Handler(r)
+		if pattern == "" {
+			pattern = "(unmatched)"
+		}
+
+		start := time.Now()
+		ww := &wrapWriter{ResponseWriter: w, status: 200}
+		next.ServeHTTP(ww, r)
+		m.RecordHTTP(r.Method, pattern, ww.status, time.Since(start))
+	})
+}
+
+// WriteTo renders every metric as Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) {
+	writeCounterVec(w, "melvin_http_requests_total", "Total HTTP requests.", m.httpRequestsTotal, []string{"method", "path", "status"})
+	writeHistogramVec(w, "melvin_http_request_duration_seconds", "HTTP request duration in seconds.", m.httpRequestSeconds, []string{"method", "path"})
+	writeCounterVec(w, "melvin_commands_total", "Total dispatched REPL/single-shot commands.", m.commandsTotal, []string{"name", "status"})
+
+	fmt.Fprintf(w, "# HELP melvin_transcripts_stored_total Transcripts currently stored.\n# TYPE melvin_transcripts_stored_total gauge\nmelvin_transcripts_stored_total %d\n", atomic.LoadInt64(&m.transcriptsStored))
+	fmt.Fprintf(w, "# HELP melvin_modules_loaded Native and script modules currently loaded.\n# TYPE melvin_modules_loaded gauge\nmelvin_modules_loaded %d\n", atomic.LoadInt64(&m.modulesLoaded))
+}
+
+func writeCounterVec(w io.Writer, name, help string, cv *counterVec, labelNames []string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	snap := cv.snapshot()
+	for _, k := range sortedKeys(snap) {
+		fmt.Fprintf(w, "%s{%s} %d\n", name, formatLabels(labelNames, k), snap[k])
+	}
+}
+
+func writeHistogramVec(w io.Writer, name, help string, hv *histogramVec, labelNames []string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	snap := hv.snapshot()
+	for _, k := range sortedHistogramKeys(snap) {
+		hs := snap[k]
+		labels := formatLabels(labelNames, k)
+		for i, bound := range defaultHistogramBounds {
+			fmt.Fprintf(w, "%s_bucket{%s,le=%q} %d\n", name, labels, formatBound(bound), atomic.LoadUint64(&hs.buckets[i]))
+		}
+		count := atomic.LoadUint64(&hs.count)
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels, strconv.FormatFloat(math.Float64frombits(atomic.LoadUint64(&hs.sumBits)), 'f', -1, 64))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, count)
+	}
+}
+
+// formatLabels zips names with key's labelSep-delimited values into
+// Prometheus label syntax: `name1="v1",name2="v2"`.
+func formatLabels(names []string, key string) string {
+	values := strings.Split(key, labelSep)
+	parts := make([]string, len(names))
+	for i, n := range names {
+		v := ""
+		if i < len(values) {
+			v = values[i]
+		}
+		parts[i] = fmt.Sprintf("%s=%q", n, v)
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatBound(b float64) string {
+	return strconv.FormatFloat(b, 'f', -1, 64)
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedHistogramKeys(m map[string]*histogramSeries) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+/* =========================
+   metrics-module
+   ========================= */
+
+// MetricsModule exposes Metrics as a Prometheus-scrapeable /metrics
+// endpoint, registered as a core module the same way EventsModule exposes
+// EventBus over HTTP.
+type MetricsModule struct {
+	metrics *Metrics
+}
+
+func (m *MetricsModule) Name() string { return "metrics-module" }
+
+func (m *MetricsModule) Init(ctx *Context) error {
+	ctx.Mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.metrics.WriteTo(w)
+	})
+	return nil
+}
+
+func (m *MetricsModule) HTTPRoutes(_ *http.ServeMux) {}
+
+func (m *MetricsModule) Commands() map[string]CommandFunc { return map[string]CommandFunc{} }
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/* =========================
+   module registry
+   ========================= */
+
+// moduleRegistry holds the native modules (fixed for the process's
+// lifetime) alongside the script modules loadScriptModules discovered,
+// and the command dispatcher built from both. reloadScriptModules swaps
+// just the scripts slice and rebuilds dispatch, under mu, so a SIGHUP
+// reload can't race a dispatch lookup. A command already looked up runs
+// outside mu; if the reload stops its script module first, it returns an
+// error instead of running.
+type moduleRegistry struct {
+	mu sync.Mutex
+
+	native   []Module
+	scripts  []Module
+	builtins map[string]CommandFunc
+	dispatch map[string]CommandFunc
+}
+
+// rebuild recomputes dispatch from native, scripts and builtins. Call it
+// after changing any of those.
+func (reg *moduleRegistry) rebuild() {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.rebuildLocked()
+}
+
+func (reg *moduleRegistry) rebuildLocked() {
+	dispatch := make(map[string]CommandFunc)
+	for k, fn := range reg.builtins {
+		dispatch[k] = fn
+	}
+	for _, m := range reg.native {
+		for k, fn := range m.Commands() {
+			dispatch[k] = fn
+		}
+	}
+	for _, m := range reg.scripts {
+		for k, fn := range m.Commands() {
+			dispatch[k] = fn
+		}
+	}
+	reg.dispatch = dispatch
+}
+
+// addBuiltins installs "help" and "list", then rebuilds so they show up
+// in dispatch. Separate from the registry's own constructor because both
+// read the registry they're closing over, so they need reg to already
+// exist.
+func (reg *moduleRegistry) addBuiltins() {
+	reg.mu.Lock()
+	reg.builtins = map[string]CommandFunc{
+		"help": func(_ *Context, _ []string) error {
+			fmt.Println("builtins: help, list, quit")
+			fmt.Println("commands:")
+			reg.mu.Lock()
+			defer reg.mu.Unlock()
+			for k := range reg.dispatch {
+				fmt.Printf("  %s\n", k)
+			}
+			return nil
+		},
+		"list": func(_ *Context, _ []string) error {
+			for _, m := range reg.all() {
+				fmt.Println(m.Name())
+			}
+			return nil
+		},
+	}
+	reg.mu.Unlock()
+	reg.rebuild()
+}
+
+func (reg *moduleRegistry) command(name string) (CommandFunc, bool) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	fn, ok := reg.dispatch[name]
+	return fn, ok
+}
+
+// commands returns a snapshot of the dispatch table, for "/cmds" to range
+// over without holding reg.mu itself.
+func (reg *moduleRegistry) commands() map[string]CommandFunc {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	out := make(map[string]CommandFunc, len(reg.dispatch))
+	for k, fn := range reg.dispatch {
+		out[k] = fn
+	}
+	return out
+}
+
+func (reg *moduleRegistry) all() []Module {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	out := make([]Module, 0, len(reg.native)+len(reg.scripts))
+	out = append(out, reg.native...)
+	out = append(out, reg.scripts...)
+	return out
+}
+
+func (reg *moduleRegistry) nativeModules() []Module {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	return append([]Module{}, reg.native...)
+}
+
+func (reg *moduleRegistry) scriptModules() []Module {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	return append([]Module{}, reg.scripts...)
+}
+
+// reloadScriptModules re-scans $MELVIN_DIR/modules on SIGHUP, loading a
+// fresh ScriptModule (and VM) per directory, swapping them into reg, and
+// shutting down the VM goroutines the old ones started. HTTP routes
+// already mounted on ctx.Mux are left alone - see the SIGHUP comment in
+// main - so only commands actually pick up the reload.
+func reloadScriptModules(ctx *Context, reg *moduleRegistry) {
+	fresh, err := loadScriptModules(ctx)
+	if err != nil {
+		ctx.Logger.Printf("[reload] scan modules: %v", err)
+		return
+	}
+	for _, m := range fresh {
+		if err := m.Init(ctx); err != nil {
+			ctx.Logger.Printf("[reload] init %s: %v", m.Name(), err)
+		}
+	}
+
+	reg.mu.Lock()
+	old := reg.scripts
+	reg.scripts = fresh
+	reg.rebuildLocked()
+	total := len(reg.native) + len(reg.scripts)
+	reg.mu.Unlock()
+
+	for _, m := range old {
+		if sm, ok := m.(*ScriptModule); ok {
+			sm.Stop()
+		}
+	}
+
+	ctx.Metrics.SetModulesLoaded(int64(total))
+	ctx.Logger.Printf("[reload] %d script module(s) reloaded", len(fresh))
+}
+
+// reloadNativeModules calls Reload on every native module that implements
+// ModuleReloader, alongside reloadScriptModules on the same SIGHUP - e.g.
+// HTTPServerModule picking up a changed MELVIN_STATIC_DIR or
+// YTTranscriberModule a new MELVIN_DEFAULT_LANG, without dropping the
+// listening socket.
+func reloadNativeModules(ctx *Context, reg *moduleRegistry) {
+	for _, m := range reg.nativeModules() {
+		rl, ok := m.(ModuleReloader)
+		if !ok {
+			continue
+		}
+		if err := rl.Reload(ctx); err != nil {
+			ctx.Logger.Printf("[reload] %s: %v", m.Name(), err)
+		}
+	}
+}
+
+// shutdownAll calls Shutdown on every module that implements
+// ModuleShutdowner, in reverse-init order (script modules, loaded after
+// native ones, stop first), bounded by timeout.
+func shutdownAll(ctx *Context, reg *moduleRegistry, timeout time.Duration) {
+	deadline, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	all := reg.all()
+	for i := len(all) - 1; i >= 0; i-- {
+		m := all[i]
+		sd, ok := m.(ModuleShutdowner)
+		if !ok {
+			continue
+		}
+		if err := sd.Shutdown(deadline); err != nil {
+			ctx.Logger.Printf("[shutdown] %s: %v", m.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+/* =========================
+   script-module (JS user modules)
+   ========================= */
+
+// ScriptModule loads and runs one user-authored module.js under
+// $MELVIN_DIR/modules/<name>/, inside a sandboxed goja VM. Unlike
+// HTTPServerModule and YTTranscriberModule, its commands and HTTP routes
+// aren't declared in Go - the script assigns a top-level `commands` object
+// and a top-level `routes` object during init(ctx), and ScriptModule reads
+// those back out once init returns.
+//
+// goja.Runtime isn't safe for concurrent use, so every call into the VM -
+// init, a command, a route handler, a setTimeout callback - is posted to
+// tasks and run one at a time by loop, the module's single VM goroutine.
+type ScriptModule struct {
+	name string
+	dir  string
+
+	vm    *goja.Runtime
+	tasks chan func()
+	done  chan struct{}
+
+	mu     sync.Mutex
+	timers []*time.Timer
+}
+
+// NewScriptModule returns a ScriptModule for the script found under dir,
+// not yet loaded - Init does that.
+func NewScriptModule(name, dir string) *ScriptModule {
+	return &ScriptModule{
+		name:  name,
+		dir:   dir,
+		tasks: make(chan func(), 32),
+		done:  make(chan struct{}),
+	}
+}
+
+// Stop tears the module's VM goroutine down: it cancels any setTimeout
+// timers still pending (mirroring Watcher.Stop in
+// tetra/tgo/go/fs_watcher_middleware.go) and signals loop to exit via
+// done rather than closing tasks, so a timer that fires just as reload
+// races Stop sends into a channel nobody's listening to anymore instead
+// of panicking on a closed one.
+func (m *ScriptModule) Stop() {
+	m.mu.Lock()
+	for _, t := range m.timers {
+		t.Stop()
+	}
+	m.timers = nil
+	m.mu.Unlock()
+	close(m.done)
+}
+
+// post runs task on the VM goroutine, dropping it silently if Stop has
+// already torn the module down - e.g. an HTTP route mounted before a
+// SIGHUP reload, firing after the superseded module's loop exited.
+func (m *ScriptModule) post(task func()) {
+	select {
+	case m.tasks <- task:
+	case <-m.done:
+	}
+}
+
+func (m *ScriptModule) Name() string { return "script:" + m.name }
+
+func (m *ScriptModule) Init(ctx *Context) error {
+	path, err := scriptEntryPoint(m.dir)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(path, ".lua") {
+		ctx.Logger.Printf("[%s] found module.lua but no Lua runtime is wired in yet, skipping", m.Name())
+		return nil
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", path, err)
+	}
+
+	m.vm = goja.New()
+	m.bindHost(ctx)
+	go m.loop()
+
+	// Routed through tasks like every other VM entry point below, rather
+	// than called directly from this goroutine - a top-level
+	// setTimeout(fn, 0) would otherwise let loop call back into the same
+	// goja.Runtime while RunScript is still running on this goroutine,
+	// violating goja's "not safe for concurrent use" requirement.
+	runErrCh := make(chan error, 1)
+	m.tasks <- func() {
+		_, err := m.vm.RunScript(path, string(src))
+		runErrCh <- err
+	}
+	if err := <-runErrCh; err != nil {
+		return fmt.Errorf("run %s: %w", path, err)
+	}
+
+	initFn, ok := goja.AssertFunction(m.vm.Get("init"))
+	if !ok {
+		return fmt.Errorf("%s: module.js must define init(ctx)", m.name)
+	}
+
+	errCh := make(chan error, 1)
+	m.tasks <- func() {
+		_, err := initFn(goja.Undefined())
+		errCh <- err
+	}
+	return <-errCh
+}
+
+// HTTPRoutes wires every entry of the script's top-level `routes` object
+// into mux, keyed on the pattern it was assigned under - the script's
+// equivalent of HTTPServerModule.HTTPRoutes.
+func (m *ScriptModule) HTTPRoutes(mux *http.ServeMux) {
+	if m.vm == nil {
+		return
+	}
+	routesVal := m.vm.Get("routes")
+	if routesVal == nil || goja.IsUndefined(routesVal) || goja.IsNull(routesVal) {
+		return
+	}
+
+	obj := routesVal.ToObject(m.vm)
+	for _, pattern := range obj.Keys() {
+		fn, ok := goja.AssertFunction(obj.Get(pattern))
+		if !ok {
+			continue
+		}
+		pattern, fn := pattern, fn
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			req := map[string]any{
+				"method": r.Method,
+				"path":   r.URL.Path,
+				"query":  r.URL.Query().Encode(),
+			}
+
+			type result struct {
+				body string
+				err  error
+			}
+			resCh := make(chan result, 1)
+			m.post(func() {
+				v, err := fn(goja.Undefined(), m.vm.ToValue(req))
+				body := ""
+				if err == nil && v != nil {
+					body = v.String()
+				}
+				resCh <- result{body: body, err: err}
+			})
+
+			var res result
+			select {
+			case res = <-resCh:
+			case <-m.done:
+				sendJSONError(w, m.Name()+" has been stopped", http.StatusServiceUnavailable)
+				return
+			}
+			if res.err != nil {
+				sendJSONError(w, res.err.Error(), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			fmt.Fprint(w, res.body)
+		})
+	}
+}
+
+// Commands adapts the script's top-level `commands` object into the same
+// map[string]CommandFunc every native Module returns, so the REPL
+// dispatcher can't tell a script-backed command from a Go one.
+func (m *ScriptModule) Commands() map[string]CommandFunc {
+	out := make(map[string]CommandFunc)
+	if m.vm == nil {
+		return out
+	}
+	commandsVal := m.vm.Get("commands")
+	if commandsVal == nil || goja.IsUndefined(commandsVal) || goja.IsNull(commandsVal) {
+		return out
+	}
+
+	obj := commandsVal.ToObject(m.vm)
+	for _, name := range obj.Keys() {
+		fn, ok := goja.AssertFunction(obj.Get(name))
+		if !ok {
+			continue
+		}
+		name, fn := name, fn
+		out[name] = func(_ *Context, args []string) error {
+			errCh := make(chan error, 1)
+			m.post(func() {
+				_, err := fn(goja.Undefined(), m.vm.ToValue(args))
+				errCh <- err
+			})
+			select {
+			case err := <-errCh:
+				return err
+			case <-m.done:
+				return fmt.Errorf("%s has been stopped", m.Name())
+			}
+		}
+	}
+	return out
+}
+
+// loop is the ScriptModule's one VM goroutine: init, commands, route
+// handlers and setTimeout callbacks all funnel through tasks so the
+// goja.Runtime is never touched from two goroutines at once. It exits
+// once Stop closes done, rather than waiting on tasks to be closed.
+func (m *ScriptModule) loop() {
+	for {
+		select {
+		case task := <-m.tasks:
+			task()
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// removeTimer returns timers with t removed, used by a firing timer to
+// drop itself from ScriptModule.timers so Stop doesn't try to cancel an
+// already-fired one.
+func removeTimer(timers []*time.Timer, t *time.Timer) []*time.Timer {
+	out := timers[:0:0]
+	for _, existing := range timers {
+		if existing != t {
+			out = append(out, existing)
+		}
+	}
+	return out
+}
+
+// bindHost installs the host bindings every module.js sees: store.*
+// mirroring Store's own methods, http.get for outbound fetches, yt.transcript
+// for pulling a caption track through ctx.YT, and a setTimeout that defers
+// a callback onto this module's own loop rather than a raw goroutine.
+func (m *ScriptModule) bindHost(ctx *Context) {
+	vm := m.vm
+
+	store := vm.NewObject()
+	store.Set("writeJSON", func(call goja.FunctionCall) goja.Value {
+		path := call.Argument(0).String()
+		if err := ctx.Store.WriteJSON(path, call.Argument(1).Export()); err != nil {
+			panic(vm.ToValue(err.Error()))
+		}
+		return goja.Undefined()
+	})
+	store.Set("prefsGet", func(call goja.FunctionCall) goja.Value {
+		v, ok, err := ctx.Store.PrefsGet(call.Argument(0).String(), call.Argument(1).String())
+		if err != nil {
+			panic(vm.ToValue(err.Error()))
+		}
+		if !ok {
+			return goja.Null()
+		}
+		return vm.ToValue(v)
+	})
+	store.Set("prefsSet", func(call goja.FunctionCall) goja.Value {
+		err := ctx.Store.PrefsSet(call.Argument(0).String(), call.Argument(1).String(), call.Argument(2).String())
+		if err != nil {
+			panic(vm.ToValue(err.Error()))
+		}
+		return goja.Undefined()
+	})
+	store.Set("tagNote", func(call goja.FunctionCall) goja.Value {
+		path, err := ctx.Store.TagNote(call.Argument(0).String(), call.Argument(1).String())
+		if err != nil {
+			panic(vm.ToValue(err.Error()))
+		}
+		return vm.ToValue(path)
+	})
+	vm.Set("store", store)
+
+	httpObj := vm.NewObject()
+	httpObj.Set("get", func(call goja.FunctionCall) goja.Value {
+		resp, err := http.Get(call.Argument(0).String())
+		if err != nil {
+			panic(vm.ToValue(err.Error()))
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			panic(vm.ToValue(err.Error()))
+		}
+		return vm.ToValue(string(body))
+	})
+	vm.Set("http", httpObj)
+
+	ytObj := vm.NewObject()
+	ytObj.Set("transcript", func(call goja.FunctionCall) goja.Value {
+		lang := "en"
+		if len(call.Arguments) > 1 {
+			lang = call.Argument(1).String()
+		}
+		text, err := fetchTranscriptText(ctx, call.Argument(0).String(), lang)
+		if err != nil {
+			panic(vm.ToValue(err.Error()))
+		}
+		return vm.ToValue(text)
+	})
+	vm.Set("yt", ytObj)
+
+	vm.Set("setTimeout", func(call goja.FunctionCall) goja.Value {
+		fn, ok := goja.AssertFunction(call.Argument(0))
+		if !ok {
+			return goja.Undefined()
+		}
+		delay := time.Duration(call.Argument(1).ToInteger()) * time.Millisecond
+		var t *time.Timer
+		t = time.AfterFunc(delay, func() {
+			m.mu.Lock()
+			m.timers = removeTimer(m.timers, t)
+			m.mu.Unlock()
+			m.post(func() { fn(goja.Undefined()) })
+		})
+		m.mu.Lock()
+		m.timers = append(m.timers, t)
+		m.mu.Unlock()
+		return goja.Undefined()
+	})
+}
+
+// scriptEntryPoint returns dir's module.js or module.lua, in that order,
+// erroring if dir has neither.
+func scriptEntryPoint(dir string) (string, error) {
+	for _, name := range []string{"module.js", "module.lua"} {
+		p := filepath.Join(dir, name)
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("no module.js or module.lua in %s", dir)
+}
+
+// loadScriptModules scans $MELVIN_DIR/modules for one subdirectory per
+// script module, skipping any that have neither a module.js nor a
+// module.lua - modules/ also holds ModulePath's own per-module state, so
+// not every entry is a script.
+func loadScriptModules(ctx *Context) ([]Module, error) {
+	root := filepath.Join(ctx.Store.Root, "modules")
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var mods []Module
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, e.Name())
+		if _, err := scriptEntryPoint(dir); err != nil {
+			continue
+		}
+		mods = append(mods, NewScriptModule(e.Name(), dir))
+	}
+	return mods, nil
+}
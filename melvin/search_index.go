@@ -0,0 +1,404 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* =========================
+   transcript search index
+   ========================= */
+
+// stopwords are dropped from the index entirely - common enough that
+// they'd otherwise dominate every posting list without narrowing a
+// search.
+var stopwords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {},
+	"but": {}, "by": {}, "for": {}, "from": {}, "if": {}, "in": {}, "is": {},
+	"it": {}, "of": {}, "on": {}, "or": {}, "that": {}, "the": {}, "this": {},
+	"to": {}, "was": {}, "were": {}, "with": {},
+}
+
+var tokenRx = regexp.MustCompile(`[A-Za-z0-9']+`)
+
+// tokenize splits text into its raw words, for snippet display, and the
+// lowercase, stopword-filtered term each word indexes under - "" for a
+// word that isn't indexed, so a position into terms always lines up with
+// the same position into words.
+func tokenize(text string) (words []string, terms []string) {
+	words = tokenRx.FindAllString(text, -1)
+	terms = make([]string, len(words))
+	for i, w := range words {
+		lw := strings.ToLower(w)
+		if _, stop := stopwords[lw]; stop {
+			continue
+		}
+		terms[i] = lw
+	}
+	return words, terms
+}
+
+// tokenPosting is one term's occurrences within a single transcript,
+// keyed by the transcript's TS (also its filename under transcripts/).
+type tokenPosting struct {
+	TS        int64  `json:"ts"`
+	ID        string `json:"id"`
+	Positions []int  `json:"positions"`
+}
+
+// txIndexDoc is the per-transcript metadata BM25 needs without having to
+// re-read the transcript file: Length is the token count BM25's length
+// normalization (the "b" term) divides against.
+type txIndexDoc struct {
+	TS     int64  `json:"ts"`
+	ID     string `json:"id"`
+	Lang   string `json:"lang"`
+	Title  string `json:"title"`
+	Length int    `json:"length"`
+}
+
+// txIndex is the on-disk shape of $MELVIN_DIR/data/tx_index.json: an
+// inverted index (term -> posting list) plus the per-doc metadata BM25
+// scoring needs.
+type txIndex struct {
+	Terms map[string][]tokenPosting `json:"terms"`
+	Docs  map[string]txIndexDoc     `json:"docs"`
+}
+
+// BM25 constants - the usual defaults (Robertson & Zaragoza's original
+// paper and most production search engines use the same pair).
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// SearchHit is one ranked result from TranscriptIndex.Search.
+type SearchHit struct {
+	TS      int64   `json:"ts"`
+	ID      string  `json:"id"`
+	Title   string  `json:"title"`
+	Lang    string  `json:"lang"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+}
+
+// TranscriptIndex is the searchable index over persistTranscript's
+// corpus: every Add keeps it current, Reindex rebuilds it from scratch by
+// walking transcripts/, and Search answers a query with BM25-ranked hits.
+type TranscriptIndex struct {
+	store *Store
+	path  string
+
+	mu   sync.Mutex
+	data txIndex
+}
+
+// NewTranscriptIndex loads path's existing index, if any, starting empty
+// otherwise - a missing or corrupt index is no different from one that
+// simply hasn't indexed anything yet.
+func NewTranscriptIndex(store *Store) *TranscriptIndex {
+	ix := &TranscriptIndex{
+		store: store,
+		path:  filepath.Join(store.Root, "data", "tx_index.json"),
+		data:  txIndex{Terms: map[string][]tokenPosting{}, Docs: map[string]txIndexDoc{}},
+	}
+	_ = store.ReadJSON(ix.path, &ix.data)
+	if ix.data.Terms == nil {
+		ix.data.Terms = map[string][]tokenPosting{}
+	}
+	if ix.data.Docs == nil {
+		ix.data.Docs = map[string]txIndexDoc{}
+	}
+	return ix
+}
+
+// Add tokenizes rec.Text and merges its postings and doc metadata into
+// the index, then persists it.
+func (ix *TranscriptIndex) Add(rec *TranscriptRecord) error {
+	words, terms := tokenize(rec.Text)
+	positions := map[string][]int{}
+	for i, t := range terms {
+		if t == "" {
+			continue
+		}
+		positions[t] = append(positions[t], i)
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	key := strconv.FormatInt(rec.TS, 10)
+	ix.data.Docs[key] = txIndexDoc{TS: rec.TS, ID: rec.ID, Lang: rec.Lang, Title: rec.Title, Length: len(words)}
+	for term, pos := range positions {
+		ix.data.Terms[term] = append(ix.data.Terms[term], tokenPosting{TS: rec.TS, ID: rec.ID, Positions: pos})
+	}
+	return ix.saveLocked()
+}
+
+// Remove drops ts's doc entry and prunes it out of every posting list it
+// appears in, for GC.
+func (ix *TranscriptIndex) Remove(ts int64) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	key := strconv.FormatInt(ts, 10)
+	delete(ix.data.Docs, key)
+	for term, postings := range ix.data.Terms {
+		kept := postings[:0]
+		for _, p := range postings {
+			if p.TS != ts {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) == 0 {
+			delete(ix.data.Terms, term)
+		} else {
+			ix.data.Terms[term] = kept
+		}
+	}
+	return ix.saveLocked()
+}
+
+// Reindex rebuilds the index from scratch by walking dir's .transcript
+// files, then atomically replaces the on-disk index with the result - the
+// fix for an index that's drifted from the files on disk, or was lost.
+func (ix *TranscriptIndex) Reindex() (int, error) {
+	dir := filepath.Join(ix.store.Root, "transcripts")
+	fresh := txIndex{Terms: map[string][]tokenPosting{}, Docs: map[string]txIndexDoc{}}
+
+	count := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".transcript") {
+			return nil
+		}
+
+		var rec TranscriptRecord
+		if err := ix.store.ReadJSON(path, &rec); err != nil {
+			return nil // skip an unreadable record rather than aborting the whole reindex
+		}
+
+		words, terms := tokenize(rec.Text)
+		fresh.Docs[strconv.FormatInt(rec.TS, 10)] = txIndexDoc{TS: rec.TS, ID: rec.ID, Lang: rec.Lang, Title: rec.Title, Length: len(words)}
+		positions := map[string][]int{}
+		for i, t := range terms {
+			if t == "" {
+				continue
+			}
+			positions[t] = append(positions[t], i)
+		}
+		for term, pos := range positions {
+			fresh.Terms[term] = append(fresh.Terms[term], tokenPosting{TS: rec.TS, ID: rec.ID, Positions: pos})
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	ix.mu.Lock()
+	ix.data = fresh
+	err = ix.saveLocked()
+	ix.mu.Unlock()
+	return count, err
+}
+
+// Count returns how many transcripts are currently indexed -
+// melvin_transcripts_stored_total's source.
+func (ix *TranscriptIndex) Count() int {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	return len(ix.data.Docs)
+}
+
+// GC deletes every transcript (file and index entry) older than
+// retentionDays. retentionDays <= 0 - MELVIN_TX_RETENTION_DAYS unset -
+// disables it.
+func (ix *TranscriptIndex) GC(retentionDays int) (int, error) {
+	if retentionDays <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays).Unix()
+
+	ix.mu.Lock()
+	var stale []int64
+	for _, d := range ix.data.Docs {
+		if d.TS < cutoff {
+			stale = append(stale, d.TS)
+		}
+	}
+	ix.mu.Unlock()
+
+	removed := 0
+	for _, ts := range stale {
+		path := filepath.Join(ix.store.Root, "transcripts", fmt.Sprintf("%d.transcript", ts))
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err := ix.Remove(ts); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// docScore accumulates one document's BM25 total across every query term
+// it matched, plus the position of its rarest (highest-idf) matching term
+// to anchor the result's snippet on.
+type docScore struct {
+	score     float64
+	anchor    int
+	anchorIDF float64
+}
+
+// Search tokenizes query the same way Add does, scores every candidate
+// document with BM25, and returns the top limit hits with a snippet
+// windowed around each one's best-matching position. lang == "" matches
+// every language.
+func (ix *TranscriptIndex) Search(query, lang string, limit int) ([]SearchHit, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	_, qterms := tokenize(query)
+
+	ix.mu.Lock()
+	n := len(ix.data.Docs)
+	if n == 0 {
+		ix.mu.Unlock()
+		return nil, nil
+	}
+	var totalLen int
+	for _, d := range ix.data.Docs {
+		totalLen += d.Length
+	}
+	avgdl := float64(totalLen) / float64(n)
+	if avgdl == 0 {
+		avgdl = 1
+	}
+
+	docs := map[string]*docScore{}
+	for _, term := range qterms {
+		if term == "" {
+			continue
+		}
+		postings := ix.data.Terms[term]
+		df := len(postings)
+		if df == 0 {
+			continue
+		}
+		idf := math.Log(1 + (float64(n)-float64(df)+0.5)/(float64(df)+0.5))
+		for _, p := range postings {
+			d, ok := ix.data.Docs[strconv.FormatInt(p.TS, 10)]
+			if !ok || len(p.Positions) == 0 {
+				continue
+			}
+			if lang != "" && d.Lang != lang {
+				continue
+			}
+			tf := float64(len(p.Positions))
+			dl := float64(d.Length)
+			denom := tf + bm25K1*(1-bm25B+bm25B*dl/avgdl)
+			score := idf * (tf * (bm25K1 + 1)) / denom
+
+			key := strconv.FormatInt(p.TS, 10)
+			ds, ok := docs[key]
+			if !ok {
+				ds = &docScore{}
+				docs[key] = ds
+			}
+			ds.score += score
+			if idf > ds.anchorIDF {
+				ds.anchorIDF = idf
+				ds.anchor = p.Positions[0]
+			}
+		}
+	}
+	ix.mu.Unlock()
+
+	type ranked struct {
+		key   string
+		score float64
+	}
+	all := make([]ranked, 0, len(docs))
+	for k, ds := range docs {
+		all = append(all, ranked{k, ds.score})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].score > all[j].score })
+	if len(all) > limit {
+		all = all[:limit]
+	}
+
+	hits := make([]SearchHit, 0, len(all))
+	for _, r := range all {
+		ts, _ := strconv.ParseInt(r.key, 10, 64)
+		ix.mu.Lock()
+		d := ix.data.Docs[r.key]
+		ix.mu.Unlock()
+		hits = append(hits, SearchHit{
+			TS:      ts,
+			ID:      d.ID,
+			Title:   d.Title,
+			Lang:    d.Lang,
+			Score:   r.score,
+			Snippet: ix.snippet(ts, docs[r.key].anchor),
+		})
+	}
+	return hits, nil
+}
+
+// snippet re-reads ts's transcript and windows ±10 tokens around anchor,
+// the position Search found its rarest matching term at.
+func (ix *TranscriptIndex) snippet(ts int64, anchor int) string {
+	rec, err := ix.loadRecord(ts)
+	if err != nil {
+		return ""
+	}
+	words, _ := tokenize(rec.Text)
+	if len(words) == 0 {
+		return ""
+	}
+	start := anchor - 10
+	if start < 0 {
+		start = 0
+	}
+	end := anchor + 11
+	if end > len(words) {
+		end = len(words)
+	}
+	if start >= len(words) {
+		start = len(words) - 1
+	}
+	return strings.Join(words[start:end], " ")
+}
+
+func (ix *TranscriptIndex) loadRecord(ts int64) (*TranscriptRecord, error) {
+	path := filepath.Join(ix.store.Root, "transcripts", fmt.Sprintf("%d.transcript", ts))
+	var rec TranscriptRecord
+	if err := ix.store.ReadJSON(path, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+// saveLocked persists ix.data via Store.WriteJSON's tmp-then-rename
+// pattern, the same atomic write persistTranscript itself relies on.
+// Caller must hold ix.mu.
+func (ix *TranscriptIndex) saveLocked() error {
+	return ix.store.WriteJSON(ix.path, ix.data)
+}
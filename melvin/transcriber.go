@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kkdai/youtube/v2"
+)
+
+/* =========================
+   transcriber chain
+   ========================= */
+
+// Transcriber is one way of turning a YouTube video into a
+// TranscriptRecord. CaptionTranscriber is YTTranscriberModule's original
+// behavior; WhisperTranscriber and OpenAITranscriber fall back to
+// actually transcribing the audio for the common case where the uploader
+// never turned captions on.
+type Transcriber interface {
+	Name() string
+	Fetch(ctx *Context, videoID, lang string) (*TranscriptRecord, error)
+}
+
+// StreamingTranscriber is the subset of the chain that can report partial
+// text as it decodes, instead of only a finished TranscriptRecord - what
+// /api/transcript?stream=1 uses to flush progress to the client.
+type StreamingTranscriber interface {
+	Transcriber
+	FetchStream(ctx *Context, videoID, lang string, onPartial func(string)) (*TranscriptRecord, error)
+}
+
+func newRecord(videoID, lang, title, text string) *TranscriptRecord {
+	return &TranscriptRecord{
+		TS:    time.Now().Unix(),
+		ID:    videoID,
+		URL:   "https://www.youtube.com/watch?v=" + videoID,
+		Title: title,
+		Lang:  lang,
+		Text:  text,
+	}
+}
+
+// CaptionTranscriber reads whatever caption track YouTube already has -
+// no fallback, no audio download.
+type CaptionTranscriber struct{}
+
+func (CaptionTranscriber) Name() string { return "captions" }
+
+func (CaptionTranscriber) Fetch(ctx *Context, videoID, lang string) (*TranscriptRecord, error) {
+	v, err := ctx.YT.GetVideo(videoID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch video metadata")
+	}
+	text, err := transcriptText(ctx, v, lang)
+	if err != nil {
+		return nil, err
+	}
+	return newRecord(videoID, lang, v.Title, text), nil
+}
+
+// audioStream fetches videoID's metadata and opens its best available
+// audio-only format - the shared first step WhisperTranscriber and
+// OpenAITranscriber both need before they can POST anything to an ASR
+// backend. Caller must close the returned stream.
+func audioStream(ctx *Context, videoID string) (io.ReadCloser, *youtube.Video, error) {
+	v, err := ctx.YT.GetVideo(videoID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch video metadata")
+	}
+	formats := v.Formats.WithAudioChannels()
+	if len(formats) == 0 {
+		return nil, nil, fmt.Errorf("no audio-only format available")
+	}
+	stream, _, err := ctx.YT.GetStream(v, &formats[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("open audio stream: %w", err)
+	}
+	return stream, v, nil
+}
+
+// WhisperTranscriber POSTs videoID's audio straight through to a
+// self-hosted Whisper/faster-whisper HTTP server at endpoint, which is
+// expected to stream back one JSON object per line - {"text": "..."} per
+// decoded segment - the same line-delimited shape Ollama's /api/generate
+// uses.
+type WhisperTranscriber struct {
+	endpoint string
+}
+
+func (t *WhisperTranscriber) Name() string { return "whisper" }
+
+func (t *WhisperTranscriber) Fetch(ctx *Context, videoID, lang string) (*TranscriptRecord, error) {
+	return t.fetch(ctx, videoID, lang, nil)
+}
+
+func (t *WhisperTranscriber) FetchStream(ctx *Context, videoID, lang string, onPartial func(string)) (*TranscriptRecord, error) {
+	return t.fetch(ctx, videoID, lang, onPartial)
+}
+
+func (t *WhisperTranscriber) fetch(ctx *Context, videoID, lang string, onPartial func(string)) (*TranscriptRecord, error) {
+	if t.endpoint == "" {
+		return nil, fmt.Errorf("MELVIN_WHISPER_ENDPOINT not configured")
+	}
+
+	stream, v, err := audioStream(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	req, err := http.NewRequest(http.MethodPost, t.endpoint+"/transcribe?lang="+url.QueryEscape(lang), stream)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("whisper endpoint returned %s", resp.Status)
+	}
+
+	var b strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var chunk struct {
+			Text string `json:"text"`
+		}
+		if err := json.Unmarshal(line, &chunk); err != nil || chunk.Text == "" {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(chunk.Text)
+		if onPartial != nil {
+			onPartial(chunk.Text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return newRecord(videoID, lang, v.Title, b.String()), nil
+}
+
+// OpenAITranscriber POSTs videoID's audio as multipart form data to any
+// server implementing the OpenAI /v1/audio/transcriptions API (OpenAI
+// itself, or a local whisper.cpp/faster-whisper server that mimics it).
+type OpenAITranscriber struct {
+	endpoint  string
+	apiKeyEnv string
+	model     string
+}
+
+func (t *OpenAITranscriber) Name() string { return "openai" }
+
+func (t *OpenAITranscriber) Fetch(ctx *Context, videoID, lang string) (*TranscriptRecord, error) {
+	if t.endpoint == "" {
+		return nil, fmt.Errorf("MELVIN_OPENAI_TRANSCRIBE_ENDPOINT not configured")
+	}
+
+	stream, v, err := audioStream(ctx, videoID)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", videoID+".audio")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, stream); err != nil {
+		return nil, err
+	}
+	if t.model != "" {
+		_ = writer.WriteField("model", t.model)
+	}
+	if lang != "" {
+		_ = writer.WriteField("language", lang)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, t.endpoint+"/v1/audio/transcriptions", &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if t.apiKeyEnv != "" {
+		if key := os.Getenv(t.apiKeyEnv); key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("openai transcriptions endpoint returned %s", resp.Status)
+	}
+
+	var result struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return newRecord(videoID, lang, v.Title, result.Text), nil
+}
+
+// TranscriberChain tries each Transcriber in order, returning the first
+// success and recording every earlier failure into the winning record's
+// Meta.
+type TranscriberChain struct {
+	chain []Transcriber
+}
+
+func newTranscriberChain(chain []Transcriber) *TranscriberChain {
+	return &TranscriberChain{chain: chain}
+}
+
+func (c *TranscriberChain) Fetch(ctx *Context, videoID, lang string) (*TranscriptRecord, error) {
+	tried := map[string]string{}
+	for _, t := range c.chain {
+		rec, err := t.Fetch(ctx, videoID, lang)
+		if err != nil {
+			tried["tried."+t.Name()] = err.Error()
+			continue
+		}
+		rec.Meta = mergeMeta(tried, map[string]string{"source": t.Name()})
+		return rec, nil
+	}
+	return nil, fmt.Errorf("no transcriber in the chain succeeded: %v", tried)
+}
+
+// FetchStream is Fetch, but a chain member implementing
+// StreamingTranscriber reports its partial text through onPartial as it
+// decodes; one that doesn't just reports its whole result as a single
+// partial once it finishes.
+func (c *TranscriberChain) FetchStream(ctx *Context, videoID, lang string, onPartial func(string)) (*TranscriptRecord, error) {
+	tried := map[string]string{}
+	for _, t := range c.chain {
+		var rec *TranscriptRecord
+		var err error
+		if st, ok := t.(StreamingTranscriber); ok {
+			rec, err = st.FetchStream(ctx, videoID, lang, onPartial)
+		} else {
+			rec, err = t.Fetch(ctx, videoID, lang)
+			if err == nil && onPartial != nil {
+				onPartial(rec.Text)
+			}
+		}
+		if err != nil {
+			tried["tried."+t.Name()] = err.Error()
+			continue
+		}
+		rec.Meta = mergeMeta(tried, map[string]string{"source": t.Name()})
+		return rec, nil
+	}
+	return nil, fmt.Errorf("no transcriber in the chain succeeded: %v", tried)
+}
+
+func mergeMeta(maps ...map[string]string) map[string]string {
+	out := map[string]string{}
+	for _, m := range maps {
+		for k, v := range m {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// parseTranscriberChain builds the chain MELVIN_TRANSCRIBER_CHAIN
+// describes - a comma-separated list drawn from "captions", "whisper"
+// and "openai" - falling back to captions-only if spec names none of
+// them. whisper and openai read their endpoints from
+// MELVIN_WHISPER_ENDPOINT and MELVIN_OPENAI_TRANSCRIBE_ENDPOINT; an
+// endpoint left unset just means that backend always fails over to the
+// next one in the chain.
+func parseTranscriberChain(spec string) []Transcriber {
+	var chain []Transcriber
+	for _, name := range strings.Split(spec, ",") {
+		switch strings.TrimSpace(name) {
+		case "captions":
+			chain = append(chain, CaptionTranscriber{})
+		case "whisper":
+			chain = append(chain, &WhisperTranscriber{endpoint: getenv("MELVIN_WHISPER_ENDPOINT", "")})
+		case "openai":
+			chain = append(chain, &OpenAITranscriber{
+				endpoint:  getenv("MELVIN_OPENAI_TRANSCRIBE_ENDPOINT", ""),
+				apiKeyEnv: getenv("MELVIN_OPENAI_API_KEY_ENV", "OPENAI_API_KEY"),
+				model:     getenv("MELVIN_OPENAI_TRANSCRIBE_MODEL", "whisper-1"),
+			})
+		}
+	}
+	if len(chain) == 0 {
+		chain = append(chain, CaptionTranscriber{})
+	}
+	return chain
+}
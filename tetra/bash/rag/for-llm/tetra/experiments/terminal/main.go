@@ -4,23 +4,71 @@ import (
 	"image"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"time"
 
 	"gioui.org/app"
 	"gioui.org/font/gofont"
+	"gioui.org/io/event"
+	"gioui.org/io/key"
+	"gioui.org/io/pointer"
 	"gioui.org/layout"
 	"gioui.org/op"
+	"gioui.org/op/clip"
 	"gioui.org/text"
 	"gioui.org/unit"
 	"gioui.org/widget"
 	"gioui.org/widget/material"
 )
 
+// defaultSplit is Split's value on first launch and what double-clicking
+// the splitter resets it to.
+const defaultSplit = 0.38
+
+// splitMin/splitMax clamp Split so neither pane can be dragged away
+// entirely; minPaneWidthDp additionally keeps the handle from being
+// dragged past either pane's minimum usable width.
+const (
+	splitMin          = 0.1
+	splitMax          = 0.9
+	minPaneWidthDp    = 120
+	splitNudge        = 0.02
+	doubleClickWindow = 350 * time.Millisecond
+)
+
 type Model struct {
 	LeftText   string
 	RightText  string
 	Split      float32 // 0..1, fraction for left pane width
 	dragging   bool
 	dragStartX float32
+	// dragStartSplit is Split's value when the current drag began, so
+	// deltas are computed from a fixed baseline rather than accumulating
+	// rounding error frame to frame.
+	dragStartSplit float32
+	lastClickAt    time.Duration
+
+	Left  paneState
+	Right paneState
+
+	// ToggleWrapKey flips the focused pane between wrap and no-wrap.
+	ToggleWrapKey string
+
+	// cheatsheetOpen shows/hides the "?" keybinding overlay.
+	cheatsheetOpen bool
+}
+
+// paneState is the scroll/wrap state kept per pane: List drives vertical
+// scrolling as before, OriginX is the horizontal scroll offset (in
+// pixels) used only when WrapMode is off, and focused tracks whether
+// this pane currently owns keyboard focus, since losing focus resets
+// OriginX to 0.
+type paneState struct {
+	List     widget.List
+	OriginX  int
+	WrapMode bool
+	focused  bool
 }
 
 func main() {
@@ -41,13 +89,13 @@ func loop(w *app.Window) error {
 
 	var ops op.Ops
 	m := &Model{
-		LeftText:  "left pane\n…fzgrep results here…",
-		RightText: "right pane\n…file preview content…",
-		Split:     0.38,
+		LeftText:      "left pane\n…fzgrep results here…",
+		RightText:     "right pane\n…file preview content…",
+		Split:         loadSplit(),
+		ToggleWrapKey: "W",
 	}
-	var leftList, rightList widget.List
-	leftList.Axis = layout.Vertical
-	rightList.Axis = layout.Vertical
+	m.Left.List.Axis = layout.Vertical
+	m.Right.List.Axis = layout.Vertical
 
 	for {
 		switch e := w.Event().(type) {
@@ -55,36 +103,272 @@ func loop(w *app.Window) error {
 			return e.Err
 		case app.FrameEvent:
 			gtx := app.NewContext(&ops, e)
-			layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
-				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					// Left pane width = Split * window width
-					w := int(float32(gtx.Constraints.Max.X) * m.Split)
-					gtx.Constraints.Max.X = w
-					gtx.Constraints.Min.X = w
-					return pane(gtx, th, &leftList, m.LeftText, unit.Sp(12)) // small font
-				}),
-				layout.Rigid(func(gtx layout.Context) layout.Dimensions {
-					// Splitter handle (draggable)
-					const handle = 6
-					gtx.Constraints.Min.X, gtx.Constraints.Max.X = handle, handle
-					return layout.Dimensions{Size: image.Pt(handle, gtx.Constraints.Max.Y)}
+			handleSplitKeys(gtx, m)
+			handleCheatsheetKey(gtx, m)
+			windowWidth := gtx.Constraints.Max.X
+			minFrac := float32(gtx.Dp(minPaneWidthDp)) / float32(windowWidth)
+
+			layout.Stack{}.Layout(gtx,
+				layout.Expanded(func(gtx layout.Context) layout.Dimensions {
+					return layout.Flex{Axis: layout.Horizontal}.Layout(gtx,
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							// Left pane width = Split * window width
+							w := int(float32(gtx.Constraints.Max.X) * m.Split)
+							gtx.Constraints.Max.X = w
+							gtx.Constraints.Min.X = w
+							return pane(gtx, th, &m.Left, m.LeftText, unit.Sp(12), m.ToggleWrapKey) // small font
+						}),
+						layout.Rigid(func(gtx layout.Context) layout.Dimensions {
+							return splitter(gtx, m, windowWidth, minFrac)
+						}),
+						layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
+							return pane(gtx, th, &m.Right, m.RightText, unit.Sp(16), m.ToggleWrapKey) // larger font
+						}),
+					)
 				}),
-				layout.Flexed(1, func(gtx layout.Context) layout.Dimensions {
-					return pane(gtx, th, &rightList, m.RightText, unit.Sp(16)) // larger font
+				layout.Stacked(func(gtx layout.Context) layout.Dimensions {
+					if !m.cheatsheetOpen {
+						return layout.Dimensions{}
+					}
+					return cheatsheetOverlayWidget(gtx, th, m)
 				}),
 			)
 			e.Frame(gtx.Ops)
 		}
 	}
-	return nil
 }
 
-func pane(gtx layout.Context, th *material.Theme, list *widget.List, text string, size unit.Sp) layout.Dimensions {
-	return material.List(th, list).Layout(gtx, 1, func(gtx layout.Context, _ int) layout.Dimensions {
+// splitterTag identifies the draggable handle between the two panes for
+// pointer event routing.
+var splitterTag = new(int)
+
+// splitter draws the 6px handle between the two panes and wires it to
+// pointer events: drag updates m.Split in real time (clamped to
+// [splitMin, splitMax] and to minFrac per side so neither pane can be
+// squeezed below minPaneWidthDp), a double-click resets Split to
+// defaultSplit, and hovering shows a column-resize cursor.
+func splitter(gtx layout.Context, m *Model, windowWidth int, minFrac float32) layout.Dimensions {
+	const handle = 6
+	gtx.Constraints.Min.X, gtx.Constraints.Max.X = handle, handle
+	size := image.Pt(handle, gtx.Constraints.Max.Y)
+
+	defer clip.Rect{Max: size}.Push(gtx.Ops).Pop()
+	event.Op(gtx.Ops, splitterTag)
+	pointer.CursorColResize.Add(gtx.Ops)
+
+	for {
+		ev, ok := gtx.Event(pointer.Filter{
+			Target: splitterTag,
+			Kinds:  pointer.Press | pointer.Drag | pointer.Release | pointer.Cancel,
+		})
+		if !ok {
+			break
+		}
+		pe, ok := ev.(pointer.Event)
+		if !ok {
+			continue
+		}
+		switch pe.Kind {
+		case pointer.Press:
+			if pe.Time-m.lastClickAt < doubleClickWindow {
+				m.Split = defaultSplit
+				saveSplit(m.Split)
+				m.dragging = false
+				continue
+			}
+			m.lastClickAt = pe.Time
+			m.dragging = true
+			m.dragStartX = pe.Position.X
+			m.dragStartSplit = m.Split
+		case pointer.Drag:
+			if !m.dragging {
+				continue
+			}
+			deltaX := pe.Position.X - m.dragStartX
+			frac := m.dragStartSplit + deltaX/float32(windowWidth)
+			if frac < minFrac {
+				frac = minFrac
+			}
+			if frac > 1-minFrac {
+				frac = 1 - minFrac
+			}
+			m.Split = clampSplit(frac)
+		case pointer.Release, pointer.Cancel:
+			if m.dragging {
+				m.dragging = false
+				saveSplit(m.Split)
+			}
+		}
+	}
+
+	return layout.Dimensions{Size: size}
+}
+
+// handleSplitKeys applies Ctrl+[ / Ctrl+] split nudges for the whole
+// window (not tied to pane focus, since the splitter itself can't hold
+// keyboard focus).
+func handleSplitKeys(gtx layout.Context, m *Model) {
+	event.Op(gtx.Ops, m)
+	for {
+		ev, ok := gtx.Event(
+			key.Filter{Name: "[", Required: key.ModCtrl},
+			key.Filter{Name: "]", Required: key.ModCtrl},
+		)
+		if !ok {
+			break
+		}
+		ke, ok := ev.(key.Event)
+		if !ok || ke.State != key.Press {
+			continue
+		}
+		switch ke.Name {
+		case "[":
+			m.Split -= splitNudge
+		case "]":
+			m.Split += splitNudge
+		}
+		m.Split = clampSplit(m.Split)
+		saveSplit(m.Split)
+	}
+}
+
+// cheatsheetText is the static keybinding reference shown by the "?"
+// overlay, grouped the same way as the tui package's cheatsheet: by
+// context, then by what the binding does.
+const cheatsheetText = `Keybindings
+
+Pane (focused)
+  Shift+Wheel, Left/Right  pan horizontally (no-wrap mode)
+  W                        toggle wrap / no-wrap
+
+Splitter
+  drag                     resize panes
+  double-click             reset to default split
+  Ctrl+[ / Ctrl+]          nudge split
+
+?                          toggle this overlay
+Esc                        close this overlay`
+
+// cheatsheetTag identifies the window-level key target used for "?" and
+// Esc, independent of whichever pane currently has focus.
+var cheatsheetTag = new(int)
+
+// handleCheatsheetKey toggles the overlay on "?" and closes it on Esc,
+// both regardless of pane focus.
+func handleCheatsheetKey(gtx layout.Context, m *Model) {
+	event.Op(gtx.Ops, cheatsheetTag)
+	for {
+		ev, ok := gtx.Event(
+			key.Filter{Name: "?"},
+			key.Filter{Name: key.NameEscape},
+		)
+		if !ok {
+			break
+		}
+		ke, ok := ev.(key.Event)
+		if !ok || ke.State != key.Press {
+			continue
+		}
+		switch ke.Name {
+		case "?":
+			m.cheatsheetOpen = !m.cheatsheetOpen
+		case key.NameEscape:
+			m.cheatsheetOpen = false
+		}
+	}
+}
+
+// cheatsheetOverlayWidget draws cheatsheetText centered in a dimmed
+// panel on top of the pane layout.
+func cheatsheetOverlayWidget(gtx layout.Context, th *material.Theme, m *Model) layout.Dimensions {
+	return layout.Center.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+		return widget.Border{Width: unit.Dp(1)}.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+			return layout.UniformInset(unit.Dp(16)).Layout(gtx, func(gtx layout.Context) layout.Dimensions {
+				lbl := material.Label(th, unit.Sp(14), cheatsheetText)
+				lbl.MaxLines = 0
+				return lbl.Layout(gtx)
+			})
+		})
+	})
+}
+
+func clampSplit(s float32) float32 {
+	if s < splitMin {
+		return splitMin
+	}
+	if s > splitMax {
+		return splitMax
+	}
+	return s
+}
+
+// splitConfigPath returns where the last-used Split ratio is persisted
+// between launches.
+func splitConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "fzgrep-viewer")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "split"), nil
+}
+
+// loadSplit reads the persisted Split ratio, falling back to
+// defaultSplit if none is saved yet or it fails to parse.
+func loadSplit() float32 {
+	path, err := splitConfigPath()
+	if err != nil {
+		return defaultSplit
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return defaultSplit
+	}
+	f, err := strconv.ParseFloat(string(b), 32)
+	if err != nil {
+		return defaultSplit
+	}
+	return clampSplit(float32(f))
+}
+
+// saveSplit persists ratio so the next launch restores it.
+func saveSplit(ratio float32) {
+	path, err := splitConfigPath()
+	if err != nil {
+		return
+	}
+	_ = os.WriteFile(path, []byte(strconv.FormatFloat(float64(ratio), 'f', -1, 32)), 0o644)
+}
+
+// paneScrollStep is how many pixels one arrow-key press or wheel tick
+// moves OriginX.
+const paneScrollStep = 24
+
+// pane renders text inside a vertically-scrollable list, as before, but
+// now also tracks horizontal scroll (OriginX) and a wrap/no-wrap toggle
+// bound to toggleWrapKey. Horizontal scrolling only applies in no-wrap
+// mode - when wrapped, there's nothing to pan past the pane's width.
+func pane(gtx layout.Context, th *material.Theme, ps *paneState, text string, size unit.Sp, toggleWrapKey string) layout.Dimensions {
+	handlePaneEvents(gtx, ps, toggleWrapKey)
+
+	return material.List(th, &ps.List).Layout(gtx, 1, func(gtx layout.Context, _ int) layout.Dimensions {
 		inset := layout.UniformInset(unit.Dp(8))
 		return inset.Layout(gtx, func(gtx layout.Context) layout.Dimensions {
 			lbl := material.Label(th, size, text)
-			lbl.MaxLines = 0
+			if ps.WrapMode {
+				lbl.MaxLines = 0
+			} else {
+				lbl.MaxLines = 1
+				lbl.WrapPolicy = text.WrapWords
+			}
+
+			if !ps.WrapMode && ps.OriginX != 0 {
+				defer op.Offset(image.Pt(-ps.OriginX, 0)).Push(gtx.Ops).Pop()
+			}
+
 			d := lbl.Layout(gtx)
 			// Ensure we always consume space to enable scrolling
 			if d.Size.Y < gtx.Constraints.Max.Y {
@@ -94,3 +378,60 @@ func pane(gtx layout.Context, th *material.Theme, list *widget.List, text string
 		})
 	})
 }
+
+// handlePaneEvents registers ps as a focus/scroll target for this frame
+// and applies Shift+Wheel and Left/Right-arrow events to ps.OriginX, and
+// toggleWrapKey presses to ps.WrapMode. Losing focus resets OriginX to
+// 0, matching the left/right-scroll behavior common to terminal TUIs.
+func handlePaneEvents(gtx layout.Context, ps *paneState, toggleWrapKey string) {
+	defer clip.Rect{Max: gtx.Constraints.Max}.Push(gtx.Ops).Pop()
+	event.Op(gtx.Ops, ps)
+
+	for {
+		ev, ok := gtx.Event(
+			pointer.Filter{Target: ps, Kinds: pointer.Scroll},
+			key.Filter{Focus: ps, Name: key.NameLeftArrow},
+			key.Filter{Focus: ps, Name: key.NameRightArrow},
+			key.Filter{Focus: ps, Name: key.Name(toggleWrapKey)},
+			key.FocusFilter{Target: ps},
+		)
+		if !ok {
+			break
+		}
+		switch ev := ev.(type) {
+		case key.FocusEvent:
+			ps.focused = ev.Focus
+			if !ps.focused {
+				ps.OriginX = 0
+			}
+		case pointer.Event:
+			if ev.Kind == pointer.Scroll && ev.Modifiers.Contain(key.ModShift) {
+				ps.scrollX(int(ev.Scroll.Y))
+			}
+		case key.Event:
+			if ev.State != key.Press {
+				continue
+			}
+			switch ev.Name {
+			case key.NameLeftArrow:
+				ps.scrollX(-paneScrollStep)
+			case key.NameRightArrow:
+				ps.scrollX(paneScrollStep)
+			case key.Name(toggleWrapKey):
+				ps.WrapMode = !ps.WrapMode
+				if ps.WrapMode {
+					ps.OriginX = 0
+				}
+			}
+		}
+	}
+}
+
+// scrollX moves OriginX by delta, clamped to 0 so the pane can't scroll
+// past its left edge; no-wrap mode is the only mode this applies to.
+func (ps *paneState) scrollX(delta int) {
+	ps.OriginX += delta
+	if ps.OriginX < 0 {
+		ps.OriginX = 0
+	}
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Panel is anything that can render itself from the current State and
+// describe its own tab label. FileTreePanel, EditorPanel and VimPanel all
+// satisfy this implicitly.
+type Panel interface {
+	Render(state State)
+	TabLabel(state State) string
+}
+
+// layoutFile is where DockView persists the last-used split ratio, since
+// this build has no broader config file of its own to round-trip through.
+const layoutFile = ".tgo-layout.json"
+
+// DockView renders the left/right docks side by side and keeps their split
+// in sync with State.Layout.SplitRatio, both ways: Render() applies the
+// stored ratio to the tview.Flex proportions, and mouse drags / keybindings
+// dispatch ResizeDockAction to update the store.
+type DockView struct {
+	*tview.Flex
+	store *Store
+	app   *tview.Application
+
+	left, right *tview.Flex
+
+	dragging     bool
+	dragStartX   int
+	dragStartRat float64
+}
+
+// NewDockView lays out leftPrimitives and rightPrimitives (keyed by panel
+// name, in display order) as two flexed columns and wires up resizing.
+func NewDockView(store *Store, app *tview.Application, leftOrder []string, leftPrimitives map[string]tview.Primitive, rightOrder []string, rightPrimitives map[string]tview.Primitive) *DockView {
+	d := &DockView{
+		Flex:  tview.NewFlex().SetDirection(tview.FlexColumn),
+		store: store,
+		app:   app,
+		left:  tview.NewFlex().SetDirection(tview.FlexRow),
+		right: tview.NewFlex().SetDirection(tview.FlexRow),
+	}
+
+	for _, name := range leftOrder {
+		d.left.AddItem(leftPrimitives[name], 0, 1, false)
+	}
+	for _, name := range rightOrder {
+		d.right.AddItem(rightPrimitives[name], 0, 1, false)
+	}
+
+	d.AddItem(d.left, 0, 1, true).AddItem(d.right, 0, 1, false)
+
+	app.SetMouseCapture(d.handleMouse)
+	app.SetInputCapture(d.handleKey)
+
+	if ratio, ok := loadLayoutRatio(); ok {
+		store.Dispatch(ResizeDockAction{Delta: ratio - store.GetState().Layout.SplitRatio})
+	}
+
+	return d
+}
+
+// Render applies the store's current split ratio to the two dock columns.
+func (d *DockView) Render(state State) {
+	_, _, width, _ := d.GetRect()
+	if width <= 0 {
+		return
+	}
+	leftWidth := int(float64(width) * state.Layout.SplitRatio)
+	d.ResizeItem(d.left, leftWidth, 1)
+	d.ResizeItem(d.right, width-leftWidth, 1)
+}
+
+// handleMouse detects click-and-drag on the border between the two docks
+// and dispatches ResizeDockAction proportional to the drag distance.
+func (d *DockView) handleMouse(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+	x, _ := event.Position()
+	_, _, width, _ := d.GetRect()
+	if width <= 0 {
+		return event, action
+	}
+	borderX := int(float64(width) * d.store.GetState().Layout.SplitRatio)
+
+	switch action {
+	case tview.MouseLeftDown:
+		if abs(x-borderX) <= 1 {
+			d.dragging = true
+			d.dragStartX = x
+			d.dragStartRat = d.store.GetState().Layout.SplitRatio
+			return nil, action
+		}
+	case tview.MouseMove:
+		if d.dragging {
+			delta := float64(x-d.dragStartX) / float64(width)
+			newRatio := d.dragStartRat + delta
+			d.store.Dispatch(ResizeDockAction{Delta: newRatio - d.store.GetState().Layout.SplitRatio})
+			return nil, action
+		}
+	case tview.MouseLeftUp:
+		if d.dragging {
+			d.dragging = false
+			saveLayoutRatio(d.store.GetState().Layout.SplitRatio)
+			return nil, action
+		}
+	}
+	return event, action
+}
+
+// handleKey provides keyboard alternatives to dragging: Ctrl+Left/Right
+// nudge the dock split, Ctrl+Up/Down nudge the focused panel's height.
+func (d *DockView) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	if event.Modifiers()&tcell.ModCtrl == 0 {
+		return event
+	}
+	const dockStep = 0.02
+	const panelStep = 1
+
+	switch event.Key() {
+	case tcell.KeyLeft:
+		d.store.Dispatch(ResizeDockAction{Delta: -dockStep})
+		saveLayoutRatio(d.store.GetState().Layout.SplitRatio)
+		return nil
+	case tcell.KeyRight:
+		d.store.Dispatch(ResizeDockAction{Delta: dockStep})
+		saveLayoutRatio(d.store.GetState().Layout.SplitRatio)
+		return nil
+	case tcell.KeyUp:
+		d.store.Dispatch(ResizePanelAction{PanelName: d.focusedPanelName(), Delta: -panelStep})
+		return nil
+	case tcell.KeyDown:
+		d.store.Dispatch(ResizePanelAction{PanelName: d.focusedPanelName(), Delta: panelStep})
+		return nil
+	}
+	return event
+}
+
+// focusedPanelName returns the name of the currently active panel in the
+// active dock, which is what Ctrl+Up/Down should resize.
+func (d *DockView) focusedPanelName() string {
+	state := d.store.GetState()
+	return state.ActivePanels[state.ActiveDock]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// loadLayoutRatio reads a previously persisted split ratio, if any.
+func loadLayoutRatio() (float64, bool) {
+	data, err := os.ReadFile(layoutFile)
+	if err != nil {
+		return 0, false
+	}
+	var layout struct {
+		SplitRatio float64 `json:"split_ratio"`
+	}
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return 0, false
+	}
+	return layout.SplitRatio, true
+}
+
+// saveLayoutRatio persists the split ratio so the next run starts where
+// this one left off.
+func saveLayoutRatio(ratio float64) {
+	layout := struct {
+		SplitRatio float64 `json:"split_ratio"`
+	}{SplitRatio: ratio}
+	data, err := json.Marshal(layout)
+	if err != nil {
+		return
+	}
+	path, err := filepath.Abs(layoutFile)
+	if err != nil {
+		path = layoutFile
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		log.Printf("dock: save layout: %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watcherDebounce coalesces a burst of writes to the same file (many
+// editors/formatters save in several small writes) into a single
+// FileChangedOnDiskAction dispatch.
+const watcherDebounce = 150 * time.Millisecond
+
+// Watcher owns the fsnotify.Watcher backing NewFSWatcherMiddleware and
+// the goroutine that drains its event channel, so the middleware's
+// caller has something concrete to Stop when the app shuts down.
+type Watcher struct {
+	fsw  *fsnotify.Watcher
+	done chan struct{}
+
+	mu      sync.Mutex
+	pending map[string]*time.Timer
+}
+
+// Stop tears the watcher down cleanly: it stops draining fsnotify
+// events, cancels any debounce timers still pending, and closes the
+// underlying fsnotify.Watcher (which stops the kernel-level watches).
+func (w *Watcher) Stop() {
+	close(w.done)
+	w.mu.Lock()
+	for _, t := range w.pending {
+		t.Stop()
+	}
+	w.pending = nil
+	w.mu.Unlock()
+	_ = w.fsw.Close()
+}
+
+// NewFSWatcherMiddleware watches State.Pwd (recursively) and State.CurrentFile
+// for on-disk writes and dispatches FileChangedOnDiskAction (debounced by
+// watcherDebounce) so panels can reload or flag a conflict. It is installed
+// once via Store.AddMiddleware; the returned Watcher's Stop method tears
+// down the background goroutine and the underlying fsnotify watches, and
+// should be deferred by whatever owns the Store's lifetime.
+func NewFSWatcherMiddleware() (Middleware, *Watcher) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		log.Printf("fs watcher: %v", err)
+		noop := func(store *Store) func(next Dispatcher) Dispatcher {
+			return func(next Dispatcher) Dispatcher { return next }
+		}
+		return noop, &Watcher{done: make(chan struct{})}
+	}
+
+	w := &Watcher{
+		fsw:     fsw,
+		done:    make(chan struct{}),
+		pending: make(map[string]*time.Timer),
+	}
+
+	watchedFile := ""
+
+	mw := func(store *Store) func(next Dispatcher) Dispatcher {
+		go w.run(store)
+		w.addWatchRoot(store.GetState().Pwd)
+
+		return func(next Dispatcher) Dispatcher {
+			return func(action Action) {
+				next(action)
+
+				state := store.GetState()
+				if state.CurrentFile != watchedFile {
+					if watchedFile != "" {
+						_ = w.fsw.Remove(watchedFile)
+					}
+					watchedFile = state.CurrentFile
+					if watchedFile != "" {
+						if err := w.fsw.Add(watchedFile); err != nil {
+							log.Printf("fs watcher: watch %s: %v", watchedFile, err)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return mw, w
+}
+
+// addWatchRoot walks pwd and watches every directory under it; fsnotify only
+// watches the directories it's told about, not their descendants.
+func (w *Watcher) addWatchRoot(pwd string) {
+	if pwd == "" {
+		return
+	}
+	_ = walkDirs(pwd, func(dir string) {
+		if err := w.fsw.Add(dir); err != nil {
+			log.Printf("fs watcher: watch %s: %v", dir, err)
+		}
+	})
+}
+
+func walkDirs(root string, visit func(dir string)) error {
+	visit(root)
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			_ = walkDirs(root+"/"+e.Name(), visit)
+		}
+	}
+	return nil
+}
+
+// run drains fsw's Events/Errors channels until Stop closes w.done. A
+// directory rename (the old name no longer resolving, or an explicit
+// Rename op) drops that watch and re-walks Pwd to pick up its
+// replacement; a write to the current file is debounced and dispatched
+// as FileChangedOnDiskAction.
+func (w *Watcher) run(store *Store) {
+	for {
+		select {
+		case <-w.done:
+			return
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handleEvent(store, event)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("fs watcher: %v", err)
+		}
+	}
+}
+
+func (w *Watcher) handleEvent(store *Store, event fsnotify.Event) {
+	if event.Op&fsnotify.Rename != 0 {
+		// The watched path no longer refers to the same file/directory
+		// (it or an ancestor was renamed out from under us); fsnotify
+		// drops the now-stale watch on its own, so just re-establish
+		// coverage of Pwd so the renamed replacement gets watched too.
+		_ = w.fsw.Remove(event.Name)
+		w.addWatchRoot(store.GetState().Pwd)
+		return
+	}
+
+	if event.Op&fsnotify.Write == 0 {
+		return
+	}
+	if event.Name != store.GetState().CurrentFile {
+		return
+	}
+	w.debounce(event.Name, func() {
+		data, err := os.ReadFile(event.Name)
+		if err != nil {
+			return
+		}
+		store.Dispatch(FileChangedOnDiskAction{Path: event.Name, Content: string(data)})
+	})
+}
+
+// debounce delays fn by watcherDebounce, restarting the delay if called
+// again for the same path before it fires - collapsing a burst of writes
+// to one dispatch instead of one per write.
+func (w *Watcher) debounce(path string, fn func()) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pending == nil {
+		return // Stop has already run
+	}
+	if t, ok := w.pending[path]; ok {
+		t.Stop()
+	}
+	w.pending[path] = time.AfterFunc(watcherDebounce, func() {
+		w.mu.Lock()
+		delete(w.pending, path)
+		w.mu.Unlock()
+		fn()
+	})
+}
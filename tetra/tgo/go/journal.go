@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+const journalPath = ".tubes/journal.jsonl"
+
+// journalEntry is the on-disk representation of one dispatched action: a
+// monotonic sequence number, the time it was dispatched, and its JSON
+// encoding tagged with its registered type name so it can be decoded back
+// into the right concrete Action.
+type journalEntry struct {
+	Seq  int64           `json:"seq"`
+	Time time.Time       `json:"time"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// actionTypes maps a registered action's type name to its reflect.Type.
+// Only actions registered here can round-trip through the journal; an
+// unregistered action is still dispatched normally, it's just silently
+// skipped by the journal middleware.
+var actionTypes = map[string]reflect.Type{}
+
+// RegisterActionType makes an action type journal-able. Called once per
+// type from this file's init.
+func RegisterActionType(action Action) {
+	t := reflect.TypeOf(action)
+	actionTypes[t.Name()] = t
+}
+
+func init() {
+	for _, a := range []Action{
+		SwitchDockAction{}, CycleTabAction{}, FileSelectedAction{}, SaveFileAction{},
+		OpenFileAction{}, UpdateEditorContentAction{}, ExecuteCommandAction{},
+		CommandOutputAction{}, FileChangedOnDiskAction{}, SnapshotPanelAction{},
+		UndoAction{}, RedoAction{}, RegisterYankAction{}, PasteAction{},
+		ResizeDockAction{}, ResizePanelAction{}, SetFilterAction{}, ClearFilterAction{},
+	} {
+		RegisterActionType(a)
+	}
+}
+
+// NewJournalMiddleware appends every dispatched action of a registered type
+// to .tubes/journal.jsonl
+// so a session can be replayed or crash-recovered via Store.Replay.
+func NewJournalMiddleware() Middleware {
+	f, err := openJournal(journalPath)
+	if err != nil {
+		log.Printf("journal: %v", err)
+		return func(store *Store) func(next Dispatcher) Dispatcher {
+			return func(next Dispatcher) Dispatcher { return next }
+		}
+	}
+
+	var seq int64 = lastSeq(journalPath)
+
+	return func(store *Store) func(next Dispatcher) Dispatcher {
+		return func(next Dispatcher) Dispatcher {
+			return func(action Action) {
+				next(action)
+				if action == nil {
+					return
+				}
+				if _, ok := actionTypes[reflect.TypeOf(action).Name()]; !ok {
+					return
+				}
+				n := atomic.AddInt64(&seq, 1)
+				if err := appendEntry(f, n, action); err != nil {
+					log.Printf("journal: append: %v", err)
+				}
+			}
+		}
+	}
+}
+
+func openJournal(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+}
+
+// lastSeq returns the highest sequence number already on disk, so a journal
+// resumed after a restart keeps counting up instead of starting over.
+func lastSeq(path string) int64 {
+	entries, err := readJournal(path)
+	if err != nil || len(entries) == 0 {
+		return 0
+	}
+	return entries[len(entries)-1].Seq
+}
+
+func appendEntry(f *os.File, seq int64, action Action) error {
+	data, err := json.Marshal(action)
+	if err != nil {
+		return err
+	}
+	entry := journalEntry{
+		Seq:  seq,
+		Time: time.Now(),
+		Type: reflect.TypeOf(action).Name(),
+		Data: data,
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func readJournal(path string) ([]journalEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []journalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func decodeAction(entry journalEntry) (Action, error) {
+	t, ok := actionTypes[entry.Type]
+	if !ok {
+		return nil, fmt.Errorf("journal: unregistered action type %q", entry.Type)
+	}
+	ptr := reflect.New(t)
+	if err := json.Unmarshal(entry.Data, ptr.Interface()); err != nil {
+		return nil, err
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+// Replay reconstructs State by feeding every journaled action up to and
+// including sequence number upto (or the whole journal, if upto <= 0)
+// through the reducer from a fresh initial state.
+func (s *Store) Replay(path string, upto int) (State, error) {
+	entries, err := readJournal(path)
+	if err != nil {
+		return State{}, err
+	}
+
+	state := State{
+		PanelStates:  make(map[string]PanelState),
+		ActivePanels: make(map[DockID]string),
+		Registers:    make(map[string]string),
+		Layout:       LayoutState{SplitRatio: 0.5, PanelHeights: make(map[string]int)},
+	}
+
+	for _, entry := range entries {
+		if upto > 0 && int(entry.Seq) > upto {
+			break
+		}
+		action, err := decodeAction(entry)
+		if err != nil {
+			return state, err
+		}
+		state = Reducer(state, action)
+	}
+	return state, nil
+}
+
+// InverseAction computes the action that undoes the journal entry at index,
+// using earlier entries to recover whatever prior value the inverse needs
+// (e.g. an edit's inverse is the content from before it). Action types with
+// no defined inverse report ok=false; callers should fall back to Replay
+// instead of dispatching a synthetic action.
+func InverseAction(entries []journalEntry, index int) (action Action, ok bool) {
+	if index < 0 || index >= len(entries) {
+		return nil, false
+	}
+	decoded, err := decodeAction(entries[index])
+	if err != nil {
+		return nil, false
+	}
+
+	switch a := decoded.(type) {
+	case UpdateEditorContentAction:
+		return UpdateEditorContentAction{PanelName: a.PanelName, Content: priorContent(entries, index, a.PanelName)}, true
+	case SetFilterAction:
+		return ClearFilterAction{PanelName: a.PanelName}, true
+	}
+	return nil, false
+}
+
+// priorContent walks backwards from index to find the last content an
+// editor panel held before the action being undone.
+func priorContent(entries []journalEntry, index int, panelName string) string {
+	for i := index - 1; i >= 0; i-- {
+		decoded, err := decodeAction(entries[i])
+		if err != nil {
+			continue
+		}
+		if u, ok := decoded.(UpdateEditorContentAction); ok && u.PanelName == panelName {
+			return u.Content
+		}
+	}
+	return ""
+}
+
+// UndoLast dispatches the inverse of the most recently journaled action.
+func (s *Store) UndoLast() error {
+	entries, err := readJournal(journalPath)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	last := entries[len(entries)-1]
+	original, err := decodeAction(last)
+	if err != nil {
+		return err
+	}
+	inverse, ok := InverseAction(entries, len(entries)-1)
+	if !ok {
+		return fmt.Errorf("journal: no inverse known for %s", last.Type)
+	}
+
+	s.Dispatch(inverse)
+	s.mu.Lock()
+	s.undone = append(s.undone, original)
+	s.mu.Unlock()
+	return nil
+}
+
+// RedoLast re-dispatches the most recently undone action.
+func (s *Store) RedoLast() error {
+	s.mu.Lock()
+	if len(s.undone) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	action := s.undone[len(s.undone)-1]
+	s.undone = s.undone[:len(s.undone)-1]
+	s.mu.Unlock()
+
+	s.Dispatch(action)
+	return nil
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
@@ -11,6 +13,9 @@ type FileTreePanel struct {
 	*tview.TreeView
 	store  *Store
 	config PanelConfig
+
+	filtering bool // true while the "/" filter input is being typed
+	query     string
 }
 
 func NewFileTreePanel(store *Store, config PanelConfig) *FileTreePanel {
@@ -42,14 +47,64 @@ func NewFileTreePanel(store *Store, config PanelConfig) *FileTreePanel {
 			store.Dispatch(FileSelectedAction{Path: ref})
 		}
 	})
+
+	p.SetInputCapture(p.handleInput)
 	return p
 }
 
+// handleInput implements the cross-cutting "/" list-filter: "/" opens the
+// filter line, typed runes build the query, Enter commits it via
+// SetFilterAction, Esc (or an empty Enter) clears it via ClearFilterAction.
+func (p *FileTreePanel) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	if p.filtering {
+		switch event.Key() {
+		case tcell.KeyEnter:
+			p.filtering = false
+			if p.query == "" {
+				p.store.Dispatch(ClearFilterAction{PanelName: p.config.Name})
+			} else {
+				p.store.Dispatch(SetFilterAction{PanelName: p.config.Name, Query: p.query})
+			}
+			p.refresh()
+			return nil
+		case tcell.KeyEsc:
+			p.filtering = false
+			p.query = ""
+			p.store.Dispatch(ClearFilterAction{PanelName: p.config.Name})
+			p.refresh()
+			return nil
+		case tcell.KeyBackspace, tcell.KeyBackspace2:
+			if len(p.query) > 0 {
+				p.query = p.query[:len(p.query)-1]
+			}
+			p.refresh()
+			return nil
+		default:
+			if event.Rune() != 0 {
+				p.query += string(event.Rune())
+				p.refresh()
+			}
+			return nil
+		}
+	}
+
+	if event.Rune() == '/' {
+		p.filtering = true
+		p.query = ""
+		p.refresh()
+		return nil
+	}
+	return event
+}
+
 func (p *FileTreePanel) populateNode(node *tview.TreeNode) {
 	path := node.GetReference().(string)
 	entries, _ := os.ReadDir(path)
 	for _, entry := range entries {
 		name := entry.Name()
+		if p.query != "" && !matchFilter(name, p.query, p.config.FuzzyFilter) {
+			continue
+		}
 		if entry.IsDir() {
 			name += "/"
 		}
@@ -58,7 +113,58 @@ func (p *FileTreePanel) populateNode(node *tview.TreeNode) {
 	}
 }
 
+// refresh reapplies the current query to every expanded directory node.
+func (p *FileTreePanel) refresh() {
+	root := p.GetRoot()
+	root.ClearChildren()
+	p.populateNode(root)
+	p.walkExpanded(root)
+	p.updateTitle()
+}
+
+func (p *FileTreePanel) walkExpanded(node *tview.TreeNode) {
+	for _, child := range node.GetChildren() {
+		ref, _ := child.GetReference().(string)
+		if fi, err := os.Stat(ref); err == nil && fi.IsDir() {
+			p.populateNode(child)
+			p.walkExpanded(child)
+		}
+	}
+}
+
+func (p *FileTreePanel) updateTitle() {
+	title := " " + p.config.Name + " "
+	if p.filtering {
+		title = fmt.Sprintf(" %s [/%s] ", p.config.Name, p.query)
+	} else if p.query != "" {
+		matched, total := p.filteredCount()
+		title = fmt.Sprintf(" %s (%d/%d) ", p.config.Name, matched, total)
+	}
+	p.SetTitle(title)
+}
+
+// filteredCount counts how many immediate root entries currently match the
+// active query, against how many exist on disk unfiltered.
+func (p *FileTreePanel) filteredCount() (matched, total int) {
+	path, _ := p.GetRoot().GetReference().(string)
+	entries, _ := os.ReadDir(path)
+	total = len(entries)
+	for _, entry := range entries {
+		if matchFilter(entry.Name(), p.query, p.config.FuzzyFilter) {
+			matched++
+		}
+	}
+	return matched, total
+}
+
 // Render for FileTreePanel is a no-op as its state is managed internally for now.
 func (p *FileTreePanel) Render(state State) {}
 
-func (p *FileTreePanel) TabLabel(state State) string { return p.config.Name }
+func (p *FileTreePanel) TabLabel(state State) string {
+	ps, ok := state.PanelStates[p.config.Name]
+	if !ok || !ps.FilterActive {
+		return p.config.Name
+	}
+	matched, total := p.filteredCount()
+	return fmt.Sprintf("%s (%d/%d)", p.config.Name, matched, total)
+}
@@ -0,0 +1,54 @@
+package main
+
+import "strings"
+
+// matchFilter reports whether text satisfies query under the panel's
+// configured filter mode. Fuzzy mode matches query's runes against text in
+// order (not necessarily contiguous); substring mode is a plain
+// case-insensitive contains check.
+func matchFilter(text, query string, fuzzy bool) bool {
+	if query == "" {
+		return true
+	}
+	if !fuzzy {
+		return strings.Contains(strings.ToLower(text), strings.ToLower(query))
+	}
+
+	text = strings.ToLower(text)
+	query = strings.ToLower(query)
+	ti := 0
+	for _, qr := range query {
+		found := false
+		for ; ti < len(text); ti++ {
+			if rune(text[ti]) == qr {
+				found = true
+				ti++
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// filteredLineCount returns how many lines of content match query (and the
+// total line count), so tab labels can show e.g. "(3/40)" while a filter is
+// active without having to re-render the panel's full content.
+func filteredLineCount(content, query string, fuzzy bool) (matched, total int) {
+	if content == "" {
+		return 0, 0
+	}
+	lines := strings.Split(content, "\n")
+	total = len(lines)
+	if query == "" {
+		return total, total
+	}
+	for _, line := range lines {
+		if matchFilter(line, query, fuzzy) {
+			matched++
+		}
+	}
+	return matched, total
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/charmbracelet/glamour"
+	"github.com/rivo/tview"
+)
+
+// PreviewPanel renders State.CurrentFile through glamour whenever it's a
+// Markdown or HTML file, giving a live side-by-side preview of whatever
+// PanelConfig.SourcePanel (normally the sibling VimPanel/EditorPanel) is
+// editing. HTML is converted to Markdown first so glamour only has to
+// handle one input format.
+type PreviewPanel struct {
+	*tview.TextView
+	store  *Store
+	config PanelConfig
+
+	mu       sync.Mutex
+	cacheKey string
+	cached   string
+}
+
+func NewPreviewPanel(store *Store, config PanelConfig) *PreviewPanel {
+	p := &PreviewPanel{
+		TextView: tview.NewTextView().SetDynamicColors(true).SetWrap(true),
+		store:    store,
+		config:   config,
+	}
+
+	theme := ResolveTheme(config.PreviewTheme)
+	p.SetBorder(true).SetTitle(" " + config.Name + " ").SetTitleColor(theme.TitleColor)
+	p.SetBorderColor(theme.BorderColor)
+
+	go p.watch()
+	return p
+}
+
+// watch re-renders on every store event, so an edit to the sibling editor
+// panel shows up here without the preview needing its own key bindings.
+func (p *PreviewPanel) watch() {
+	for range p.store.Events() {
+		p.Render(p.store.GetState())
+	}
+}
+
+func isPreviewable(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".md", ".markdown", ".html":
+		return true
+	}
+	return false
+}
+
+// Render re-renders the preview from the source panel's content, using a
+// content-hash cache so a store event that leaves the file and its content
+// unchanged doesn't re-run glamour; every edit that changes the content
+// is rendered again.
+func (p *PreviewPanel) Render(state State) {
+	if !isPreviewable(state.CurrentFile) {
+		p.SetText(fmt.Sprintf("(no preview for %s)", filepath.Base(state.CurrentFile)))
+		return
+	}
+
+	content := state.PanelStates[p.config.SourcePanel].Content
+	key := hashContent(state.CurrentFile, content)
+
+	p.mu.Lock()
+	if key == p.cacheKey {
+		rendered := p.cached
+		p.mu.Unlock()
+		p.SetText(rendered)
+		return
+	}
+	p.mu.Unlock()
+
+	rendered, err := p.render(state.CurrentFile, content)
+	if err != nil {
+		rendered = fmt.Sprintf("(preview error: %v)", err)
+	}
+
+	p.mu.Lock()
+	p.cacheKey, p.cached = key, rendered
+	p.mu.Unlock()
+
+	p.SetText(rendered)
+}
+
+// render converts HTML to Markdown when needed, then runs glamour.
+func (p *PreviewPanel) render(path, content string) (string, error) {
+	if strings.ToLower(filepath.Ext(path)) == ".html" {
+		converted, err := md.NewConverter("", true, nil).ConvertString(content)
+		if err != nil {
+			return "", err
+		}
+		content = converted
+	}
+
+	renderer, err := glamour.NewTermRenderer(glamour.WithStandardStyle(themeStyleName(p.config.PreviewTheme)))
+	if err != nil {
+		return "", err
+	}
+	return renderer.Render(content)
+}
+
+// themeStyleName maps our "dark"/"light"/"auto" config values onto the
+// style names glamour itself understands.
+func themeStyleName(configured string) string {
+	if configured == "" {
+		return "auto"
+	}
+	return configured
+}
+
+func hashContent(path, content string) string {
+	sum := sha256.Sum256([]byte(path + "\x00" + content))
+	return hex.EncodeToString(sum[:])
+}
+
+func (p *PreviewPanel) TabLabel(state State) string { return p.config.Name }
@@ -2,17 +2,34 @@ package main
 
 import (
 	"fmt"
+	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// VimPanel is a modal (vim-like) text editor panel. It tracks its own cursor
+// and pending command state, rendering into the embedded tview.TextArea; all
+// mutations are dispatched through the Store so the reducer remains the
+// single source of truth for panel content.
 type VimPanel struct {
 	*tview.TextArea
 	store  *Store
 	config PanelConfig
-	mode   string // "normal" or "insert"
+	mode   string // "normal", "insert", "visual", "visual-line", "command", "filter"
+
+	cursor   int    // rune offset into the panel's content
+	count    string // accumulated digit prefix for a pending motion/operator
+	op       rune   // pending operator ('d', 'y', 'c'), 0 if none
+	gPending bool   // true right after a lone 'g', awaiting a second 'g' for "gg"
+
+	visualStart int // cursor position when visual mode was entered
+
+	cmdline string // text typed after ':' in command mode
+	status  string // last status/error message, shown alongside the command line
 }
 
 func NewVimPanel(store *Store, config PanelConfig) *VimPanel {
@@ -33,26 +50,447 @@ func NewVimPanel(store *Store, config PanelConfig) *VimPanel {
 	return p
 }
 
+func (p *VimPanel) content() string {
+	return p.GetText()
+}
+
+func (p *VimPanel) setContent(text string) {
+	p.SetText(text, false)
+	p.store.Dispatch(UpdateEditorContentAction{PanelName: p.config.Name, Content: text})
+}
+
+// snapshot records the current content on the undo stack before a mutation.
+func (p *VimPanel) snapshot() {
+	p.store.Dispatch(SnapshotPanelAction{PanelName: p.config.Name})
+}
+
 func (p *VimPanel) handleInput(event *tcell.EventKey) *tcell.EventKey {
-	if p.mode == "normal" {
-		if event.Rune() == 'i' {
-			p.mode = "insert"
-			p.store.Dispatch(nil) // Trigger a rerender for the title change
-			return nil
-		}
-		// In normal mode, we don't pass most keys to the textarea.
-		// A real implementation would handle vim commands here.
+	switch p.mode {
+	case "insert":
+		return p.handleInsert(event)
+	case "command":
+		p.handleCommand(event)
+		return nil
+	case "visual", "visual-line":
+		p.handleVisual(event)
+		return nil
+	case "filter":
+		p.handleFilter(event)
+		return nil
+	default:
+		p.handleNormal(event)
 		return nil
 	}
+}
 
-	// In insert mode
+func (p *VimPanel) handleInsert(event *tcell.EventKey) *tcell.EventKey {
 	if event.Key() == tcell.KeyEsc {
 		p.mode = "normal"
-		p.store.Dispatch(nil) // Trigger a rerender for the title change
+		p.store.Dispatch(nil) // trigger a rerender for the title/status change
 		return nil
 	}
+	return event // let the textarea's default handler insert the rune
+}
+
+// handleNormal implements motions (h/j/k/l/w/b/0/$/gg/G), operators (d/y/c)
+// with optional counts, p/P paste, and entry into visual/insert/command mode.
+func (p *VimPanel) handleNormal(event *tcell.EventKey) {
+	r := event.Rune()
+
+	switch {
+	case event.Key() == tcell.KeyEsc:
+		p.count, p.op, p.gPending = "", 0, false
+		return
+	case r >= '1' && r <= '9', r == '0' && p.count != "":
+		p.count += string(r)
+		return
+	case r == 'i':
+		p.mode = "insert"
+		p.store.Dispatch(nil)
+		return
+	case r == 'v':
+		p.mode, p.visualStart = "visual", p.cursor
+		p.store.Dispatch(nil)
+		return
+	case r == 'V':
+		p.mode, p.visualStart = "visual-line", p.cursor
+		p.store.Dispatch(nil)
+		return
+	case r == ':':
+		p.mode, p.cmdline = "command", ""
+		p.store.Dispatch(nil)
+		return
+	case r == '/':
+		p.mode, p.cmdline = "filter", ""
+		p.store.Dispatch(nil)
+		return
+	case r == 'u':
+		p.store.Dispatch(UndoAction{PanelName: p.config.Name})
+		return
+	case event.Key() == tcell.KeyCtrlR:
+		p.store.Dispatch(RedoAction{PanelName: p.config.Name})
+		return
+	case r == 'p' || r == 'P':
+		p.paste(r == 'P')
+		return
+	case r == 'g':
+		if p.gPending {
+			p.cursor = 0 // "gg" moves to the start of the buffer
+			p.gPending = false
+			p.takeCount()
+		} else {
+			p.gPending = true
+		}
+		return
+	case r == 'd' || r == 'y' || r == 'c':
+		if p.op == r {
+			p.applyLineOperator(r, p.takeCount()) // dd/yy/cc operate on whole lines
+			p.op = 0
+		} else {
+			p.op = r
+		}
+		return
+	case isMotionRune(r) || event.Key() == tcell.KeyHome || event.Key() == tcell.KeyEnd:
+		motion := r
+		if event.Key() == tcell.KeyHome {
+			motion = '0'
+		} else if event.Key() == tcell.KeyEnd {
+			motion = '$'
+		}
+		n := p.takeCount()
+		if p.op != 0 {
+			p.applyOperatorMotion(p.op, motion, n)
+			p.op = 0
+		} else {
+			p.cursor = p.applyMotion(motion, n)
+		}
+		p.gPending = false
+	}
+}
+
+func (p *VimPanel) takeCount() int {
+	n := 1
+	if p.count != "" {
+		if v, err := strconv.Atoi(p.count); err == nil && v > 0 {
+			n = v
+		}
+	}
+	p.count = ""
+	return n
+}
+
+func isMotionRune(r rune) bool {
+	switch r {
+	case 'h', 'j', 'k', 'l', 'w', 'b', '0', '$', 'G':
+		return true
+	}
+	return false
+}
+
+// applyMotion returns the new cursor position for a single motion applied n times.
+func (p *VimPanel) applyMotion(motion rune, n int) int {
+	text := []rune(p.content())
+	pos := p.cursor
+	for i := 0; i < n; i++ {
+		pos = motionOnce(text, pos, motion)
+	}
+	if pos < 0 {
+		pos = 0
+	}
+	if pos > len(text) {
+		pos = len(text)
+	}
+	return pos
+}
 
-	return event // Pass the event to the textarea's default handler
+func motionOnce(text []rune, pos int, motion rune) int {
+	switch motion {
+	case 'h':
+		if pos > 0 {
+			pos--
+		}
+	case 'l':
+		if pos < len(text) {
+			pos++
+		}
+	case 'j':
+		pos = lineOffset(text, pos, 1)
+	case 'k':
+		pos = lineOffset(text, pos, -1)
+	case '0':
+		pos = lineStart(text, pos)
+	case '$':
+		pos = lineEnd(text, pos)
+	case 'w':
+		pos = nextWordStart(text, pos)
+	case 'b':
+		pos = prevWordStart(text, pos)
+	case 'G':
+		pos = len(text)
+	}
+	return pos
+}
+
+func lineStart(text []rune, pos int) int {
+	for pos > 0 && text[pos-1] != '\n' {
+		pos--
+	}
+	return pos
+}
+
+func lineEnd(text []rune, pos int) int {
+	for pos < len(text) && text[pos] != '\n' {
+		pos++
+	}
+	return pos
+}
+
+func lineOffset(text []rune, pos, delta int) int {
+	col := pos - lineStart(text, pos)
+	for ; delta > 0; delta-- {
+		end := lineEnd(text, pos)
+		if end >= len(text) {
+			break
+		}
+		pos = end + 1
+	}
+	for ; delta < 0; delta++ {
+		start := lineStart(text, pos)
+		if start == 0 {
+			break
+		}
+		pos = lineStart(text, start-1)
+	}
+	newStart := lineStart(text, pos)
+	newEnd := lineEnd(text, pos)
+	if newStart+col < newEnd {
+		return newStart + col
+	}
+	return newEnd
+}
+
+func nextWordStart(text []rune, pos int) int {
+	n := len(text)
+	for pos < n && !isSpace(text[pos]) {
+		pos++
+	}
+	for pos < n && isSpace(text[pos]) {
+		pos++
+	}
+	return pos
+}
+
+func prevWordStart(text []rune, pos int) int {
+	for pos > 0 && isSpace(text[pos-1]) {
+		pos--
+	}
+	for pos > 0 && !isSpace(text[pos-1]) {
+		pos--
+	}
+	return pos
+}
+
+func isSpace(r rune) bool { return r == ' ' || r == '\t' || r == '\n' }
+
+// applyOperatorMotion runs operator op ('d'/'y'/'c') over the span from the
+// cursor to the destination of motion applied n times.
+func (p *VimPanel) applyOperatorMotion(op rune, motion rune, n int) {
+	text := []rune(p.content())
+	dest := p.applyMotion(motion, n)
+	from, to := p.cursor, dest
+	if from > to {
+		from, to = to, from
+	}
+	p.runOperator(op, text, from, to)
+}
+
+// applyLineOperator runs operator op over n whole lines starting at the cursor (dd/yy/cc).
+func (p *VimPanel) applyLineOperator(op rune, n int) {
+	text := []rune(p.content())
+	from := lineStart(text, p.cursor)
+	to := from
+	for i := 0; i < n; i++ {
+		to = lineEnd(text, to)
+		if to < len(text) {
+			to++ // consume the trailing newline too
+		}
+	}
+	p.runOperator(op, text, from, to)
+}
+
+func (p *VimPanel) runOperator(op rune, text []rune, from, to int) {
+	selected := string(text[from:to])
+	if op != 'y' {
+		p.snapshot()
+	}
+	switch op {
+	case 'y':
+		p.store.Dispatch(RegisterYankAction{Register: `"`, Text: selected})
+		p.cursor = from
+	case 'd':
+		p.store.Dispatch(RegisterYankAction{Register: `"`, Text: selected})
+		newText := string(text[:from]) + string(text[to:])
+		p.cursor = from
+		p.setContent(newText)
+	case 'c':
+		p.store.Dispatch(RegisterYankAction{Register: `"`, Text: selected})
+		newText := string(text[:from]) + string(text[to:])
+		p.cursor = from
+		p.setContent(newText)
+		p.mode = "insert"
+	}
+}
+
+func (p *VimPanel) paste(before bool) {
+	state := p.store.GetState()
+	reg := state.Registers[`"`]
+	if reg == "" {
+		return
+	}
+	text := []rune(p.content())
+	pos := p.cursor
+	if !before && pos < len(text) {
+		pos++
+	}
+	if pos > len(text) {
+		pos = len(text)
+	}
+	newText := string(text[:pos]) + reg + string(text[pos:])
+	p.snapshot()
+	p.cursor = pos + len([]rune(reg))
+	p.store.Dispatch(PasteAction{PanelName: p.config.Name, Register: `"`, Before: before, Content: newText})
+	p.SetText(newText, false)
+}
+
+// handleVisual extends the selection with motions and commits d/y/c over the
+// selected span; Esc returns to normal mode without changes.
+func (p *VimPanel) handleVisual(event *tcell.EventKey) {
+	r := event.Rune()
+	switch {
+	case event.Key() == tcell.KeyEsc:
+		p.mode = "normal"
+	case r == 'd' || r == 'y' || r == 'c':
+		from, to := p.visualStart, p.cursor
+		if from > to {
+			from, to = to, from
+		}
+		text := []rune(p.content())
+		if p.mode == "visual-line" {
+			from = lineStart(text, from)
+			to = lineEnd(text, to)
+			if to < len(text) {
+				to++
+			}
+		} else if to < len(text) {
+			to++ // visual selections are inclusive of the end character
+		}
+		p.runOperator(r, text, from, to)
+		if r != 'c' {
+			p.mode = "normal"
+		}
+	case isMotionRune(r):
+		p.cursor = p.applyMotion(r, 1)
+	}
+	p.store.Dispatch(nil)
+}
+
+// handleCommand builds up the ex command-line buffer shown at the bottom of
+// the panel and executes it on Enter.
+func (p *VimPanel) handleCommand(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyEsc:
+		p.mode, p.cmdline = "normal", ""
+	case tcell.KeyEnter:
+		p.execCommand(p.cmdline)
+		p.cmdline = ""
+		if p.mode == "command" {
+			p.mode = "normal"
+		}
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(p.cmdline) > 0 {
+			p.cmdline = p.cmdline[:len(p.cmdline)-1]
+		} else {
+			p.mode = "normal"
+		}
+	default:
+		if r := event.Rune(); r != 0 {
+			p.cmdline += string(r)
+		}
+	}
+	p.store.Dispatch(nil)
+}
+
+// handleFilter reads the query for the cross-panel "/" list-filter, reusing
+// cmdline as the input buffer. Enter commits the filter via SetFilterAction
+// (or clears it, if empty); Esc cancels without changing the active filter.
+func (p *VimPanel) handleFilter(event *tcell.EventKey) {
+	switch event.Key() {
+	case tcell.KeyEsc:
+		p.mode, p.cmdline = "normal", ""
+	case tcell.KeyEnter:
+		if p.cmdline == "" {
+			p.store.Dispatch(ClearFilterAction{PanelName: p.config.Name})
+		} else {
+			p.store.Dispatch(SetFilterAction{PanelName: p.config.Name, Query: p.cmdline})
+		}
+		p.cmdline, p.mode = "", "normal"
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(p.cmdline) > 0 {
+			p.cmdline = p.cmdline[:len(p.cmdline)-1]
+		} else {
+			p.mode = "normal"
+		}
+	default:
+		if r := event.Rune(); r != 0 {
+			p.cmdline += string(r)
+		}
+	}
+	p.store.Dispatch(nil)
+}
+
+// execCommand runs an ex-style command: :w, :q, :wq, :e <path>, :set <opt>, :!<shell>.
+func (p *VimPanel) execCommand(line string) {
+	line = strings.TrimSpace(line)
+	switch {
+	case line == "":
+		return
+	case line == "w", line == "wq":
+		p.store.Dispatch(SaveFileAction{PanelName: p.config.Name})
+	case line == "q":
+		p.status = "" // nothing panel-local to close; the dock owns panel lifecycle
+	case strings.HasPrefix(line, "e "):
+		path := strings.TrimSpace(strings.TrimPrefix(line, "e "))
+		p.store.Dispatch(OpenFileAction{Path: path})
+	case strings.HasPrefix(line, "set "):
+		p.status = fmt.Sprintf("set: %s", strings.TrimSpace(strings.TrimPrefix(line, "set ")))
+	case strings.HasPrefix(line, "!"):
+		p.runShell(strings.TrimPrefix(line, "!"))
+	case line == "undo":
+		if err := p.store.UndoLast(); err != nil {
+			p.status = err.Error()
+		}
+	case line == "redo":
+		if err := p.store.RedoLast(); err != nil {
+			p.status = err.Error()
+		}
+	default:
+		p.status = fmt.Sprintf("unknown command: %s", line)
+	}
+}
+
+// runShell pipes the current content through the given shell command and
+// replaces it with the command's stdout, mirroring vim's `:!` filter.
+func (p *VimPanel) runShell(shellCmd string) {
+	cmd := exec.Command("sh", "-c", shellCmd)
+	cmd.Stdin = strings.NewReader(p.content())
+	out, err := cmd.Output()
+	if err != nil {
+		p.status = fmt.Sprintf("!%s: %v", shellCmd, err)
+		return
+	}
+	p.snapshot()
+	p.setContent(string(out))
+	p.status = fmt.Sprintf("!%s", shellCmd)
 }
 
 func (p *VimPanel) Render(state State) {
@@ -65,10 +503,15 @@ func (p *VimPanel) Render(state State) {
 		p.SetText(panelState.Content, false)
 	}
 
-	modeStr := "[N]"
-	if p.mode == "insert" {
-		modeStr = "[I]"
-	}
+	modeStr := map[string]string{
+		"normal":      "[N]",
+		"insert":      "[I]",
+		"visual":      "[V]",
+		"visual-line": "[V-LINE]",
+		"command":     "[C]",
+		"filter":      "[/]",
+	}[p.mode]
+
 	title := p.config.Name
 	if state.CurrentFile != "" {
 		title = filepath.Base(state.CurrentFile)
@@ -76,7 +519,21 @@ func (p *VimPanel) Render(state State) {
 	if panelState.IsDirty {
 		title += " *"
 	}
-	p.SetTitle(fmt.Sprintf(" %s %s ", title, modeStr))
+	if panelState.FilterActive && p.mode != "filter" {
+		matched, total := filteredLineCount(panelState.Content, panelState.Filter, p.config.FuzzyFilter)
+		title += fmt.Sprintf(" (%d/%d)", matched, total)
+	}
+
+	switch {
+	case p.mode == "command":
+		p.SetTitle(fmt.Sprintf(" %s %s  :%s", title, modeStr, p.cmdline))
+	case p.mode == "filter":
+		p.SetTitle(fmt.Sprintf(" %s %s  /%s", title, modeStr, p.cmdline))
+	case p.status != "":
+		p.SetTitle(fmt.Sprintf(" %s %s  %s", title, modeStr, p.status))
+	default:
+		p.SetTitle(fmt.Sprintf(" %s %s ", title, modeStr))
+	}
 }
 
 func (p *VimPanel) TabLabel(state State) string {
@@ -91,5 +548,9 @@ func (p *VimPanel) TabLabel(state State) string {
 	if panelState.IsDirty {
 		label += " *"
 	}
+	if panelState.FilterActive {
+		matched, total := filteredLineCount(panelState.Content, panelState.Filter, p.config.FuzzyFilter)
+		label += fmt.Sprintf(" (%d/%d)", matched, total)
+	}
 	return label
 }
@@ -24,7 +24,7 @@ func (d DockID) String() string {
 
 // The single source of truth for the application.
 type State struct {
-	Pwd	string
+	Pwd         string
 	ActiveDock  DockID
 	CurrentFile string
 	// Map of panel names to their individual states.
@@ -34,13 +34,47 @@ type State struct {
 	// Ordered lists of panel names for each dock to allow cycling.
 	LeftDockPanels  []string
 	RightDockPanels []string
+	// Named yank registers, shared across panels like vim's.
+	Registers map[string]string
+
+	// Layout holds the user-adjustable dock split, mutated by
+	// ResizeDockAction/ResizePanelAction. DockView saves SplitRatio after
+	// each drag or Ctrl+Left/Right nudge; PanelHeights is not persisted.
+	Layout LayoutState
+}
+
+// LayoutState captures the parts of the dock layout a user can resize:
+// the left/right split and, for docks that stack panels as rows instead of
+// tabs, each panel's individual height.
+type LayoutState struct {
+	// SplitRatio is the fraction of terminal width given to the left dock,
+	// in (0, 1). The right dock gets the remainder.
+	SplitRatio float64
+	// PanelHeights maps a panel name to its height in terminal rows, for
+	// docks that render panels stacked rather than tabbed.
+	PanelHeights map[string]int
 }
 
 // PanelState holds the state for a single panel.
 type PanelState struct {
-	Content	   string
-	IsDirty	   bool
+	Content           string
+	IsDirty           bool
 	IsContentUpToDate bool // Flag for TextViewPanel to avoid re-running commands
+
+	// Undo/redo history for modal editors (e.g. VimPanel). Each entry is a
+	// full content snapshot taken immediately before the edit that produced it.
+	UndoStack []string
+	RedoStack []string
+
+	// HasConflict is set when the file backing this panel changed on disk
+	// while the panel had unsaved edits; cleared on the next save or reload.
+	HasConflict bool
+
+	// Filter holds the in-progress or committed query for this panel's
+	// filter mode (the "/" list-filter pattern). FilterActive distinguishes
+	// an empty-but-committed filter from no filter at all.
+	Filter       string
+	FilterActive bool
 }
 
 // ===== Actions =====
@@ -50,7 +84,8 @@ type Action interface{}
 type SwitchDockAction struct{}
 type CycleTabAction struct{ Delta int }
 type FileSelectedAction struct{ Path string }
-type SaveFileAction struct{}
+type SaveFileAction struct{ PanelName string }
+type OpenFileAction struct{ Path string }
 type UpdateEditorContentAction struct {
 	PanelName string
 	Content   string
@@ -61,9 +96,67 @@ type ExecuteCommandAction struct {
 }
 type CommandOutputAction struct {
 	PanelName string
-	Output	string
+	Output    string
+}
+
+// FileChangedOnDiskAction is dispatched by the filesystem watcher middleware
+// when the current file is written outside the app. Panels that aren't dirty
+// pick up the new content; dirty panels are flagged with a conflict instead
+// of silently losing the user's in-progress edit.
+type FileChangedOnDiskAction struct {
+	Path    string
+	Content string
 }
 
+// SnapshotPanelAction pushes the panel's current content onto its undo stack,
+// clearing any pending redo history. Dispatched by VimPanel before a
+// destructive edit (d/c/p) so the edit itself can be undone.
+type SnapshotPanelAction struct{ PanelName string }
+
+// UndoAction reverts a panel's content to the previous entry on its undo stack.
+type UndoAction struct{ PanelName string }
+
+// RedoAction re-applies the most recently undone change for a panel.
+type RedoAction struct{ PanelName string }
+
+// RegisterYankAction stores text in a named register (d/y operators).
+type RegisterYankAction struct {
+	Register string
+	Text     string
+}
+
+// PasteAction inserts the contents of a register into a panel, either before
+// or after the cursor (p/P in normal mode).
+type PasteAction struct {
+	PanelName string
+	Register  string
+	Before    bool
+	Content   string // resulting panel content after the paste, computed by the caller
+}
+
+// ResizeDockAction nudges the left/right dock split ratio by Delta,
+// clamped to keep both docks visible. Dispatched by a drag on the vertical
+// border between docks, or by Ctrl+Left/Ctrl+Right.
+type ResizeDockAction struct{ Delta float64 }
+
+// ResizePanelAction nudges a single panel's row height by Delta rows, for
+// docks that stack panels instead of tabbing them. Dispatched by Ctrl+Up/
+// Ctrl+Down on the focused panel.
+type ResizePanelAction struct {
+	PanelName string
+	Delta     int
+}
+
+// SetFilterAction commits a panel's list-filter query (the "/" pattern).
+type SetFilterAction struct {
+	PanelName string
+	Query     string
+}
+
+// ClearFilterAction turns off a panel's list-filter, restoring the
+// unfiltered view.
+type ClearFilterAction struct{ PanelName string }
+
 // ===== Reducer =====
 // A pure function that returns a new state based on the previous state and an action.
 func Reducer(state State, action Action) State {
@@ -107,8 +200,8 @@ func Reducer(state State, action Action) State {
 			newState.PanelStates = make(map[string]PanelState) // Reset states
 			for name := range state.PanelStates {
 				newState.PanelStates[name] = PanelState{
-					Content:	   string(data),
-					IsDirty:	   false,
+					Content:           string(data),
+					IsDirty:           false,
 					IsContentUpToDate: false, // Mark as needing update
 				}
 			}
@@ -129,27 +222,167 @@ func Reducer(state State, action Action) State {
 			ps.IsContentUpToDate = true
 			newState.PanelStates[a.PanelName] = ps
 		}
+
+	case OpenFileAction:
+		return Reducer(newState, FileSelectedAction{Path: a.Path})
+
+	case FileChangedOnDiskAction:
+		if a.Path != state.CurrentFile {
+			break
+		}
+		for name, ps := range state.PanelStates {
+			if ps.IsDirty {
+				ps.HasConflict = true
+			} else {
+				ps.Content = a.Content
+				ps.IsContentUpToDate = false
+			}
+			newState.PanelStates[name] = ps
+		}
+
+	case SaveFileAction:
+		if err := os.WriteFile(newState.CurrentFile, []byte(newState.PanelStates[a.PanelName].Content), 0644); err == nil {
+			if ps, ok := newState.PanelStates[a.PanelName]; ok {
+				ps.IsDirty = false
+				ps.HasConflict = false
+				newState.PanelStates[a.PanelName] = ps
+			}
+		}
+
+	case RegisterYankAction:
+		newState.Registers = copyRegisters(state.Registers)
+		newState.Registers[a.Register] = a.Text
+
+	case PasteAction:
+		if ps, ok := newState.PanelStates[a.PanelName]; ok {
+			ps.Content = a.Content
+			ps.IsDirty = true
+			newState.PanelStates[a.PanelName] = ps
+		}
+
+	case SnapshotPanelAction:
+		if ps, ok := newState.PanelStates[a.PanelName]; ok {
+			ps.UndoStack = append(append([]string{}, ps.UndoStack...), ps.Content)
+			ps.RedoStack = nil
+			newState.PanelStates[a.PanelName] = ps
+		}
+
+	case UndoAction:
+		if ps, ok := newState.PanelStates[a.PanelName]; ok && len(ps.UndoStack) > 0 {
+			last := len(ps.UndoStack) - 1
+			prev := ps.UndoStack[last]
+			ps.UndoStack = ps.UndoStack[:last]
+			ps.RedoStack = append(append([]string{}, ps.RedoStack...), ps.Content)
+			ps.Content = prev
+			ps.IsDirty = true
+			newState.PanelStates[a.PanelName] = ps
+		}
+
+	case RedoAction:
+		if ps, ok := newState.PanelStates[a.PanelName]; ok && len(ps.RedoStack) > 0 {
+			last := len(ps.RedoStack) - 1
+			next := ps.RedoStack[last]
+			ps.RedoStack = ps.RedoStack[:last]
+			ps.UndoStack = append(append([]string{}, ps.UndoStack...), ps.Content)
+			ps.Content = next
+			ps.IsDirty = true
+			newState.PanelStates[a.PanelName] = ps
+		}
+
+	case ResizeDockAction:
+		ratio := state.Layout.SplitRatio + a.Delta
+		if ratio < minDockSplitRatio {
+			ratio = minDockSplitRatio
+		} else if ratio > maxDockSplitRatio {
+			ratio = maxDockSplitRatio
+		}
+		newState.Layout.SplitRatio = ratio
+
+	case ResizePanelAction:
+		newState.Layout.PanelHeights = copyPanelHeights(state.Layout.PanelHeights)
+		height := newState.Layout.PanelHeights[a.PanelName] + a.Delta
+		if height < minPanelHeight {
+			height = minPanelHeight
+		}
+		newState.Layout.PanelHeights[a.PanelName] = height
+
+	case SetFilterAction:
+		if ps, ok := newState.PanelStates[a.PanelName]; ok {
+			ps.Filter = a.Query
+			ps.FilterActive = true
+			newState.PanelStates[a.PanelName] = ps
+		}
+
+	case ClearFilterAction:
+		if ps, ok := newState.PanelStates[a.PanelName]; ok {
+			ps.Filter = ""
+			ps.FilterActive = false
+			newState.PanelStates[a.PanelName] = ps
+		}
 	}
 	return newState
 }
 
+// copyRegisters returns a shallow copy of a register map so reducer updates
+// never mutate the previous state in place.
+func copyRegisters(regs map[string]string) map[string]string {
+	out := make(map[string]string, len(regs))
+	for k, v := range regs {
+		out[k] = v
+	}
+	return out
+}
+
+// copyPanelHeights returns a shallow copy of a panel-height map so reducer
+// updates never mutate the previous state in place.
+func copyPanelHeights(heights map[string]int) map[string]int {
+	out := make(map[string]int, len(heights))
+	for k, v := range heights {
+		out[k] = v
+	}
+	return out
+}
+
+const (
+	minDockSplitRatio = 0.1
+	maxDockSplitRatio = 0.9
+	minPanelHeight    = 1
+)
+
+// ===== Middleware =====
+
+// Dispatcher is the function signature for dispatching actions.
+type Dispatcher func(action Action)
+
+// Middleware enhances the store's dispatch function to handle side effects.
+type Middleware func(store *Store) func(next Dispatcher) Dispatcher
+
 // ===== Store =====
 // Holds the state, applies the reducer, and notifies subscribers.
 type Store struct {
-	mu	  sync.Mutex
-	state	 State
-	reducer	 func(state State, action Action) State
+	mu          sync.Mutex
+	state       State
+	reducer     func(state State, action Action) State
 	middlewares []Middleware
-	events	  chan struct{}
+	events      chan struct{}
+
+	// undone holds actions popped off by UndoLast, in the order they can be
+	// RedoLast-ed back.
+	undone []Action
 }
 
 func NewStore(pwd string) *Store {
 	return &Store{
 		state: State{
-			Pwd:	   pwd,
-			ActiveDock:  DockLeft,
-			PanelStates: make(map[string]PanelState),
+			Pwd:          pwd,
+			ActiveDock:   DockLeft,
+			PanelStates:  make(map[string]PanelState),
 			ActivePanels: make(map[DockID]string),
+			Registers:    make(map[string]string),
+			Layout: LayoutState{
+				SplitRatio:   0.5,
+				PanelHeights: make(map[string]int),
+			},
 		},
 		reducer: Reducer,
 		events:  make(chan struct{}, 1),
@@ -0,0 +1,28 @@
+package main
+
+import "github.com/gdamore/tcell/v2"
+
+// Theme describes the border/title chrome applied to themed panels such as
+// PreviewPanel.
+type Theme struct {
+	BorderColor tcell.Color
+	TitleColor  tcell.Color
+}
+
+// Styles maps a theme name to its Theme.
+var Styles = map[string]Theme{
+	"dark":  {BorderColor: tcell.ColorWhite, TitleColor: tcell.ColorAqua},
+	"light": {BorderColor: tcell.ColorBlack, TitleColor: tcell.ColorNavy},
+}
+
+// ResolveTheme looks up a theme by name, treating "" and "auto" as "dark"
+// since this build has no way to query the terminal's actual background.
+func ResolveTheme(name string) Theme {
+	if name == "" || name == "auto" {
+		name = "dark"
+	}
+	if t, ok := Styles[name]; ok {
+		return t
+	}
+	return Styles["dark"]
+}
@@ -17,11 +17,18 @@ func main() {
 	log.SetOutput(f)
 
 	port := flag.String("port", "8080", "Port for the API server")
+	height := flag.String("height", "", "Run inline in a reserved region of N rows or N% of the TTY height, instead of taking over the full screen")
+	reverse := flag.Bool("reverse", false, "Render input above cursor/content instead of below it")
+	authDisabled := flag.Bool("auth-disabled", false, "Disable API bearer-token auth and rate limiting (local dev only)")
 	flag.Parse()
 
 	// Create and run the new Tubes model
-	model := tui.NewTubesModel(*port)
-	
+	model := tui.NewTubesModelWithOptions(*port, tui.TubesOptions{
+		Height:       *height,
+		Reverse:      *reverse,
+		AuthDisabled: *authDisabled,
+	})
+
 	log.Printf("Starting Tubes with new architecture on port %s", *port)
 	
 	if err := model.Run(); err != nil {
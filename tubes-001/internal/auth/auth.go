@@ -0,0 +1,197 @@
+// Package auth issues and verifies the bearer tokens api.Server's
+// middleware checks requests against, each scoped to a subset of
+// routes (cursors:read, cursors:write, mc:admin, snapshots:*,
+// events:stream).
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scope names one slice of the API a token may be granted access to.
+// A scope ending in ":*" covers every scope sharing its prefix, e.g.
+// "snapshots:*" covers "snapshots:read" and "snapshots:write".
+type Scope string
+
+const (
+	ScopeCursorsRead  Scope = "cursors:read"
+	ScopeCursorsWrite Scope = "cursors:write"
+	ScopeMCAdmin      Scope = "mc:admin"
+	ScopeSnapshots    Scope = "snapshots:*"
+	ScopeEventsStream Scope = "events:stream"
+)
+
+// Token is one issued credential. Hash, never the plaintext secret, is
+// what's persisted and compared against - the secret is returned only
+// once, from Issue.
+type Token struct {
+	ID       string    `json:"id"`
+	Hash     string    `json:"hash"`
+	Scopes   []Scope   `json:"scopes"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"last_used,omitempty"`
+}
+
+// HasScope reports whether t carries scope, either directly or via a
+// "x:*" scope covering it.
+func (t *Token) HasScope(scope Scope) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(string(s), "*"); ok && strings.HasPrefix(string(scope), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted is a Token with Hash stripped, for GET /api/auth/tokens.
+type Redacted struct {
+	ID       string    `json:"id"`
+	Scopes   []Scope   `json:"scopes"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"last_used,omitempty"`
+}
+
+// Store persists issued tokens to path, hashed at rest, and verifies
+// bearer secrets against them.
+type Store struct {
+	mu     sync.Mutex
+	path   string
+	tokens map[string]*Token
+}
+
+// NewStore loads path's tokens if the file exists, tolerating a
+// missing or unreadable file the same way CommandHistory tolerates a
+// missing history file - it just starts empty and logs why.
+func NewStore(path string) *Store {
+	s := &Store{path: path, tokens: make(map[string]*Token)}
+	if err := s.load(); err != nil {
+		log.Printf("auth: starting with no tokens, failed to load %s: %v", path, err)
+	}
+	return s
+}
+
+func (s *Store) load() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var list []*Token
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	for _, t := range list {
+		s.tokens[t.ID] = t
+	}
+	return nil
+}
+
+func (s *Store) save() error {
+	list := make([]*Token, 0, len(s.tokens))
+	for _, t := range s.tokens {
+		list = append(list, t)
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(s.path, data, 0o600)
+}
+
+// Issue creates and persists a new token with the given scopes,
+// returning its id and the one-time plaintext secret a caller sends as
+// "Authorization: Bearer <secret>".
+func (s *Store) Issue(scopes []Scope) (id, secret string, err error) {
+	idBytes := make([]byte, 8)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", err
+	}
+	secretBytes := make([]byte, 32)
+	if _, err := rand.Read(secretBytes); err != nil {
+		return "", "", err
+	}
+
+	id = hex.EncodeToString(idBytes)
+	secret = hex.EncodeToString(secretBytes)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[id] = &Token{ID: id, Hash: hashSecret(secret), Scopes: scopes, Created: time.Now()}
+	if err := s.save(); err != nil {
+		delete(s.tokens, id)
+		return "", "", err
+	}
+	return id, secret, nil
+}
+
+// List returns every token's metadata with its hash stripped.
+func (s *Store) List() []Redacted {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Redacted, 0, len(s.tokens))
+	for _, t := range s.tokens {
+		out = append(out, Redacted{ID: t.ID, Scopes: t.Scopes, Created: t.Created, LastUsed: t.LastUsed})
+	}
+	return out
+}
+
+// Revoke deletes the token with the given id.
+func (s *Store) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.tokens[id]; !exists {
+		return fmt.Errorf("token %s not found", id)
+	}
+	delete(s.tokens, id)
+	return s.save()
+}
+
+// Verify checks secret against every stored token's hash in constant
+// time, bumping LastUsed and returning the matching Token on success.
+func (s *Store) Verify(secret string) (*Token, bool) {
+	hash := hashSecret(secret)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, t := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(t.Hash)) == 1 {
+			t.LastUsed = time.Now()
+			s.save()
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// bucket is a token-bucket limiter for one key (one API token's id, or
+// a caller's remote address when auth is disabled).
+type bucket struct {
+	tokens     float64
+	lastRefill time.Time
+}
+
+// RateLimiter enforces a token-bucket rate limit per key, refilling at
+// ratePerSecond up to a burst ceiling. The same drop-when-exhausted
+// tradeoff EventBus and CommandMiddleware's streaming take for slow
+// subscribers - here it's "reject the request" instead of "drop the
+// message".
+type RateLimiter struct {
+	mu            sync.Mutex
+	ratePerSecond float64
+	burst         float64
+	buckets       map[string]*bucket
+}
+
+// NewRateLimiter returns a limiter allowing ratePerSecond requests per
+// key on average, with bursts up to burst requests.
+func NewRateLimiter(ratePerSecond, burst float64) *RateLimiter {
+	return &RateLimiter{
+		ratePerSecond: ratePerSecond,
+		burst:         burst,
+		buckets:       make(map[string]*bucket),
+	}
+}
+
+// Allow reports whether key may make a request right now, consuming
+// one token from its bucket if so.
+func (r *RateLimiter) Allow(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	b, exists := r.buckets[key]
+	if !exists {
+		b = &bucket{tokens: r.burst, lastRefill: now}
+		r.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.lastRefill).Seconds()
+	b.tokens += elapsed * r.ratePerSecond
+	if b.tokens > r.burst {
+		b.tokens = r.burst
+	}
+	b.lastRefill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
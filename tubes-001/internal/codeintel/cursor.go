@@ -1,14 +1,32 @@
 package codeintel
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"mime"
+	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+
+	"tubes/internal/fswatch"
+)
+
+// ErrMCNotFound and ErrCursorNotFound are wrapped into RefreshCursor's
+// "not found" errors with fmt.Errorf's %w, so callers (api.Server in
+// particular) can classify them with errors.Is instead of matching
+// error strings. The other methods' "not found" errors do not wrap them.
+var (
+	ErrMCNotFound     = errors.New("multicursor not found")
+	ErrCursorNotFound = errors.New("cursor not found")
 )
 
 // Cursor represents a semantic selection of code (SpaCy-style)
 type Cursor struct {
 	ID        string                 `json:"id"`
+	Source    string                 `json:"source"` // name of the adapter FilePath resolves through (e.g. "filesystem", "http"); NewCursor sets "filesystem", and an empty Source matches no mounted adapter
 	FilePath  string                 `json:"file_path"`
 	DirName   string                 `json:"dir_name"`
 	StartLine int                    `json:"start_line"`
@@ -19,8 +37,27 @@ type Cursor struct {
 	Metadata  map[string]interface{} `json:"metadata"`  // AST node types, symbols, etc.
 	Tags      []string               `json:"tags"`      // user-defined tags
 	Prompt    string                 `json:"prompt"`    // default prompt for this cursor
+	Attachments []Attachment         `json:"attachments"` // supplementary files attached to this cursor
 	Created   time.Time              `json:"created"`
 	Updated   time.Time              `json:"updated"`
+	Orphaned  bool                   `json:"orphaned"` // true once FilePath is reported removed/renamed by the fs watcher
+
+	// ETag is the Workspace.ReadRange hash of Content as of the last
+	// time it was captured from disk, and Stale is set by
+	// RefreshCursor once a re-read's ETag no longer matches it.
+	ETag  string `json:"etag,omitempty"`
+	Stale bool   `json:"stale,omitempty"`
+}
+
+// Attachment is a file attached to a Cursor - supplementary material
+// (a screenshot, a spec doc) that travels with the code selection it
+// annotates.
+type Attachment struct {
+	ID      string    `json:"id"`
+	Path    string    `json:"path"`
+	Mime    string    `json:"mime"`
+	Size    int64     `json:"size"`
+	AddedAt time.Time `json:"added_at"`
 }
 
 // MultiCursor represents a collection of related cursors
@@ -42,6 +79,13 @@ type CursorDirectory struct {
 	CurrentMC    string                  `json:"current_mc"`    // currently selected multicursor
 	CurrentC     string                  `json:"current_c"`     // currently selected cursor within MC
 	NextID       int                     `json:"next_id"`       // for generating unique IDs
+
+	// Events publishes a typed Event for multicursor create/delete, cursor
+	// add/remove/move, selection switches and a cursor turning stale, for
+	// the streaming /api/events and /api/cursors/{mcID}/stream endpoints.
+	// ToggleExpanded, the attachment and Bulk* methods and HandleFSEvent
+	// publish nothing.
+	Events *EventBus `json:"-"`
 }
 
 // NewCursorDirectory creates a new cursor directory
@@ -49,6 +93,7 @@ func NewCursorDirectory() *CursorDirectory {
 	return &CursorDirectory{
 		MultiCursors: make(map[string]*MultiCursor),
 		NextID:       1,
+		Events:       NewEventBus(),
 	}
 }
 
@@ -59,6 +104,7 @@ func (cd *CursorDirectory) NewCursor(filePath string, startLine, endLine int, co
 	
 	return &Cursor{
 		ID:        id,
+		Source:    "filesystem",
 		FilePath:  filePath,
 		DirName:   filepath.Dir(filePath),
 		StartLine: startLine,
@@ -90,21 +136,157 @@ func (cd *CursorDirectory) NewMultiCursor(title, description string) *MultiCurso
 	}
 	
 	cd.MultiCursors[id] = mc
+	cd.Events.Publish(Event{Event: EventMCCreated, MCID: id, Payload: mc})
 	return mc
 }
 
+// DeleteMultiCursor removes the multicursor with the given id, clearing
+// the current selection if it pointed into it.
+func (cd *CursorDirectory) DeleteMultiCursor(mcID string) error {
+	if _, exists := cd.MultiCursors[mcID]; !exists {
+		return fmt.Errorf("multicursor %s not found", mcID)
+	}
+
+	delete(cd.MultiCursors, mcID)
+	if cd.CurrentMC == mcID {
+		cd.CurrentMC = ""
+		cd.CurrentC = ""
+	}
+	cd.Events.Publish(Event{Event: EventMCDeleted, MCID: mcID})
+	return nil
+}
+
 // AddCursorToMC adds a cursor to the specified multicursor
 func (cd *CursorDirectory) AddCursorToMC(mcID string, cursor *Cursor) error {
 	mc, exists := cd.MultiCursors[mcID]
 	if !exists {
 		return fmt.Errorf("multicursor %s not found", mcID)
 	}
-	
+
 	mc.Cursors = append(mc.Cursors, *cursor)
 	mc.Updated = time.Now()
+	cd.Events.Publish(Event{Event: EventCursorAdded, MCID: mcID, CursorID: cursor.ID, Payload: cursor})
 	return nil
 }
 
+// RemoveCursor removes the cursor with the given id from the specified
+// multicursor.
+func (cd *CursorDirectory) RemoveCursor(mcID, cursorID string) error {
+	mc, exists := cd.MultiCursors[mcID]
+	if !exists {
+		return fmt.Errorf("multicursor %s not found", mcID)
+	}
+
+	for i, c := range mc.Cursors {
+		if c.ID == cursorID {
+			mc.Cursors = append(mc.Cursors[:i], mc.Cursors[i+1:]...)
+			mc.Updated = time.Now()
+			cd.Events.Publish(Event{Event: EventCursorRemoved, MCID: mcID, CursorID: cursorID})
+			return nil
+		}
+	}
+	return fmt.Errorf("cursor %s not found in multicursor %s", cursorID, mcID)
+}
+
+// MoveCursor updates the line range of the cursor with the given id
+// within the specified multicursor, for dragging a cursor's boundaries
+// after it's already been created.
+func (cd *CursorDirectory) MoveCursor(mcID, cursorID string, startLine, endLine int) error {
+	mc, exists := cd.MultiCursors[mcID]
+	if !exists {
+		return fmt.Errorf("multicursor %s not found", mcID)
+	}
+
+	for i, c := range mc.Cursors {
+		if c.ID == cursorID {
+			mc.Cursors[i].StartLine = startLine
+			mc.Cursors[i].EndLine = endLine
+			mc.Cursors[i].Updated = time.Now()
+			mc.Updated = time.Now()
+			cd.Events.Publish(Event{
+				Event:    EventCursorMoved,
+				MCID:     mcID,
+				CursorID: cursorID,
+				Payload:  map[string]int{"start_line": startLine, "end_line": endLine},
+			})
+			return nil
+		}
+	}
+	return fmt.Errorf("cursor %s not found in multicursor %s", cursorID, mcID)
+}
+
+// FindCursorMC returns the id of the multicursor containing cursorID.
+func (cd *CursorDirectory) FindCursorMC(cursorID string) (mcID string, ok bool) {
+	for id, mc := range cd.MultiCursors {
+		for _, c := range mc.Cursors {
+			if c.ID == cursorID {
+				return id, true
+			}
+		}
+	}
+	return "", false
+}
+
+// FindCursor returns the cursor with the given id within mcID, or nil
+// if either doesn't exist.
+func (cd *CursorDirectory) FindCursor(mcID, cursorID string) *Cursor {
+	mc, exists := cd.MultiCursors[mcID]
+	if !exists {
+		return nil
+	}
+	for i := range mc.Cursors {
+		if mc.Cursors[i].ID == cursorID {
+			return &mc.Cursors[i]
+		}
+	}
+	return nil
+}
+
+// RefreshCursor re-reads cursorID's FilePath range through ws and
+// marks it Stale if the content's ETag no longer matches what was
+// last captured, publishing EventCursorStale the moment it turns
+// stale. A cursor that's still fresh has its Content refreshed from
+// disk (its ETag can't have changed, so there's nothing to overwrite).
+// ctx is forwarded to ws.ReadRange so a disconnected client aborts the
+// re-read instead of refreshing a cursor nobody's waiting on.
+func (cd *CursorDirectory) RefreshCursor(ctx context.Context, mcID, cursorID string, ws *Workspace) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	mc, exists := cd.MultiCursors[mcID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrMCNotFound, mcID)
+	}
+
+	for i, c := range mc.Cursors {
+		if c.ID != cursorID {
+			continue
+		}
+
+		content, etag, err := ws.ReadRange(ctx, c.FilePath, c.StartLine, c.EndLine)
+		if err != nil {
+			return err
+		}
+
+		wasStale := mc.Cursors[i].Stale
+		mc.Cursors[i].Stale = etag != c.ETag
+		if !mc.Cursors[i].Stale {
+			mc.Cursors[i].Content = content
+		}
+		if mc.Cursors[i].Stale && !wasStale {
+			cd.Events.Publish(Event{
+				Event:    EventCursorStale,
+				MCID:     mcID,
+				CursorID: cursorID,
+				Payload:  map[string]string{"etag": etag},
+			})
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: cursor %s not found in multicursor %s", ErrCursorNotFound, cursorID, mcID)
+}
+
 // GetCurrentMultiCursor returns the currently selected multicursor
 func (cd *CursorDirectory) GetCurrentMultiCursor() *MultiCursor {
 	if cd.CurrentMC == "" {
@@ -128,10 +310,15 @@ func (cd *CursorDirectory) GetCurrentCursor() *Cursor {
 	return nil
 }
 
-// SetCurrentSelection updates the current selection
+// SetCurrentSelection updates the current selection, publishing
+// EventMCSwitched when it moves to a different multicursor.
 func (cd *CursorDirectory) SetCurrentSelection(mcID, cursorID string) {
+	switched := mcID != cd.CurrentMC
 	cd.CurrentMC = mcID
 	cd.CurrentC = cursorID
+	if switched {
+		cd.Events.Publish(Event{Event: EventMCSwitched, MCID: mcID, CursorID: cursorID})
+	}
 }
 
 // ToggleExpanded toggles the expanded state of a multicursor
@@ -186,6 +373,97 @@ type NavigationItem struct {
 	Level    int    `json:"level"`    // indentation level
 }
 
+// FilteredItem is a NavigationItem carrying its fuzzy-match result:
+// Score ranks it against other matches, and Offsets are the byte offsets
+// into Title that matched the filter query (for highlight rendering).
+// Offsets is empty for multicursor headers kept visible only because a
+// child cursor matched, and for every item when no filter is active.
+type FilteredItem struct {
+	NavigationItem
+	Score   int
+	Offsets []int
+}
+
+// FilterVisibleItems is GetVisibleItems narrowed by a fuzzy-matched
+// query over each cursor's title, file path, tags, and content. A
+// multicursor stays visible (and expanded) if any of its cursors match,
+// even if the multicursor itself was collapsed, so filtering doesn't
+// hide results behind a closed folder. Matches within a multicursor sort
+// by descending score. An empty query returns GetVisibleItems unchanged.
+func (cd *CursorDirectory) FilterVisibleItems(query string) []FilteredItem {
+	if query == "" {
+		items := cd.GetVisibleItems()
+		out := make([]FilteredItem, len(items))
+		for i, item := range items {
+			out[i] = FilteredItem{NavigationItem: item}
+		}
+		return out
+	}
+
+	var out []FilteredItem
+	for mcID, mc := range cd.MultiCursors {
+		var matches []FilteredItem
+		for _, cursor := range mc.Cursors {
+			score, offsets, matched := fuzzyScoreCursor(query, &cursor)
+			if !matched {
+				continue
+			}
+			matches = append(matches, FilteredItem{
+				NavigationItem: NavigationItem{
+					Type:  "cursor",
+					ID:    cursor.ID,
+					Title: fmt.Sprintf("%s:%d-%d", filepath.Base(cursor.FilePath), cursor.StartLine, cursor.EndLine),
+					Level: 1,
+				},
+				Score:   score,
+				Offsets: offsets,
+			})
+		}
+		if len(matches) == 0 {
+			continue
+		}
+
+		sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+
+		out = append(out, FilteredItem{NavigationItem: NavigationItem{
+			Type:     "multicursor",
+			ID:       mcID,
+			Title:    mc.Title,
+			Expanded: true,
+			Level:    0,
+		}})
+		out = append(out, matches...)
+	}
+	return out
+}
+
+// fuzzyScoreCursor scores query against a cursor's title, file path,
+// tags, and content, and returns the best-scoring field's score. Offsets
+// are only reported when the title itself is the best match, since
+// that's the only field rendered in the cursor pane.
+func fuzzyScoreCursor(query string, c *Cursor) (score int, offsets []int, matched bool) {
+	title := fmt.Sprintf("%s:%d-%d", filepath.Base(c.FilePath), c.StartLine, c.EndLine)
+	fields := []string{title, c.FilePath, strings.Join(c.Tags, " "), c.Content}
+
+	best := -1
+	for i, field := range fields {
+		s, fieldOffsets, ok := FuzzyMatch(query, field)
+		if !ok {
+			continue
+		}
+		matched = true
+		if s > best {
+			best = s
+			if i == 0 {
+				offsets = fieldOffsets
+			} else {
+				offsets = nil
+			}
+		}
+	}
+	return best, offsets, matched
+}
+
 // BuildLLMContext builds context from the current selection
 func (cd *CursorDirectory) BuildLLMContext() string {
 	mc := cd.GetCurrentMultiCursor()
@@ -204,6 +482,149 @@ func (cd *CursorDirectory) BuildLLMContext() string {
 	return context
 }
 
+// AttachFile stats path and appends it to cursor.Attachments, guessing
+// its MIME type from its extension and falling back to a generic binary
+// type when that fails.
+func (cd *CursorDirectory) AttachFile(cursor *Cursor, path string) (*Attachment, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+
+	id := fmt.Sprintf("att_%d", cd.NextID)
+	cd.NextID++
+
+	a := Attachment{
+		ID:      id,
+		Path:    path,
+		Mime:    mimeType,
+		Size:    info.Size(),
+		AddedAt: time.Now(),
+	}
+	cursor.Attachments = append(cursor.Attachments, a)
+	cursor.Updated = time.Now()
+	return &a, nil
+}
+
+// DetachFile removes the attachment with the given id from cursor.
+func (cd *CursorDirectory) DetachFile(cursor *Cursor, id string) error {
+	for i, a := range cursor.Attachments {
+		if a.ID == id {
+			cursor.Attachments = append(cursor.Attachments[:i], cursor.Attachments[i+1:]...)
+			cursor.Updated = time.Now()
+			return nil
+		}
+	}
+	return fmt.Errorf("attachment %s not found", id)
+}
+
+// HandleFSEvent applies a filesystem change reported by an fswatch.Watcher
+// to every cursor whose FilePath matches ev.Path: a write bumps Updated so
+// the pane's "last modified" reflects the on-disk edit, while a remove or
+// rename marks the cursor Orphaned since its backing file is gone.
+func (cd *CursorDirectory) HandleFSEvent(ev fswatch.Event) {
+	for _, mc := range cd.MultiCursors {
+		for i := range mc.Cursors {
+			c := &mc.Cursors[i]
+			if c.FilePath != ev.Path {
+				continue
+			}
+			switch ev.Op {
+			case fswatch.WriteEvent:
+				c.Updated = time.Now()
+			case fswatch.RemoveEvent, fswatch.RenameEvent:
+				c.Orphaned = true
+			}
+		}
+	}
+}
+
+// SelectedCursors returns pointers to every cursor in cd whose ID is a key
+// with a true value in ids, for bulk operations (e.g. /bulk tag, /bulk
+// delete) that apply across a multi-selection spanning multicursors.
+func (cd *CursorDirectory) SelectedCursors(ids map[string]bool) []*Cursor {
+	var out []*Cursor
+	for _, mc := range cd.MultiCursors {
+		for i := range mc.Cursors {
+			if ids[mc.Cursors[i].ID] {
+				out = append(out, &mc.Cursors[i])
+			}
+		}
+	}
+	return out
+}
+
+// BulkSetPrompt sets Prompt on every cursor in cursors (/bulk prompt).
+func (cd *CursorDirectory) BulkSetPrompt(cursors []*Cursor, prompt string) {
+	for _, c := range cursors {
+		c.Prompt = prompt
+		c.Updated = time.Now()
+	}
+}
+
+// BulkTag adds each tag in adds and removes each tag in removes from every
+// cursor in cursors (/bulk tag +foo -bar). Tags already present are not
+// duplicated.
+func (cd *CursorDirectory) BulkTag(cursors []*Cursor, adds, removes []string) {
+	removeSet := make(map[string]bool, len(removes))
+	for _, t := range removes {
+		removeSet[t] = true
+	}
+
+	for _, c := range cursors {
+		var kept []string
+		for _, t := range c.Tags {
+			if !removeSet[t] {
+				kept = append(kept, t)
+			}
+		}
+		for _, t := range adds {
+			already := false
+			for _, existing := range kept {
+				if existing == t {
+					already = true
+					break
+				}
+			}
+			if !already {
+				kept = append(kept, t)
+			}
+		}
+		c.Tags = kept
+		c.Updated = time.Now()
+	}
+}
+
+// BulkDelete removes every cursor in cursors from its owning multicursor
+// (/bulk delete).
+func (cd *CursorDirectory) BulkDelete(cursors []*Cursor) {
+	ids := make(map[string]bool, len(cursors))
+	for _, c := range cursors {
+		ids[c.ID] = true
+	}
+
+	for _, mc := range cd.MultiCursors {
+		var kept []Cursor
+		changed := false
+		for _, c := range mc.Cursors {
+			if ids[c.ID] {
+				changed = true
+				continue
+			}
+			kept = append(kept, c)
+		}
+		if changed {
+			mc.Cursors = kept
+			mc.Updated = time.Now()
+		}
+	}
+}
+
 // GetMetadataDisplay returns formatted metadata for the right pane
 func (c *Cursor) GetMetadataDisplay() string {
 	display := fmt.Sprintf("File: %s\n", filepath.Base(c.FilePath))
@@ -0,0 +1,116 @@
+package codeintel
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType enumerates the CursorDirectory mutations EventBus publishes.
+type EventType string
+
+const (
+	EventMCCreated        EventType = "mc_created"
+	EventMCDeleted        EventType = "mc_deleted"
+	EventCursorAdded      EventType = "cursor_added"
+	EventCursorRemoved    EventType = "cursor_removed"
+	EventCursorMoved      EventType = "cursor_moved"
+	EventMCSwitched       EventType = "mc_switched"
+	EventThemeReloaded    EventType = "theme_reloaded"
+	EventSnapshotSaved    EventType = "snapshot_saved"
+	EventSnapshotRestored EventType = "snapshot_restored"
+	EventCursorStale      EventType = "cursor_stale"
+)
+
+// Event is one typed state mutation, serialized as a newline-delimited
+// JSON frame over GET /api/events and GET /api/cursors/{mcID}/stream.
+// Seq is monotonically increasing across every Event a given EventBus
+// ever publishes, letting a reconnecting client resume with ?since=Seq.
+type Event struct {
+	Seq      uint64      `json:"seq"`
+	Event    EventType   `json:"event"`
+	MCID     string      `json:"mc_id,omitempty"`
+	CursorID string      `json:"cursor_id,omitempty"`
+	Payload  interface{} `json:"payload,omitempty"`
+	TS       time.Time   `json:"ts"`
+}
+
+// eventBacklogSize bounds how many past Events Subscribe can replay to a
+// client resuming with ?since=<seq>.
+const eventBacklogSize = 256
+
+// subscriberBacklog bounds how many unread Events a slow subscriber's
+// channel can hold before Publish drops it rather than blocking.
+const subscriberBacklog = 32
+
+// EventBus fans CursorDirectory mutations out to subscribers - the
+// streaming API handlers - keeping a bounded backlog so a client
+// reconnecting with ?since=<seq> can resume instead of missing whatever
+// happened while it was disconnected.
+type EventBus struct {
+	mu      sync.Mutex
+	nextSeq uint64
+	backlog []Event
+	subs    map[chan Event]bool
+}
+
+// NewEventBus returns an empty EventBus ready to Publish to and
+// Subscribe from.
+func NewEventBus() *EventBus {
+	return &EventBus{subs: make(map[chan Event]bool)}
+}
+
+// Publish assigns ev the next sequence number and current time, appends
+// it to the backlog, and fans it out to every current subscriber.
+// A subscriber whose channel is already full is dropped instead of
+// blocking the publisher - the same "let a slow client fall behind
+// rather than stall everyone else" tradeoff CommandMiddleware's
+// streaming took for panel output.
+func (b *EventBus) Publish(ev Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextSeq++
+	ev.Seq = b.nextSeq
+	ev.TS = time.Now()
+
+	b.backlog = append(b.backlog, ev)
+	if len(b.backlog) > eventBacklogSize {
+		b.backlog = b.backlog[len(b.backlog)-eventBacklogSize:]
+	}
+
+	for ch := range b.subs {
+		select {
+		case ch <- ev:
+		default:
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe registers a new subscriber and returns its event channel, an
+// unsubscribe func the caller must call when done, and every backlogged
+// Event with Seq greater than since - the replay a ?since=<seq>
+// reconnect needs before it starts reading the live channel.
+func (b *EventBus) Subscribe(since uint64) (events <-chan Event, unsubscribe func(), resume []Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ev := range b.backlog {
+		if ev.Seq > since {
+			resume = append(resume, ev)
+		}
+	}
+
+	ch := make(chan Event, subscriberBacklog)
+	b.subs[ch] = true
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if b.subs[ch] {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}, resume
+}
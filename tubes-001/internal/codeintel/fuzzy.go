@@ -0,0 +1,87 @@
+package codeintel
+
+import "unicode"
+
+// Bonus/penalty weights for FuzzyMatch, tuned the way sahilm/fuzzy and fzf
+// tune theirs: a flat per-rune match score, a bonus for landing on a word
+// boundary or right after the previous match, and a penalty per skipped
+// rune between matches.
+const (
+	fuzzyScoreMatch       = 16
+	fuzzyGapPenalty       = 3
+	fuzzyBonusBoundary    = 10
+	fuzzyBonusConsecutive = 8
+)
+
+// runeAt pairs a decoded rune from a fuzzy-match candidate with its byte
+// offset in the original string, so matched positions can be reported in
+// bytes (what tview color tags need) while matching runs over runes.
+type runeAt struct {
+	r      rune
+	offset int
+}
+
+// FuzzyMatch reports whether query's runes all appear in candidate, in
+// order and case-insensitively, and scores how good a match it is: each
+// matched rune scores fuzzyScoreMatch, plus fuzzyBonusBoundary if it lands
+// right after a '/', '_', '-', '.' separator (or at the very start) or at
+// a camelCase transition, plus fuzzyBonusConsecutive if it immediately
+// follows the previous match, minus fuzzyGapPenalty for each candidate
+// rune skipped since the previous match. offsets holds the byte offset of
+// each matched rune into candidate, for callers that want to highlight
+// them. ok is false (and score/offsets are zero) if query doesn't match
+// candidate at all.
+func FuzzyMatch(query, candidate string) (score int, offsets []int, ok bool) {
+	if query == "" {
+		return 0, nil, true
+	}
+	if candidate == "" {
+		return 0, nil, false
+	}
+
+	cr := make([]runeAt, 0, len(candidate))
+	for i, r := range candidate {
+		cr = append(cr, runeAt{r, i})
+	}
+	qr := []rune(query)
+
+	qi := 0
+	lastMatch := -1
+	for ci := 0; ci < len(cr) && qi < len(qr); ci++ {
+		if unicode.ToLower(cr[ci].r) != unicode.ToLower(qr[qi]) {
+			continue
+		}
+
+		offsets = append(offsets, cr[ci].offset)
+		score += fuzzyScoreMatch
+
+		if isWordBoundary(cr, ci) {
+			score += fuzzyBonusBoundary
+		}
+		if lastMatch == ci-1 {
+			score += fuzzyBonusConsecutive
+		} else if lastMatch >= 0 {
+			score -= fuzzyGapPenalty * (ci - lastMatch - 1)
+		}
+
+		lastMatch = ci
+		qi++
+	}
+
+	if qi < len(qr) {
+		return 0, nil, false
+	}
+	return score, offsets, true
+}
+
+func isWordBoundary(cr []runeAt, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := cr[i-1].r
+	switch prev {
+	case '/', '_', '-', '.':
+		return true
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cr[i].r)
+}
@@ -0,0 +1,115 @@
+package codeintel
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Tokenizer counts how many LLM tokens a string would cost. Swap in a
+// real tiktoken encoding here if one's ever vendored in; NewTokenizer's
+// default approximates cl100k_base's density without shipping its
+// ~100k-entry merge table.
+type Tokenizer interface {
+	Count(text string) int
+}
+
+// splitPattern mirrors cl100k_base's pre-tokenizer at a coarse grain:
+// runs of letters, runs of digits, runs of other non-space symbols, and
+// runs of whitespace each become their own piece before BPE merging
+// would normally apply.
+var splitPattern = regexp.MustCompile(`[\p{L}]+|[\p{N}]+|[^\s\p{L}\p{N}]+|\s+`)
+
+// avgTokenBytes is roughly cl100k_base's average bytes-per-token for
+// English text; pieces longer than this cost more than one token.
+const avgTokenBytes = 4
+
+// defaultTokenizer approximates cl100k_base without its real merge
+// table: each pre-tokenized piece costs one token per avgTokenBytes,
+// rounded up.
+type defaultTokenizer struct{}
+
+// NewTokenizer returns the built-in cl100k_base-approximating Tokenizer.
+func NewTokenizer() Tokenizer { return defaultTokenizer{} }
+
+func (defaultTokenizer) Count(text string) int {
+	count := 0
+	for _, piece := range splitPattern.FindAllString(text, -1) {
+		n := len(piece) / avgTokenBytes
+		if len(piece)%avgTokenBytes != 0 || n == 0 {
+			n++
+		}
+		count += n
+	}
+	return count
+}
+
+// Packer builds an LLM context from a CursorDirectory's current
+// multicursor selection within a token budget, unlike BuildLLMContext
+// which always concatenates every cursor verbatim regardless of size.
+type Packer struct {
+	Tokenizer Tokenizer
+	MaxTokens int
+}
+
+// NewPacker returns a Packer using the default cl100k_base-approximating
+// Tokenizer and the given token budget.
+func NewPacker(maxTokens int) *Packer {
+	return &Packer{Tokenizer: NewTokenizer(), MaxTokens: maxTokens}
+}
+
+// cursorSection is BuildLLMContext's per-cursor section format, reused
+// here so Pack's output matches it.
+func cursorSection(c Cursor) string {
+	return fmt.Sprintf("## %s (%d-%d)\n\n```\n%s\n```\n\n",
+		filepath.Base(c.FilePath), c.StartLine, c.EndLine, c.Content)
+}
+
+// Pack builds context in the same shape as BuildLLMContext, but drops
+// cursors once MaxTokens would be exceeded, preferring to keep the most
+// recently updated ones (the ones most likely to be what the user's
+// currently looking at) while preserving the multicursor's original
+// cursor order in the output. It returns the packed context and how
+// many cursors didn't fit.
+func (p *Packer) Pack(cd *CursorDirectory) (context string, dropped int) {
+	mc := cd.GetCurrentMultiCursor()
+	if mc == nil {
+		return "No multicursor selected", 0
+	}
+
+	header := fmt.Sprintf("# %s\n\n%s\n\n", mc.Title, mc.Description)
+	budget := p.MaxTokens - p.Tokenizer.Count(header)
+
+	sections := make([]string, len(mc.Cursors))
+	costs := make([]int, len(mc.Cursors))
+	order := make([]int, len(mc.Cursors))
+	for i, cursor := range mc.Cursors {
+		sections[i] = cursorSection(cursor)
+		costs[i] = p.Tokenizer.Count(sections[i])
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return mc.Cursors[order[i]].Updated.After(mc.Cursors[order[j]].Updated)
+	})
+
+	included := make([]bool, len(mc.Cursors))
+	for _, i := range order {
+		if costs[i] > budget {
+			dropped++
+			continue
+		}
+		budget -= costs[i]
+		included[i] = true
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	for i, section := range sections {
+		if included[i] {
+			b.WriteString(section)
+		}
+	}
+	return b.String(), dropped
+}
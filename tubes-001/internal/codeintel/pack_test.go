@@ -0,0 +1,122 @@
+package codeintel
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// wordCountTokenizer is a fake Tokenizer for tests: it costs one token
+// per whitespace-separated word, independent of NewTokenizer's
+// cl100k_base approximation, so tests can reason about exact budgets.
+type wordCountTokenizer struct{}
+
+func (wordCountTokenizer) Count(text string) int {
+	return len(strings.Fields(text))
+}
+
+func newTestCursor(id, path, content string, updated time.Time) Cursor {
+	return Cursor{
+		ID:        id,
+		FilePath:  path,
+		StartLine: 1,
+		EndLine:   1,
+		Content:   content,
+		Updated:   updated,
+	}
+}
+
+func newTestDirectory(cursors ...Cursor) *CursorDirectory {
+	cd := NewCursorDirectory()
+	mc := cd.NewMultiCursor("Test MC", "a test multicursor")
+	mc.Cursors = append(mc.Cursors, cursors...)
+	cd.SetCurrentSelection(mc.ID, "")
+	return cd
+}
+
+func TestPackFitsEverythingWithinBudget(t *testing.T) {
+	now := time.Now()
+	cd := newTestDirectory(
+		newTestCursor("c1", "a.go", "one two three", now),
+		newTestCursor("c2", "b.go", "four five", now),
+	)
+
+	p := &Packer{Tokenizer: wordCountTokenizer{}, MaxTokens: 1000}
+	context, dropped := p.Pack(cd)
+
+	if dropped != 0 {
+		t.Fatalf("Pack() dropped = %d, want 0", dropped)
+	}
+	if !strings.Contains(context, "one two three") || !strings.Contains(context, "four five") {
+		t.Fatalf("Pack() context = %q, want both cursors included", context)
+	}
+}
+
+func TestPackDropsOversizedCursor(t *testing.T) {
+	now := time.Now()
+	cd := newTestDirectory(
+		newTestCursor("c1", "a.go", "one two three four five six seven eight", now),
+	)
+
+	p := &Packer{Tokenizer: wordCountTokenizer{}, MaxTokens: 1}
+	context, dropped := p.Pack(cd)
+
+	if dropped != 1 {
+		t.Fatalf("Pack() dropped = %d, want 1", dropped)
+	}
+	if strings.Contains(context, "one two three") {
+		t.Fatalf("Pack() context = %q, want the oversized cursor dropped", context)
+	}
+}
+
+func TestPackPrefersMostRecentlyUpdated(t *testing.T) {
+	older := time.Now().Add(-time.Hour)
+	newer := time.Now()
+
+	cd := newTestDirectory(
+		newTestCursor("old", "old.go", "alpha beta", older),
+		newTestCursor("new", "new.go", "gamma delta", newer),
+	)
+
+	// Budget fits exactly one cursor's section (both cost the same under
+	// wordCountTokenizer), so the more recently updated one should win
+	// while the multicursor's original order is preserved in the output.
+	header := `# Test MC
+
+a test multicursor
+
+`
+	headerCost := wordCountTokenizer{}.Count(header)
+	oneSection := wordCountTokenizer{}.Count(cursorSection(newTestCursor("new", "new.go", "gamma delta", newer)))
+	p := &Packer{Tokenizer: wordCountTokenizer{}, MaxTokens: headerCost + oneSection}
+
+	context, dropped := p.Pack(cd)
+
+	if dropped != 1 {
+		t.Fatalf("Pack() dropped = %d, want 1", dropped)
+	}
+	if !strings.Contains(context, "gamma delta") {
+		t.Fatalf("Pack() context = %q, want the more recently updated cursor kept", context)
+	}
+	if strings.Contains(context, "alpha beta") {
+		t.Fatalf("Pack() context = %q, want the older cursor dropped", context)
+	}
+	// The kept cursor's section still comes after the multicursor header.
+	if strings.Index(context, "# Test MC") > strings.Index(context, "gamma delta") {
+		t.Fatalf("Pack() context = %q, want header before cursor sections", context)
+	}
+}
+
+func TestPackNoMultiCursorSelected(t *testing.T) {
+	cd := NewCursorDirectory()
+	p := &Packer{Tokenizer: wordCountTokenizer{}, MaxTokens: 100}
+
+	context, dropped := p.Pack(cd)
+
+	if dropped != 0 {
+		t.Fatalf("Pack() dropped = %d, want 0", dropped)
+	}
+	if context != "No multicursor selected" {
+		t.Fatalf("Pack() context = %q, want the no-selection message", context)
+	}
+}
@@ -0,0 +1,365 @@
+package codeintel
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrSnapshotNotFound is wrapped into every "snapshot not found" error
+// below, so api.Server can classify it with errors.Is instead of
+// matching the message.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// SnapshotMeta describes one stored snapshot, tracked in the store's
+// manifest alongside the blob it describes.
+type SnapshotMeta struct {
+	Name         string    `json:"name"`
+	Created      time.Time `json:"created"`
+	Size         int64     `json:"size"`
+	SHA256       string    `json:"sha256"`
+	Compressed   bool      `json:"compressed"`
+	MultiCursors int       `json:"multi_cursors"`
+	Cursors      int       `json:"cursors"`
+}
+
+// SnapshotStore persists whole-CursorDirectory snapshots under dir as
+// JSON blobs (optionally gzip-compressed), tracked in a manifest.json.
+// It keeps at most maxHistory snapshots, evicting the oldest once a new
+// one would exceed that bound; maxHistory <= 0 means unbounded.
+type SnapshotStore struct {
+	dir        string
+	maxHistory int
+}
+
+// NewSnapshotStore returns a SnapshotStore rooted at dir, which is
+// created on first Create if it doesn't already exist.
+func NewSnapshotStore(dir string, maxHistory int) *SnapshotStore {
+	return &SnapshotStore{dir: dir, maxHistory: maxHistory}
+}
+
+func (s *SnapshotStore) manifestPath() string {
+	return filepath.Join(s.dir, "manifest.json")
+}
+
+// blobPath joins name onto s.dir, rejecting any name that would resolve
+// outside it - name comes straight from the request body on POST
+// /api/snapshots and friends, never through http.ServeMux's path
+// cleaning, so it needs the same containment check Workspace.Resolve
+// applies to URL paths.
+func (s *SnapshotStore) blobPath(name string, compressed bool) (string, error) {
+	ext := ".json"
+	if compressed {
+		ext = ".json.gz"
+	}
+	full := filepath.Join(s.dir, filepath.Clean("/"+name)+ext)
+	if full != s.dir && !strings.HasPrefix(full, s.dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("snapshot name %q escapes snapshot dir", name)
+	}
+	return full, nil
+}
+
+func (s *SnapshotStore) loadManifest() ([]SnapshotMeta, error) {
+	data, err := os.ReadFile(s.manifestPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var manifest []SnapshotMeta
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (s *SnapshotStore) saveManifest(manifest []SnapshotMeta) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.manifestPath(), data, 0o644)
+}
+
+// Create snapshots cd under name, optionally gzip-compressed, and
+// returns the resulting metadata. A snapshot already named name is
+// replaced. Once the store holds more than maxHistory snapshots, the
+// oldest are evicted (blob and manifest entry both). ctx is checked
+// before any disk I/O so a disconnected client's snapshot isn't
+// written for nothing.
+func (s *SnapshotStore) Create(ctx context.Context, name string, cd *CursorDirectory, compress bool) (SnapshotMeta, error) {
+	if err := ctx.Err(); err != nil {
+		return SnapshotMeta{}, err
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return SnapshotMeta{}, err
+	}
+
+	data, err := json.MarshalIndent(cd, "", "  ")
+	if err != nil {
+		return SnapshotMeta{}, err
+	}
+
+	sum := sha256.Sum256(data)
+	meta := SnapshotMeta{
+		Name:         name,
+		Created:      time.Now(),
+		SHA256:       hex.EncodeToString(sum[:]),
+		Compressed:   compress,
+		MultiCursors: len(cd.MultiCursors),
+		Cursors:      totalCursors(cd),
+	}
+
+	if err := s.writeBlob(name, data, compress); err != nil {
+		return SnapshotMeta{}, err
+	}
+
+	path, err := s.blobPath(name, compress)
+	if err != nil {
+		return SnapshotMeta{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return SnapshotMeta{}, err
+	}
+	meta.Size = info.Size()
+
+	manifest, err := s.loadManifest()
+	if err != nil {
+		return SnapshotMeta{}, err
+	}
+	manifest = append(removeByName(manifest, name), meta)
+	sort.Slice(manifest, func(i, j int) bool { return manifest[i].Created.Before(manifest[j].Created) })
+
+	for s.maxHistory > 0 && len(manifest) > s.maxHistory {
+		evicted := manifest[0]
+		manifest = manifest[1:]
+		if evictedPath, err := s.blobPath(evicted.Name, evicted.Compressed); err == nil {
+			os.Remove(evictedPath)
+		}
+	}
+
+	if err := s.saveManifest(manifest); err != nil {
+		return SnapshotMeta{}, err
+	}
+	return meta, nil
+}
+
+func (s *SnapshotStore) writeBlob(name string, data []byte, compress bool) error {
+	path, err := s.blobPath(name, compress)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if !compress {
+		_, err := f.Write(data)
+		return err
+	}
+
+	gw := gzip.NewWriter(f)
+	if _, err := gw.Write(data); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// List returns every snapshot's metadata, most recently created first.
+func (s *SnapshotStore) List(ctx context.Context) ([]SnapshotMeta, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	manifest, err := s.loadManifest()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(manifest, func(i, j int) bool { return manifest[i].Created.After(manifest[j].Created) })
+	return manifest, nil
+}
+
+// Load reads back the named snapshot's raw CursorDirectory JSON blob.
+func (s *SnapshotStore) Load(ctx context.Context, name string) ([]byte, SnapshotMeta, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, SnapshotMeta{}, err
+	}
+
+	manifest, err := s.loadManifest()
+	if err != nil {
+		return nil, SnapshotMeta{}, err
+	}
+	meta, ok := findByName(manifest, name)
+	if !ok {
+		return nil, SnapshotMeta{}, fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+
+	path, err := s.blobPath(name, meta.Compressed)
+	if err != nil {
+		return nil, SnapshotMeta{}, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, SnapshotMeta{}, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if meta.Compressed {
+		gr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, SnapshotMeta{}, err
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, SnapshotMeta{}, err
+	}
+	return data, meta, nil
+}
+
+// Restore decodes the named snapshot's CursorDirectory into dst in
+// place, preserving dst's EventBus so existing subscribers stay
+// attached across the swap, and publishes EventSnapshotRestored.
+func (s *SnapshotStore) Restore(ctx context.Context, name string, dst *CursorDirectory) error {
+	data, _, err := s.Load(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	var restored CursorDirectory
+	if err := json.Unmarshal(data, &restored); err != nil {
+		return err
+	}
+
+	dst.MultiCursors = restored.MultiCursors
+	dst.CurrentMC = restored.CurrentMC
+	dst.CurrentC = restored.CurrentC
+	dst.NextID = restored.NextID
+	dst.Events.Publish(Event{Event: EventSnapshotRestored, Payload: name})
+	return nil
+}
+
+// Delete removes the named snapshot's blob and manifest entry.
+func (s *SnapshotStore) Delete(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	manifest, err := s.loadManifest()
+	if err != nil {
+		return err
+	}
+	meta, ok := findByName(manifest, name)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+	}
+	path, err := s.blobPath(name, meta.Compressed)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return s.saveManifest(removeByName(manifest, name))
+}
+
+// SnapshotDiff is a structural, multicursor-level summary of what
+// changed between two snapshots - enough to preview a restore without
+// diffing the full JSON blobs.
+type SnapshotDiff struct {
+	AddedMC     []string `json:"added_mc"`
+	RemovedMC   []string `json:"removed_mc"`
+	ChangedMC   []string `json:"changed_mc"`
+	CursorDelta int      `json:"cursor_delta"`
+}
+
+// Diff compares the snapshot named from against to: which multicursor
+// ids were added, removed, or changed (title, cursor count, or updated
+// time differ), plus the net change in total cursor count.
+func (s *SnapshotStore) Diff(ctx context.Context, from, to string) (SnapshotDiff, error) {
+	fromData, _, err := s.Load(ctx, from)
+	if err != nil {
+		return SnapshotDiff{}, err
+	}
+	toData, _, err := s.Load(ctx, to)
+	if err != nil {
+		return SnapshotDiff{}, err
+	}
+
+	var a, b CursorDirectory
+	if err := json.Unmarshal(fromData, &a); err != nil {
+		return SnapshotDiff{}, err
+	}
+	if err := json.Unmarshal(toData, &b); err != nil {
+		return SnapshotDiff{}, err
+	}
+
+	var diff SnapshotDiff
+	for id, mc := range b.MultiCursors {
+		prev, existed := a.MultiCursors[id]
+		if !existed {
+			diff.AddedMC = append(diff.AddedMC, id)
+			continue
+		}
+		if prev.Title != mc.Title || len(prev.Cursors) != len(mc.Cursors) || !prev.Updated.Equal(mc.Updated) {
+			diff.ChangedMC = append(diff.ChangedMC, id)
+		}
+	}
+	for id := range a.MultiCursors {
+		if _, exists := b.MultiCursors[id]; !exists {
+			diff.RemovedMC = append(diff.RemovedMC, id)
+		}
+	}
+	diff.CursorDelta = totalCursors(&b) - totalCursors(&a)
+
+	sort.Strings(diff.AddedMC)
+	sort.Strings(diff.RemovedMC)
+	sort.Strings(diff.ChangedMC)
+	return diff, nil
+}
+
+func totalCursors(cd *CursorDirectory) int {
+	total := 0
+	for _, mc := range cd.MultiCursors {
+		total += len(mc.Cursors)
+	}
+	return total
+}
+
+func findByName(manifest []SnapshotMeta, name string) (SnapshotMeta, bool) {
+	for _, m := range manifest {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return SnapshotMeta{}, false
+}
+
+func removeByName(manifest []SnapshotMeta, name string) []SnapshotMeta {
+	out := manifest[:0:0]
+	for _, m := range manifest {
+		if m.Name != name {
+			out = append(out, m)
+		}
+	}
+	return out
+}
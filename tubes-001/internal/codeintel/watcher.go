@@ -0,0 +1,221 @@
+package codeintel
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// CursorWatcher watches every unique FilePath referenced by cursors in a
+// CursorDirectory and keeps their cached Content in sync with disk. It's
+// a narrower cousin of fswatch.Watcher: rather than a directory tree, it
+// watches exactly the files cursors point at, and understands enough
+// about Cursor to re-read just the StartLine..EndLine range that changed.
+type CursorWatcher struct {
+	cd      *CursorDirectory
+	fsw     *fsnotify.Watcher
+	redraw  chan struct{}
+	done    chan struct{}
+	mu      sync.Mutex
+	watched map[string]int // FilePath -> number of cursors referencing it
+}
+
+// NewCursorWatcher starts watching every FilePath currently referenced by
+// cd's cursors. Call Close when done.
+func NewCursorWatcher(cd *CursorDirectory) (*CursorWatcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &CursorWatcher{
+		cd:      cd,
+		fsw:     fsw,
+		redraw:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		watched: make(map[string]int),
+	}
+
+	for _, mc := range cd.MultiCursors {
+		for i := range mc.Cursors {
+			w.Watch(mc.Cursors[i].FilePath)
+		}
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// Redraw receives a value whenever a watched file changed and at least
+// one cursor's cached Content (or Orphaned state) was refreshed as a
+// result - consume it from the tview event loop (e.g. via
+// app.QueueUpdateDraw) to trigger a repaint.
+func (w *CursorWatcher) Redraw() <-chan struct{} { return w.redraw }
+
+// Watch adds path to the watch set, or bumps its reference count if it's
+// already watched. Call once per cursor added (e.g. via /cursor add) that
+// points at path.
+func (w *CursorWatcher) Watch(path string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.watched[path] > 0 {
+		w.watched[path]++
+		return nil
+	}
+	if err := w.fsw.Add(path); err != nil {
+		return err
+	}
+	w.watched[path] = 1
+	return nil
+}
+
+// Unwatch drops one reference to path (e.g. for a cursor removed via
+// /cursor rm), removing the underlying fsnotify watch once no cursor
+// references it anymore.
+func (w *CursorWatcher) Unwatch(path string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.watched[path] == 0 {
+		return
+	}
+	w.watched[path]--
+	if w.watched[path] == 0 {
+		delete(w.watched, path)
+		w.fsw.Remove(path)
+	}
+}
+
+// Watched returns the paths currently under watch, for /watch status.
+func (w *CursorWatcher) Watched() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	paths := make([]string, 0, len(w.watched))
+	for p := range w.watched {
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+// Close stops the watcher and releases the underlying fsnotify watcher.
+func (w *CursorWatcher) Close() error {
+	close(w.done)
+	return w.fsw.Close()
+}
+
+func (w *CursorWatcher) run() {
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			switch {
+			case event.Op&fsnotify.Write != 0:
+				w.refresh(event.Name)
+			case event.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+				w.markStale(event.Name)
+			}
+
+		case _, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// refresh re-reads path's StartLine..EndLine range into Content for every
+// cursor pointing at it, marking a cursor Orphaned instead of crashing if
+// the read fails (e.g. the file was truncated out from under it).
+func (w *CursorWatcher) refresh(path string) {
+	changed := false
+	for _, mc := range w.cd.MultiCursors {
+		for i := range mc.Cursors {
+			c := &mc.Cursors[i]
+			if c.FilePath != path {
+				continue
+			}
+
+			content, err := readLineRange(path, c.StartLine, c.EndLine)
+			if err != nil {
+				if !c.Orphaned {
+					c.Orphaned = true
+					changed = true
+				}
+				continue
+			}
+			if c.Orphaned || content != c.Content {
+				c.Content = content
+				c.Orphaned = false
+				c.Updated = time.Now()
+				changed = true
+			}
+		}
+	}
+	if changed {
+		w.signal()
+	}
+}
+
+// markStale flags every cursor pointing at path as orphaned rather than
+// crashing on its next read.
+func (w *CursorWatcher) markStale(path string) {
+	changed := false
+	for _, mc := range w.cd.MultiCursors {
+		for i := range mc.Cursors {
+			c := &mc.Cursors[i]
+			if c.FilePath == path && !c.Orphaned {
+				c.Orphaned = true
+				changed = true
+			}
+		}
+	}
+	if changed {
+		w.signal()
+	}
+}
+
+func (w *CursorWatcher) signal() {
+	select {
+	case w.redraw <- struct{}{}:
+	default:
+	}
+}
+
+// readLineRange reads the 1-indexed, inclusive line range [start, end]
+// from path.
+func readLineRange(path string, start, end int) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if line < start {
+			continue
+		}
+		if line > end {
+			break
+		}
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,159 @@
+package codeintel
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrRangeOutOfBounds is wrapped into the error ReadRange returns when
+// start is beyond the file's last line, so api.Server can classify it
+// with errors.Is instead of matching the message.
+var ErrRangeOutOfBounds = errors.New("range out of bounds")
+
+// FileEntry is one entry in a Workspace directory listing.
+type FileEntry struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	IsDir   bool      `json:"is_dir"`
+}
+
+// SortBy selects the field Workspace.List orders a listing by.
+type SortBy string
+
+const (
+	SortByName SortBy = "name"
+	SortBySize SortBy = "size"
+	SortByTime SortBy = "time"
+)
+
+// Workspace roots file-browsing and content-reading at a fixed
+// directory, rejecting any requested path that would resolve outside
+// it - the strict path-traversal check GET /api/files and
+// /api/files/content both go through before touching the filesystem.
+type Workspace struct {
+	root string
+}
+
+// NewWorkspace returns a Workspace rooted at the absolute path of root.
+func NewWorkspace(root string) (*Workspace, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	return &Workspace{root: abs}, nil
+}
+
+// Resolve joins root with path, rejecting the result if it would
+// escape root.
+func (w *Workspace) Resolve(path string) (string, error) {
+	full := filepath.Join(w.root, filepath.Clean("/"+path))
+	if full != w.root && !strings.HasPrefix(full, w.root+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes workspace root", path)
+	}
+	return full, nil
+}
+
+// List returns path's directory entries, sorted by sortBy ("name" by
+// default) in ascending order unless desc is set. ctx is checked
+// before touching the filesystem so a disconnected client's listing
+// is abandoned rather than walked for nothing.
+func (w *Workspace) List(ctx context.Context, path string, sortBy SortBy, desc bool) ([]FileEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	full, err := w.Resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(full)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]FileEntry, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		out = append(out, FileEntry{
+			Name:    e.Name(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			IsDir:   e.IsDir(),
+		})
+	}
+
+	less := func(i, j int) bool {
+		switch sortBy {
+		case SortBySize:
+			return out[i].Size < out[j].Size
+		case SortByTime:
+			return out[i].ModTime.Before(out[j].ModTime)
+		default:
+			return out[i].Name < out[j].Name
+		}
+	}
+	if desc {
+		asc := less
+		less = func(i, j int) bool { return asc(j, i) }
+	}
+	sort.Slice(out, less)
+
+	return out, nil
+}
+
+// ReadRange reads lines start..end (1-indexed, inclusive; start<1 is
+// clamped to 1 and end<=0 or beyond the file means to the last line)
+// from path, returning the joined content and a stable ETag that
+// changes whenever that range's text does. ctx is checked before
+// touching the filesystem so a disconnected client's read is abandoned
+// rather than served for nothing.
+func (w *Workspace) ReadRange(ctx context.Context, path string, start, end int) (content, etag string, err error) {
+	if err := ctx.Err(); err != nil {
+		return "", "", err
+	}
+
+	full, err := w.Resolve(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	data, err := os.ReadFile(full)
+	if err != nil {
+		return "", "", err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	if start < 1 {
+		start = 1
+	}
+	if end <= 0 || end > len(lines) {
+		end = len(lines)
+	}
+	if start > len(lines) {
+		return "", "", fmt.Errorf("%w: start line %d beyond end of file (%d lines)", ErrRangeOutOfBounds, start, len(lines))
+	}
+
+	content = strings.Join(lines[start-1:end], "\n")
+	return content, ETag(content), nil
+}
+
+// ETag hashes content to a short stable identifier: unchanged content
+// always hashes the same, so a cursor's recorded ETag can detect when
+// the range it points at has drifted.
+func ETag(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,203 @@
+// Package fswatch recursively watches a directory tree for changes,
+// debouncing bursts of fsnotify events into one typed Event per path per
+// settle period.
+package fswatch
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Op is the kind of change an Event reports.
+type Op int
+
+const (
+	CreateEvent Op = iota
+	WriteEvent
+	RemoveEvent
+	RenameEvent
+)
+
+func (op Op) String() string {
+	switch op {
+	case CreateEvent:
+		return "create"
+	case WriteEvent:
+		return "write"
+	case RemoveEvent:
+		return "remove"
+	case RenameEvent:
+		return "rename"
+	default:
+		return "unknown"
+	}
+}
+
+// Event reports a debounced filesystem change at Path.
+type Event struct {
+	Path string
+	Op   Op
+}
+
+// Watcher recursively watches a directory tree (plus any individually
+// added paths), coalescing bursts into one Event per path per debounce
+// period.
+type Watcher struct {
+	fsw      *fsnotify.Watcher
+	events   chan Event
+	done     chan struct{}
+	debounce time.Duration
+	seen     map[string]bool // real (symlink-resolved) dirs already watched, to guard against symlink loops
+}
+
+// New starts watching root's directory tree recursively. Call Close when
+// done.
+func New(root string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		fsw:      fsw,
+		events:   make(chan Event, 8),
+		done:     make(chan struct{}),
+		debounce: 100 * time.Millisecond,
+		seen:     make(map[string]bool),
+	}
+
+	if err := w.addTree(root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// addTree walks root, adding every directory to the underlying fsnotify
+// watcher. Directories are keyed by their symlink-resolved real path so a
+// symlink cycle (a directory linking back to an ancestor) is only ever
+// descended into once. fsnotify only watches one directory level at a
+// time, so subdirectories created later are picked up in run via their
+// own fsnotify.Create event.
+func (w *Watcher) addTree(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		real, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return nil
+		}
+		if w.seen[real] {
+			return filepath.SkipDir
+		}
+		w.seen[real] = true
+		return w.fsw.Add(path)
+	})
+}
+
+// AddFile watches an individual file (e.g. a Cursor's FilePath) in
+// addition to the directory tree passed to New.
+func (w *Watcher) AddFile(path string) error {
+	return w.fsw.Add(path)
+}
+
+// AddDir recursively watches an additional directory tree (e.g. one a
+// Cursor points into outside of the root passed to New), applying the
+// same symlink-loop guard as the initial tree.
+func (w *Watcher) AddDir(path string) error {
+	return w.addTree(path)
+}
+
+// Events returns the channel Event values are delivered on.
+func (w *Watcher) Events() <-chan Event { return w.events }
+
+// Close stops the watcher and releases the underlying fsnotify watcher.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.fsw.Close()
+}
+
+func (w *Watcher) run() {
+	type pending struct {
+		op    Op
+		timer *time.Timer
+	}
+	timers := make(map[string]*pending)
+
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+
+			op, ok := translateOp(event.Op)
+			if !ok {
+				continue
+			}
+
+			if op == CreateEvent {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					_ = w.addTree(event.Name)
+				}
+			}
+
+			path := event.Name
+			if p, exists := timers[path]; exists {
+				p.op = op
+				p.timer.Reset(w.debounce)
+				continue
+			}
+			p := &pending{op: op}
+			p.timer = time.AfterFunc(w.debounce, func() {
+				w.emit(Event{Path: path, Op: p.op})
+			})
+			timers[path] = p
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("fswatch: %v", err)
+
+		case <-w.done:
+			for _, p := range timers {
+				p.timer.Stop()
+			}
+			return
+		}
+	}
+}
+
+func (w *Watcher) emit(e Event) {
+	select {
+	case w.events <- e:
+	default:
+	}
+}
+
+func translateOp(op fsnotify.Op) (Op, bool) {
+	switch {
+	case op&fsnotify.Create != 0:
+		return CreateEvent, true
+	case op&fsnotify.Remove != 0:
+		return RemoveEvent, true
+	case op&fsnotify.Rename != 0:
+		return RenameEvent, true
+	case op&fsnotify.Write != 0:
+		return WriteEvent, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,226 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ContrastIssue is one named style whose foreground/background pair
+// fails its WCAG contrast threshold.
+type ContrastIssue struct {
+	Style      string
+	Foreground string
+	Background string
+	Ratio      float64
+	Required   float64
+	LargeText  bool
+}
+
+// largeTextStyles are the named styles treated as "large text" under
+// WCAG (>=18pt, or >=14pt bold) - headings - which only need a 3:1
+// contrast ratio against their background instead of body text's 4.5:1.
+var largeTextStyles = map[string]bool{
+	"header": true, "title": true, "subtitle": true,
+}
+
+// Validate checks every named style in s whose foreground and background
+// both resolve to a hex color, and returns the ones that fail WCAG AA
+// contrast: 4.5:1 for body text, 3:1 for the large/heading styles in
+// largeTextStyles.
+func Validate(s *Styles) []ContrastIssue {
+	var issues []ContrastIssue
+	for name := range s.ByName {
+		if issue, fails := contrastIssue(s, name); fails {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// contrastIssue reports whether name's style currently fails its WCAG
+// threshold in s, for Validate and renderSection's swatch annotations.
+func contrastIssue(s *Styles, name string) (ContrastIssue, bool) {
+	st, ok := s.ByName[name]
+	if !ok {
+		return ContrastIssue{}, false
+	}
+	fg := resolveHex(s, st.GetForeground())
+	bg := resolveHex(s, st.GetBackground())
+	if fg == "" || bg == "" {
+		return ContrastIssue{}, false
+	}
+
+	required := 4.5
+	large := largeTextStyles[name]
+	if large {
+		required = 3.0
+	}
+	ratio := contrastRatio(fg, bg)
+	if ratio >= required {
+		return ContrastIssue{}, false
+	}
+	return ContrastIssue{
+		Style: name, Foreground: fg, Background: bg,
+		Ratio: ratio, Required: required, LargeText: large,
+	}, true
+}
+
+// AutoFix darkens or lightens each Validate issue's foreground in HSL
+// space until its contrast ratio against the unchanged background clears
+// the WCAG threshold, mutating s.ByName (and the matching named field,
+// same as LoadStyleset) in place.
+func AutoFix(s *Styles) {
+	for _, issue := range Validate(s) {
+		fixed := fixContrast(issue.Foreground, issue.Background, issue.Required)
+		st := s.ByName[issue.Style].Foreground(lipgloss.Color(fixed))
+		s.ByName[issue.Style] = st
+		if set, ok := namedFields[issue.Style]; ok {
+			set(s, st)
+		}
+	}
+}
+
+// fixContrast nudges fgHex's HSL lightness toward white (if bgHex is
+// dark) or black (if bgHex is light) in 2% steps until the ratio against
+// bgHex clears required, or the lightness range is exhausted.
+func fixContrast(fgHex, bgHex string, required float64) string {
+	h, sat, l := rgbToHSL(hexToRGB(fgHex))
+	lighten := relativeLuminance(bgHex) < 0.5
+
+	for step := 0; step <= 50; step++ {
+		hex := hslToHex(h, sat, l)
+		if contrastRatio(hex, bgHex) >= required {
+			return hex
+		}
+		if lighten {
+			l = math.Min(1, l+0.02)
+		} else {
+			l = math.Max(0, l-0.02)
+		}
+	}
+	if lighten {
+		return hslToHex(h, sat, 1)
+	}
+	return hslToHex(h, sat, 0)
+}
+
+// contrastRatio is the WCAG contrast ratio between two hex colors:
+// (L1+0.05)/(L2+0.05), L1 the lighter of the two relative luminances.
+func contrastRatio(fgHex, bgHex string) float64 {
+	l1 := relativeLuminance(fgHex)
+	l2 := relativeLuminance(bgHex)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// relativeLuminance implements WCAG's L = 0.2126*R + 0.7152*G + 0.0722*B,
+// each channel linearized from sRGB first.
+func relativeLuminance(hex string) float64 {
+	r, g, b := hexToRGB(hex)
+	lin := func(c float64) float64 {
+		c /= 255
+		if c <= 0.03928 {
+			return c / 12.92
+		}
+		return math.Pow((c+0.055)/1.055, 2.4)
+	}
+	return 0.2126*lin(r) + 0.7152*lin(g) + 0.0722*lin(b)
+}
+
+// hexToRGB parses a "#RGB" or "#RRGGBB" string into 0-255 channel
+// values, returning all zero for anything else.
+func hexToRGB(hex string) (r, g, b float64) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return 0, 0, 0
+	}
+	ri, _ := strconv.ParseInt(hex[0:2], 16, 32)
+	gi, _ := strconv.ParseInt(hex[2:4], 16, 32)
+	bi, _ := strconv.ParseInt(hex[4:6], 16, 32)
+	return float64(ri), float64(gi), float64(bi)
+}
+
+// rgbToHSL converts 0-255 RGB channels to hue (0-360), saturation, and
+// lightness (both 0-1).
+func rgbToHSL(r, g, b float64) (h, s, l float64) {
+	r /= 255
+	g /= 255
+	b /= 255
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	l = (max + min) / 2
+	if max == min {
+		return 0, 0, l
+	}
+
+	d := max - min
+	if l > 0.5 {
+		s = d / (2 - max - min)
+	} else {
+		s = d / (max + min)
+	}
+	switch max {
+	case r:
+		h = (g - b) / d
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	return h, s, l
+}
+
+// hslToHex is rgbToHSL's inverse, returning a "#RRGGBB" string.
+func hslToHex(h, s, l float64) string {
+	if s == 0 {
+		v := int(math.Round(l * 255))
+		return fmt.Sprintf("#%02X%02X%02X", v, v, v)
+	}
+
+	var q float64
+	if l < 0.5 {
+		q = l * (1 + s)
+	} else {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+	hk := h / 360
+
+	r := hueToRGB(p, q, hk+1.0/3)
+	g := hueToRGB(p, q, hk)
+	b := hueToRGB(p, q, hk-1.0/3)
+	return fmt.Sprintf("#%02X%02X%02X",
+		int(math.Round(r*255)), int(math.Round(g*255)), int(math.Round(b*255)))
+}
+
+func hueToRGB(p, q, t float64) float64 {
+	if t < 0 {
+		t++
+	}
+	if t > 1 {
+		t--
+	}
+	switch {
+	case t < 1.0/6:
+		return p + (q-p)*6*t
+	case t < 1.0/2:
+		return q
+	case t < 2.0/3:
+		return p + (q-p)*(2.0/3-t)*6
+	default:
+		return p
+	}
+}
@@ -0,0 +1,320 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tokenNames is the Config field order Editor tabs through - the same
+// order AdaptivePalette lists them in.
+var tokenNames = []string{
+	"background", "foreground", "muted", "accent",
+	"success", "warning", "danger", "border",
+}
+
+// borderGlyphs are the border styles Editor cycles Header/Input/Active
+// through with the "b" key: normal, rounded, thick, double.
+var borderGlyphs = []lipgloss.Border{
+	lipgloss.NormalBorder(),
+	lipgloss.RoundedBorder(),
+	lipgloss.ThickBorder(),
+	lipgloss.DoubleBorder(),
+}
+
+// Editor is a Bubble Tea program that lets a user tab through a Styles'
+// Config tokens and edit their hex values, cycle the border glyph, and
+// nudge the Main pane's padding - reusing Preview, GetColorPalette, and
+// GetDesignTokens as the live panel it renders while doing so. Run it
+// with RunEditor; host applications drop into it from a keybinding and
+// get the mutated Styles back on exit.
+type Editor struct {
+	styles *Styles
+	cursor int
+
+	editing bool
+	input   string
+
+	undoStack []Config
+	redoStack []Config
+
+	borderIdx int
+	padding   int
+	showAll   bool
+
+	savePath string
+	status   string
+	quitting bool
+}
+
+// NewEditor returns an Editor over s, ready to run via RunEditor or
+// tea.NewProgram directly.
+func NewEditor(s *Styles) *Editor {
+	return &Editor{styles: s, padding: s.Main.GetPaddingTop()}
+}
+
+// RunEditor drops the caller into an interactive editor over s and
+// returns the (possibly mutated) Styles once the user quits.
+func RunEditor(s *Styles) (*Styles, error) {
+	e := NewEditor(s)
+	final, err := tea.NewProgram(e).Run()
+	if err != nil {
+		return s, err
+	}
+	return final.(*Editor).styles, nil
+}
+
+func (e *Editor) Init() tea.Cmd { return nil }
+
+func (e *Editor) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return e, nil
+	}
+	if e.editing {
+		return e.updateEditing(keyMsg)
+	}
+	return e.updateBrowsing(keyMsg)
+}
+
+func (e *Editor) updateBrowsing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "ctrl+c":
+		e.quitting = true
+		return e, tea.Quit
+	case "tab", "right", "l":
+		e.cursor = (e.cursor + 1) % len(tokenNames)
+	case "shift+tab", "left", "h":
+		e.cursor = (e.cursor - 1 + len(tokenNames)) % len(tokenNames)
+	case "enter", "e":
+		e.editing = true
+		e.input = e.currentHex()
+	case "u":
+		e.undo()
+	case "r", "ctrl+r":
+		e.redo()
+	case "b":
+		e.cycleBorder()
+	case "+", "=":
+		e.adjustPadding(1)
+	case "-":
+		e.adjustPadding(-1)
+	case "d":
+		e.copyFromPreset("dracula")
+	case "m":
+		e.copyFromPreset("monokai")
+	case "s":
+		e.copyFromPreset("solarized")
+	case "ctrl+s":
+		e.save()
+	case "t":
+		e.showAll = !e.showAll
+	}
+	return e, nil
+}
+
+func (e *Editor) updateEditing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		e.applyHex(e.input)
+		e.editing = false
+	case tea.KeyEsc:
+		e.editing = false
+	case tea.KeyBackspace:
+		if len(e.input) > 0 {
+			e.input = e.input[:len(e.input)-1]
+		}
+	case tea.KeyRunes:
+		e.input += string(msg.Runes)
+	}
+	return e, nil
+}
+
+func (e *Editor) currentToken() string { return tokenNames[e.cursor] }
+
+func (e *Editor) currentHex() string {
+	c, _ := e.tokenColor(e.currentToken())
+	return resolveHex(e.styles, c)
+}
+
+func (e *Editor) tokenColor(name string) (lipgloss.AdaptiveColor, bool) {
+	cfg := e.styles.cfg
+	switch name {
+	case "background":
+		return cfg.Background, true
+	case "foreground":
+		return cfg.Foreground, true
+	case "muted":
+		return cfg.Muted, true
+	case "accent":
+		return cfg.Accent, true
+	case "success":
+		return cfg.Success, true
+	case "warning":
+		return cfg.Warning, true
+	case "danger":
+		return cfg.Danger, true
+	case "border":
+		return cfg.Border, true
+	default:
+		return lipgloss.AdaptiveColor{}, false
+	}
+}
+
+// pushUndo snapshots the current Config before a mutation, clearing the
+// redo stack the way any editor's "new edit after undo" does.
+func (e *Editor) pushUndo() {
+	e.undoStack = append(e.undoStack, e.styles.cfg)
+	e.redoStack = nil
+}
+
+func (e *Editor) rebuild(cfg Config) {
+	e.styles = NewWithRenderer(e.styles.renderer, cfg)
+}
+
+func (e *Editor) undo() {
+	if len(e.undoStack) == 0 {
+		e.status = "Nothing to undo"
+		return
+	}
+	prev := e.undoStack[len(e.undoStack)-1]
+	e.undoStack = e.undoStack[:len(e.undoStack)-1]
+	e.redoStack = append(e.redoStack, e.styles.cfg)
+	e.rebuild(prev)
+	e.status = "Undid last change"
+}
+
+func (e *Editor) redo() {
+	if len(e.redoStack) == 0 {
+		e.status = "Nothing to redo"
+		return
+	}
+	next := e.redoStack[len(e.redoStack)-1]
+	e.redoStack = e.redoStack[:len(e.redoStack)-1]
+	e.undoStack = append(e.undoStack, e.styles.cfg)
+	e.rebuild(next)
+	e.status = "Redid change"
+}
+
+// applyHex sets the current token's Config field to hex, fixed (same
+// value for both light/dark variants - the user typed one concrete
+// color), and recompiles Styles from the result.
+func (e *Editor) applyHex(hex string) {
+	if hex == "" {
+		return
+	}
+	e.pushUndo()
+
+	cfg := e.styles.cfg
+	color := fixed(hex)
+	switch e.currentToken() {
+	case "background":
+		cfg.Background = color
+	case "foreground":
+		cfg.Foreground = color
+	case "muted":
+		cfg.Muted = color
+	case "accent":
+		cfg.Accent = color
+	case "success":
+		cfg.Success = color
+	case "warning":
+		cfg.Warning = color
+	case "danger":
+		cfg.Danger = color
+	case "border":
+		cfg.Border = color
+	}
+	e.rebuild(cfg)
+	e.status = fmt.Sprintf("Set %s to %s", e.currentToken(), hex)
+}
+
+// cycleBorder advances Header/Input/Active's border glyph through
+// borderGlyphs. The BORDER STYLES list getBorderStyles prints is a fixed
+// table and does not follow this choice.
+func (e *Editor) cycleBorder() {
+	e.borderIdx = (e.borderIdx + 1) % len(borderGlyphs)
+	b := borderGlyphs[e.borderIdx]
+	e.styles.Header = e.styles.Header.Border(b)
+	e.styles.Input = e.styles.Input.Border(b)
+	e.styles.Active = e.styles.Active.Border(b)
+	e.styles.ByName["header"] = e.styles.Header
+	e.styles.ByName["input"] = e.styles.Input
+	e.styles.ByName["active"] = e.styles.Active
+	e.status = "Cycled border glyph"
+}
+
+// adjustPadding nudges Main's padding by delta (floored at 0), the
+// "spacing" token the SPACING SCALE section of GetDesignTokens lists.
+func (e *Editor) adjustPadding(delta int) {
+	e.padding += delta
+	if e.padding < 0 {
+		e.padding = 0
+	}
+	e.styles.Main = e.styles.Main.Padding(e.padding)
+	e.styles.ByName["main"] = e.styles.Main
+	e.status = fmt.Sprintf("Main padding: %d", e.padding)
+}
+
+// copyFromPreset overwrites every token with preset's Config, the same
+// "start over from a known-good palette" shortcut a CSS theme switcher
+// gives you.
+func (e *Editor) copyFromPreset(preset string) {
+	src, ok := Themes[preset]
+	if !ok {
+		e.status = fmt.Sprintf("No preset %q", preset)
+		return
+	}
+	e.pushUndo()
+	e.rebuild(src.cfg)
+	e.status = fmt.Sprintf("Copied tokens from %s", preset)
+}
+
+// Save writes the editor's current Config to path (JSON or TOML, same
+// rule as LoadTheme/SaveTheme) and remembers path for a later ctrl+s.
+func (e *Editor) Save(path string) error {
+	e.savePath = path
+	return SaveTheme(path, e.styles)
+}
+
+func (e *Editor) save() {
+	if e.savePath == "" {
+		e.status = "No save path set - call Editor.Save(path) first"
+		return
+	}
+	if err := e.Save(e.savePath); err != nil {
+		e.status = fmt.Sprintf("Save failed: %s", err)
+	} else {
+		e.status = fmt.Sprintf("Saved to %s", e.savePath)
+	}
+}
+
+func (e *Editor) View() string {
+	if e.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("THEME EDITOR - tab: select token, enter: edit hex, b: border, +/-: padding, t: toggle full tokens, u/r: undo/redo, d/m/s: copy preset, ctrl+s: save, q: quit\n\n")
+	if e.showAll {
+		b.WriteString(GetDesignTokens(e.styles))
+	} else {
+		b.WriteString(Preview(e.styles))
+		b.WriteString("\n\n")
+		b.WriteString(GetColorPalette(e.styles))
+	}
+	b.WriteString("\n\n")
+
+	name := e.currentToken()
+	if e.editing {
+		fmt.Fprintf(&b, "> editing %s: %s_\n", name, e.input)
+	} else {
+		fmt.Fprintf(&b, "> %s (press enter to edit)\n", name)
+	}
+	if e.status != "" {
+		b.WriteString(e.status + "\n")
+	}
+	return b.String()
+}
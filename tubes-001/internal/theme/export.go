@@ -0,0 +1,125 @@
+package theme
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// resolveHex returns c's hex value for the variant s.renderer currently
+// reports (light/dark), the same resolution lipgloss itself performs at
+// Render() time. Falls back to fmt.Sprint for any TerminalColor that
+// isn't a plain Color or AdaptiveColor (e.g. ANSIColor).
+func resolveHex(s *Styles, c lipgloss.TerminalColor) string {
+	switch v := c.(type) {
+	case lipgloss.Color:
+		return string(v)
+	case lipgloss.AdaptiveColor:
+		if s.renderer.HasDarkBackground() {
+			return v.Dark
+		}
+		return v.Light
+	case lipgloss.NoColor:
+		return ""
+	default:
+		return fmt.Sprint(c)
+	}
+}
+
+// ExportGumFlags renders the named style in s as the `gum style` flags
+// that reproduce it: --foreground, --background, --border, --padding,
+// --margin. Returns "" if name isn't in s.ByName.
+func ExportGumFlags(name string, s *Styles) string {
+	st, ok := s.ByName[name]
+	if !ok {
+		return ""
+	}
+
+	var flags []string
+	if fg := resolveHex(s, st.GetForeground()); fg != "" {
+		flags = append(flags, fmt.Sprintf("--foreground %q", fg))
+	}
+	if bg := resolveHex(s, st.GetBackground()); bg != "" {
+		flags = append(flags, fmt.Sprintf("--background %q", bg))
+	}
+	if st.GetBorderTop() || st.GetBorderRight() || st.GetBorderBottom() || st.GetBorderLeft() {
+		flags = append(flags, fmt.Sprintf("--border %q", gumBorderName(st.GetBorderStyle())))
+	}
+	if pad := fmt.Sprintf("%d %d %d %d",
+		st.GetPaddingTop(), st.GetPaddingRight(), st.GetPaddingBottom(), st.GetPaddingLeft()); pad != "0 0 0 0" {
+		flags = append(flags, fmt.Sprintf("--padding %q", pad))
+	}
+	if mar := fmt.Sprintf("%d %d %d %d",
+		st.GetMarginTop(), st.GetMarginRight(), st.GetMarginBottom(), st.GetMarginLeft()); mar != "0 0 0 0" {
+		flags = append(flags, fmt.Sprintf("--margin %q", mar))
+	}
+	if st.GetBold() {
+		flags = append(flags, "--bold")
+	}
+	if st.GetItalic() {
+		flags = append(flags, "--italic")
+	}
+	if st.GetUnderline() {
+		flags = append(flags, "--underline")
+	}
+
+	return strings.Join(flags, " ")
+}
+
+// gumBorderName maps a lipgloss.Border to the --border value gum
+// recognizes, falling back to "normal" for one this table doesn't know.
+func gumBorderName(b lipgloss.Border) string {
+	switch b {
+	case lipgloss.RoundedBorder():
+		return "rounded"
+	case lipgloss.ThickBorder():
+		return "thick"
+	case lipgloss.DoubleBorder():
+		return "double"
+	case lipgloss.HiddenBorder():
+		return "hidden"
+	default:
+		return "normal"
+	}
+}
+
+// ExportCSSVariables renders every named style's foreground color in s
+// as `--color-<name>: #HEX;` custom-property declarations, sorted
+// alphabetically by style name, one per line so a caller can paste the
+// block into a <style> tag or a CSS file.
+func ExportCSSVariables(s *Styles) string {
+	names := make([]string, 0, len(s.ByName))
+	for name := range s.ByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		hex := resolveHex(s, s.ByName[name].GetForeground())
+		if hex == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("--color-%s: %s;", name, hex))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ExportANSISequences returns, for every named style in s, the raw SGR
+// escape sequence that style applies - suitable for a shell script to
+// embed directly (e.g. `printf '%s'"$(seq)"'Some text\033[0m'`) without
+// going through lipgloss or gum at all.
+func ExportANSISequences(s *Styles) map[string]string {
+	out := make(map[string]string, len(s.ByName))
+	for name, st := range s.ByName {
+		// Render a sentinel byte that can't appear in the output, then
+		// take everything before it - that's the escape sequence
+		// Render would otherwise have wrapped real content in.
+		rendered := st.Render("\x00")
+		seq, _, _ := strings.Cut(rendered, "\x00")
+		out[name] = seq
+	}
+	return out
+}
@@ -0,0 +1,253 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/fsnotify/fsnotify"
+)
+
+// colorPair is the on-disk shape of one AdaptiveColor token: a hex value
+// for each background variant, e.g. {light = "#FFFFFF", dark = "#000000"}.
+type colorPair struct {
+	Light string `json:"light" toml:"light"`
+	Dark  string `json:"dark" toml:"dark"`
+}
+
+func (p colorPair) toAdaptive() lipgloss.AdaptiveColor {
+	return lipgloss.AdaptiveColor{Light: p.Light, Dark: p.Dark}
+}
+
+func fromAdaptive(c lipgloss.AdaptiveColor) colorPair {
+	return colorPair{Light: c.Light, Dark: c.Dark}
+}
+
+// themeFile is the on-disk shape of a theme definition, in either JSON or
+// TOML (field names match Config's, lowercased), each token a colorPair.
+type themeFile struct {
+	Background colorPair `json:"background" toml:"background"`
+	Foreground colorPair `json:"foreground" toml:"foreground"`
+	Muted      colorPair `json:"muted" toml:"muted"`
+	Accent     colorPair `json:"accent" toml:"accent"`
+	Success    colorPair `json:"success" toml:"success"`
+	Warning    colorPair `json:"warning" toml:"warning"`
+	Danger     colorPair `json:"danger" toml:"danger"`
+	Border     colorPair `json:"border" toml:"border"`
+}
+
+func (f themeFile) toConfig() Config {
+	return Config{
+		Background: f.Background.toAdaptive(),
+		Foreground: f.Foreground.toAdaptive(),
+		Muted:      f.Muted.toAdaptive(),
+		Accent:     f.Accent.toAdaptive(),
+		Success:    f.Success.toAdaptive(),
+		Warning:    f.Warning.toAdaptive(),
+		Danger:     f.Danger.toAdaptive(),
+		Border:     f.Border.toAdaptive(),
+	}
+}
+
+func configToFile(cfg Config) themeFile {
+	return themeFile{
+		Background: fromAdaptive(cfg.Background),
+		Foreground: fromAdaptive(cfg.Foreground),
+		Muted:      fromAdaptive(cfg.Muted),
+		Accent:     fromAdaptive(cfg.Accent),
+		Success:    fromAdaptive(cfg.Success),
+		Warning:    fromAdaptive(cfg.Warning),
+		Danger:     fromAdaptive(cfg.Danger),
+		Border:     fromAdaptive(cfg.Border),
+	}
+}
+
+// LoadTheme reads a theme definition from path - JSON if its extension is
+// ".json", TOML otherwise - and compiles it into a *Styles against
+// lipgloss.DefaultRenderer(). Any field the file omits keeps its
+// DefaultConfig value.
+func LoadTheme(path string) (*Styles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := configToFile(DefaultConfig())
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("theme %s: %w", path, err)
+		}
+	default:
+		if err := toml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("theme %s: %w", path, err)
+		}
+	}
+
+	return NewWithRenderer(lipgloss.DefaultRenderer(), file.toConfig()), nil
+}
+
+// Themes is the built-in registry of preset Configs, keyed by name. Each
+// is compiled against lipgloss.DefaultRenderer() the same way
+// NewDefaultStyles is; callers wanting a specific renderer should compile
+// the preset's Config themselves via NewWithRenderer.
+var Themes = map[string]*Styles{
+	"dark":      NewWithRenderer(lipgloss.DefaultRenderer(), DefaultConfig()),
+	"light":     NewWithRenderer(lipgloss.DefaultRenderer(), lightConfig()),
+	"solarized": NewWithRenderer(lipgloss.DefaultRenderer(), solarizedConfig()),
+	"dracula":   NewWithRenderer(lipgloss.DefaultRenderer(), draculaConfig()),
+	"monokai":   NewWithRenderer(lipgloss.DefaultRenderer(), monokaiConfig()),
+}
+
+// fixed builds an AdaptiveColor that doesn't adapt: the named presets
+// below are specific artist palettes, not meant to swap variants just
+// because the terminal reports a different background.
+func fixed(hex string) lipgloss.AdaptiveColor {
+	return lipgloss.AdaptiveColor{Light: hex, Dark: hex}
+}
+
+func lightConfig() Config {
+	return Config{
+		Background: fixed("#FFFFFF"),
+		Foreground: fixed("#1A1A1A"),
+		Muted:      fixed("#6E6E6E"),
+		Accent:     fixed("#0052A3"),
+		Success:    fixed("#1B7F1B"),
+		Warning:    fixed("#B36B00"),
+		Danger:     fixed("#A30000"),
+		Border:     fixed("#CCCCCC"),
+	}
+}
+
+func solarizedConfig() Config {
+	return Config{
+		Background: fixed("#002B36"),
+		Foreground: fixed("#839496"),
+		Muted:      fixed("#586E75"),
+		Accent:     fixed("#268BD2"),
+		Success:    fixed("#859900"),
+		Warning:    fixed("#B58900"),
+		Danger:     fixed("#DC322F"),
+		Border:     fixed("#073642"),
+	}
+}
+
+func draculaConfig() Config {
+	return Config{
+		Background: fixed("#282A36"),
+		Foreground: fixed("#F8F8F2"),
+		Muted:      fixed("#6272A4"),
+		Accent:     fixed("#BD93F9"),
+		Success:    fixed("#50FA7B"),
+		Warning:    fixed("#F1FA8C"),
+		Danger:     fixed("#FF5555"),
+		Border:     fixed("#44475A"),
+	}
+}
+
+func monokaiConfig() Config {
+	return Config{
+		Background: fixed("#272822"),
+		Foreground: fixed("#F8F8F2"),
+		Muted:      fixed("#75715E"),
+		Accent:     fixed("#66D9EF"),
+		Success:    fixed("#A6E22E"),
+		Warning:    fixed("#E6DB74"),
+		Danger:     fixed("#F92672"),
+		Border:     fixed("#49483E"),
+	}
+}
+
+// SaveTheme writes s's Config to path as JSON or TOML, chosen the same
+// way LoadTheme picks a format on read.
+func SaveTheme(path string, s *Styles) error {
+	file := configToFile(s.cfg)
+
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err = json.MarshalIndent(file, "", "  ")
+	default:
+		var b strings.Builder
+		err = toml.NewEncoder(&b).Encode(file)
+		data = []byte(b.String())
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Watch watches path - a JSON or TOML theme file loaded via LoadTheme -
+// for on-disk changes and calls onReload with a freshly loaded *Styles
+// each time it changes, debounced 150ms the same way WatchStyleset
+// debounces styleset reloads, so a burst of editor saves only triggers
+// one reload. The returned stop function closes the underlying fsnotify
+// watcher; callers should defer it (or call it on their own Close).
+func Watch(path string, onReload func(*Styles)) (func() error, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fsw.Add(filepath.Dir(path)); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		var timer *time.Timer
+		reload := func() {
+			s, err := LoadTheme(path)
+			if err != nil {
+				log.Printf("theme watcher: %v", err)
+				return
+			}
+			onReload(s)
+		}
+
+		for {
+			select {
+			case event, ok := <-fsw.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(path) {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				if timer == nil {
+					timer = time.AfterFunc(150*time.Millisecond, reload)
+				} else {
+					timer.Reset(150 * time.Millisecond)
+				}
+			case err, ok := <-fsw.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("theme watcher: %v", err)
+			case <-done:
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			}
+		}
+	}()
+
+	stop := func() error {
+		close(done)
+		return fsw.Close()
+	}
+	return stop, nil
+}
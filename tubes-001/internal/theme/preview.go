@@ -55,9 +55,9 @@ func PreviewOneLine(s *Styles) string {
 // renderSection renders a section of styles with a title
 func renderSection(title string, styleNames []string, s *Styles) string {
 	var lines []string
-	
+
 	// Section title
-	titleStyle := lipgloss.NewStyle().Bold(true).Underline(true)
+	titleStyle := s.renderer.NewStyle().Bold(true).Underline(true)
 	lines = append(lines, titleStyle.Render(title))
 	
 	// Render each style
@@ -67,6 +67,9 @@ func renderSection(title string, styleNames []string, s *Styles) string {
 			preview := style.Render(fmt.Sprintf(" %s sample ", name))
 			description := getStyleDescription(name)
 			line := fmt.Sprintf("%-12s %s  %s", name+":", preview, description)
+			if issue, fails := contrastIssue(s, name); fails {
+				line += fmt.Sprintf("  [LOW CONTRAST %.2f:1, needs %.1f:1]", issue.Ratio, issue.Required)
+			}
 			lines = append(lines, line)
 		}
 	}
@@ -100,59 +103,59 @@ func getStyleDescription(name string) string {
 	return "Style description not available"
 }
 
-// GetColorPalette returns a formatted display of the color palette
-func GetColorPalette() string {
-	colors := []struct {
-		name  string
-		hex   string
-		usage string
-	}{
-		{"background", "#000000", "Main background color"},
-		{"foreground", "#FFFFFF", "Primary text color"},
-		{"muted", "#808080", "Secondary/muted text"},
-		{"accent", "#0066CC", "Highlight and accent color"},
-		{"success", "#00AA00", "Success states and confirmations"},
-		{"warning", "#FFAA00", "Warning states and alerts"},
-		{"danger", "#CC0000", "Error states and critical alerts"},
-		{"border", "#444444", "Borders and dividers"},
+// GetColorPalette returns a formatted display of the color palette s was
+// compiled from, with swatches rendered through s's renderer so they
+// respect its detected color profile. The variant (light/dark) actually
+// live on s's renderer is named in the section title and is the one
+// whose hex each row prints.
+func GetColorPalette(s *Styles) string {
+	dark := s.renderer.HasDarkBackground()
+	variant := "light"
+	if dark {
+		variant = "dark"
 	}
-	
+
 	var lines []string
-	lines = append(lines, lipgloss.NewStyle().Bold(true).Underline(true).Render("COLOR PALETTE"))
-	
-	for _, c := range colors {
+	lines = append(lines, s.renderer.NewStyle().Bold(true).Underline(true).
+		Render(fmt.Sprintf("COLOR PALETTE (%s)", variant)))
+
+	for _, c := range s.cfg.AdaptivePalette() {
+		hex := c.Color.Light
+		if dark {
+			hex = c.Color.Dark
+		}
 		// Create a color swatch
-		colorStyle := lipgloss.NewStyle().Background(lipgloss.Color(c.hex)).Foreground(lipgloss.Color("#FFFFFF"))
+		colorStyle := s.renderer.NewStyle().Background(lipgloss.Color(hex)).Foreground(lipgloss.Color("#FFFFFF"))
 		swatch := colorStyle.Render("  ")
-		line := fmt.Sprintf("%-12s %s %s  %s", c.name+":", swatch, c.hex, c.usage)
+		line := fmt.Sprintf("%-12s %s %s  %s", c.Name+":", swatch, hex, c.Usage)
 		lines = append(lines, line)
 	}
-	
+
 	return strings.Join(lines, "\n")
 }
 
 // GetDesignTokens returns a structured view of all design tokens
 func GetDesignTokens(s *Styles) string {
 	var sections []string
-	
+
 	// Color palette
-	sections = append(sections, GetColorPalette())
-	
+	sections = append(sections, GetColorPalette(s))
+
 	// Typography scale
-	sections = append(sections, getTypographyScale())
-	
+	sections = append(sections, getTypographyScale(s))
+
 	// Spacing scale
-	sections = append(sections, getSpacingScale())
-	
+	sections = append(sections, getSpacingScale(s))
+
 	// Border styles
-	sections = append(sections, getBorderStyles())
-	
+	sections = append(sections, getBorderStyles(s))
+
 	return strings.Join(sections, "\n\n")
 }
 
-func getTypographyScale() string {
+func getTypographyScale(s *Styles) string {
 	var lines []string
-	lines = append(lines, lipgloss.NewStyle().Bold(true).Underline(true).Render("TYPOGRAPHY SCALE"))
+	lines = append(lines, s.renderer.NewStyle().Bold(true).Underline(true).Render("TYPOGRAPHY SCALE"))
 	
 	typography := []struct {
 		name string
@@ -172,9 +175,9 @@ func getTypographyScale() string {
 	return strings.Join(lines, "\n")
 }
 
-func getSpacingScale() string {
+func getSpacingScale(s *Styles) string {
 	var lines []string
-	lines = append(lines, lipgloss.NewStyle().Bold(true).Underline(true).Render("SPACING SCALE"))
+	lines = append(lines, s.renderer.NewStyle().Bold(true).Underline(true).Render("SPACING SCALE"))
 	
 	spacing := []struct {
 		name string
@@ -196,9 +199,9 @@ func getSpacingScale() string {
 	return strings.Join(lines, "\n")
 }
 
-func getBorderStyles() string {
+func getBorderStyles(s *Styles) string {
 	var lines []string
-	lines = append(lines, lipgloss.NewStyle().Bold(true).Underline(true).Render("BORDER STYLES"))
+	lines = append(lines, s.renderer.NewStyle().Bold(true).Underline(true).Render("BORDER STYLES"))
 	
 	borders := []struct {
 		name string
@@ -0,0 +1,53 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/rivo/tview"
+)
+
+// chromaStyleName is the chroma style whose colors HighlightSource
+// renders into tview color tags.
+const chromaStyleName = "monokai"
+
+// HighlightSource syntax-highlights content (the bytes at path's
+// extension-detected language) into tview color tags, for display in a
+// TextView with SetDynamicColors(true). When no chroma lexer matches
+// path, the lexer is guessed from content; falls back to content escaped
+// but otherwise unstyled if neither finds a lexer or tokenizing fails.
+func HighlightSource(path, content string) string {
+	lexer := lexers.Match(path)
+	if lexer == nil {
+		lexer = lexers.Analyse(content)
+	}
+	if lexer == nil {
+		return tview.Escape(content)
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	style := styles.Get(chromaStyleName)
+	if style == nil {
+		style = styles.Fallback
+	}
+
+	iterator, err := lexer.Tokenise(nil, content)
+	if err != nil {
+		return tview.Escape(content)
+	}
+
+	var b strings.Builder
+	for _, token := range iterator.Tokens() {
+		text := tview.Escape(token.Value)
+		entry := style.Get(token.Type)
+		if entry.Colour.IsSet() {
+			fmt.Fprintf(&b, "[#%s]%s[-]", entry.Colour.String(), text)
+		} else {
+			b.WriteString(text)
+		}
+	}
+	return b.String()
+}
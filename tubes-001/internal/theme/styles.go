@@ -8,24 +8,45 @@ import (
 // Styles holds all design tokens for the UI
 type Styles struct {
 	ByName map[string]lipgloss.Style
-	
+
+	// renderer is the lipgloss.Renderer every style in this Styles was
+	// built against. It carries the detected color profile (TrueColor /
+	// 256 / ANSI / NoColor) and dark-background guess for whichever
+	// output stream it was constructed with, so Preview and friends
+	// render correctly even when that differs from the server process's
+	// own stdout (e.g. over SSH). Never nil - NewWithRenderer falls back
+	// to lipgloss.DefaultRenderer() when passed nil.
+	renderer *lipgloss.Renderer
+
+	// cfg is the Config s's styles were compiled from, so token viewers
+	// like GetColorPalette can report the palette actually backing s
+	// instead of a value hardcoded to the defaults.
+	cfg Config
+
+	// Raw holds the fg/bg/attribute values a loaded styleset set for each
+	// selector, alongside the compiled lipgloss.Style in ByName, so tcell
+	// consumers (tview components) can resolve the same colors without
+	// unpacking a lipgloss.Style. Nil on NewDefaultStyles' result - only
+	// LoadStyleset populates it.
+	Raw map[string]SelectorAttrs
+
 	// Core layout styles
 	Header    lipgloss.Style
 	Sidebar   lipgloss.Style
 	Main      lipgloss.Style
 	Input     lipgloss.Style
-	
+
 	// State styles
 	Ok        lipgloss.Style
 	Info      lipgloss.Style
 	Warn      lipgloss.Style
 	Error     lipgloss.Style
-	
+
 	// Interactive styles
 	Selected  lipgloss.Style
 	Active    lipgloss.Style
 	Inactive  lipgloss.Style
-	
+
 	// Typography
 	Title     lipgloss.Style
 	Subtitle  lipgloss.Style
@@ -33,111 +54,214 @@ type Styles struct {
 	Caption   lipgloss.Style
 }
 
-// NewDefaultStyles creates the default theme with all design tokens
+// Config holds the raw token values NewWithRenderer compiles into a
+// Styles. Each token is a lipgloss.AdaptiveColor rather than a flat hex
+// value, so the same Config renders correctly whether the renderer it's
+// compiled against is looking at a light or dark terminal background.
+// NewDefaultStyles is just NewWithRenderer(DefaultRenderer(),
+// DefaultConfig()); LoadTheme builds a Config from an on-disk theme file
+// instead.
+type Config struct {
+	Background lipgloss.AdaptiveColor
+	Foreground lipgloss.AdaptiveColor
+	Muted      lipgloss.AdaptiveColor
+	Accent     lipgloss.AdaptiveColor
+	Success    lipgloss.AdaptiveColor
+	Warning    lipgloss.AdaptiveColor
+	Danger     lipgloss.AdaptiveColor
+	Border     lipgloss.AdaptiveColor
+}
+
+// DefaultConfig returns the palette NewDefaultStyles has always used,
+// with a light-background variant added for each token so it no longer
+// assumes a dark terminal.
+func DefaultConfig() Config {
+	return Config{
+		Background: lipgloss.AdaptiveColor{Light: "#FFFFFF", Dark: "#000000"},
+		Foreground: lipgloss.AdaptiveColor{Light: "#1A1A1A", Dark: "#FFFFFF"},
+		Muted:      lipgloss.AdaptiveColor{Light: "#6E6E6E", Dark: "#808080"},
+		Accent:     lipgloss.AdaptiveColor{Light: "#0052A3", Dark: "#0066CC"},
+		Success:    lipgloss.AdaptiveColor{Light: "#1B7F1B", Dark: "#00AA00"},
+		Warning:    lipgloss.AdaptiveColor{Light: "#B36B00", Dark: "#FFAA00"},
+		Danger:     lipgloss.AdaptiveColor{Light: "#A30000", Dark: "#CC0000"},
+		Border:     lipgloss.AdaptiveColor{Light: "#CCCCCC", Dark: "#444444"},
+	}
+}
+
+// PaletteEntry is one row of an AdaptivePalette: a named token, its
+// light/dark color pair, and a human description of where it's used.
+type PaletteEntry struct {
+	Name  string
+	Color lipgloss.AdaptiveColor
+	Usage string
+}
+
+// AdaptivePalette is a Config's tokens in display order, the shape
+// GetColorPalette iterates over instead of a flat struct literal.
+type AdaptivePalette []PaletteEntry
+
+// AdaptivePalette returns cfg's tokens as an AdaptivePalette.
+func (cfg Config) AdaptivePalette() AdaptivePalette {
+	return AdaptivePalette{
+		{"background", cfg.Background, "Main background color"},
+		{"foreground", cfg.Foreground, "Primary text color"},
+		{"muted", cfg.Muted, "Secondary/muted text"},
+		{"accent", cfg.Accent, "Highlight and accent color"},
+		{"success", cfg.Success, "Success states and confirmations"},
+		{"warning", cfg.Warning, "Warning states and alerts"},
+		{"danger", cfg.Danger, "Error states and critical alerts"},
+		{"border", cfg.Border, "Borders and dividers"},
+	}
+}
+
+// BackgroundMode forces Styles.Renderer's dark/light background guess,
+// overriding whatever it auto-detected - primarily for generating
+// deterministic screenshots of both variants of a theme.
+type BackgroundMode int
+
+const (
+	// BackgroundAuto leaves the renderer's own detection in place.
+	BackgroundAuto BackgroundMode = iota
+	BackgroundLight
+	BackgroundDark
+)
+
+// SetBackgroundMode forces s's renderer to treat the terminal as
+// light/dark-background, re-resolving every AdaptiveColor in s
+// accordingly. BackgroundAuto restores the renderer's own detection.
+func (s *Styles) SetBackgroundMode(mode BackgroundMode) {
+	switch mode {
+	case BackgroundDark:
+		s.renderer.SetHasDarkBackground(true)
+	case BackgroundLight:
+		s.renderer.SetHasDarkBackground(false)
+	case BackgroundAuto:
+		s.renderer.SetHasDarkBackground(s.renderer.Output().HasDarkBackground())
+	}
+}
+
+// NewDefaultStyles creates the default theme with all design tokens,
+// rendered against lipgloss's global default renderer.
 func NewDefaultStyles() *Styles {
+	return NewWithRenderer(lipgloss.DefaultRenderer(), DefaultConfig())
+}
+
+// NewWithRenderer builds the same design tokens as NewDefaultStyles, but
+// compiles every lipgloss.Style against r instead of the global
+// singleton, so swatches respect r's detected color profile and
+// dark-background guess. Pass a renderer bound to the remote terminal's
+// output (e.g. via lipgloss.NewRenderer(conn)) to get correct colors over
+// SSH; pass a fixed-profile renderer (termenv.Ascii, ...) for
+// deterministic preview snapshots in tests.
+func NewWithRenderer(r *lipgloss.Renderer, cfg Config) *Styles {
+	if r == nil {
+		r = lipgloss.DefaultRenderer()
+	}
+
 	s := &Styles{
-		ByName: make(map[string]lipgloss.Style),
+		ByName:   make(map[string]lipgloss.Style),
+		renderer: r,
+		cfg:      cfg,
 	}
-	
-	// Define color palette
-	background := lipgloss.Color("#000000")  // Black
-	foreground := lipgloss.Color("#FFFFFF")  // White
-	muted      := lipgloss.Color("#808080")  // Gray
-	accent     := lipgloss.Color("#0066CC")  // Blue
-	success    := lipgloss.Color("#00AA00")  // Green
-	warning    := lipgloss.Color("#FFAA00")  // Orange
-	danger     := lipgloss.Color("#CC0000")  // Red
-	border     := lipgloss.Color("#444444")  // Dark Gray
-	
+
+	background := cfg.Background
+	foreground := cfg.Foreground
+	muted := cfg.Muted
+	accent := cfg.Accent
+	success := cfg.Success
+	warning := cfg.Warning
+	danger := cfg.Danger
+	border := cfg.Border
+
 	// Core layout styles
-	s.Header = lipgloss.NewStyle().
+	s.Header = r.NewStyle().
 		Background(background).
 		Foreground(foreground).
 		Bold(true).
 		Padding(0, 1).
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(border)
-	
-	s.Sidebar = lipgloss.NewStyle().
+
+	s.Sidebar = r.NewStyle().
 		Background(background).
 		Foreground(foreground).
 		Padding(0, 1).
 		Width(30)
-	
-	s.Main = lipgloss.NewStyle().
+
+	s.Main = r.NewStyle().
 		Background(background).
 		Foreground(foreground).
 		Padding(1)
-	
-	s.Input = lipgloss.NewStyle().
+
+	s.Input = r.NewStyle().
 		Background(background).
 		Foreground(foreground).
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(border).
 		Padding(0, 1)
-	
+
 	// State styles
-	s.Ok = lipgloss.NewStyle().
+	s.Ok = r.NewStyle().
 		Background(background).
 		Foreground(success).
 		Bold(true)
-	
-	s.Info = lipgloss.NewStyle().
+
+	s.Info = r.NewStyle().
 		Background(background).
 		Foreground(accent).
 		Bold(false)
-	
-	s.Warn = lipgloss.NewStyle().
+
+	s.Warn = r.NewStyle().
 		Background(background).
 		Foreground(warning).
 		Bold(true)
-	
-	s.Error = lipgloss.NewStyle().
+
+	s.Error = r.NewStyle().
 		Background(background).
 		Foreground(danger).
 		Bold(true)
-	
+
 	// Interactive styles
-	s.Selected = lipgloss.NewStyle().
+	s.Selected = r.NewStyle().
 		Background(accent).
 		Foreground(foreground).
 		Bold(true)
-	
-	s.Active = lipgloss.NewStyle().
+
+	s.Active = r.NewStyle().
 		Background(background).
 		Foreground(foreground).
 		Bold(true).
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(accent)
-	
-	s.Inactive = lipgloss.NewStyle().
+
+	s.Inactive = r.NewStyle().
 		Background(background).
 		Foreground(muted).
 		Bold(false)
-	
+
 	// Typography
-	s.Title = lipgloss.NewStyle().
+	s.Title = r.NewStyle().
 		Background(background).
 		Foreground(foreground).
 		Bold(true).
 		Underline(true)
-	
-	s.Subtitle = lipgloss.NewStyle().
+
+	s.Subtitle = r.NewStyle().
 		Background(background).
 		Foreground(foreground).
 		Bold(true)
-	
-	s.Body = lipgloss.NewStyle().
+
+	s.Body = r.NewStyle().
 		Background(background).
 		Foreground(foreground).
 		Bold(false)
-	
-	s.Caption = lipgloss.NewStyle().
+
+	s.Caption = r.NewStyle().
 		Background(background).
 		Foreground(muted).
 		Bold(false).
 		Italic(true)
-	
+
 	// Populate ByName map
 	s.ByName["header"] = s.Header
 	s.ByName["sidebar"] = s.Sidebar
@@ -154,10 +278,21 @@ func NewDefaultStyles() *Styles {
 	s.ByName["subtitle"] = s.Subtitle
 	s.ByName["body"] = s.Body
 	s.ByName["caption"] = s.Caption
-	
+
 	return s
 }
 
+// Renderer returns the lipgloss.Renderer s's styles were compiled
+// against.
+func (s *Styles) Renderer() *lipgloss.Renderer {
+	return s.renderer
+}
+
+// Config returns the Config s's styles were compiled from.
+func (s *Styles) Config() Config {
+	return s.cfg
+}
+
 // TcellColors returns tcell color equivalents for tview components
 type TcellColors struct {
 	Background tcell.Color
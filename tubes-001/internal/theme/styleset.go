@@ -0,0 +1,268 @@
+package theme
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gdamore/tcell/v2"
+)
+
+// SelectorAttrs holds the raw foreground/background/attribute values a
+// styleset file set for one selector (e.g. "header" or
+// "sidebar.selected"), before they're compiled into a lipgloss.Style.
+type SelectorAttrs struct {
+	FG        string
+	BG        string
+	Bold      bool
+	Italic    bool
+	Underline bool
+	Reverse   bool
+}
+
+// namedFields maps the selector names NewDefaultStyles already exposes as
+// struct fields to setters, so a loaded styleset keeps both Styles.ByName
+// and the matching named field (s.Header, s.Sidebar, ...) in sync. A
+// selector not in this table (e.g. a styleset-only name like
+// "cursor.tag.python") still lands in ByName, just without a dedicated
+// field.
+var namedFields = map[string]func(s *Styles, st lipgloss.Style){
+	"header":   func(s *Styles, st lipgloss.Style) { s.Header = st },
+	"sidebar":  func(s *Styles, st lipgloss.Style) { s.Sidebar = st },
+	"main":     func(s *Styles, st lipgloss.Style) { s.Main = st },
+	"input":    func(s *Styles, st lipgloss.Style) { s.Input = st },
+	"ok":       func(s *Styles, st lipgloss.Style) { s.Ok = st },
+	"info":     func(s *Styles, st lipgloss.Style) { s.Info = st },
+	"warn":     func(s *Styles, st lipgloss.Style) { s.Warn = st },
+	"error":    func(s *Styles, st lipgloss.Style) { s.Error = st },
+	"selected": func(s *Styles, st lipgloss.Style) { s.Selected = st },
+	"active":   func(s *Styles, st lipgloss.Style) { s.Active = st },
+	"inactive": func(s *Styles, st lipgloss.Style) { s.Inactive = st },
+	"title":    func(s *Styles, st lipgloss.Style) { s.Title = st },
+	"subtitle": func(s *Styles, st lipgloss.Style) { s.Subtitle = st },
+	"body":     func(s *Styles, st lipgloss.Style) { s.Body = st },
+	"caption":  func(s *Styles, st lipgloss.Style) { s.Caption = st },
+}
+
+// LoadStyleset parses an aerc-style styleset file at path into a *Styles,
+// layered on top of NewDefaultStyles. Each non-blank, non-comment line is
+// "selector.attribute = value", e.g.:
+//
+//	header.fg = #ffffff
+//	sidebar.selected.bg = #0066cc
+//	status.error.bold = true
+//
+// Selectors already known to NewDefaultStyles (header, sidebar, ...) have
+// their attributes merged over the default; any other selector (like
+// cursor.tag.*) starts from a blank style and is only reachable via
+// Styles.ByName.
+func LoadStyleset(path string) (*Styles, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	raw := make(map[string]*SelectorAttrs)
+	selector := func(name string) *SelectorAttrs {
+		a, ok := raw[name]
+		if !ok {
+			a = &SelectorAttrs{}
+			raw[name] = a
+		}
+		return a
+	}
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("styleset %s:%d: missing '='", path, lineNo)
+		}
+		key := strings.TrimSpace(line[:eq])
+		value := strings.TrimSpace(line[eq+1:])
+
+		dot := strings.LastIndex(key, ".")
+		if dot < 0 {
+			return nil, fmt.Errorf("styleset %s:%d: key %q has no .attribute suffix", path, lineNo, key)
+		}
+		name, attr := key[:dot], key[dot+1:]
+		a := selector(name)
+		switch attr {
+		case "fg":
+			a.FG = value
+		case "bg":
+			a.BG = value
+		case "bold":
+			a.Bold, _ = strconv.ParseBool(value)
+		case "italic":
+			a.Italic, _ = strconv.ParseBool(value)
+		case "underline":
+			a.Underline, _ = strconv.ParseBool(value)
+		case "reverse":
+			a.Reverse, _ = strconv.ParseBool(value)
+		default:
+			return nil, fmt.Errorf("styleset %s:%d: unknown attribute %q", path, lineNo, attr)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	s := NewDefaultStyles()
+	s.Raw = make(map[string]SelectorAttrs, len(raw))
+
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		a := raw[name]
+		base, ok := s.ByName[name]
+		if !ok {
+			base = s.renderer.NewStyle()
+		}
+		st := base
+		if a.FG != "" {
+			st = st.Foreground(lipgloss.Color(a.FG))
+		}
+		if a.BG != "" {
+			st = st.Background(lipgloss.Color(a.BG))
+		}
+		if a.Bold {
+			st = st.Bold(true)
+		}
+		if a.Italic {
+			st = st.Italic(true)
+		}
+		if a.Underline {
+			st = st.Underline(true)
+		}
+		if a.Reverse {
+			st = st.Reverse(true)
+		}
+
+		s.ByName[name] = st
+		s.Raw[name] = *a
+		if set, ok := namedFields[name]; ok {
+			set(s, st)
+		}
+	}
+
+	return s, nil
+}
+
+// TcellColor resolves selector's "fg" or "bg" attribute to a tcell.Color
+// for tview components, using whatever the active styleset set - falling
+// back to GetTcellColors' default foreground/background when s has no
+// Raw entry for selector, or the styleset didn't set that attribute.
+func (s *Styles) TcellColor(selector, attr string) tcell.Color {
+	defaults := GetTcellColors()
+	fallback := defaults.Foreground
+	if attr == "bg" {
+		fallback = defaults.Background
+	}
+
+	a, ok := s.Raw[selector]
+	if !ok {
+		return fallback
+	}
+	value := a.FG
+	if attr == "bg" {
+		value = a.BG
+	}
+	if value == "" {
+		return fallback
+	}
+	return tcell.GetColor(value)
+}
+
+// StylesetDir returns the directory stylesets are loaded from, rooted at
+// $TUBES_DIR if set or the current working directory otherwise, creating
+// it if it doesn't exist yet.
+func StylesetDir() (string, error) {
+	root := os.Getenv("TUBES_DIR")
+	if root == "" {
+		var err error
+		root, err = os.Getwd()
+		if err != nil {
+			return "", err
+		}
+	}
+	dir := filepath.Join(root, "stylesets")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// StylesetRegistry loads and caches stylesets from a directory of
+// "<name>.conf" files, tracking which one is currently active so
+// /theme can list and switch between them.
+type StylesetRegistry struct {
+	dir    string
+	active string
+	styles *Styles
+}
+
+// NewStylesetRegistry returns a registry rooted at dir.
+func NewStylesetRegistry(dir string) *StylesetRegistry {
+	return &StylesetRegistry{dir: dir}
+}
+
+// Dir returns the directory this registry loads stylesets from.
+func (r *StylesetRegistry) Dir() string { return r.dir }
+
+// Path returns the on-disk path of the named styleset.
+func (r *StylesetRegistry) Path(name string) string {
+	return filepath.Join(r.dir, name+".conf")
+}
+
+// Names lists the stylesets available in the registry's directory,
+// sorted alphabetically, by file name without the ".conf" suffix.
+func (r *StylesetRegistry) Names() ([]string, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".conf" {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(e.Name(), ".conf"))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Switch loads the named styleset and makes it the active one.
+func (r *StylesetRegistry) Switch(name string) (*Styles, error) {
+	s, err := LoadStyleset(r.Path(name))
+	if err != nil {
+		return nil, err
+	}
+	r.active = name
+	r.styles = s
+	return s, nil
+}
+
+// Active returns the name and Styles of the currently active styleset,
+// or ("", nil) if Switch hasn't been called yet.
+func (r *StylesetRegistry) Active() (string, *Styles) {
+	return r.active, r.styles
+}
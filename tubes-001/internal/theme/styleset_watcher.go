@@ -0,0 +1,74 @@
+package theme
+
+import (
+	"log"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// WatchStyleset watches path for on-disk changes and calls onReload with
+// a freshly loaded *Styles each time it changes, debounced by 150ms so a
+// burst of editor saves only triggers one reload. The returned stop
+// function closes the underlying fsnotify watcher; callers should defer
+// it (or call it on their own Close).
+func WatchStyleset(path string, onReload func(*Styles)) (func() error, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fsw.Add(filepath.Dir(path)); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		var timer *time.Timer
+		reload := func() {
+			s, err := LoadStyleset(path)
+			if err != nil {
+				log.Printf("styleset watcher: %v", err)
+				return
+			}
+			onReload(s)
+		}
+
+		for {
+			select {
+			case event, ok := <-fsw.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != filepath.Clean(path) {
+					continue
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+					continue
+				}
+				if timer == nil {
+					timer = time.AfterFunc(150*time.Millisecond, reload)
+				} else {
+					timer.Reset(150 * time.Millisecond)
+				}
+			case err, ok := <-fsw.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("styleset watcher: %v", err)
+			case <-done:
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			}
+		}
+	}()
+
+	stop := func() error {
+		close(done)
+		return fsw.Close()
+	}
+	return stop, nil
+}
@@ -0,0 +1,91 @@
+// Package adapter defines a pluggable backend for listing and
+// loading/saving Cursor content, following the adapter pattern used by
+// content-source plugins like neonmodem/gobbs. HybridModel and
+// CursorDirectory were local-filesystem-only; a Registry of named
+// Adapters lets a tree mount several backends (local disk, a remote HTTP
+// store, ...) at once, with each Cursor recording which one it came from
+// in its Source field.
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"tubes/internal/codeintel"
+)
+
+// Caps describes what an Adapter supports, so callers (e.g. /source, /cd)
+// can decide what's safe to offer - a read-only adapter shouldn't get a
+// save command wired up to it, for instance.
+type Caps struct {
+	List  bool
+	Read  bool
+	Write bool
+}
+
+// Adapter is a named content source a Cursor's Source field can point
+// at. parentID/id are adapter-relative paths, not filesystem paths - the
+// filesystem adapter happens to treat them the same way, but an HTTP
+// adapter treats them as URL suffixes.
+type Adapter interface {
+	Name() string
+	List(ctx context.Context, parentID string) ([]*codeintel.Cursor, error)
+	Load(ctx context.Context, id string) (io.ReadCloser, error)
+	Save(ctx context.Context, id string, r io.Reader) error
+	Capabilities() Caps
+}
+
+// Registry holds the adapters mounted into the current cursor tree,
+// keyed by the name used in Cursor.Source and in `/cd <source>:/path`.
+type Registry struct {
+	adapters map[string]Adapter
+}
+
+// NewRegistry creates an empty adapter registry.
+func NewRegistry() *Registry {
+	return &Registry{adapters: make(map[string]Adapter)}
+}
+
+// Register mounts a, replacing any existing adapter with the same name.
+func (r *Registry) Register(a Adapter) {
+	r.adapters[a.Name()] = a
+}
+
+// Get looks up a mounted adapter by name.
+func (r *Registry) Get(name string) (Adapter, bool) {
+	a, ok := r.adapters[name]
+	return a, ok
+}
+
+// Names returns the mounted adapter names, sorted for stable display.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.adapters))
+	for name := range r.adapters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load resolves cursor through the adapter named by cursor.Source and
+// loads its content, the one indirection CursorDirectory needed to stop
+// assuming every Cursor lives on local disk.
+func (r *Registry) Load(ctx context.Context, cursor *codeintel.Cursor) (io.ReadCloser, error) {
+	a, ok := r.Get(cursor.Source)
+	if !ok {
+		return nil, fmt.Errorf("no adapter mounted as %q", cursor.Source)
+	}
+	return a.Load(ctx, cursor.FilePath)
+}
+
+// Save resolves cursor through the adapter named by cursor.Source and
+// writes r to it.
+func (r *Registry) Save(ctx context.Context, cursor *codeintel.Cursor, content io.Reader) error {
+	a, ok := r.Get(cursor.Source)
+	if !ok {
+		return fmt.Errorf("no adapter mounted as %q", cursor.Source)
+	}
+	return a.Save(ctx, cursor.FilePath, content)
+}
@@ -0,0 +1,64 @@
+package adapter
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"tubes/internal/codeintel"
+)
+
+// Filesystem is the default Adapter: it reads and writes local disk
+// paths rooted at Root, the behavior CursorDirectory had before adapters
+// existed.
+type Filesystem struct {
+	name string
+	root string
+}
+
+// NewFilesystem creates a Filesystem adapter named name, rooted at root.
+func NewFilesystem(name, root string) *Filesystem {
+	return &Filesystem{name: name, root: root}
+}
+
+func (f *Filesystem) Name() string { return f.name }
+
+func (f *Filesystem) Capabilities() Caps {
+	return Caps{List: true, Read: true, Write: true}
+}
+
+// List returns one Cursor per non-directory entry under parentID.
+func (f *Filesystem) List(ctx context.Context, parentID string) ([]*codeintel.Cursor, error) {
+	entries, err := os.ReadDir(filepath.Join(f.root, parentID))
+	if err != nil {
+		return nil, err
+	}
+
+	var cursors []*codeintel.Cursor
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		cursors = append(cursors, &codeintel.Cursor{
+			FilePath: filepath.Join(parentID, e.Name()),
+			DirName:  parentID,
+			Source:   f.Name(),
+		})
+	}
+	return cursors, nil
+}
+
+func (f *Filesystem) Load(ctx context.Context, id string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(f.root, id))
+}
+
+func (f *Filesystem) Save(ctx context.Context, id string, r io.Reader) error {
+	out, err := os.Create(filepath.Join(f.root, id))
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, r)
+	return err
+}
@@ -0,0 +1,89 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"tubes/internal/codeintel"
+)
+
+// HTTP is an Adapter backed by GET/PUT requests against BaseURL,
+// authenticated with an optional bearer Token. It can't enumerate a
+// remote tree, so Capabilities().List is false - callers have to name a
+// Cursor's id explicitly rather than browsing to it.
+type HTTP struct {
+	name    string
+	BaseURL string
+	Token   string
+	client  *http.Client
+}
+
+// NewHTTP creates an HTTP adapter named name, talking to baseURL with the
+// given bearer token (empty for no auth).
+func NewHTTP(name, baseURL, token string) *HTTP {
+	return &HTTP{name: name, BaseURL: baseURL, Token: token, client: &http.Client{}}
+}
+
+func (h *HTTP) Name() string { return h.name }
+
+func (h *HTTP) Capabilities() Caps {
+	return Caps{List: false, Read: true, Write: true}
+}
+
+func (h *HTTP) List(ctx context.Context, parentID string) ([]*codeintel.Cursor, error) {
+	return nil, fmt.Errorf("adapter %s: listing is not supported over HTTP", h.name)
+}
+
+func (h *HTTP) Load(ctx context.Context, id string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	h.authorize(req)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("adapter %s: GET %s: %s", h.name, id, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func (h *HTTP) Save(ctx context.Context, id string, r io.Reader) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, h.url(id), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	h.authorize(req)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("adapter %s: PUT %s: %s", h.name, id, resp.Status)
+	}
+	return nil
+}
+
+func (h *HTTP) authorize(req *http.Request) {
+	if h.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+h.Token)
+	}
+}
+
+func (h *HTTP) url(id string) string {
+	return strings.TrimRight(h.BaseURL, "/") + "/" + strings.TrimLeft(id, "/")
+}
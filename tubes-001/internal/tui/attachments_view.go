@@ -0,0 +1,76 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"tubes/internal/codeintel"
+
+	"github.com/rivo/tview"
+)
+
+// AttachmentsView renders a Cursor's attachments as a list: a
+// mime-derived icon, its path relative to the cursor's directory, and a
+// dim size suffix. It's a thin delegate over tview.List, which already
+// highlights the focused row.
+type AttachmentsView struct {
+	*tview.List
+}
+
+// NewAttachmentsView returns an empty attachments list.
+func NewAttachmentsView() *AttachmentsView {
+	v := &AttachmentsView{List: tview.NewList().ShowSecondaryText(false)}
+	v.SetBorder(true).SetTitle("Attachments")
+	return v
+}
+
+// SetAttachments repopulates the view from cursor's attachments.
+func (v *AttachmentsView) SetAttachments(cursor *codeintel.Cursor) {
+	v.Clear()
+	if len(cursor.Attachments) == 0 {
+		v.AddItem("(no attachments)", "", 0, nil)
+		return
+	}
+	for _, a := range cursor.Attachments {
+		v.AddItem(formatAttachment(cursor, a), "", 0, nil)
+	}
+}
+
+// formatAttachment renders one attachment's icon, relative path, and
+// size/id suffix.
+func formatAttachment(cursor *codeintel.Cursor, a codeintel.Attachment) string {
+	rel, err := filepath.Rel(cursor.DirName, a.Path)
+	if err != nil {
+		rel = a.Path
+	}
+	return fmt.Sprintf("%s %s [gray](%s, %s)[-]", attachmentIcon(a.Mime), rel, humanSize(a.Size), a.ID)
+}
+
+// attachmentIcon picks a glyph for mime's broad category.
+func attachmentIcon(mimeType string) string {
+	switch {
+	case strings.HasPrefix(mimeType, "image/"):
+		return "🖼"
+	case strings.HasPrefix(mimeType, "text/"):
+		return "📄"
+	case mimeType == "application/pdf":
+		return "📕"
+	default:
+		return "📎"
+	}
+}
+
+// humanSize formats n bytes as a short, unit-scaled string (1.2MiB).
+func humanSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,326 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// CommandEntry is one command the palette can find and run: its name
+// (including the leading "/"), any aliases, and a one-line help
+// description shown in the preview pane. Enter passes Name to
+// executeCommand.
+//
+// m.commands (type map[string]Command) is a pre-existing field that no
+// code in this snapshot ever populates or reads - type Command itself is
+// never defined anywhere - so the palette doesn't attempt to merge it
+// in; it only covers the switch-cased built-ins in executeCommand.
+type CommandEntry struct {
+	Name        string
+	Aliases     []string
+	Description string
+}
+
+// CommandRegistry holds the commands the palette fuzzy-searches over.
+type CommandRegistry struct {
+	entries []CommandEntry
+}
+
+// NewCommandRegistry returns an empty registry.
+func NewCommandRegistry() *CommandRegistry {
+	return &CommandRegistry{}
+}
+
+// Register adds e to the registry.
+func (r *CommandRegistry) Register(e CommandEntry) {
+	r.entries = append(r.entries, e)
+}
+
+// Entries returns every registered command, in registration order.
+func (r *CommandRegistry) Entries() []CommandEntry {
+	return r.entries
+}
+
+// CommandMatch is one ranked palette result: the matched entry, its
+// fuzzy score (higher is better), and the byte offsets into Entry.Name
+// that matched the typed pattern, for highlighting.
+type CommandMatch struct {
+	Entry     CommandEntry
+	Score     int
+	Positions []int
+}
+
+// registerBuiltinCommands seeds the palette with every command
+// executeCommand's switch handles.
+func (m *HybridModel) registerBuiltinCommands() {
+	m.commandRegistry.Register(CommandEntry{Name: "/help", Description: "Show available commands"})
+	m.commandRegistry.Register(CommandEntry{Name: "/clear", Description: "Clear output"})
+	m.commandRegistry.Register(CommandEntry{Name: "/quit", Aliases: []string{"/exit"}, Description: "Exit application"})
+	m.commandRegistry.Register(CommandEntry{Name: "/mode", Description: "Show current mode"})
+	m.commandRegistry.Register(CommandEntry{Name: "/echo", Description: "Echo text back"})
+	m.commandRegistry.Register(CommandEntry{Name: "/theme", Description: "List stylesets, or switch to one"})
+	m.commandRegistry.Register(CommandEntry{Name: "/palette", Description: "Open the fuzzy command palette"})
+	m.commandRegistry.Register(CommandEntry{Name: "/attach", Description: "Attach a file to the current cursor"})
+	m.commandRegistry.Register(CommandEntry{Name: "/detach", Description: "Remove an attachment from the current cursor"})
+	m.commandRegistry.Register(CommandEntry{Name: "/attachments", Description: "Show the current cursor's attachments"})
+	m.commandRegistry.Register(CommandEntry{Name: "/watch", Description: "Watch the cwd (and any cursor's file) for changes"})
+	m.commandRegistry.Register(CommandEntry{Name: "/source", Description: "Mount or list content adapters"})
+	m.commandRegistry.Register(CommandEntry{Name: "/cd", Description: "Mark a mounted source as current"})
+	m.commandRegistry.Register(CommandEntry{Name: "/save-log", Description: "Dump the full scrollback to a file"})
+}
+
+// initCommandPalette builds the palette's modal widgets (input, result
+// list, help preview) and adds it as a hidden page alongside "main".
+func (m *HybridModel) initCommandPalette() {
+	m.commandRegistry = NewCommandRegistry()
+	m.registerBuiltinCommands()
+
+	m.paletteInput = tview.NewInputField().SetLabel("› ")
+	m.paletteInput.SetBorder(true).SetTitle("Command Palette")
+
+	m.paletteList = tview.NewList().ShowSecondaryText(false)
+	m.paletteList.SetBorder(true).SetTitle("Matches")
+
+	m.paletteHelp = tview.NewTextView().SetDynamicColors(true).SetWordWrap(true)
+	m.paletteHelp.SetBorder(true).SetTitle("Help")
+
+	body := tview.NewFlex().
+		AddItem(m.paletteList, 0, 1, false).
+		AddItem(m.paletteHelp, 0, 1, false)
+	box := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(m.paletteInput, 3, 0, true).
+		AddItem(body, 0, 1, false)
+
+	m.paletteInput.SetChangedFunc(func(text string) {
+		m.refreshPaletteMatches(text)
+	})
+	m.paletteInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			m.closePalette()
+			return nil
+		case tcell.KeyDown:
+			m.movePaletteSelection(1)
+			return nil
+		case tcell.KeyUp:
+			m.movePaletteSelection(-1)
+			return nil
+		case tcell.KeyEnter:
+			m.runPaletteSelection()
+			return nil
+		}
+		return event
+	})
+
+	m.pages.AddPage("palette", centeredModal(box, 70, 16), true, false)
+}
+
+// centeredModal wraps p in nested Flexes so it renders as a fixed-size
+// box centered over whatever page is beneath it.
+func centeredModal(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 0, true).
+			AddItem(nil, 0, 1, false),
+			width, 0, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// openPalette shows the palette page, resets its query, and focuses its
+// input field.
+func (m *HybridModel) openPalette() {
+	m.paletteOpen = true
+	m.paletteInput.SetText("")
+	m.refreshPaletteMatches("")
+	m.pages.ShowPage("palette")
+	m.app.SetFocus(m.paletteInput)
+}
+
+// closePalette hides the palette page and returns focus to the main
+// input field.
+func (m *HybridModel) closePalette() {
+	m.paletteOpen = false
+	m.pages.HidePage("palette")
+	m.app.SetFocus(m.input)
+}
+
+// refreshPaletteMatches re-ranks the registry against pattern and
+// repopulates the result list and help preview.
+func (m *HybridModel) refreshPaletteMatches(pattern string) {
+	m.paletteMatches = rankCommands(m.commandRegistry.Entries(), pattern)
+	m.paletteList.Clear()
+	for _, cm := range m.paletteMatches {
+		m.paletteList.AddItem(highlightCommandName(cm), "", 0, nil)
+	}
+	if len(m.paletteMatches) == 0 {
+		m.paletteHelp.SetText("No matching commands")
+		return
+	}
+	m.paletteList.SetCurrentItem(0)
+	m.paletteHelp.SetText(m.paletteMatches[0].Entry.Description)
+}
+
+// movePaletteSelection moves the list's current item by delta, wrapping
+// at either end, and updates the help preview to match.
+func (m *HybridModel) movePaletteSelection(delta int) {
+	count := m.paletteList.GetItemCount()
+	if count == 0 {
+		return
+	}
+	idx := m.paletteList.GetCurrentItem() + delta
+	if idx < 0 {
+		idx = count - 1
+	}
+	if idx >= count {
+		idx = 0
+	}
+	m.paletteList.SetCurrentItem(idx)
+	m.paletteHelp.SetText(m.paletteMatches[idx].Entry.Description)
+}
+
+// runPaletteSelection closes the palette and runs the selected entry
+// through executeCommand, same as typing it into the main input field.
+func (m *HybridModel) runPaletteSelection() {
+	idx := m.paletteList.GetCurrentItem()
+	if idx < 0 || idx >= len(m.paletteMatches) {
+		m.closePalette()
+		return
+	}
+	entry := m.paletteMatches[idx].Entry
+	m.closePalette()
+	m.addOutput(m.executeCommand(entry.Name, nil))
+}
+
+// rankCommands scores every entry's name and aliases against pattern and
+// returns the matches - keeping each entry's best-scoring name variant -
+// in descending score order. An empty pattern matches everything in
+// registration order.
+func rankCommands(entries []CommandEntry, pattern string) []CommandMatch {
+	var matches []CommandMatch
+	for _, e := range entries {
+		best, bestPositions, ok := paletteFuzzyMatch(pattern, e.Name)
+		for _, alias := range e.Aliases {
+			score, positions, aliasOK := paletteFuzzyMatch(pattern, alias)
+			if aliasOK && (!ok || score > best) {
+				best, bestPositions, ok = score, positions, true
+			}
+		}
+		if !ok {
+			continue
+		}
+		matches = append(matches, CommandMatch{Entry: e, Score: best, Positions: bestPositions})
+	}
+
+	sortCommandMatches(matches)
+	return matches
+}
+
+func sortCommandMatches(matches []CommandMatch) {
+	for i := 1; i < len(matches); i++ {
+		for j := i; j > 0 && matches[j].Score > matches[j-1].Score; j-- {
+			matches[j], matches[j-1] = matches[j-1], matches[j]
+		}
+	}
+}
+
+// highlightCommandName renders a match's name with its matched runes
+// wrapped in tview's bold color tag.
+func highlightCommandName(cm CommandMatch) string {
+	matched := make(map[int]bool, len(cm.Positions))
+	for _, p := range cm.Positions {
+		matched[p] = true
+	}
+	var b strings.Builder
+	for i, r := range cm.Entry.Name {
+		if matched[i] {
+			b.WriteString("[::b]")
+			b.WriteRune(r)
+			b.WriteString("[::-]")
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Fuzzy scoring, in the spirit of fzf/sahilm's algorithm: reward
+// consecutive matches, word-boundary starts and camelCase humps, and
+// penalize gaps between matched characters.
+const (
+	paletteScoreMatch        = 16
+	paletteScoreGapStart     = -3
+	paletteScoreGapExtension = -1
+	paletteBonusBoundary     = 8
+	paletteBonusConsecutive  = 4
+	paletteBonusCamel        = 8
+)
+
+// paletteFuzzyMatch reports whether every rune of pattern appears in
+// text, in order (case-insensitively), and if so returns its score and
+// the byte offsets in text that matched.
+func paletteFuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+
+	lowerText := strings.ToLower(text)
+	lowerPattern := strings.ToLower(pattern)
+
+	pi := 0
+	prevMatched := -2
+	for ti := 0; ti < len(lowerText) && pi < len(lowerPattern); ti++ {
+		if lowerText[ti] != lowerPattern[pi] {
+			continue
+		}
+
+		s := paletteScoreMatch
+		if isPaletteWordBoundary(text, ti) {
+			s += paletteBonusBoundary
+		}
+		if isPaletteCamelHump(text, ti) {
+			s += paletteBonusCamel
+		}
+		switch {
+		case ti == prevMatched+1:
+			s += paletteBonusConsecutive
+		case prevMatched >= 0:
+			gap := ti - prevMatched - 1
+			s += paletteScoreGapStart + gap*paletteScoreGapExtension
+		}
+
+		score += s
+		positions = append(positions, ti)
+		prevMatched = ti
+		pi++
+	}
+
+	if pi < len(lowerPattern) {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+func isPaletteWordBoundary(text string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	switch text[i-1] {
+	case '/', '.', '_', '-', ' ':
+		return true
+	default:
+		return false
+	}
+}
+
+func isPaletteCamelHump(text string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	c, prev := text[i], text[i-1]
+	return c >= 'A' && c <= 'Z' && prev >= 'a' && prev <= 'z'
+}
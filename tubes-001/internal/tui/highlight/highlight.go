@@ -0,0 +1,74 @@
+// Package highlight wraps github.com/alecthomas/chroma/v2 behind a small
+// Highlighter interface so callers in the tui package (the markdown
+// renderer's fenced code blocks, the editor's buffer view) can share one
+// syntax-highlighting backend instead of each picking their own Chroma
+// settings.
+package highlight
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+)
+
+// Highlighter renders source code as ANSI-styled text for a given
+// language and Chroma style name.
+type Highlighter interface {
+	HighlightCode(source, lang, theme string) (string, error)
+}
+
+// Chroma is the default Highlighter, backed by chroma/v2's lexers,
+// styles, and a true-color ANSI formatter.
+type Chroma struct{}
+
+// HighlightCode tokenizes source with the lexer named by lang (falling
+// back to content-based detection, then a no-op lexer), looks up theme
+// as a Chroma style name (falling back to the built-in default style),
+// and formats the result as ANSI escape sequences.
+func (Chroma) HighlightCode(source, lang, theme string) (string, error) {
+	lexer := lexers.Get(lang)
+	if lexer == nil {
+		lexer = lexers.Analyse(source)
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	style := styles.Get(theme)
+	if style == nil {
+		style = styles.Fallback
+	}
+
+	iterator, err := lexer.Tokenise(nil, source)
+	if err != nil {
+		return "", fmt.Errorf("highlight: tokenize: %w", err)
+	}
+
+	var b strings.Builder
+	if err := formatters.TTY16m.Format(&b, style, iterator); err != nil {
+		return "", fmt.Errorf("highlight: format: %w", err)
+	}
+	return b.String(), nil
+}
+
+// LangForPath guesses a Chroma lexer name from a file path's extension,
+// for callers that only have a path and not a declared language.
+func LangForPath(path string) string {
+	lexer := lexers.Match(path)
+	if lexer == nil {
+		return ""
+	}
+	return lexer.Config().Name
+}
+
+// HighlightBuffer highlights content for display as a whole editor
+// buffer, detecting the language from path's extension rather than
+// requiring the caller to know it up front.
+func HighlightBuffer(h Highlighter, path, content, theme string) (string, error) {
+	return h.HighlightCode(content, LangForPath(path), theme)
+}
@@ -5,9 +5,30 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
+// defaultHistoryMaxEntries bounds a HybridModel's CommandHistory when
+// no override is given.
+const defaultHistoryMaxEntries = 500
+
+// historyStateFile returns the on-disk path CommandHistory persists to,
+// rooted at $TUBES_DIR (same convention as layoutStateFile and
+// theme.StylesetDir) or the current working directory.
+func historyStateFile() (string, error) {
+	root := os.Getenv("TUBES_DIR")
+	if root == "" {
+		var err error
+		root, err = os.Getwd()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(root, "history.json"), nil
+}
+
 // CommandHistoryEntry represents a single command execution record
 type CommandHistoryEntry struct {
 	ID         int       `json:"id"`
@@ -299,4 +320,121 @@ func (ch *CommandHistory) GetStats() map[string]interface{} {
 		"avg_duration":   fmt.Sprintf("%dms", avgDuration),
 		"total_duration": fmt.Sprintf("%dms", totalDuration),
 	}
+}
+
+// SearchOptions filters and configures a CommandHistory.Search call.
+type SearchOptions struct {
+	// Fuzzy enables subsequence matching - query's characters must
+	// appear in order but not necessarily adjacent - instead of a
+	// plain substring match.
+	Fuzzy bool
+	// Mode, PWD, and Success, when non-zero/non-nil, restrict results
+	// to entries with a matching field.
+	Mode    string
+	PWD     string
+	Success *bool
+}
+
+// historyRecencyWeight outweighs any plausible tightness score, so two
+// matches of similar shape are broken by recency first - the way a
+// real Ctrl-R search favors "most recent match" above all else.
+const historyRecencyWeight = 1000
+
+// Search returns entries whose command+args match query, most relevant
+// first, restricted by opts' filters. Relevance combines recency (an
+// entry's position in Entries, which is already most-recent-first) with
+// match tightness (how short a span of the command query matched
+// within). Because historyRecencyWeight exceeds the tightness range of
+// ordinary commands, a more recent match ranks above an older one
+// whatever their tightness.
+func (ch *CommandHistory) Search(query string, opts SearchOptions) []*CommandHistoryEntry {
+	type scored struct {
+		entry *CommandHistoryEntry
+		score int
+	}
+
+	var matches []scored
+	for i, entry := range ch.Entries {
+		if opts.Mode != "" && entry.Mode != opts.Mode {
+			continue
+		}
+		if opts.PWD != "" && entry.PWD != opts.PWD {
+			continue
+		}
+		if opts.Success != nil && entry.Success != *opts.Success {
+			continue
+		}
+
+		tightness, ok := historyMatchTightness(historySearchText(entry), query, opts.Fuzzy)
+		if !ok {
+			continue
+		}
+
+		recency := len(ch.Entries) - i
+		matches = append(matches, scored{entry: entry, score: recency*historyRecencyWeight + tightness})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	out := make([]*CommandHistoryEntry, len(matches))
+	for i, m := range matches {
+		out[i] = m.entry
+	}
+	return out
+}
+
+// historySearchText is what Search matches query against: the command
+// plus its args, space-joined.
+func historySearchText(entry *CommandHistoryEntry) string {
+	if len(entry.Args) == 0 {
+		return entry.Command
+	}
+	return entry.Command + " " + strings.Join(entry.Args, " ")
+}
+
+// historyMatchTightness reports whether query matches text (a
+// substring, or an in-order subsequence if fuzzy is set) and, if so, a
+// score rewarding a shorter match span - "go bui" matches "go build"
+// tighter than it matches "go run build-all", which scores lower.
+func historyMatchTightness(text, query string, fuzzy bool) (score int, ok bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	lower := strings.ToLower(text)
+	lowerQuery := strings.ToLower(query)
+
+	if !fuzzy {
+		if !strings.Contains(lower, lowerQuery) {
+			return 0, false
+		}
+		return 100 - len(lowerQuery), true
+	}
+
+	start, end, ok := historySubsequenceSpan(lower, lowerQuery)
+	if !ok {
+		return 0, false
+	}
+	return 100 - (end - start), true
+}
+
+// historySubsequenceSpan reports whether every byte of query appears in
+// text in order, returning the start/end byte offsets of the first such
+// match found scanning left to right.
+func historySubsequenceSpan(text, query string) (start, end int, ok bool) {
+	qi := 0
+	start = -1
+	for i := 0; i < len(text) && qi < len(query); i++ {
+		if text[i] == query[qi] {
+			if start < 0 {
+				start = i
+			}
+			end = i + 1
+			qi++
+		}
+	}
+	if qi < len(query) {
+		return 0, 0, false
+	}
+	return start, end, true
 }
\ No newline at end of file
@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// historySearch holds Ctrl-R's reverse-incremental search state: the
+// typed query, which ranked match is currently selected (Ctrl-R/Ctrl-S
+// cycle it without re-running Search), and whether fuzzy (subsequence)
+// matching is on instead of substring.
+type historySearch struct {
+	active  bool
+	query   string
+	fuzzy   bool
+	matches []*CommandHistoryEntry
+	idx     int
+}
+
+// initHistorySearch builds the Ctrl-R overlay's mini input, anchored at
+// the bottom of the output pane like the "/" scrollback search, and adds
+// it as a hidden page alongside "main".
+func (m *HybridModel) initHistorySearch() {
+	m.historyInput = tview.NewInputField()
+	m.historyInput.SetBorder(true).
+		SetTitle("History search (Ctrl-R: older, Ctrl-S: newer, Enter: accept, Esc: cancel)")
+
+	m.historyInput.SetChangedFunc(func(text string) {
+		m.runHistorySearch(text)
+	})
+	m.historyInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			m.closeHistorySearch()
+			return nil
+		case tcell.KeyEnter:
+			m.acceptHistorySearch()
+			return nil
+		case tcell.KeyCtrlR:
+			m.cycleHistorySearch(1)
+			return nil
+		case tcell.KeyCtrlS:
+			m.cycleHistorySearch(-1)
+			return nil
+		}
+		return event
+	})
+
+	m.pages.AddPage("history-search", bottomBar(m.historyInput, 3), true, false)
+}
+
+// openHistorySearch enters Ctrl-R search mode, focusing the mini input.
+func (m *HybridModel) openHistorySearch() {
+	m.historySearch = historySearch{active: true}
+	m.historyInput.SetText("")
+	m.updateHistorySearchLabel()
+	m.pages.ShowPage("history-search")
+	m.app.SetFocus(m.historyInput)
+}
+
+// closeHistorySearch leaves search mode without touching the main input
+// and returns focus to it.
+func (m *HybridModel) closeHistorySearch() {
+	m.historySearch = historySearch{}
+	m.pages.HidePage("history-search")
+	m.app.SetFocus(m.input)
+}
+
+// runHistorySearch re-ranks history against query and selects the top
+// (most relevant) match.
+func (m *HybridModel) runHistorySearch(query string) {
+	m.historySearch.query = query
+	m.historySearch.matches = m.history.Search(query, SearchOptions{Fuzzy: m.historySearch.fuzzy})
+	m.historySearch.idx = 0
+	m.updateHistorySearchLabel()
+}
+
+// cycleHistorySearch moves the selected match by delta - Ctrl-R (+1)
+// steps to an older match, Ctrl-S (-1) back to a newer one - wrapping at
+// either end.
+func (m *HybridModel) cycleHistorySearch(delta int) {
+	n := len(m.historySearch.matches)
+	if n == 0 {
+		return
+	}
+	m.historySearch.idx = ((m.historySearch.idx+delta)%n + n) % n
+	m.updateHistorySearchLabel()
+}
+
+// updateHistorySearchLabel shows the selected match's command, args,
+// timestamp, and duration inline in the overlay's label, the way
+// liner.Liner's reverse-i-search prompt echoes the matched line.
+func (m *HybridModel) updateHistorySearchLabel() {
+	if len(m.historySearch.matches) == 0 {
+		m.historyInput.SetLabel("(failed reverse-i-search)` ")
+		return
+	}
+	entry := m.historySearch.matches[m.historySearch.idx]
+	m.historyInput.SetLabel(fmt.Sprintf("(reverse-i-search '%s %v' @ %s, %dms)` ",
+		entry.Command, entry.Args, entry.Timestamp.Format("15:04:05"), entry.Duration))
+}
+
+// acceptHistorySearch copies the selected match into the main input and
+// returns focus to it, same as Enter in a real reverse-i-search.
+func (m *HybridModel) acceptHistorySearch() {
+	if len(m.historySearch.matches) > 0 {
+		entry := m.historySearch.matches[m.historySearch.idx]
+		m.input.SetText(historySearchText(entry))
+	}
+	m.pages.HidePage("history-search")
+	m.historySearch = historySearch{}
+	m.app.SetFocus(m.input)
+}
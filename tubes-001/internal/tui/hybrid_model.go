@@ -2,8 +2,16 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
-	
+	"time"
+
+	"tubes/internal/codeintel"
+	"tubes/internal/fswatch"
+	"tubes/internal/theme"
+	"tubes/internal/tui/adapter"
+
 	"github.com/rivo/tview"
 	"github.com/gdamore/tcell/v2"
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,17 +22,61 @@ type HybridModel struct {
 	// Tea state (keep existing state management)
 	commands      map[string]Command
 	currentFeedback string
-	scrollingOutput []string
 	currentInputMode inputMode
 	width, height   int
+
+	// Output scrollback: a capped ring buffer spilling to disk, searched
+	// and highlighted via "/" in view mode (see output_search.go).
+	scrollback *ScrollbackBuffer
+	search     outputSearch
+	searchInput *tview.InputField
+
+	// Command history: every executed command is logged here, and
+	// Ctrl-R opens a liner-style reverse-incremental search over it
+	// (see historysearch.go).
+	history        *CommandHistory
+	historySearch  historySearch
+	historyInput   *tview.InputField
 	
-	// tview components  
+	// tview components
 	app       *tview.Application
+	pages     *tview.Pages
 	flex      *tview.Flex
 	output    *tview.TextView
 	input     *tview.InputField
 	status    *tview.TextView
-	
+
+	// Command palette (Ctrl-P / /palette)
+	commandRegistry *CommandRegistry
+	paletteOpen     bool
+	paletteInput    *tview.InputField
+	paletteList     *tview.List
+	paletteHelp     *tview.TextView
+	paletteMatches  []CommandMatch
+
+	// Resizable output/input split: mouse-draggable gutter and
+	// Ctrl-Up/Down keyboard nudges, persisted across restarts.
+	rowSplit    *SplitContainer
+	layoutState *SplitRatios
+
+	// Cursor attachments: /attach and /detach operate on the current
+	// cursor's Attachments, shown via attachmentsView.
+	cursors         *codeintel.CursorDirectory
+	attachmentsView *AttachmentsView
+
+	// Live filesystem watch, toggled by /watch on|off: keeps cursors'
+	// Updated/Orphaned state in sync with on-disk changes and, while
+	// enabled, logs each event to output via addOutput.
+	fsWatcher    *fswatch.Watcher
+	watchEnabled bool
+
+	// Content-source adapters: /source mounts a named backend (the local
+	// filesystem is mounted as "filesystem" by default) and /cd records
+	// one of them as currentSource. Nothing reads currentSource; cursors
+	// get Source "filesystem" from NewCursor whatever /cd chose.
+	adapters      *adapter.Registry
+	currentSource string
+
 	// Hybrid coordination
 	teaProgram *tea.Program
 	shouldQuit bool
@@ -45,15 +97,24 @@ type HybridModel struct {
 	suggestions  []string
 	leftContent  []string
 	leftVP       *mockViewport
+
+	// Styleset subsystem: loaded colors/styles, broadcast live to
+	// output/input/status on /theme and on-disk reload.
+	styles            *theme.Styles
+	stylesets         *theme.StylesetRegistry
+	stopStylesetWatch func() error
 }
 
 // NewHybridModel creates a hybrid Tea+tview model
 func NewHybridModel(port string) *HybridModel {
+	layoutState := LoadSplitRatios()
+
 	m := &HybridModel{
 		commands:         make(map[string]Command),
-		scrollingOutput:  make([]string, 0),
 		currentInputMode: viewMode,
 		curMode:          modeSelf,
+		layoutState:      layoutState,
+		cursors:          codeintel.NewCursorDirectory(),
 		col1Ratio:        0.3,
 		headerH:          1,
 		cliH:             1,
@@ -63,11 +124,37 @@ func NewHybridModel(port string) *HybridModel {
 		suggestions:      []string{},
 		leftContent:      []string{},
 		leftVP:           &mockViewport{},
+		styles:           theme.NewDefaultStyles(),
 	}
-	
+	if dir, err := theme.StylesetDir(); err == nil {
+		m.stylesets = theme.NewStylesetRegistry(dir)
+	}
+
+	historyPath, err := historyStateFile()
+	if err != nil {
+		historyPath = ""
+	}
+	m.history = NewCommandHistory(defaultHistoryMaxEntries, historyPath)
+
+	scrollback, err := NewScrollbackBuffer(DefaultScrollbackCap)
+	if err != nil {
+		scrollback = &ScrollbackBuffer{Cap: DefaultScrollbackCap}
+	}
+	m.scrollback = scrollback
+
+	// The local filesystem is always mounted as "filesystem", matching
+	// the behavior cursors had before adapters existed.
+	root, err := os.Getwd()
+	if err != nil {
+		root = "."
+	}
+	m.adapters = adapter.NewRegistry()
+	m.adapters.Register(adapter.NewFilesystem("filesystem", root))
+	m.currentSource = "filesystem"
+
 	// Initialize tview components
 	m.initTviewComponents()
-	
+
 	// Commands are handled directly in executeCommand method
 	
 	return m
@@ -101,18 +188,121 @@ func (m *HybridModel) initTviewComponents() {
 		SetTextAlign(tview.AlignLeft)
 	m.status.SetBorder(false)
 	
-	// Main flex layout: output (flexible) + input (fixed) + status (fixed)
-	m.flex = tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(m.output, 0, 1, false).     // Output takes remaining space
-		AddItem(m.input, 3, 0, true).       // Input is 3 lines high, focused
-		AddItem(m.status, 1, 0, false)      // Status is 1 line
-	
+	// Output/input split: mouse-draggable gutter and Ctrl-Up/Down nudges
+	// replace the old fixed 3-line input height.
+	m.rowSplit = NewSplitContainer(SplitHorizontal, m.layoutState.Get("hybrid.row", 0.85), 0.5, 0.95, func(ratio float64) {
+		m.layoutState.Set("hybrid.row", ratio)
+	})
+
+	// Main flex layout: output + input share the split ratio; status is
+	// a fixed 1-line footer below both.
+	m.flex = tview.NewFlex().SetDirection(tview.FlexRow)
+	m.rebuildFlex()
+
+	// Pages lets the command palette overlay the main layout instead of
+	// replacing it.
+	m.pages = tview.NewPages().
+		AddPage("main", m.flex, true, true)
+
 	// Set up input handling
 	m.setupInputHandling()
-	
+
 	// Set up key bindings
 	m.setupKeyBindings()
+
+	// Mouse handling for the resizable output/input gutter
+	m.app.SetMouseCapture(m.handleMouse)
+
+	// Command palette
+	m.initCommandPalette()
+
+	// Ctrl-R reverse-incremental history search
+	m.initHistorySearch()
+
+	// Attachments view, opened by /attach, /detach and /attachments
+	m.attachmentsView = NewAttachmentsView()
+	m.attachmentsView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			m.closeAttachments()
+			return nil
+		}
+		return event
+	})
+	m.pages.AddPage("attachments", centeredModal(m.attachmentsView, 60, 12), true, false)
+
+	// Scrollback search, opened with "/" in view mode: a mini input
+	// anchored to the bottom of the output pane.
+	m.searchInput = tview.NewInputField().SetLabel("/")
+	m.searchInput.SetBorder(true).SetTitle("Search scrollback (Enter: search, Esc: close)")
+	m.searchInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			m.closeSearch()
+			return nil
+		case tcell.KeyEnter:
+			m.runSearch(m.searchInput.GetText())
+			return nil
+		}
+		return event
+	})
+	m.pages.AddPage("search", bottomBar(m.searchInput, 3), true, false)
+}
+
+// currentCursor returns the cursor directory's current selection, or nil
+// if none is selected yet.
+func (m *HybridModel) currentCursor() *codeintel.Cursor {
+	if m.cursors == nil {
+		return nil
+	}
+	return m.cursors.GetCurrentCursor()
+}
+
+// openAttachments refreshes and shows the attachments overlay for the
+// current cursor.
+func (m *HybridModel) openAttachments() {
+	cursor := m.currentCursor()
+	if cursor == nil {
+		m.addOutput("No cursor selected")
+		return
+	}
+	m.attachmentsView.SetAttachments(cursor)
+	m.pages.ShowPage("attachments")
+	m.app.SetFocus(m.attachmentsView)
+}
+
+// closeAttachments hides the attachments overlay and returns focus to
+// the main input field.
+func (m *HybridModel) closeAttachments() {
+	m.pages.HidePage("attachments")
+	m.app.SetFocus(m.input)
+}
+
+// rebuildFlex re-lays-out output and input at rowSplit's current ratio,
+// keeping status as a fixed 1-line footer.
+func (m *HybridModel) rebuildFlex() {
+	outputWeight := int(m.rowSplit.Ratio() * 1000)
+	m.flex.Clear().
+		AddItem(m.output, 0, outputWeight, false).
+		AddItem(m.input, 0, 1000-outputWeight, true).
+		AddItem(m.status, 1, 0, false)
+}
+
+// handleMouse drives rowSplit's draggable gutter between output and
+// input.
+func (m *HybridModel) handleMouse(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+	x, y, w, h := m.flex.GetRect()
+	if m.rowSplit.HandleMouseEvent(x, y, w, h-1, event, action) { // exclude the fixed status row
+		m.rebuildFlex()
+		return nil, action
+	}
+	return event, action
+}
+
+// adjustRowRatio nudges the output/input split by delta, persisting the
+// new ratio.
+func (m *HybridModel) adjustRowRatio(delta float64) {
+	m.rowSplit.Nudge(delta)
+	m.rebuildFlex()
 }
 
 // setupInputHandling configures input field behavior
@@ -136,10 +326,27 @@ func (m *HybridModel) setupKeyBindings() {
 			m.shouldQuit = true
 			m.app.Stop()
 			return nil
+		case tcell.KeyCtrlP:
+			m.openPalette()
+			return nil
+		case tcell.KeyCtrlR:
+			if !m.historySearch.active {
+				m.openHistorySearch()
+				return nil
+			}
+		case tcell.KeyCtrlUp:
+			m.adjustRowRatio(-splitNudgeStep)
+			return nil
+		case tcell.KeyCtrlDown:
+			m.adjustRowRatio(splitNudgeStep)
+			return nil
 		}
 		
-		// In view mode, handle scrolling
-		if m.currentInputMode == viewMode {
+		// In view mode, handle scrolling and scrollback search - but not
+		// while the palette, scrollback search, or history search input
+		// itself has focus, so their own typed queries ("n", "/", ...)
+		// aren't stolen here.
+		if m.currentInputMode == viewMode && !m.paletteOpen && !m.search.active && !m.historySearch.active {
 			switch event.Key() {
 			case tcell.KeyUp:
 				row, col := m.output.GetScrollOffset()
@@ -149,6 +356,18 @@ func (m *HybridModel) setupKeyBindings() {
 				row, col := m.output.GetScrollOffset()
 				m.output.ScrollTo(row+1, col)
 				return nil
+			case tcell.KeyRune:
+				switch event.Rune() {
+				case '/':
+					m.openSearch()
+					return nil
+				case 'n':
+					m.nextMatch()
+					return nil
+				case 'N':
+					m.prevMatch()
+					return nil
+				}
 			}
 		}
 		
@@ -185,32 +404,86 @@ func (m *HybridModel) handleCommand(input string) {
 		parts := strings.Fields(input)
 		cmdName := parts[0]
 		args := parts[1:]
-		
+
+		start := time.Now()
 		result := m.executeCommand(cmdName, args)
 		m.addOutput(result)
+		m.logHistoryEntry(cmdName, args, result, time.Since(start))
 	} else {
 		m.addOutput("Use /help to see available commands")
 	}
 }
 
+// logHistoryEntry records one executed command in m.history, so Ctrl-R
+// search has something to search. success is a heuristic over result
+// since executeCommand returns plain text rather than an error.
+func (m *HybridModel) logHistoryEntry(cmdName string, args []string, result string, duration time.Duration) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		pwd = ""
+	}
+	success := !strings.HasPrefix(result, "Unknown command") && !strings.HasPrefix(result, "Error")
+	// Truncated with truncateString (to 500, where GetLLMContext cuts to
+	// 200), so a command with a huge result doesn't bloat every future
+	// AddEntry's history.json rewrite.
+	m.history.AddEntry(cmdName, args, pwd, fmt.Sprintf("%v", m.curMode), success, truncateString(result, 500), "", duration.Milliseconds(), "")
+}
+
 // executeCommand handles built-in commands for hybrid mode
 func (m *HybridModel) executeCommand(cmd string, args []string) string {
 	switch cmd {
 	case "/help":
 		return `Available commands:
 /help     - Show this help
-/clear    - Clear output  
+/clear    - Clear output
 /quit     - Exit application
 /mode     - Show current mode
-/echo <text> - Echo text back`
+/echo <text> - Echo text back
+/theme [name] - List stylesets, or switch to one
+/palette  - Open the fuzzy command palette (or press Ctrl-P)
+/attach <path> - Attach a file to the current cursor
+/detach <id>   - Remove an attachment from the current cursor
+/attachments   - Show the current cursor's attachments
+/watch on|off  - Watch the cwd (and any cursor's file) for changes
+/source add <name> <type> <url> - Mount an adapter ("filesystem" or "http") as <name>
+/source list   - List mounted adapters
+/cd <source>:/path - Mark a mounted source as current (cursors still use filesystem)
+/ (view mode)  - Search scrollback; n/N jump between matches
+/save-log <path> - Dump the full scrollback (including spilled history) to path
+Ctrl-Up/Down - Resize the output/input split (drag the gutter with the mouse too)`
 	case "/clear":
-		m.scrollingOutput = []string{}
-		m.output.SetText("")
+		m.scrollback.Clear()
+		m.renderOutput()
 		return "Output cleared"
+	case "/save-log":
+		return m.handleSaveLogCommand(args)
 	case "/quit":
+		if m.stopStylesetWatch != nil {
+			m.stopStylesetWatch()
+		}
+		m.scrollback.Close()
+		m.stopWatching()
 		m.shouldQuit = true
 		m.app.Stop()
 		return "Goodbye!"
+	case "/theme":
+		return m.handleThemeCommand(args)
+	case "/palette":
+		m.openPalette()
+		return "Opened command palette"
+	case "/attach":
+		return m.handleAttachCommand(args)
+	case "/detach":
+		return m.handleDetachCommand(args)
+	case "/attachments":
+		m.openAttachments()
+		return "Opened attachments"
+	case "/watch":
+		return m.handleWatchCommand(args)
+	case "/source":
+		return m.handleSourceCommand(args)
+	case "/cd":
+		return m.handleCdCommand(args)
 	case "/mode":
 		modeText := "view"
 		if m.currentInputMode == textMode {
@@ -227,18 +500,23 @@ func (m *HybridModel) executeCommand(cmd string, args []string) string {
 	}
 }
 
-// addOutput adds text to the output area
+// addOutput appends text to the scrollback buffer and redraws the output
+// area, re-applying any active search highlight.
 func (m *HybridModel) addOutput(text string) {
-	m.scrollingOutput = append(m.scrollingOutput, text)
-	
-	// Keep only last 200 lines
-	if len(m.scrollingOutput) > 200 {
-		m.scrollingOutput = m.scrollingOutput[len(m.scrollingOutput)-200:]
+	m.scrollback.Append(text)
+	m.renderOutput()
+}
+
+// handleSaveLogCommand implements /save-log <path>: it dumps the full
+// scrollback, including lines already spilled to disk, to path.
+func (m *HybridModel) handleSaveLogCommand(args []string) string {
+	if len(args) == 0 {
+		return "Usage: /save-log <path>"
 	}
-	
-	// Update tview output
-	m.output.SetText(strings.Join(m.scrollingOutput, "\n"))
-	m.output.ScrollToEnd()
+	if err := m.scrollback.Dump(args[0]); err != nil {
+		return fmt.Sprintf("Error saving log: %s", err)
+	}
+	return fmt.Sprintf("Saved scrollback to %s", args[0])
 }
 
 // updateStatus updates the status bar with current mode and info
@@ -267,7 +545,7 @@ func (m *HybridModel) Run() error {
 	// Start in view mode
 	m.app.SetFocus(m.output)
 	
-	return m.app.SetRoot(m.flex, true).Run()
+	return m.app.SetRoot(m.pages, true).Run()
 }
 
 // Bubbletea interface methods for compatibility
@@ -306,4 +584,226 @@ func (m *HybridModel) adjustBand(which string, delta int) {
 	m.addOutput(fmt.Sprintf("Adjusting band %s by %d", which, delta))
 }
 
+// handleAttachCommand implements /attach <path>: it stats path and
+// appends it to the current cursor's Attachments.
+func (m *HybridModel) handleAttachCommand(args []string) string {
+	if len(args) == 0 {
+		return "Usage: /attach <path>"
+	}
+	cursor := m.currentCursor()
+	if cursor == nil {
+		return "No cursor selected"
+	}
+	att, err := m.cursors.AttachFile(cursor, args[0])
+	if err != nil {
+		return fmt.Sprintf("Error attaching %s: %s", args[0], err)
+	}
+	m.attachmentsView.SetAttachments(cursor)
+	return fmt.Sprintf("Attached %s (%s)", att.Path, att.Mime)
+}
+
+// handleDetachCommand implements /detach <id>: it removes the named
+// attachment from the current cursor.
+func (m *HybridModel) handleDetachCommand(args []string) string {
+	if len(args) == 0 {
+		return "Usage: /detach <id>"
+	}
+	cursor := m.currentCursor()
+	if cursor == nil {
+		return "No cursor selected"
+	}
+	if err := m.cursors.DetachFile(cursor, args[0]); err != nil {
+		return fmt.Sprintf("Error: %s", err)
+	}
+	m.attachmentsView.SetAttachments(cursor)
+	return fmt.Sprintf("Detached %s", args[0])
+}
+
+// handleWatchCommand implements /watch on|off.
+func (m *HybridModel) handleWatchCommand(args []string) string {
+	if len(args) == 0 {
+		return "Usage: /watch on|off"
+	}
+	switch args[0] {
+	case "on":
+		return m.startWatching()
+	case "off":
+		return m.stopWatching()
+	default:
+		return "Usage: /watch on|off"
+	}
+}
+
+// startWatching recursively watches the cwd plus every cursor's file,
+// applying each event to m.cursors and, while enabled, logging it to
+// output. There's no separate file tree in this snapshot to incrementally
+// refresh - output and the cursor directory's own Updated/Orphaned state
+// are all there is to keep in sync.
+func (m *HybridModel) startWatching() string {
+	if m.watchEnabled {
+		return "Already watching"
+	}
+	root, err := os.Getwd()
+	if err != nil {
+		return fmt.Sprintf("Error: %s", err)
+	}
+	w, err := fswatch.New(root)
+	if err != nil {
+		return fmt.Sprintf("Error starting watcher: %s", err)
+	}
+	for _, mc := range m.cursors.MultiCursors {
+		for _, c := range mc.Cursors {
+			_ = w.AddDir(filepath.Dir(c.FilePath))
+		}
+	}
+	m.fsWatcher = w
+	m.watchEnabled = true
+	go m.consumeFSEvents(w)
+	return fmt.Sprintf("Watching %s", root)
+}
+
+// stopWatching stops the filesystem watcher, if one is running.
+func (m *HybridModel) stopWatching() string {
+	if !m.watchEnabled {
+		return "Not watching"
+	}
+	m.watchEnabled = false
+	m.fsWatcher.Close()
+	m.fsWatcher = nil
+	return "Stopped watching"
+}
+
+// consumeFSEvents applies fswatch events to the cursor directory and, if
+// still enabled, logs them to output, until the watcher is closed.
+func (m *HybridModel) consumeFSEvents(w *fswatch.Watcher) {
+	for ev := range w.Events() {
+		m.cursors.HandleFSEvent(ev)
+		m.app.QueueUpdateDraw(func() {
+			if m.watchEnabled {
+				m.addOutput(fmt.Sprintf("[watch] %s %s", ev.Op, ev.Path))
+			}
+		})
+	}
+}
+
+// handleSourceCommand implements /source add <name> <type> <url> and
+// /source list. <type> is "filesystem" (url is a root directory) or
+// "http" (url is a base URL; an optional 4th arg is a bearer token).
+func (m *HybridModel) handleSourceCommand(args []string) string {
+	if len(args) == 0 {
+		return "Usage: /source add <name> <type> <url> | /source list"
+	}
+	switch args[0] {
+	case "list":
+		return "Mounted sources: " + strings.Join(m.adapters.Names(), ", ")
+	case "add":
+		if len(args) < 4 {
+			return "Usage: /source add <name> <type> <url>"
+		}
+		name, kind, url := args[1], args[2], args[3]
+		switch kind {
+		case "filesystem":
+			m.adapters.Register(adapter.NewFilesystem(name, url))
+		case "http":
+			token := ""
+			if len(args) > 4 {
+				token = args[4]
+			}
+			m.adapters.Register(adapter.NewHTTP(name, url, token))
+		default:
+			return fmt.Sprintf("Unknown adapter type %q (want filesystem or http)", kind)
+		}
+		return fmt.Sprintf("Mounted %s (%s) as %q", url, kind, name)
+	default:
+		return "Usage: /source add <name> <type> <url> | /source list"
+	}
+}
+
+// handleCdCommand implements /cd <source>:/path: it checks that source is
+// mounted and stores it in m.currentSource. The path part is only echoed
+// back, and no cursor creation reads currentSource.
+func (m *HybridModel) handleCdCommand(args []string) string {
+	if len(args) == 0 {
+		return "Usage: /cd <source>:/path"
+	}
+	name, path, ok := strings.Cut(args[0], ":")
+	if !ok {
+		return "Usage: /cd <source>:/path"
+	}
+	if _, mounted := m.adapters.Get(name); !mounted {
+		return fmt.Sprintf("No source mounted as %q (see /source list)", name)
+	}
+	m.currentSource = name
+	return fmt.Sprintf("Switched to %s:%s", name, path)
+}
+
+// handleThemeCommand implements /theme: with no args, it lists the
+// stylesets available in the registry's directory; with a name, it
+// switches to that styleset and starts hot-reloading it.
+func (m *HybridModel) handleThemeCommand(args []string) string {
+	if m.stylesets == nil {
+		return "Styleset registry unavailable"
+	}
+	if len(args) == 0 {
+		names, err := m.stylesets.Names()
+		if err != nil {
+			return fmt.Sprintf("Error listing stylesets: %s", err)
+		}
+		if len(names) == 0 {
+			return fmt.Sprintf("No stylesets found in %s", m.stylesets.Dir())
+		}
+		return "Available stylesets: " + strings.Join(names, ", ")
+	}
+
+	if err := m.switchStyleset(args[0]); err != nil {
+		return fmt.Sprintf("Error loading styleset %q: %s", args[0], err)
+	}
+	return fmt.Sprintf("Switched to styleset: %s", args[0])
+}
+
+// switchStyleset loads and applies the named styleset, then re-arms a
+// watch on its file so on-disk edits take effect live without a restart.
+// Any previous watch is stopped first so switching stylesets doesn't
+// leak watchers.
+func (m *HybridModel) switchStyleset(name string) error {
+	s, err := m.stylesets.Switch(name)
+	if err != nil {
+		return err
+	}
+	m.applyStyles(s)
+
+	if m.stopStylesetWatch != nil {
+		m.stopStylesetWatch()
+		m.stopStylesetWatch = nil
+	}
+	stop, err := theme.WatchStyleset(m.stylesets.Path(name), func(s *theme.Styles) {
+		m.app.QueueUpdateDraw(func() {
+			m.applyStyles(s)
+		})
+		if m.cursors != nil {
+			m.cursors.Events.Publish(codeintel.Event{Event: codeintel.EventThemeReloaded, Payload: name})
+		}
+	})
+	if err == nil {
+		m.stopStylesetWatch = stop
+	}
+	return nil
+}
+
+// applyStyles pushes s's colors onto the live tview components - output,
+// input, and status - so a /theme switch or hot-reload takes effect
+// without restarting. There's no separate TUI or cursor view type in
+// this snapshot to broadcast to; HybridModel's own components are all
+// there is to update.
+func (m *HybridModel) applyStyles(s *theme.Styles) {
+	m.styles = s
+	m.output.SetTextColor(s.TcellColor("main", "fg"))
+	m.output.SetBackgroundColor(s.TcellColor("main", "bg"))
+	m.status.SetTextColor(s.TcellColor("sidebar", "fg"))
+	m.status.SetBackgroundColor(s.TcellColor("sidebar", "bg"))
+	m.input.SetFieldTextColor(s.TcellColor("input", "fg"))
+	m.input.SetFieldBackgroundColor(s.TcellColor("input", "bg"))
+	m.addOutput("Styles reloaded")
+}
+
 
@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// minInlineHeight is the smallest inline region we'll honor - enough room
+// for the input band plus a sliver of cursor/content above it.
+const minInlineHeight = 6
+
+// enableInline sizes the model to m.heightSpec's row count instead of the
+// full screen, so Run renders Tubes in a reserved region at the bottom of
+// the terminal rather than taking over the alternate screen buffer. It
+// falls back to the 80x24 default silently if the TTY size can't be read
+// (e.g. stdout isn't a terminal).
+func (m *TubesModel) enableInline() {
+	w, h, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return
+	}
+
+	rows, err := parseHeightSpec(m.heightSpec, h)
+	if err != nil {
+		return
+	}
+
+	m.width, m.height = w, rows
+	m.inline = true
+}
+
+// parseHeightSpec resolves a --height value ("24" rows, or "40%" of the
+// current TTY height) into an absolute row count, clamped to
+// [minInlineHeight, ttyHeight].
+func parseHeightSpec(spec string, ttyHeight int) (int, error) {
+	spec = strings.TrimSpace(spec)
+
+	if pct, ok := strings.CutSuffix(spec, "%"); ok {
+		n, err := strconv.Atoi(pct)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --height percentage %q: %w", spec, err)
+		}
+		return clampHeight(ttyHeight*n/100, ttyHeight), nil
+	}
+
+	n, err := strconv.Atoi(spec)
+	if err != nil {
+		return 0, fmt.Errorf("invalid --height %q: %w", spec, err)
+	}
+	return clampHeight(n, ttyHeight), nil
+}
+
+func clampHeight(rows, ttyHeight int) int {
+	if rows < minInlineHeight {
+		rows = minInlineHeight
+	}
+	if ttyHeight > 0 && rows > ttyHeight {
+		rows = ttyHeight
+	}
+	return rows
+}
+
+// watchResize re-resolves the inline height against SIGWINCH so a
+// terminal resize while Tubes is running doesn't leave it drawing over
+// stale dimensions. Only meaningful once enableInline has run.
+func (m *TubesModel) watchResize() {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGWINCH)
+
+	for range sig {
+		w, h, err := term.GetSize(int(os.Stdout.Fd()))
+		if err != nil {
+			continue
+		}
+		rows, err := parseHeightSpec(m.heightSpec, h)
+		if err != nil {
+			continue
+		}
+		m.app.QueueUpdateDraw(func() {
+			m.Resize(w, rows)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// layoutStateFile returns the on-disk path split ratios persist to,
+// rooted at $TUBES_DIR (same convention as theme.StylesetDir) or the
+// current working directory.
+func layoutStateFile() (string, error) {
+	root := os.Getenv("TUBES_DIR")
+	if root == "" {
+		var err error
+		root, err = os.Getwd()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(root, "layout.json"), nil
+}
+
+// SplitRatios is the persisted set of split ratios, keyed by a short name
+// ("tubes.col1", "hybrid.row") so every SplitContainer in the process can
+// share one file without clobbering the others. There's no cursor-manager
+// on-disk format in this snapshot to fold this into, so it's its own
+// small sidecar file, the same way CommandHistory persists itself.
+type SplitRatios struct {
+	Ratios map[string]float64 `json:"ratios"`
+	path   string
+}
+
+// LoadSplitRatios reads the persisted ratios, returning an empty (but
+// still saveable) set if the file doesn't exist yet or can't be read.
+func LoadSplitRatios() *SplitRatios {
+	r := &SplitRatios{Ratios: map[string]float64{}}
+	path, err := layoutStateFile()
+	if err != nil {
+		return r
+	}
+	r.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return r
+	}
+	json.Unmarshal(data, r)
+	if r.Ratios == nil {
+		r.Ratios = map[string]float64{}
+	}
+	return r
+}
+
+// Get returns the persisted ratio for key, or fallback if none is stored.
+func (r *SplitRatios) Get(key string, fallback float64) float64 {
+	if v, ok := r.Ratios[key]; ok {
+		return v
+	}
+	return fallback
+}
+
+// Set stores ratio under key and saves to disk immediately.
+func (r *SplitRatios) Set(key string, ratio float64) {
+	r.Ratios[key] = ratio
+	r.save()
+}
+
+func (r *SplitRatios) save() {
+	if r.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return
+	}
+	os.WriteFile(r.path, data, 0644)
+}
@@ -6,16 +6,44 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/glamour"
+
+	"tubes/internal/tui/highlight"
 )
 
+// defaultHighlightTheme is the Chroma style name used when a
+// MarkdownRenderer is built without an explicit WithHighlightTheme.
+const defaultHighlightTheme = "monokai"
+
 // MarkdownRenderer handles rendering markdown content with syntax highlighting
 type MarkdownRenderer struct {
 	renderer *glamour.TermRenderer
 	width    int
+
+	// highlighter, when set, is what FormatCodeBlock renders code with
+	// instead of glamour's built-in (Chroma-less) code styling. Fenced
+	// blocks inside a document passed to RenderString or RenderFile are
+	// still styled by glamour.
+	highlighter    highlight.Highlighter
+	highlightTheme string
+}
+
+// MarkdownOption configures a MarkdownRenderer at construction time.
+type MarkdownOption func(*MarkdownRenderer)
+
+// WithHighlighter makes FormatCodeBlock (and fenced code blocks rendered
+// through it) use h instead of glamour's own code-block styling.
+func WithHighlighter(h highlight.Highlighter) MarkdownOption {
+	return func(mr *MarkdownRenderer) { mr.highlighter = h }
+}
+
+// WithHighlightTheme sets the Chroma style name passed to the
+// highlighter. Defaults to defaultHighlightTheme.
+func WithHighlightTheme(theme string) MarkdownOption {
+	return func(mr *MarkdownRenderer) { mr.highlightTheme = theme }
 }
 
 // NewMarkdownRenderer creates a new markdown renderer with the specified width
-func NewMarkdownRenderer(width int) (*MarkdownRenderer, error) {
+func NewMarkdownRenderer(width int, opts ...MarkdownOption) (*MarkdownRenderer, error) {
 	// Configure glamour with appropriate settings for TUI
 	renderer, err := glamour.NewTermRenderer(
 		glamour.WithAutoStyle(),
@@ -24,11 +52,16 @@ func NewMarkdownRenderer(width int) (*MarkdownRenderer, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to create markdown renderer: %w", err)
 	}
-	
-	return &MarkdownRenderer{
-		renderer: renderer,
-		width:    width,
-	}, nil
+
+	mr := &MarkdownRenderer{
+		renderer:       renderer,
+		width:          width,
+		highlightTheme: defaultHighlightTheme,
+	}
+	for _, opt := range opts {
+		opt(mr)
+	}
+	return mr, nil
 }
 
 // RenderFile renders a markdown file and returns the formatted output
@@ -148,11 +181,18 @@ func ExtractTitle(content string) string {
 	return ""
 }
 
-// FormatCodeBlock formats a code block with syntax highlighting
+// FormatCodeBlock formats a code block with syntax highlighting. When a
+// Highlighter was installed via WithHighlighter, it renders the code
+// directly; otherwise it falls back to glamour's own fenced-code-block
+// styling.
 func (mr *MarkdownRenderer) FormatCodeBlock(code, language string) (string, error) {
+	if mr.highlighter != nil {
+		return mr.highlighter.HighlightCode(code, language, mr.highlightTheme)
+	}
+
 	// Create a markdown code block
 	markdown := fmt.Sprintf("```%s\n%s\n```", language, code)
-	
+
 	return mr.RenderString(markdown)
 }
 
@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// outputSearch holds the output pane's "/"-entered search mode: the
+// compiled query (falling back to a literal match if it doesn't compile
+// as a regex), how many matches the last render found, and which one
+// n/N is currently centered on.
+type outputSearch struct {
+	active  bool
+	query   string
+	re      *regexp.Regexp
+	matches int
+	current int
+}
+
+// bottomBar overlays p as a fixed-height bar anchored to the bottom of
+// the screen, full width - used for the scrollback search mini input.
+func bottomBar(p tview.Primitive, height int) tview.Primitive {
+	return tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(p, height, 0, true)
+}
+
+// openSearch enters search mode, focusing the mini input overlaid at the
+// bottom of the output pane.
+func (m *HybridModel) openSearch() {
+	m.search.active = true
+	m.searchInput.SetText("")
+	m.pages.ShowPage("search")
+	m.app.SetFocus(m.searchInput)
+}
+
+// closeSearch leaves search mode, clearing any highlight and returning
+// focus to the output pane.
+func (m *HybridModel) closeSearch() {
+	m.search = outputSearch{}
+	m.pages.HidePage("search")
+	m.app.SetFocus(m.output)
+	m.renderOutput()
+}
+
+// runSearch compiles query as a regex, falling back to a literal
+// (quote-escaped) match on compile error, then re-renders output with
+// every match region-tagged.
+func (m *HybridModel) runSearch(query string) {
+	if query == "" {
+		return
+	}
+	re, err := regexp.Compile(query)
+	if err != nil {
+		re = regexp.MustCompile(regexp.QuoteMeta(query))
+	}
+	m.search.query = query
+	m.search.re = re
+	m.search.current = 0
+	m.renderOutput()
+}
+
+// nextMatch/prevMatch move the highlighted match with n/N, wrapping
+// around, and scroll the viewport to keep it in view.
+func (m *HybridModel) nextMatch() {
+	if m.search.matches == 0 {
+		return
+	}
+	m.search.current = (m.search.current + 1) % m.search.matches
+	m.focusCurrentMatch()
+}
+
+func (m *HybridModel) prevMatch() {
+	if m.search.matches == 0 {
+		return
+	}
+	m.search.current = (m.search.current - 1 + m.search.matches) % m.search.matches
+	m.focusCurrentMatch()
+}
+
+// focusCurrentMatch highlights the current match's region, which
+// tview's TextView uses to scroll it into view via ScrollToHighlight.
+func (m *HybridModel) focusCurrentMatch() {
+	m.output.Highlight(fmt.Sprintf("match-%d", m.search.current))
+	m.output.ScrollToHighlight()
+}
+
+// renderOutput redraws m.output from the scrollback buffer, tagging
+// every search match (if a search is active) as its own tview region so
+// n/N and ScrollToHighlight can jump between them. With no active
+// search it just joins the scrollback lines, matching addOutput's
+// previous plain-text behavior.
+func (m *HybridModel) renderOutput() {
+	lines := m.scrollback.Lines()
+	if m.search.re == nil {
+		m.output.SetText(tview.Escape(strings.Join(lines, "\n")))
+		m.output.ScrollToEnd()
+		return
+	}
+
+	id := 0
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		last := 0
+		for _, loc := range m.search.re.FindAllStringIndex(line, -1) {
+			b.WriteString(tview.Escape(line[last:loc[0]]))
+			fmt.Fprintf(&b, `["match-%d"][black:yellow]%s[-:-][""]`, id, tview.Escape(line[loc[0]:loc[1]]))
+			id++
+			last = loc[1]
+		}
+		b.WriteString(tview.Escape(line[last:]))
+	}
+
+	m.search.matches = id
+	if m.search.current >= m.search.matches {
+		m.search.current = 0
+	}
+	m.output.SetText(b.String())
+	if m.search.matches > 0 {
+		m.focusCurrentMatch()
+	} else {
+		m.output.ScrollToEnd()
+	}
+}
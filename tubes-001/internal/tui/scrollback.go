@@ -0,0 +1,107 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// DefaultScrollbackCap is how many lines ScrollbackBuffer keeps in
+// memory before spilling the oldest ones to disk.
+const DefaultScrollbackCap = 10000
+
+// ScrollbackBuffer is a ring buffer of output lines backed by an on-disk
+// spill file: only the most recent Cap lines are kept in memory for
+// rendering/search, but every line ever appended survives in the spill
+// file for /save-log to recover in full.
+type ScrollbackBuffer struct {
+	Cap   int
+	lines []string
+	spill *os.File
+}
+
+// scrollbackSpillFile returns the on-disk path the spill file is created
+// at, rooted at $TUBES_DIR (same convention as layoutStateFile) or the
+// current working directory.
+func scrollbackSpillFile() (string, error) {
+	root := os.Getenv("TUBES_DIR")
+	if root == "" {
+		var err error
+		root, err = os.Getwd()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(root, "scrollback.log"), nil
+}
+
+// NewScrollbackBuffer creates a buffer capped at capLines, spilling
+// evicted lines to a fresh spill file (truncated on open - each run
+// starts its own history).
+func NewScrollbackBuffer(capLines int) (*ScrollbackBuffer, error) {
+	path, err := scrollbackSpillFile()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &ScrollbackBuffer{Cap: capLines, spill: f}, nil
+}
+
+// Append adds line, spilling the oldest in-memory line to disk once Cap
+// is exceeded.
+func (b *ScrollbackBuffer) Append(line string) {
+	b.lines = append(b.lines, line)
+	if len(b.lines) > b.Cap {
+		fmt.Fprintln(b.spill, b.lines[0])
+		b.lines = b.lines[1:]
+	}
+}
+
+// Clear drops every in-memory line after writing it to the spill file,
+// so /save-log can still recover pre-/clear history.
+func (b *ScrollbackBuffer) Clear() {
+	for _, line := range b.lines {
+		fmt.Fprintln(b.spill, line)
+	}
+	b.lines = nil
+}
+
+// Lines returns the in-memory tail of the buffer, oldest first.
+func (b *ScrollbackBuffer) Lines() []string {
+	return b.lines
+}
+
+// Dump writes every line ever appended - spilled lines followed by the
+// current in-memory tail - to path.
+func (b *ScrollbackBuffer) Dump(path string) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	w := bufio.NewWriter(out)
+
+	if _, err := b.spill.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, b.spill); err != nil {
+		return err
+	}
+	if _, err := b.spill.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+	for _, line := range b.lines {
+		fmt.Fprintln(w, line)
+	}
+	return w.Flush()
+}
+
+// Close releases the spill file.
+func (b *ScrollbackBuffer) Close() error {
+	return b.spill.Close()
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// SplitDirection is the axis a SplitContainer's gutter runs along.
+type SplitDirection int
+
+const (
+	SplitVertical   SplitDirection = iota // gutter is a vertical line; drag left/right
+	SplitHorizontal                       // gutter is a horizontal line; drag up/down
+)
+
+// splitNudgeStep is how far a single Ctrl-arrow keypress moves a split's
+// ratio.
+const splitNudgeStep = 0.02
+
+// SplitContainer turns mouse drags on a pane's gutter, or discrete
+// keyboard nudges, into changes to the ratio between two panes - the
+// resizable-pane behavior tmux and editors like micro give draggable
+// panel borders. It doesn't own any tview widgets itself: the owning
+// model re-lays-out its panes (via a layout.Grid's Fr units, or a Flex's
+// proportional AddItem weights) whenever the ratio changes, and decides
+// where the gutter lives on screen.
+type SplitContainer struct {
+	dir      SplitDirection
+	ratio    float64
+	min, max float64
+	dragging bool
+	onChange func(ratio float64)
+}
+
+// NewSplitContainer returns a split starting at ratio (the first pane's
+// share of the total), clamped to [min, max]. onChange, if non-nil, fires
+// once a drag ends or a Nudge is applied - the hook callers use to
+// persist the new ratio.
+func NewSplitContainer(dir SplitDirection, ratio, min, max float64, onChange func(float64)) *SplitContainer {
+	return &SplitContainer{
+		dir:      dir,
+		ratio:    clampRatio(ratio, min, max),
+		min:      min,
+		max:      max,
+		onChange: onChange,
+	}
+}
+
+// Ratio returns the first pane's current share of the total.
+func (s *SplitContainer) Ratio() float64 { return s.ratio }
+
+// SetRatio clamps and applies ratio without running onChange - used to
+// seed the split from a persisted value at startup.
+func (s *SplitContainer) SetRatio(ratio float64) {
+	s.ratio = clampRatio(ratio, s.min, s.max)
+}
+
+// Nudge adjusts the ratio by delta and reports the change immediately -
+// for Ctrl-arrow keyboard resizing, which has no drag-end event of its
+// own to hang persistence off of.
+func (s *SplitContainer) Nudge(delta float64) {
+	s.SetRatio(s.ratio + delta)
+	if s.onChange != nil {
+		s.onChange(s.ratio)
+	}
+}
+
+// HandleMouseEvent updates the ratio in response to a drag on the gutter
+// within the rectangle (x, y, w, h) the two panes share, reporting true
+// if it consumed the event. The gutter is the single cell at the split's
+// current position; onChange fires once, when the drag ends.
+func (s *SplitContainer) HandleMouseEvent(x, y, w, h int, event *tcell.EventMouse, action tview.MouseAction) bool {
+	total, origin := w, x
+	if s.dir == SplitHorizontal {
+		total, origin = h, y
+	}
+	if total <= 0 {
+		return false
+	}
+
+	mx, my := event.Position()
+	pos := mx
+	if s.dir == SplitHorizontal {
+		pos = my
+	}
+	gutter := origin + int(s.ratio*float64(total))
+
+	switch action {
+	case tview.MouseLeftDown:
+		if absInt(pos-gutter) <= 1 {
+			s.dragging = true
+			return true
+		}
+	case tview.MouseMove:
+		if s.dragging {
+			s.SetRatio(float64(pos-origin) / float64(total))
+			return true
+		}
+	case tview.MouseLeftUp:
+		if s.dragging {
+			s.dragging = false
+			if s.onChange != nil {
+				s.onChange(s.ratio)
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func clampRatio(r, min, max float64) float64 {
+	if r < min {
+		return min
+	}
+	if r > max {
+		return max
+	}
+	return r
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
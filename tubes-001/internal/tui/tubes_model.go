@@ -2,18 +2,26 @@ package tui
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
-	
+	"unicode/utf8"
+
 	"tubes/internal/layout"
 	"tubes/internal/codeintel"
 	"tubes/internal/theme"
 	"tubes/internal/api"
-	
+
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/rivo/tview"
 	"github.com/gdamore/tcell/v2"
 )
 
+// defaultPackMaxTokens is /pack's token budget when none is given.
+const defaultPackMaxTokens = 8000
+
 // TubesModel represents the new architecture with deterministic layout
 type TubesModel struct {
 	// Layout management
@@ -23,7 +31,8 @@ type TubesModel struct {
 	height    int
 	rects     struct {
 		CursorPane   layout.Rect  // Left pane: cursor directory
-		ContentPane  layout.Rect  // Right pane: metadata or file content  
+		Divider      layout.Rect  // 1-column drag handle between CursorPane and ContentPane
+		ContentPane  layout.Rect  // Right pane: metadata or file content
 		Input        layout.Rect  // Input field
 		Feedback     layout.Rect  // Feedback area (2 lines)
 		Status       layout.Rect  // Status line (1 line)
@@ -31,7 +40,8 @@ type TubesModel struct {
 	
 	// UI Components
 	cursorPane    *tview.TextView
-	contentPane   *tview.TextView  
+	divider       *tview.TextView
+	contentPane   *tview.TextView
 	inputField    *tview.InputField
 	feedbackArea  *tview.TextView
 	statusLine    *tview.TextView
@@ -41,13 +51,49 @@ type TubesModel struct {
 	cursors       *codeintel.CursorDirectory
 	styles        *theme.Styles
 	apiServer     *api.Server
-	
+
+	// Resizable cursor/content split: mouse-draggable gutter and
+	// Ctrl-Left/Right keyboard nudges, persisted across restarts.
+	colSplit      *SplitContainer
+	layoutState   *SplitRatios
+
+	// Inline display options (see TubesOptions): heightSpec is the raw
+	// --height value, inline is true once Run has resolved it against the
+	// TTY and sized the model to sit at the bottom of the screen instead
+	// of taking it over, and reverse flips row order so input renders
+	// above cursor/content.
+	heightSpec    string
+	inline        bool
+	reverse       bool
+
 	// Navigation state
 	activePane    string // "input" or "cursors"
 	navIndex      int    // current navigation position in cursor pane
 	visibleItems  []codeintel.NavigationItem
+	filterOffsets [][]int // fuzzy-match byte offsets into visibleItems[i].Title, parallel to visibleItems
 	inCursorMode  bool   // true when "entered" into a cursor (showing file content)
-	
+
+	// Fuzzy filter over the cursor pane, entered with "/" in cursor mode
+	// or the /filter command.
+	filterMode    bool
+	filterQuery   string
+
+	// Per-cursor file watcher (toggled with /watch), keeping cursor
+	// Content in sync with disk and flagging deleted files as Orphaned.
+	cursorWatcher *codeintel.CursorWatcher
+	watchEnabled  bool
+
+	// wordWrap mirrors contentPane's word-wrap setting, toggled with
+	// :wrap/:nowrap (/view wrap, or v in cursor mode).
+	wordWrap      bool
+
+	// Multi-selection over cursors in the pane, toggled with spacebar in
+	// cursor mode and consumed by /bulk. pendingBulk holds a /bulk action
+	// awaiting its "press y to confirm" gate: while set, every keypress
+	// is routed to handleBulkConfirmKey instead of normal navigation.
+	selectedIDs   map[string]bool
+	pendingBulk   *bulkConfirmation
+
 	// Commands (bridge to legacy command system)
 	commands      map[string]Command
 	commandHandlers map[string]CommandHandler
@@ -76,12 +122,23 @@ type TubesModel struct {
 // CommandHandler defines the interface for command execution
 type CommandHandler func(model *TubesModel, args []string) (string, error)
 
-// NewTubesModel creates a new Tubes model with the improved architecture
-func NewTubesModel(port string) *TubesModel {
+// NewTubesModel creates a new Tubes model with the improved
+// architecture. authDisabled preserves the old wide-open API behavior
+// for local dev (the --auth-disabled flag).
+func NewTubesModel(port string, authDisabled bool) *TubesModel {
 	cursors := codeintel.NewCursorDirectory()
 	styles := theme.NewDefaultStyles()
-	apiServer := api.NewServer(port, cursors)
+	apiServer := api.NewServer(cursors, api.Config{
+		Port:         port,
+		SnapshotDir:  ".tubes/snapshots",
+		WorkspaceRoot: ".",
+		TokenFile:    ".tubes/tokens.json",
+		AuthDisabled: authDisabled,
+		CORSOrigins:  []string{"http://localhost:3000"},
+	})
 	
+	layoutState := LoadSplitRatios()
+
 	model := &TubesModel{
 		cursors:      cursors,
 		styles:       styles,
@@ -92,7 +149,10 @@ func NewTubesModel(port string) *TubesModel {
 		commandHandlers: make(map[string]CommandHandler),
 		commands:        make(map[string]Command),
 		curMode:         modeSelf,
-		col1Ratio:       0.3,
+		wordWrap:        true,
+		selectedIDs:     make(map[string]bool),
+		layoutState:     layoutState,
+		col1Ratio:       layoutState.Get("tubes.col1", 0.3),
 		headerH:         1,
 		cliH:            1,
 		statusH:         1,
@@ -116,7 +176,32 @@ func NewTubesModel(port string) *TubesModel {
 	model.initComponents()
 	model.setupCommands()
 	model.loadSampleData()
-	
+
+	return model
+}
+
+// TubesOptions configures optional startup behavior for
+// NewTubesModelWithOptions.
+type TubesOptions struct {
+	// Height is a terminal-row count ("24") or a percentage of the
+	// current TTY height ("40%"). When set, Run sizes Tubes to an
+	// inline region at the bottom of the screen instead of taking over
+	// the full screen, leaving scrollback intact on exit.
+	Height string
+	// Reverse flips the row order so input renders above cursor/content
+	// instead of below it.
+	Reverse bool
+	// AuthDisabled preserves the old wide-open API behavior for local
+	// dev (the --auth-disabled flag).
+	AuthDisabled bool
+}
+
+// NewTubesModelWithOptions is NewTubesModel plus inline-height and
+// row-order options, e.g. for `tubes --height 40% --reverse`.
+func NewTubesModelWithOptions(port string, opts TubesOptions) *TubesModel {
+	model := NewTubesModel(port, opts.AuthDisabled)
+	model.heightSpec = opts.Height
+	model.reverse = opts.Reverse
 	return model
 }
 
@@ -153,7 +238,16 @@ func (m *TubesModel) initComponents() {
 	m.statusLine = tview.NewTextView().
 		SetDynamicColors(true)
 	m.statusLine.SetBackgroundColor(tcell.ColorBlack)
-	
+
+	// Divider (1-column drag handle between CursorPane and ContentPane)
+	m.divider = tview.NewTextView()
+	m.divider.SetBackgroundColor(tcell.ColorDarkGray)
+
+	m.colSplit = NewSplitContainer(SplitVertical, m.col1Ratio, 0.15, 0.6, func(ratio float64) {
+		m.col1Ratio = ratio
+		m.layoutState.Set("tubes.col1", ratio)
+	})
+
 	m.setupLayout()
 	m.setupEventHandlers()
 }
@@ -174,31 +268,45 @@ func (m *TubesModel) computeLayout() {
 		return
 	}
 	
-	// Define grid specification
+	// Cursor/content column widths track colSplit's ratio (mouse-
+	// draggable gutter, Ctrl-Left/Right nudges) instead of a fixed width.
+	// A 1-column divider sits between them as the visible drag handle.
+	leftWeight := int(m.colSplit.Ratio() * 1000)
+	rows := []layout.Row{
+		{Height: layout.Unit{Kind: layout.Fr, Val: 1}}, // Main content area
+		{Height: layout.Unit{Kind: layout.Px, Val: 3}}, // Input (3 lines with border)
+		{Height: layout.Unit{Kind: layout.Px, Val: 2}}, // Feedback (2 lines)
+		{Height: layout.Unit{Kind: layout.Px, Val: 1}}, // Status (1 line)
+	}
+	mainRow, inputRow, feedbackRow, statusRow := 0, 1, 2, 3
+	if m.reverse {
+		// --reverse: input renders above cursor/content instead of below.
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+		mainRow, inputRow, feedbackRow, statusRow = 3, 2, 1, 0
+	}
 	spec := layout.GridSpec{
-		Rows: []layout.Row{
-			{Height: layout.Unit{Kind: layout.Fr, Val: 1}}, // Main content area
-			{Height: layout.Unit{Kind: layout.Px, Val: 3}}, // Input (3 lines with border)
-			{Height: layout.Unit{Kind: layout.Px, Val: 2}}, // Feedback (2 lines)
-			{Height: layout.Unit{Kind: layout.Px, Val: 1}}, // Status (1 line)
-		},
+		Rows: rows,
 		Cols: []layout.Col{
-			{Width: layout.Unit{Kind: layout.Px, Val: 30}, Min: 25, Max: 50}, // Cursor pane
-			{Width: layout.Unit{Kind: layout.Fr, Val: 1}},                    // Content pane
+			{Width: layout.Unit{Kind: layout.Fr, Val: leftWeight}, Min: 20, Max: 50}, // Cursor pane
+			{Width: layout.Unit{Kind: layout.Px, Val: 1}},                            // Divider / drag handle
+			{Width: layout.Unit{Kind: layout.Fr, Val: 1000 - leftWeight}},            // Content pane
 		},
 		Padding: struct{ T, R, B, L int }{0, 0, 0, 0},
 	}
-	
+
 	m.grid = layout.NewGrid(spec)
 	m.grid.Compute(m.width, m.height)
-	
+
 	// Calculate rectangles for each component
-	m.rects.CursorPane = m.grid.CellRect(0, 0)
-	m.rects.ContentPane = m.grid.CellRect(0, 1)
-	m.rects.Input = m.grid.SpanRect(1, 0, 1, 1)    // Span both columns
-	m.rects.Feedback = m.grid.SpanRect(2, 0, 2, 1) // Span both columns
-	m.rects.Status = m.grid.SpanRect(3, 0, 3, 1)   // Span both columns
-	
+	m.rects.CursorPane = m.grid.CellRect(mainRow, 0)
+	m.rects.Divider = m.grid.CellRect(mainRow, 1)
+	m.rects.ContentPane = m.grid.CellRect(mainRow, 2)
+	m.rects.Input = m.grid.SpanRect(inputRow, 0, inputRow, 2)
+	m.rects.Feedback = m.grid.SpanRect(feedbackRow, 0, feedbackRow, 2)
+	m.rects.Status = m.grid.SpanRect(statusRow, 0, statusRow, 2)
+
 	m.updateLayout()
 }
 
@@ -207,29 +315,53 @@ func (m *TubesModel) updateLayout() {
 	// Clear and rebuild flex
 	m.flex.Clear()
 	
-	// Top row: cursor pane and content pane
+	// Top row: cursor pane, draggable divider, content pane
 	topRow := tview.NewFlex().
 		AddItem(m.cursorPane, m.rects.CursorPane.W, 0, false).
+		AddItem(m.divider, m.rects.Divider.W, 0, false).
 		AddItem(m.contentPane, m.rects.ContentPane.W, 0, false)
 	
-	// Build vertical layout
-	m.flex.SetDirection(tview.FlexRow).
-		AddItem(topRow, m.rects.CursorPane.H, 0, false).
-		AddItem(m.inputField, m.rects.Input.H, 0, m.activePane == "input").
-		AddItem(m.feedbackArea, m.rects.Feedback.H, 0, false).
-		AddItem(m.statusLine, m.rects.Status.H, 0, false)
-	
+	// Build vertical layout, honoring --reverse (input above cursor/content)
+	m.flex.SetDirection(tview.FlexRow)
+	if m.reverse {
+		m.flex.
+			AddItem(m.statusLine, m.rects.Status.H, 0, false).
+			AddItem(m.feedbackArea, m.rects.Feedback.H, 0, false).
+			AddItem(m.inputField, m.rects.Input.H, 0, m.activePane == "input").
+			AddItem(topRow, m.rects.CursorPane.H, 0, false)
+	} else {
+		m.flex.
+			AddItem(topRow, m.rects.CursorPane.H, 0, false).
+			AddItem(m.inputField, m.rects.Input.H, 0, m.activePane == "input").
+			AddItem(m.feedbackArea, m.rects.Feedback.H, 0, false).
+			AddItem(m.statusLine, m.rects.Status.H, 0, false)
+	}
+
 	m.app.SetRoot(m.flex, true)
-	
+
 	// Update content
 	m.updateCursorPane()
+	m.updateDivider()
 	m.updateContentPane()
 	m.updateStatus()
 }
 
+// updateDivider fills the gutter column with a vertical drag handle glyph
+// spanning the pane height, so the resizable boundary is visible.
+func (m *TubesModel) updateDivider() {
+	m.divider.SetText(strings.Repeat("│\n", m.rects.Divider.H))
+}
+
 // setupEventHandlers configures event handling
 func (m *TubesModel) setupEventHandlers() {
 	m.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if m.pendingBulk != nil {
+			return m.handleBulkConfirmKey(event)
+		}
+		if m.filterMode {
+			return m.handleFilterKey(event)
+		}
+
 		switch event.Key() {
 		case tcell.KeyEsc:
 			m.toggleActivePane()
@@ -237,11 +369,20 @@ func (m *TubesModel) setupEventHandlers() {
 		case tcell.KeyCtrlC:
 			m.app.Stop()
 			return nil
+		case tcell.KeyCtrlLeft:
+			m.adjustColRatio(-splitNudgeStep)
+			return nil
+		case tcell.KeyCtrlRight:
+			m.adjustColRatio(splitNudgeStep)
+			return nil
 		}
-		
+
 		// Handle navigation in cursor pane when it's active
 		if m.activePane == "cursors" {
 			switch event.Rune() {
+			case '/':
+				m.enterFilter()
+				return nil
 			case 'w', 'W':
 				m.navigateUp()
 				return nil
@@ -257,24 +398,39 @@ func (m *TubesModel) setupEventHandlers() {
 			case 'e', 'E':
 				m.toggleExpanded()
 				return nil
+			case 'v', 'V':
+				m.toggleWrap()
+				m.updateContentPane()
+				return nil
+			case ' ':
+				m.toggleSelection()
+				return nil
 			}
-			
+
 			switch event.Key() {
 			case tcell.KeyTab:
 				// Future: switch between cursor pane lanes
 				return nil
 			}
 		}
-		
+
 		return event
 	})
 }
 
-// handleMouse handles mouse clicks for pane selection
+// handleMouse handles mouse clicks for pane selection and drags on the
+// cursor/content gutter for resizing.
 func (m *TubesModel) handleMouse(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+	splitRect := m.rects.CursorPane
+	splitRect.W += m.rects.ContentPane.W
+	if m.colSplit.HandleMouseEvent(splitRect.X, splitRect.Y, splitRect.W, splitRect.H, event, action) {
+		m.computeLayout()
+		return nil, action
+	}
+
 	if action == tview.MouseLeftClick {
 		x, y := event.Position()
-		
+
 		// Determine which pane was clicked
 		if m.isInRect(x, y, m.rects.Input) {
 			m.setActivePane("input")
@@ -282,7 +438,7 @@ func (m *TubesModel) handleMouse(event *tcell.EventMouse, action tview.MouseActi
 			m.setActivePane("cursors")
 		}
 	}
-	
+
 	return event, action
 }
 
@@ -369,6 +525,172 @@ func (m *TubesModel) exitCursor() {
 	}
 }
 
+// enterFilter starts fuzzy-filtering the cursor pane.
+func (m *TubesModel) enterFilter() {
+	m.filterMode = true
+	m.feedbackArea.SetText("[blue]FILTER - type to narrow, Enter to keep, Esc to clear[-]")
+	m.updateCursorPane()
+}
+
+// exitFilter closes filter mode and restores the full tree.
+func (m *TubesModel) exitFilter() {
+	m.filterMode = false
+	m.filterQuery = ""
+	m.updateCursorPane()
+	m.updateContentPane()
+	m.setActivePane("cursors")
+}
+
+// handleFilterKey consumes a keypress while filterMode is active: Esc
+// closes the filter and restores the full tree, Enter keeps the filter
+// applied but stops editing it, Backspace trims the query, and any other
+// printable rune is appended to it.
+func (m *TubesModel) handleFilterKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyEsc:
+		m.exitFilter()
+		return nil
+	case tcell.KeyEnter:
+		m.filterMode = false
+		m.updateCursorPane()
+		return nil
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(m.filterQuery) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.filterQuery)
+			m.filterQuery = m.filterQuery[:len(m.filterQuery)-size]
+			m.updateCursorPane()
+			m.updateContentPane()
+		}
+		return nil
+	}
+
+	if r := event.Rune(); r != 0 {
+		m.filterQuery += string(r)
+		m.updateCursorPane()
+		m.updateContentPane()
+	}
+	return nil
+}
+
+// toggleSelection flips whether the cursor under navIndex is part of the
+// pending /bulk selection. Only cursor rows are selectable; multicursor
+// headers are skipped.
+func (m *TubesModel) toggleSelection() {
+	if len(m.visibleItems) == 0 || m.navIndex >= len(m.visibleItems) {
+		return
+	}
+
+	item := m.visibleItems[m.navIndex]
+	if item.Type != "cursor" {
+		return
+	}
+
+	if m.selectedIDs[item.ID] {
+		delete(m.selectedIDs, item.ID)
+	} else {
+		m.selectedIDs[item.ID] = true
+	}
+	m.updateCursorPane()
+}
+
+// bulkConfirmation is a /bulk action awaiting its "press y to confirm"
+// gate: confirmBulk renders summary and changes as a panel in the content
+// pane, and apply runs only once the user presses y.
+type bulkConfirmation struct {
+	summary string
+	changes []string
+	apply   func(model *TubesModel) string
+}
+
+// confirmBulk stages a bulk action and renders its confirmation panel.
+// The next keypress, handled by handleBulkConfirmKey, either applies it
+// (y/Y) or cancels it (anything else).
+func (m *TubesModel) confirmBulk(summary string, changes []string, apply func(model *TubesModel) string) {
+	m.pendingBulk = &bulkConfirmation{summary: summary, changes: changes, apply: apply}
+	m.renderBulkConfirmation()
+}
+
+// renderBulkConfirmation draws the pending /bulk action's summary and
+// per-cursor changes into the content pane.
+func (m *TubesModel) renderBulkConfirmation() {
+	op := m.pendingBulk
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow::b]%s[-::-]\n\n", op.summary)
+	for _, change := range op.changes {
+		fmt.Fprintf(&b, "  %s\n", change)
+	}
+	b.WriteString("\n[gray]Press y to confirm, any other key to cancel[-]")
+	m.contentPane.SetText(b.String())
+}
+
+// handleBulkConfirmKey consumes the keypress that resolves a pending
+// /bulk confirmation: y/Y applies it, anything else cancels it.
+func (m *TubesModel) handleBulkConfirmKey(event *tcell.EventKey) *tcell.EventKey {
+	op := m.pendingBulk
+	m.pendingBulk = nil
+
+	var result string
+	if r := event.Rune(); r == 'y' || r == 'Y' {
+		result = op.apply(m)
+	} else {
+		result = "Bulk action cancelled"
+	}
+
+	m.feedbackArea.SetText(fmt.Sprintf("[blue]%s[-]", result))
+	m.updateCursorPane()
+	m.updateContentPane()
+	return nil
+}
+
+// startWatching starts a codeintel.CursorWatcher over every cursor's
+// FilePath and begins consuming its redraw signals.
+func (m *TubesModel) startWatching() string {
+	if m.watchEnabled {
+		return "Already watching"
+	}
+
+	w, err := codeintel.NewCursorWatcher(m.cursors)
+	if err != nil {
+		return fmt.Sprintf("Error starting watcher: %s", err)
+	}
+	m.cursorWatcher = w
+	m.watchEnabled = true
+	go m.consumeWatchRedraws(w)
+	return fmt.Sprintf("Watching %d file(s)", len(w.Watched()))
+}
+
+// stopWatching stops the running CursorWatcher, if any.
+func (m *TubesModel) stopWatching() string {
+	if !m.watchEnabled {
+		return "Not watching"
+	}
+	m.watchEnabled = false
+	m.cursorWatcher.Close()
+	m.cursorWatcher = nil
+	return "Stopped watching"
+}
+
+// watchStatus reports whether watching is enabled and which paths are
+// currently watched.
+func (m *TubesModel) watchStatus() string {
+	if !m.watchEnabled {
+		return "Not watching"
+	}
+	return fmt.Sprintf("Watching %d file(s):\n%s", len(m.cursorWatcher.Watched()), strings.Join(m.cursorWatcher.Watched(), "\n"))
+}
+
+// consumeWatchRedraws refreshes the cursor/content panes whenever w
+// reports a cursor's Content or Orphaned state changed, until w is
+// closed.
+func (m *TubesModel) consumeWatchRedraws(w *codeintel.CursorWatcher) {
+	for range w.Redraw() {
+		m.app.QueueUpdateDraw(func() {
+			m.updateCursorPane()
+			m.updateContentPane()
+		})
+	}
+}
+
 func (m *TubesModel) getCurrentMCID() string {
 	// Find the multicursor that contains the current cursor
 	for i := m.navIndex; i >= 0; i-- {
@@ -381,7 +703,14 @@ func (m *TubesModel) getCurrentMCID() string {
 
 // Update methods
 func (m *TubesModel) refreshNavigation() {
-	m.visibleItems = m.cursors.GetVisibleItems()
+	filtered := m.cursors.FilterVisibleItems(m.filterQuery)
+	m.visibleItems = make([]codeintel.NavigationItem, len(filtered))
+	m.filterOffsets = make([][]int, len(filtered))
+	for i, f := range filtered {
+		m.visibleItems[i] = f.NavigationItem
+		m.filterOffsets[i] = f.Offsets
+	}
+
 	if m.navIndex >= len(m.visibleItems) {
 		m.navIndex = len(m.visibleItems) - 1
 	}
@@ -392,12 +721,13 @@ func (m *TubesModel) refreshNavigation() {
 
 func (m *TubesModel) updateCursorPane() {
 	m.refreshNavigation()
-	
+
 	var lines []string
 	for i, item := range m.visibleItems {
 		indent := strings.Repeat("  ", item.Level)
 		prefix := ""
-		
+
+		marker := ""
 		if item.Type == "multicursor" {
 			if item.Expanded {
 				prefix = "📂 "
@@ -406,29 +736,67 @@ func (m *TubesModel) updateCursorPane() {
 			}
 		} else {
 			prefix = "📄 "
+			if m.selectedIDs[item.ID] {
+				marker = "◉ "
+			} else {
+				marker = "○ "
+			}
 		}
-		
-		line := indent + prefix + item.Title
-		
-		// Highlight current selection with white on dark blue (no yellow)
+
+		title := item.Title
+		if len(m.filterOffsets[i]) > 0 {
+			title = highlightFuzzyMatches(title, m.filterOffsets[i])
+		}
+
+		line := indent + marker + prefix + title
+
+		// Highlight current nav position with white on dark blue, or a
+		// selected-but-not-current row with black on yellow, so bulk
+		// selection stays visible as the cursor moves on.
 		if i == m.navIndex {
 			line = fmt.Sprintf("[white:darkblue]%s[white:black]", line)
+		} else if item.Type == "cursor" && m.selectedIDs[item.ID] {
+			line = fmt.Sprintf("[black:yellow]%s[white:black]", line)
 		}
-		
+
 		lines = append(lines, line)
 	}
-	
+
+	if m.filterMode || m.filterQuery != "" {
+		lines = append([]string{fmt.Sprintf("[yellow]/%s[-]", m.filterQuery)}, lines...)
+	}
+
 	m.cursorPane.SetText(strings.Join(lines, "\n"))
 }
 
+// highlightFuzzyMatches wraps each byte offset in s that FuzzyMatch
+// reported as a hit in a tview yellow color tag, so users can see why a
+// filtered result matched.
+func highlightFuzzyMatches(s string, offsets []int) string {
+	hit := make(map[int]bool, len(offsets))
+	for _, o := range offsets {
+		hit[o] = true
+	}
+
+	var b strings.Builder
+	for i, r := range s {
+		if hit[i] {
+			b.WriteString("[yellow]")
+			b.WriteRune(r)
+			b.WriteString("[-]")
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
 func (m *TubesModel) updateContentPane() {
 	if m.inCursorMode {
-		// Show file content
+		// Show a syntax-highlighted, line-numbered code preview
 		cursor := m.cursors.GetCurrentCursor()
 		if cursor != nil {
-			content := fmt.Sprintf("# %s\n\n```\n%s\n```", 
-				cursor.FilePath, cursor.Content)
-			m.contentPane.SetText(content)
+			m.contentPane.SetText(m.renderCursorPreview(cursor))
 		}
 	} else {
 		// Show metadata
@@ -459,6 +827,30 @@ func (m *TubesModel) updateContentPane() {
 	}
 }
 
+// renderCursorPreview syntax-highlights cursor's Content (chroma, picked
+// by cursor.FilePath's extension) and prefixes each line with a gutter
+// showing its real StartLine..EndLine number. Since Content is already
+// exactly that line range, the whole gutter carries the "selected range"
+// marker.
+func (m *TubesModel) renderCursorPreview(cursor *codeintel.Cursor) string {
+	highlighted := theme.HighlightSource(cursor.FilePath, cursor.Content)
+	lines := strings.Split(highlighted, "\n")
+	gutterWidth := len(strconv.Itoa(cursor.EndLine))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[::b]%s[::-] (%d-%d)\n\n", cursor.FilePath, cursor.StartLine, cursor.EndLine)
+	for i, line := range lines {
+		fmt.Fprintf(&b, "[#6272a4]%*d ┃[-] %s\n", gutterWidth, cursor.StartLine+i, line)
+	}
+	return b.String()
+}
+
+// toggleWrap flips contentPane's word-wrap setting (:wrap/:nowrap).
+func (m *TubesModel) toggleWrap() {
+	m.wordWrap = !m.wordWrap
+	m.contentPane.SetWordWrap(m.wordWrap)
+}
+
 func (m *TubesModel) updateStatus() {
 	mode := "INPUT"
 	if m.activePane == "cursors" {
@@ -521,13 +913,22 @@ func (m *TubesModel) setupCommands() {
 /mc new <title>          - Create new multicursor
 /mc list                 - List all multicursors  
 /cursor add <file:line>  - Add cursor to current multicursor
+/cursor rm <id>          - Remove cursor from current multicursor
 /cursor list             - List cursors in current multicursor
+/filter <query>          - Fuzzy-filter the cursor pane (also: / in cursor mode)
+/watch on|off|status     - Auto-refresh cursor content when its file changes on disk
+/view wrap|nowrap        - Toggle word wrap in the content preview (also: v in cursor mode)
+/bulk prompt <text>      - Set Prompt on selected cursors (space toggles selection)
+/bulk tag +foo -bar      - Add/remove tags on selected cursors
+/bulk export <dir>       - Write each selected cursor's content to <dir>/<id>.txt
+/bulk delete             - Remove selected cursors
 /ui tokens               - Show UI design tokens
 /ui preview              - Show theme preview
 /ui palette              - Show color palette
 /server start            - Start HTTP API server
 /server stop             - Stop HTTP API server
 /server status           - Show server status
+/pack [max_tokens]       - Pack current multicursor into a token-budgeted LLM context
 /clear                   - Clear feedback area
 /quit                    - Exit application`, nil
 	}
@@ -565,9 +966,9 @@ func (m *TubesModel) setupCommands() {
 	
 	m.commands["/cursor"] = func(model *TubesModel, args []string) (string, error) {
 		if len(args) == 0 {
-			return "Usage: /cursor add <file:line> | /cursor list", nil
+			return "Usage: /cursor add <file:line> | /cursor rm <id> | /cursor list", nil
 		}
-		
+
 		switch args[0] {
 		case "add":
 			if len(args) < 2 {
@@ -575,16 +976,42 @@ func (m *TubesModel) setupCommands() {
 			}
 			// Simple parsing for demo - in real implementation would parse file:line format
 			cursor := model.cursors.NewCursor("example.go", 1, 10, "sample content")
-			
+
 			// Add to first available multicursor for demo
 			for mcID := range model.cursors.MultiCursors {
 				model.cursors.AddCursorToMC(mcID, cursor)
 				model.refreshNavigation()
 				model.updateCursorPane()
+				if model.watchEnabled {
+					model.cursorWatcher.Watch(cursor.FilePath)
+				}
 				return fmt.Sprintf("Added cursor %s to multicursor %s", cursor.ID, mcID), nil
 			}
 			return "No multicursor available - create one first with /mc new", nil
-			
+
+		case "rm":
+			if len(args) < 2 {
+				return "Usage: /cursor rm <id>", nil
+			}
+			id := args[1]
+			for mcID, mc := range model.cursors.MultiCursors {
+				for _, c := range mc.Cursors {
+					if c.ID != id {
+						continue
+					}
+					if err := model.cursors.RemoveCursor(mcID, id); err != nil {
+						return fmt.Sprintf("Error: %s", err), err
+					}
+					if model.watchEnabled {
+						model.cursorWatcher.Unwatch(c.FilePath)
+					}
+					model.refreshNavigation()
+					model.updateCursorPane()
+					return fmt.Sprintf("Removed cursor %s", id), nil
+				}
+			}
+			return fmt.Sprintf("Cursor %s not found", id), nil
+
 		case "list":
 			mc := model.cursors.GetCurrentMultiCursor()
 			if mc == nil {
@@ -592,7 +1019,7 @@ func (m *TubesModel) setupCommands() {
 			}
 			var items []string
 			for _, cursor := range mc.Cursors {
-				items = append(items, fmt.Sprintf("%s: %s (%d-%d)", 
+				items = append(items, fmt.Sprintf("%s: %s (%d-%d)",
 					cursor.ID, cursor.FilePath, cursor.StartLine, cursor.EndLine))
 			}
 			if len(items) == 0 {
@@ -600,10 +1027,156 @@ func (m *TubesModel) setupCommands() {
 			}
 			return strings.Join(items, "\n"), nil
 		default:
-			return "Usage: /cursor add <file:line> | /cursor list", nil
+			return "Usage: /cursor add <file:line> | /cursor rm <id> | /cursor list", nil
 		}
 	}
-	
+
+	m.commands["/watch"] = func(model *TubesModel, args []string) (string, error) {
+		if len(args) == 0 {
+			return "Usage: /watch on|off|status", nil
+		}
+		switch args[0] {
+		case "on":
+			return model.startWatching(), nil
+		case "off":
+			return model.stopWatching(), nil
+		case "status":
+			return model.watchStatus(), nil
+		default:
+			return "Usage: /watch on|off|status", nil
+		}
+	}
+
+	m.commands["/filter"] = func(model *TubesModel, args []string) (string, error) {
+		model.filterQuery = strings.Join(args, " ")
+		model.filterMode = false
+		model.updateCursorPane()
+		model.updateContentPane()
+		if model.filterQuery == "" {
+			return "Filter cleared", nil
+		}
+		return fmt.Sprintf("Filtering on %q (%d matches)", model.filterQuery, len(model.visibleItems)), nil
+	}
+
+	m.commands["/view"] = func(model *TubesModel, args []string) (string, error) {
+		if len(args) == 0 {
+			return "Usage: /view wrap|nowrap", nil
+		}
+		switch args[0] {
+		case "wrap":
+			if !model.wordWrap {
+				model.toggleWrap()
+			}
+		case "nowrap":
+			if model.wordWrap {
+				model.toggleWrap()
+			}
+		default:
+			return "Usage: /view wrap|nowrap", nil
+		}
+		model.updateContentPane()
+		return fmt.Sprintf("Word wrap: %v", model.wordWrap), nil
+	}
+
+	m.commands["/bulk"] = func(model *TubesModel, args []string) (string, error) {
+		usage := "Usage: /bulk prompt <text> | /bulk tag +foo -bar | /bulk export <dir> | /bulk delete"
+		if len(args) == 0 {
+			return usage, nil
+		}
+
+		selected := model.cursors.SelectedCursors(model.selectedIDs)
+		if len(selected) == 0 {
+			return "No cursors selected (space toggles selection in cursor mode)", nil
+		}
+
+		switch args[0] {
+		case "prompt":
+			if len(args) < 2 {
+				return "Usage: /bulk prompt <text>", nil
+			}
+			prompt := strings.Join(args[1:], " ")
+			changes := make([]string, len(selected))
+			for i, c := range selected {
+				changes[i] = fmt.Sprintf("%s (%s): prompt -> %q", c.ID, filepath.Base(c.FilePath), prompt)
+			}
+			model.confirmBulk(fmt.Sprintf("Set prompt on %d cursor(s)", len(selected)), changes,
+				func(model *TubesModel) string {
+					model.cursors.BulkSetPrompt(selected, prompt)
+					return fmt.Sprintf("Set prompt on %d cursor(s)", len(selected))
+				})
+			return "Confirm bulk prompt change (y/n)", nil
+
+		case "tag":
+			if len(args) < 2 {
+				return "Usage: /bulk tag +foo -bar", nil
+			}
+			var adds, removes []string
+			for _, a := range args[1:] {
+				switch {
+				case strings.HasPrefix(a, "+"):
+					adds = append(adds, a[1:])
+				case strings.HasPrefix(a, "-"):
+					removes = append(removes, a[1:])
+				}
+			}
+			changes := make([]string, len(selected))
+			for i, c := range selected {
+				changes[i] = fmt.Sprintf("%s (%s): +%v -%v", c.ID, filepath.Base(c.FilePath), adds, removes)
+			}
+			model.confirmBulk(fmt.Sprintf("Tag %d cursor(s)", len(selected)), changes,
+				func(model *TubesModel) string {
+					model.cursors.BulkTag(selected, adds, removes)
+					return fmt.Sprintf("Tagged %d cursor(s)", len(selected))
+				})
+			return "Confirm bulk tag change (y/n)", nil
+
+		case "export":
+			if len(args) < 2 {
+				return "Usage: /bulk export <dir>", nil
+			}
+			dir := args[1]
+			changes := make([]string, len(selected))
+			for i, c := range selected {
+				changes[i] = fmt.Sprintf("%s (%s) -> %s", c.ID, filepath.Base(c.FilePath), filepath.Join(dir, c.ID+".txt"))
+			}
+			model.confirmBulk(fmt.Sprintf("Export %d cursor(s) to %s", len(selected), dir), changes,
+				func(model *TubesModel) string {
+					if err := os.MkdirAll(dir, 0o755); err != nil {
+						return fmt.Sprintf("Error: %s", err)
+					}
+					written := 0
+					for _, c := range selected {
+						path := filepath.Join(dir, c.ID+".txt")
+						if err := os.WriteFile(path, []byte(c.Content), 0o644); err != nil {
+							continue
+						}
+						written++
+					}
+					return fmt.Sprintf("Exported %d/%d cursor(s) to %s", written, len(selected), dir)
+				})
+			return "Confirm bulk export (y/n)", nil
+
+		case "delete":
+			changes := make([]string, len(selected))
+			for i, c := range selected {
+				changes[i] = fmt.Sprintf("%s (%s:%d-%d)", c.ID, filepath.Base(c.FilePath), c.StartLine, c.EndLine)
+			}
+			model.confirmBulk(fmt.Sprintf("Delete %d cursor(s)", len(selected)), changes,
+				func(model *TubesModel) string {
+					model.cursors.BulkDelete(selected)
+					for _, c := range selected {
+						delete(model.selectedIDs, c.ID)
+					}
+					model.refreshNavigation()
+					return fmt.Sprintf("Deleted %d cursor(s)", len(selected))
+				})
+			return "Confirm bulk delete (y/n)", nil
+
+		default:
+			return usage, nil
+		}
+	}
+
 	m.commands["/clear"] = func(model *TubesModel, args []string) (string, error) {
 		model.feedbackArea.SetText("")
 		return "Feedback cleared", nil
@@ -620,9 +1193,11 @@ func (m *TubesModel) setupCommands() {
 		case "preview":
 			return theme.Preview(model.styles), nil
 		case "palette":
-			return theme.GetColorPalette(), nil
+			return theme.GetColorPalette(model.styles), nil
+		case "export":
+			return uiExport(model.styles, args[1:])
 		default:
-			return "Usage: /ui [tokens|preview|palette]", nil
+			return "Usage: /ui [tokens|preview|palette|export]", nil
 		}
 	}
 	
@@ -654,14 +1229,64 @@ func (m *TubesModel) setupCommands() {
 		}
 	}
 	
+	m.commands["/pack"] = func(model *TubesModel, args []string) (string, error) {
+		maxTokens := defaultPackMaxTokens
+		if len(args) > 0 {
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				return "Usage: /pack [max_tokens]", nil
+			}
+			maxTokens = n
+		}
+
+		packer := codeintel.NewPacker(maxTokens)
+		context, dropped := packer.Pack(model.cursors)
+		model.contentPane.SetText(context)
+		if dropped > 0 {
+			return fmt.Sprintf("Packed context into %d token budget (%d cursor(s) dropped to fit)", maxTokens, dropped), nil
+		}
+		return fmt.Sprintf("Packed context into %d token budget", maxTokens), nil
+	}
+
 	m.commands["/quit"] = func(model *TubesModel, args []string) (string, error) {
-		// Stop server if running
+		// Stop server and file watcher if running
 		model.apiServer.Stop()
+		if model.watchEnabled {
+			model.stopWatching()
+		}
 		model.app.Stop()
 		return "Goodbye!", nil
 	}
 }
 
+// uiExport implements "/ui export <format> [style]", driving the
+// theme package's shell-consumable exporters so bash pipelines can reuse
+// the same palette the TUI renders with.
+func uiExport(styles *theme.Styles, args []string) (string, error) {
+	if len(args) == 0 {
+		return "Usage: /ui export gum <style>|css|ansi", nil
+	}
+
+	switch args[0] {
+	case "gum":
+		if len(args) < 2 {
+			return "Usage: /ui export gum <style>", nil
+		}
+		return theme.ExportGumFlags(args[1], styles), nil
+	case "css":
+		return theme.ExportCSSVariables(styles), nil
+	case "ansi":
+		var lines []string
+		for name, seq := range theme.ExportANSISequences(styles) {
+			lines = append(lines, fmt.Sprintf("%s=%q", name, seq))
+		}
+		sort.Strings(lines)
+		return strings.Join(lines, "\n"), nil
+	default:
+		return "Usage: /ui export gum <style>|css|ansi", nil
+	}
+}
+
 // loadSampleData creates some sample multicursors and cursors for testing
 func (m *TubesModel) loadSampleData() {
 	// Create sample multicursor
@@ -736,12 +1361,18 @@ func (u *User) Validate() error {
 // Run starts the application
 func (m *TubesModel) Run() error {
 	m.width, m.height = 80, 24 // Default size
+
+	if m.heightSpec != "" {
+		m.enableInline()
+		go m.watchResize()
+	}
+
 	m.computeLayout()
 	m.setActivePane("input")
-	
+
 	// Load legacy commands for compatibility
 	m.loadCommands()
-	
+
 	return m.app.Run()
 }
 
@@ -757,13 +1388,7 @@ func (m *TubesModel) renderRight(path string) {
 }
 
 func (m *TubesModel) adjustColRatio(delta float64) {
-	m.col1Ratio += delta
-	if m.col1Ratio < 0.1 {
-		m.col1Ratio = 0.1
-	}
-	if m.col1Ratio > 0.9 {
-		m.col1Ratio = 0.9
-	}
+	m.colSplit.Nudge(delta)
 	m.computeLayout()
 }
 
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ErrorCode is a stable, machine-readable identifier for an API
+// failure, distinct from its HTTP status so a client can branch on the
+// reason without string-matching Detail.
+type ErrorCode string
+
+const (
+	ErrInvalidJSON      ErrorCode = "invalid_json"
+	ErrUnknownCommand   ErrorCode = "unknown_command"
+	ErrMCNotFound       ErrorCode = "mc_not_found"
+	ErrCursorNotFound   ErrorCode = "cursor_not_found"
+	ErrRangeOutOfBounds ErrorCode = "range_out_of_bounds"
+	ErrSnapshotNotFound ErrorCode = "snapshot_not_found"
+	ErrUnauthorized     ErrorCode = "unauthorized"
+	ErrRateLimited      ErrorCode = "rate_limited"
+	ErrTimeout          ErrorCode = "timeout"
+)
+
+// errorTitles gives each ErrorCode the stable "title" an RFC 7807 body
+// carries; Detail is what varies per request.
+var errorTitles = map[ErrorCode]string{
+	ErrInvalidJSON:      "Invalid JSON",
+	ErrUnknownCommand:   "Unknown Command",
+	ErrMCNotFound:       "Multicursor Not Found",
+	ErrCursorNotFound:   "Cursor Not Found",
+	ErrRangeOutOfBounds: "Range Out Of Bounds",
+	ErrSnapshotNotFound: "Snapshot Not Found",
+	ErrUnauthorized:     "Unauthorized",
+	ErrRateLimited:      "Rate Limited",
+	ErrTimeout:          "Request Timeout",
+}
+
+// Problem is an RFC 7807 (application/problem+json) error body.
+type Problem struct {
+	Type     string    `json:"type"`
+	Title    string    `json:"title"`
+	Status   int       `json:"status"`
+	Detail   string    `json:"detail,omitempty"`
+	Instance string    `json:"instance"`
+	Code     ErrorCode `json:"code,omitempty"`
+}
+
+// respondProblem writes an RFC 7807 problem+json body for a request
+// that failed with status, tagged with the stable code a client can
+// branch on; detail is the request-specific explanation.
+func (s *Server) respondProblem(w http.ResponseWriter, r *http.Request, status int, code ErrorCode, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Problem{
+		Type:     "/errors/" + string(code),
+		Title:    errorTitles[code],
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Code:     code,
+	})
+}
+
+// respondIfCanceled writes a problem response for a request context
+// that's done and reports whether it did, so the caller can skip its
+// own error handling. A canceled context means the client already
+// disconnected, so nothing is written for that case - there's no one
+// left to read it.
+func (s *Server) respondIfCanceled(w http.ResponseWriter, r *http.Request, err error) bool {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return true
+	case errors.Is(err, context.DeadlineExceeded):
+		s.respondProblem(w, r, http.StatusRequestTimeout, ErrTimeout, "request canceled: deadline exceeded")
+		return true
+	default:
+		return false
+	}
+}
+
+// respondError is respondProblem for the error paths that don't carry
+// one of the ErrorCode values above (a missing workspace, a disk
+// failure) - same problem+json envelope, just without a Code.
+func (s *Server) respondError(w http.ResponseWriter, r *http.Request, message string, status int) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   message,
+		Instance: r.URL.Path,
+	})
+}
@@ -1,24 +1,116 @@
 package api
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
-	
+
+	"tubes/internal/auth"
 	"tubes/internal/codeintel"
 	"tubes/internal/theme"
 )
 
+// maxSnapshotHistory bounds how many snapshots SnapshotStore keeps
+// before evicting the oldest, including auto-snapshots.
+const maxSnapshotHistory = 50
+
+// autoSnapshotEvery triggers an automatic snapshot after this many
+// mutating Events, so a crash or bad restore never loses more than
+// this many significant changes.
+const autoSnapshotEvery = 20
+
+// defaultRatePerSecond and defaultBurst size the per-token rate
+// limiter when Config doesn't override them.
+const (
+	defaultRatePerSecond = 10
+	defaultBurst         = 20
+)
+
+// defaultReadHeaderTimeout, defaultReadTimeout, defaultWriteTimeout,
+// and defaultIdleTimeout bound how long httpServer waits on a
+// connection when Config doesn't override them, so a slow or stalled
+// client can't pin a handler goroutine indefinitely.
+const (
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultReadTimeout       = 30 * time.Second
+	defaultWriteTimeout      = 30 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+)
+
+// Config configures a new Server.
+type Config struct {
+	Port string
+
+	// SnapshotDir is where snapshot blobs and their manifest live.
+	SnapshotDir string
+	// WorkspaceRoot is the directory GET /api/files, GET
+	// /api/files/content, and content-less POST /api/cursors resolve
+	// paths against. Empty disables all three.
+	WorkspaceRoot string
+	// TokenFile is where issued auth tokens are persisted, hashed at
+	// rest.
+	TokenFile string
+
+	// AuthDisabled preserves the old wide-open behavior for local dev
+	// (the --auth-disabled flag).
+	AuthDisabled bool
+	// CORSOrigins is the allowlist Access-Control-Allow-Origin is drawn
+	// from; a request from an Origin not in this list gets no CORS
+	// header and fails in the browser. Empty means no cross-origin
+	// requests are allowed.
+	CORSOrigins []string
+
+	// RatePerSecond and Burst size the per-token rate limiter; zero
+	// values fall back to defaultRatePerSecond/defaultBurst.
+	RatePerSecond float64
+	Burst         float64
+
+	// ReadHeaderTimeout, ReadTimeout, WriteTimeout, and IdleTimeout bound
+	// httpServer's connection handling; zero values fall back to the
+	// default*Timeout consts above rather than http.Server's unbounded
+	// defaults.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+}
+
 // Server provides HTTP API endpoints
 type Server struct {
-	httpServer *http.Server
-	cursors    *codeintel.CursorDirectory
-	styles     *theme.Styles
-	port       string
+	httpServer   *http.Server
+	cursors      *codeintel.CursorDirectory
+	styles       *theme.Styles
+	port         string
+	snapshots    *codeintel.SnapshotStore
+	workspace    *codeintel.Workspace
+	authStore    *auth.Store
+	limiter      *auth.RateLimiter
+	authDisabled bool
+	corsOrigins  map[string]bool
+
+	readHeaderTimeout time.Duration
+	readTimeout       time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+
+	// subscribers tracks every hijacked streaming connection's mcID
+	// filter ("" means /api/events, unfiltered), so a future admin
+	// endpoint or log line can see who's currently attached.
+	subMu       sync.Mutex
+	subscribers map[net.Conn]string
+
+	// mutations counts Events seen since the last auto-snapshot.
+	mutMu     sync.Mutex
+	mutations int
 }
 
 // CommandRequest represents an incoming command request
@@ -33,14 +125,95 @@ type CommandResponse struct {
 	Stdout string      `json:"stdout"`
 	Stderr string      `json:"stderr"`
 	Data   interface{} `json:"data"`
+	// Code is set alongside Stderr when the failure matches one of the
+	// stable ErrorCode values, e.g. ErrUnknownCommand.
+	Code ErrorCode `json:"code,omitempty"`
 }
 
-// NewServer creates a new API server
-func NewServer(port string, cursors *codeintel.CursorDirectory) *Server {
-	return &Server{
-		cursors: cursors,
-		styles:  theme.NewDefaultStyles(),
-		port:    port,
+// NewServer creates a new API server from cfg.
+func NewServer(cursors *codeintel.CursorDirectory, cfg Config) *Server {
+	rate, burst := cfg.RatePerSecond, cfg.Burst
+	if rate == 0 {
+		rate = defaultRatePerSecond
+	}
+	if burst == 0 {
+		burst = defaultBurst
+	}
+
+	readHeaderTimeout, readTimeout := cfg.ReadHeaderTimeout, cfg.ReadTimeout
+	writeTimeout, idleTimeout := cfg.WriteTimeout, cfg.IdleTimeout
+	if readHeaderTimeout == 0 {
+		readHeaderTimeout = defaultReadHeaderTimeout
+	}
+	if readTimeout == 0 {
+		readTimeout = defaultReadTimeout
+	}
+	if writeTimeout == 0 {
+		writeTimeout = defaultWriteTimeout
+	}
+	if idleTimeout == 0 {
+		idleTimeout = defaultIdleTimeout
+	}
+
+	origins := make(map[string]bool, len(cfg.CORSOrigins))
+	for _, o := range cfg.CORSOrigins {
+		origins[o] = true
+	}
+
+	var workspace *codeintel.Workspace
+	if cfg.WorkspaceRoot != "" {
+		ws, err := codeintel.NewWorkspace(cfg.WorkspaceRoot)
+		if err != nil {
+			log.Printf("api: workspace disabled, failed to resolve %s: %v", cfg.WorkspaceRoot, err)
+		} else {
+			workspace = ws
+		}
+	}
+
+	s := &Server{
+		cursors:           cursors,
+		styles:            theme.NewDefaultStyles(),
+		port:              cfg.Port,
+		snapshots:         codeintel.NewSnapshotStore(cfg.SnapshotDir, maxSnapshotHistory),
+		workspace:         workspace,
+		authStore:         auth.NewStore(cfg.TokenFile),
+		limiter:           auth.NewRateLimiter(rate, burst),
+		authDisabled:      cfg.AuthDisabled,
+		corsOrigins:       origins,
+		subscribers:       make(map[net.Conn]string),
+		readHeaderTimeout: readHeaderTimeout,
+		readTimeout:       readTimeout,
+		writeTimeout:      writeTimeout,
+		idleTimeout:       idleTimeout,
+	}
+	go s.watchForAutoSnapshot()
+	return s
+}
+
+// watchForAutoSnapshot subscribes to cursors.Events for the server's
+// whole lifetime, creating an "auto-<seq>" snapshot every
+// autoSnapshotEvery events so a crash or bad restore never loses more
+// than that many significant mutations.
+func (s *Server) watchForAutoSnapshot() {
+	events, _, _ := s.cursors.Events.Subscribe(0)
+	for ev := range events {
+		if ev.Event == codeintel.EventSnapshotRestored {
+			continue
+		}
+		s.mutMu.Lock()
+		s.mutations++
+		due := s.mutations >= autoSnapshotEvery
+		if due {
+			s.mutations = 0
+		}
+		s.mutMu.Unlock()
+
+		if due {
+			name := fmt.Sprintf("auto-%d", ev.Seq)
+			if _, err := s.snapshots.Create(context.Background(), name, s.cursors, true); err != nil {
+				log.Printf("auto-snapshot %s failed: %v", name, err)
+			}
+		}
 	}
 }
 
@@ -60,6 +233,14 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/api/theme", s.handleTheme)
 	mux.HandleFunc("/api/ui", s.handleUI)
 	mux.HandleFunc("/api/status", s.handleStatus)
+	mux.HandleFunc("/api/events", s.handleEvents)
+	mux.HandleFunc("/api/cursors/", s.handleCursorSub)
+	mux.HandleFunc("/api/snapshots", s.handleSnapshots)
+	mux.HandleFunc("/api/snapshots/", s.handleSnapshot)
+	mux.HandleFunc("/api/auth/tokens", s.handleAuthTokens)
+	mux.HandleFunc("/api/auth/tokens/", s.handleAuthToken)
+	mux.HandleFunc("/api/files", s.handleFiles)
+	mux.HandleFunc("/api/files/content", s.handleFileContent)
 	
 	// Health check
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -70,8 +251,12 @@ func (s *Server) Start() error {
 	mux.HandleFunc("/", s.handleRoot)
 	
 	s.httpServer = &http.Server{
-		Addr:    ":" + s.port,
-		Handler: s.corsMiddleware(s.logMiddleware(mux)),
+		Addr:              ":" + s.port,
+		Handler:           s.corsMiddleware(s.logMiddleware(s.authMiddleware(mux))),
+		ReadHeaderTimeout: s.readHeaderTimeout,
+		ReadTimeout:       s.readTimeout,
+		WriteTimeout:      s.writeTimeout,
+		IdleTimeout:       s.idleTimeout,
 	}
 	
 	log.Printf("API server starting on port %s", s.port)
@@ -99,24 +284,30 @@ func (s *Server) handleCommand(w http.ResponseWriter, r *http.Request) {
 	
 	var req CommandRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		s.respondError(w, "Invalid JSON", http.StatusBadRequest)
+		s.respondProblem(w, r, http.StatusBadRequest, ErrInvalidJSON, "Invalid JSON")
 		return
 	}
-	
+
 	// Execute command
-	result, err := s.executeCommand(req.Command, req.Args)
-	
+	result, err := s.executeCommand(r.Context(), req.Command, req.Args)
+	if err != nil && s.respondIfCanceled(w, r, err) {
+		return
+	}
+
 	response := CommandResponse{
 		Ok:     err == nil,
 		Stdout: result,
 		Stderr: "",
 		Data:   nil,
 	}
-	
+
 	if err != nil {
 		response.Stderr = err.Error()
+		if errors.Is(err, errUnknownCommand) {
+			response.Code = ErrUnknownCommand
+		}
 	}
-	
+
 	s.respondJSON(w, response)
 }
 
@@ -150,11 +341,33 @@ func (s *Server) handleCursors(w http.ResponseWriter, r *http.Request) {
 		}
 		
 		if err := json.NewDecoder(r.Body).Decode(&cursor); err != nil {
-			s.respondError(w, "Invalid JSON", http.StatusBadRequest)
+			s.respondProblem(w, r, http.StatusBadRequest, ErrInvalidJSON, "Invalid JSON")
 			return
 		}
-		
-		newCursor := s.cursors.NewCursor(cursor.FilePath, cursor.StartLine, cursor.EndLine, cursor.Content)
+
+		content, etag := cursor.Content, ""
+		if content == "" {
+			if s.workspace == nil {
+				s.respondError(w, r, "content is required (no workspace configured)", http.StatusBadRequest)
+				return
+			}
+			resolved, tag, err := s.workspace.ReadRange(r.Context(), cursor.FilePath, cursor.StartLine, cursor.EndLine)
+			if err != nil {
+				if s.respondIfCanceled(w, r, err) {
+					return
+				}
+				if errors.Is(err, codeintel.ErrRangeOutOfBounds) {
+					s.respondProblem(w, r, http.StatusBadRequest, ErrRangeOutOfBounds, err.Error())
+					return
+				}
+				s.respondError(w, r, err.Error(), http.StatusBadRequest)
+				return
+			}
+			content, etag = resolved, tag
+		}
+
+		newCursor := s.cursors.NewCursor(cursor.FilePath, cursor.StartLine, cursor.EndLine, content)
+		newCursor.ETag = etag
 		
 		// Add to first available multicursor
 		for mcID := range s.cursors.MultiCursors {
@@ -166,7 +379,7 @@ func (s *Server) handleCursors(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		
-		s.respondError(w, "No multicursor available", http.StatusBadRequest)
+		s.respondProblem(w, r, http.StatusBadRequest, ErrMCNotFound, "No multicursor available")
 		
 	default:
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -203,10 +416,10 @@ func (s *Server) handleMultiCursors(w http.ResponseWriter, r *http.Request) {
 		}
 		
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			s.respondError(w, "Invalid JSON", http.StatusBadRequest)
+			s.respondProblem(w, r, http.StatusBadRequest, ErrInvalidJSON, "Invalid JSON")
 			return
 		}
-		
+
 		mc := s.cursors.NewMultiCursor(req.Title, req.Description)
 		s.respondJSON(w, map[string]interface{}{
 			"multicursor": mc,
@@ -258,16 +471,270 @@ func (s *Server) handleUI(w http.ResponseWriter, r *http.Request) {
 
 // handleStatus provides system status
 func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.subMu.Lock()
+	streamSubscribers := len(s.subscribers)
+	s.subMu.Unlock()
+
 	s.respondJSON(w, map[string]interface{}{
-		"status":           "running",
-		"port":             s.port,
-		"multicursors":     len(s.cursors.MultiCursors),
-		"current_mc":       s.cursors.CurrentMC,
-		"total_cursors":    s.getTotalCursorCount(),
-		"server_time":      time.Now().UTC(),
+		"status":             "running",
+		"port":               s.port,
+		"multicursors":       len(s.cursors.MultiCursors),
+		"current_mc":         s.cursors.CurrentMC,
+		"total_cursors":      s.getTotalCursorCount(),
+		"stream_subscribers": streamSubscribers,
+		"server_time":        time.Now().UTC(),
 	})
 }
 
+// handleEvents streams every CursorDirectory mutation as it happens.
+// Clients reconnect with ?since=<seq> to replay whatever they missed.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.streamEvents(w, r, "")
+}
+
+// handleCursorSub dispatches /api/cursors/{id}/stream (live events
+// scoped to the multicursor id) and /api/cursors/{id}/refresh
+// (re-read a cursor's range and check staleness).
+func (s *Server) handleCursorSub(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/cursors/")
+	parts := strings.Split(rest, "/")
+	if len(parts) != 2 || parts[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch parts[1] {
+	case "stream":
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.streamEvents(w, r, parts[0])
+	case "refresh":
+		s.handleCursorRefresh(w, r, parts[0])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleCursorRefresh re-reads cursorID's file range through the
+// workspace and reports whether it's now stale.
+func (s *Server) handleCursorRefresh(w http.ResponseWriter, r *http.Request, cursorID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.workspace == nil {
+		s.respondError(w, r, "workspace not configured", http.StatusInternalServerError)
+		return
+	}
+
+	mcID, ok := s.cursors.FindCursorMC(cursorID)
+	if !ok {
+		s.respondProblem(w, r, http.StatusNotFound, ErrCursorNotFound, fmt.Sprintf("cursor %s not found", cursorID))
+		return
+	}
+	if err := s.cursors.RefreshCursor(r.Context(), mcID, cursorID, s.workspace); err != nil {
+		if s.respondIfCanceled(w, r, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, codeintel.ErrMCNotFound):
+			s.respondProblem(w, r, http.StatusNotFound, ErrMCNotFound, err.Error())
+		case errors.Is(err, codeintel.ErrCursorNotFound):
+			s.respondProblem(w, r, http.StatusNotFound, ErrCursorNotFound, err.Error())
+		case errors.Is(err, codeintel.ErrRangeOutOfBounds):
+			s.respondProblem(w, r, http.StatusBadRequest, ErrRangeOutOfBounds, err.Error())
+		default:
+			s.respondError(w, r, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	s.respondJSON(w, s.cursors.FindCursor(mcID, cursorID))
+}
+
+// handleFiles lists a workspace directory (?path=, ?sort=name|size|time,
+// ?order=asc|desc).
+func (s *Server) handleFiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.workspace == nil {
+		s.respondError(w, r, "workspace not configured", http.StatusInternalServerError)
+		return
+	}
+
+	sortBy := codeintel.SortBy(r.URL.Query().Get("sort"))
+	if sortBy == "" {
+		sortBy = codeintel.SortByName
+	}
+
+	entries, err := s.workspace.List(r.Context(), r.URL.Query().Get("path"), sortBy, r.URL.Query().Get("order") == "desc")
+	if err != nil {
+		if s.respondIfCanceled(w, r, err) {
+			return
+		}
+		s.respondError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.respondJSON(w, map[string]interface{}{"entries": entries})
+}
+
+// handleFileContent returns a workspace file's ?start=&end= line range
+// (1-indexed, inclusive) with a stable ETag, for clients building a
+// cursor from raw file content without a preloaded string.
+func (s *Server) handleFileContent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.workspace == nil {
+		s.respondError(w, r, "workspace not configured", http.StatusInternalServerError)
+		return
+	}
+
+	q := r.URL.Query()
+	start, _ := strconv.Atoi(q.Get("start"))
+	end, _ := strconv.Atoi(q.Get("end"))
+
+	content, etag, err := s.workspace.ReadRange(r.Context(), q.Get("path"), start, end)
+	if err != nil {
+		if s.respondIfCanceled(w, r, err) {
+			return
+		}
+		if errors.Is(err, codeintel.ErrRangeOutOfBounds) {
+			s.respondProblem(w, r, http.StatusBadRequest, ErrRangeOutOfBounds, err.Error())
+			return
+		}
+		s.respondError(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	s.respondJSON(w, map[string]interface{}{"content": content, "etag": etag})
+}
+
+// streamEvents hijacks the connection and pushes codeintel.Events to it as
+// newline-delimited JSON, the same raw-stream-upgrade approach Docker uses
+// for its attach/logs endpoints. mcID "" means unfiltered (/api/events);
+// otherwise only events for that multicursor are written.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request, mcID string) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	var since uint64
+	if v := r.URL.Query().Get("since"); v != "" {
+		parsed, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid since", http.StatusBadRequest)
+			return
+		}
+		since = parsed
+	}
+
+	conn, buf, err := hijacker.Hijack()
+	if err != nil {
+		log.Printf("stream hijack failed: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// Hijacking takes the connection out from under httpServer, but the
+	// WriteTimeout deadline it set before calling this handler is still
+	// on the raw conn - clear it so a long-lived stream isn't cut off
+	// partway through.
+	conn.SetDeadline(time.Time{})
+
+	buf.WriteString("HTTP/1.1 200 OK\r\n")
+	buf.WriteString("Content-Type: application/x-ndjson\r\n")
+	buf.WriteString("Connection: close\r\n")
+	buf.WriteString("\r\n")
+	if err := buf.Flush(); err != nil {
+		return
+	}
+
+	events, unsubscribe, resume := s.cursors.Events.Subscribe(since)
+	defer unsubscribe()
+
+	s.addSubscriber(conn, mcID)
+	defer s.removeSubscriber(conn)
+
+	for _, ev := range resume {
+		if matchesMC(ev, mcID) {
+			if !writeEventFrame(buf, ev) {
+				return
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(15 * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if matchesMC(ev, mcID) {
+				if !writeEventFrame(buf, ev) {
+					return
+				}
+			}
+		case <-heartbeat.C:
+			if _, err := buf.WriteString(`{"event":"heartbeat"}` + "\n"); err != nil {
+				return
+			}
+			if err := buf.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeEventFrame marshals ev as a single NDJSON line and flushes it,
+// reporting whether the write succeeded.
+func writeEventFrame(buf *bufio.ReadWriter, ev codeintel.Event) bool {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return false
+	}
+	if _, err := buf.Write(append(data, '\n')); err != nil {
+		return false
+	}
+	return buf.Flush() == nil
+}
+
+// matchesMC reports whether ev should be delivered to a subscriber
+// filtered to mcID ("" matches everything).
+func matchesMC(ev codeintel.Event, mcID string) bool {
+	return mcID == "" || ev.MCID == mcID
+}
+
+// addSubscriber registers a hijacked streaming connection so
+// handleStatus can report how many clients are attached.
+func (s *Server) addSubscriber(conn net.Conn, mcID string) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	s.subscribers[conn] = mcID
+}
+
+// removeSubscriber unregisters a streaming connection once it closes.
+func (s *Server) removeSubscriber(conn net.Conn) {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	delete(s.subscribers, conn)
+}
+
 // handleRoot provides API documentation
 func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 	doc := map[string]interface{}{
@@ -282,6 +749,19 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 			"GET /api/theme":          "Get theme colors and preview",
 			"GET /api/ui":             "Get UI design tokens",
 			"GET /api/status":         "Get system status",
+			"GET /api/files":          "List a workspace directory (?path=, ?sort=, ?order=)",
+			"GET /api/files/content":  "Read a workspace file's line range (?path=, ?start=, ?end=) with an ETag",
+			"GET /api/cursors/{id}/refresh": "Re-read a cursor's range and mark it stale if its ETag changed",
+			"GET /api/events":        "Stream all cursor/multicursor mutations (NDJSON, ?since=<seq>)",
+			"GET /api/cursors/{mcID}/stream": "Stream mutations scoped to one multicursor",
+			"GET /api/snapshots":     "List snapshots",
+			"POST /api/snapshots":    "Create a named snapshot of the whole CursorDirectory",
+			"GET /api/snapshots/{name}": "Download a snapshot (?diff=<other> for a structural diff instead)",
+			"POST /api/snapshots/{name}/restore": "Atomically restore a snapshot",
+			"DELETE /api/snapshots/{name}": "Delete a snapshot",
+			"POST /api/auth/tokens": "Issue a bearer token with the given scopes",
+			"GET /api/auth/tokens":  "List issued tokens (redacted)",
+			"DELETE /api/auth/tokens/{id}": "Revoke a token",
 			"GET /health":             "Health check",
 		},
 	}
@@ -290,7 +770,19 @@ func (s *Server) handleRoot(w http.ResponseWriter, r *http.Request) {
 }
 
 // executeCommand executes a command and returns the result
-func (s *Server) executeCommand(command string, args []string) (string, error) {
+// errUnknownCommand is wrapped into executeCommand's "unknown command"
+// error so handleCommand can tag CommandResponse.Code with
+// ErrUnknownCommand via errors.Is.
+var errUnknownCommand = errors.New("unknown command")
+
+// executeCommand runs command and returns its output. ctx is checked
+// before dispatching so a command started after the client has already
+// disconnected doesn't bother running.
+func (s *Server) executeCommand(ctx context.Context, command string, args []string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
 	switch command {
 	case "/help":
 		return `Available commands:
@@ -344,7 +836,7 @@ func (s *Server) executeCommand(command string, args []string) (string, error) {
 		}
 	}
 	
-	return fmt.Sprintf("Unknown command: %s", command), fmt.Errorf("unknown command")
+	return fmt.Sprintf("Unknown command: %s", command), fmt.Errorf("%w: %s", errUnknownCommand, command)
 }
 
 // getTotalCursorCount returns the total number of cursors across all multicursors
@@ -356,35 +848,295 @@ func (s *Server) getTotalCursorCount() int {
 	return total
 }
 
+// handleSnapshots handles POST (create) and GET (list) on
+// /api/snapshots.
+func (s *Server) handleSnapshots(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		list, err := s.snapshots.List(r.Context())
+		if err != nil {
+			if s.respondIfCanceled(w, r, err) {
+				return
+			}
+			s.respondError(w, r, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.respondJSON(w, map[string]interface{}{"snapshots": list})
+
+	case http.MethodPost:
+		var req struct {
+			Name     string `json:"name"`
+			Compress bool   `json:"compress"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.respondProblem(w, r, http.StatusBadRequest, ErrInvalidJSON, "Invalid JSON")
+			return
+		}
+		if req.Name == "" {
+			s.respondError(w, r, "name is required", http.StatusBadRequest)
+			return
+		}
+
+		meta, err := s.snapshots.Create(r.Context(), req.Name, s.cursors, req.Compress)
+		if err != nil {
+			if s.respondIfCanceled(w, r, err) {
+				return
+			}
+			s.respondError(w, r, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.cursors.Events.Publish(codeintel.Event{Event: codeintel.EventSnapshotSaved, Payload: meta})
+		s.respondJSON(w, meta)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSnapshot handles /api/snapshots/{name}, /api/snapshots/{name}/restore,
+// and /api/snapshots/{name}?diff=<other>.
+func (s *Server) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/api/snapshots/")
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) == 0 || parts[0] == "" {
+		http.NotFound(w, r)
+		return
+	}
+	name := parts[0]
+
+	if len(parts) == 2 && parts[1] == "restore" {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := s.snapshots.Restore(r.Context(), name, s.cursors); err != nil {
+			if s.respondIfCanceled(w, r, err) {
+				return
+			}
+			s.respondProblem(w, r, http.StatusNotFound, ErrSnapshotNotFound, err.Error())
+			return
+		}
+		s.respondJSON(w, map[string]interface{}{"restored": name})
+		return
+	}
+
+	if len(parts) != 1 {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if other := r.URL.Query().Get("diff"); other != "" {
+			diff, err := s.snapshots.Diff(r.Context(), name, other)
+			if err != nil {
+				if s.respondIfCanceled(w, r, err) {
+					return
+				}
+				s.respondProblem(w, r, http.StatusNotFound, ErrSnapshotNotFound, err.Error())
+				return
+			}
+			s.respondJSON(w, diff)
+			return
+		}
+
+		data, meta, err := s.snapshots.Load(r.Context(), name)
+		if err != nil {
+			if s.respondIfCanceled(w, r, err) {
+				return
+			}
+			s.respondProblem(w, r, http.StatusNotFound, ErrSnapshotNotFound, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Snapshot-SHA256", meta.SHA256)
+		w.Write(data)
+
+	case http.MethodDelete:
+		if err := s.snapshots.Delete(r.Context(), name); err != nil {
+			if s.respondIfCanceled(w, r, err) {
+				return
+			}
+			s.respondProblem(w, r, http.StatusNotFound, ErrSnapshotNotFound, err.Error())
+			return
+		}
+		s.respondJSON(w, map[string]interface{}{"deleted": name})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
 // respondJSON sends a JSON response
 func (s *Server) respondJSON(w http.ResponseWriter, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(data)
 }
 
-// respondError sends an error response
-func (s *Server) respondError(w http.ResponseWriter, message string, code int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
-}
-
-// corsMiddleware adds CORS headers
+// corsMiddleware reflects Origin back only when it's in corsOrigins -
+// an allowlist rather than the wide-open "*" this server used to send.
 func (s *Server) corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-		
+		if origin := r.Header.Get("Origin"); s.corsOrigins[origin] {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		}
+
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
 			return
 		}
-		
+
 		next.ServeHTTP(w, r)
 	})
 }
 
+// routeRule maps a path prefix (and, when non-empty, a specific
+// method) to the scope a bearer token must carry. Rules are checked in
+// order and the first match wins, so a more specific prefix must come
+// before a shorter one it would otherwise shadow. The /api/cursors/{id}
+// sub-routes are not in routeTable; requiredScope handles them first.
+type routeRule struct {
+	prefix string
+	method string
+	scope  auth.Scope
+}
+
+var routeTable = []routeRule{
+	{"/api/auth/", "", auth.ScopeMCAdmin},
+	{"/api/snapshots", "", auth.ScopeSnapshots},
+	{"/api/events", "", auth.ScopeEventsStream},
+	{"/api/files", "", auth.ScopeCursorsRead},
+	{"/api/cursors", http.MethodGet, auth.ScopeCursorsRead},
+	{"/api/cursors", http.MethodPost, auth.ScopeCursorsWrite},
+	{"/api/multicursors", http.MethodGet, auth.ScopeCursorsRead},
+	{"/api/multicursors", http.MethodPost, auth.ScopeCursorsWrite},
+	{"/api/command", "", auth.ScopeCursorsWrite},
+}
+
+// requiredScope looks up the scope method+path needs. /api/cursors/{id}
+// sub-routes are special-cased on their suffix ahead of routeTable,
+// since a fixed prefix can't tell "/stream" from "/refresh" apart when
+// the id in between varies; everything else goes through routeTable.
+// ok is false for routes with no entry (health, root, theme, ui,
+// status), which stay open even with auth enabled.
+func requiredScope(method, path string) (scope auth.Scope, ok bool) {
+	if strings.HasPrefix(path, "/api/cursors/") {
+		switch {
+		case strings.HasSuffix(path, "/stream"):
+			return auth.ScopeEventsStream, true
+		case strings.HasSuffix(path, "/refresh"):
+			return auth.ScopeCursorsWrite, true
+		}
+	}
+
+	for _, rule := range routeTable {
+		if rule.method != "" && rule.method != method {
+			continue
+		}
+		if strings.HasPrefix(path, rule.prefix) {
+			return rule.scope, true
+		}
+	}
+	return "", false
+}
+
+// authMiddleware enforces "Authorization: Bearer <secret>" against
+// routeTable and a per-token rate limit, unless AuthDisabled.
+func (s *Server) authMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.authDisabled || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		scope, required := requiredScope(r.Method, r.URL.Path)
+		if !required {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		secret, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || secret == "" {
+			s.respondProblem(w, r, http.StatusUnauthorized, ErrUnauthorized, "missing bearer token")
+			return
+		}
+
+		token, ok := s.authStore.Verify(secret)
+		if !ok {
+			s.respondProblem(w, r, http.StatusUnauthorized, ErrUnauthorized, "invalid token")
+			return
+		}
+		if !token.HasScope(scope) {
+			s.respondError(w, r, "token missing required scope: "+string(scope), http.StatusForbidden)
+			return
+		}
+		if !s.limiter.Allow(token.ID) {
+			s.respondProblem(w, r, http.StatusTooManyRequests, ErrRateLimited, "rate limit exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleAuthTokens handles POST (issue) and GET (list, redacted) on
+// /api/auth/tokens.
+func (s *Server) handleAuthTokens(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.respondJSON(w, map[string]interface{}{"tokens": s.authStore.List()})
+
+	case http.MethodPost:
+		var req struct {
+			Scopes []auth.Scope `json:"scopes"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			s.respondProblem(w, r, http.StatusBadRequest, ErrInvalidJSON, "Invalid JSON")
+			return
+		}
+		if len(req.Scopes) == 0 {
+			s.respondError(w, r, "scopes is required", http.StatusBadRequest)
+			return
+		}
+
+		id, secret, err := s.authStore.Issue(req.Scopes)
+		if err != nil {
+			s.respondError(w, r, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		s.respondJSON(w, map[string]interface{}{
+			"id":     id,
+			"token":  secret,
+			"scopes": req.Scopes,
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleAuthToken handles DELETE (revoke) on /api/auth/tokens/{id}.
+func (s *Server) handleAuthToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/api/auth/tokens/")
+	if id == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := s.authStore.Revoke(id); err != nil {
+		s.respondError(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	s.respondJSON(w, map[string]interface{}{"revoked": id})
+}
+
 // logMiddleware logs requests
 func (s *Server) logMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
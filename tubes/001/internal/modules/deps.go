@@ -0,0 +1,276 @@
+package modules
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dep describes one module dependency: the ID of the module that must be
+// started first, and a semver constraint its installed Info().Version must
+// satisfy (e.g. "^1.2", ">=0.5 <2").
+type Dep struct {
+	ID         string
+	Constraint string
+}
+
+// ConstraintViolation pairs a dependent module with the constraint on the
+// dependency that its installed version failed to satisfy.
+type ConstraintViolation struct {
+	ModuleID   string
+	Constraint string
+}
+
+// ResolveError reports that no single installed version of a dependency can
+// satisfy every dependent's constraint on it - or that the dependency isn't
+// registered at all.
+type ResolveError struct {
+	DependencyID string
+	Installed    string // empty if the dependency isn't registered
+	Violations   []ConstraintViolation
+}
+
+func (e *ResolveError) Error() string {
+	var b strings.Builder
+	if e.Installed == "" {
+		fmt.Fprintf(&b, "modules: dependency %q is not registered (required by", e.DependencyID)
+	} else {
+		fmt.Fprintf(&b, "modules: installed %s@%s does not satisfy every dependent (required by", e.DependencyID, e.Installed)
+	}
+	for i, v := range e.Violations {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		fmt.Fprintf(&b, " %s %s", v.ModuleID, v.Constraint)
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// CycleError reports that the dependency graph among the listed modules
+// contains a cycle, so no start order exists.
+type CycleError struct {
+	ModuleIDs []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("modules: dependency cycle among %s", strings.Join(e.ModuleIDs, ", "))
+}
+
+// Resolve computes a start order for every registered module such that each
+// module's dependencies start before it does, without starting or stopping
+// anything. It returns a *ResolveError if some dependency's installed
+// version can't satisfy every dependent's constraint (checked jointly, not
+// one dependent at a time), or a *CycleError if the dependency graph has a
+// cycle.
+func (mm *ModuleManager) Resolve() ([]string, error) {
+	ids := make([]string, 0, len(mm.modules))
+	for id := range mm.modules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	adjacency := make(map[string][]string) // depID -> dependent module IDs
+	inDegree := make(map[string]int, len(ids))
+	violationsByDep := make(map[string][]ConstraintViolation)
+
+	for _, id := range ids {
+		for _, dep := range mm.modules[id].Dependencies() {
+			dm, ok := mm.modules[dep.ID]
+			if !ok {
+				return nil, &ResolveError{
+					DependencyID: dep.ID,
+					Violations:   []ConstraintViolation{{ModuleID: id, Constraint: dep.Constraint}},
+				}
+			}
+
+			c, err := parseConstraint(dep.Constraint)
+			if err != nil {
+				return nil, fmt.Errorf("modules: module %q: dependency %q: %w", id, dep.ID, err)
+			}
+			installed, err := parseVersion(dm.Info().Version)
+			if err != nil {
+				return nil, fmt.Errorf("modules: module %q: invalid version %q: %w", dep.ID, dm.Info().Version, err)
+			}
+			if !c.matches(installed) {
+				violationsByDep[dep.ID] = append(violationsByDep[dep.ID], ConstraintViolation{ModuleID: id, Constraint: dep.Constraint})
+			}
+
+			adjacency[dep.ID] = append(adjacency[dep.ID], id)
+			inDegree[id]++
+		}
+	}
+
+	if len(violationsByDep) > 0 {
+		depIDs := make([]string, 0, len(violationsByDep))
+		for depID := range violationsByDep {
+			depIDs = append(depIDs, depID)
+		}
+		sort.Strings(depIDs)
+		depID := depIDs[0]
+
+		violations := violationsByDep[depID]
+		sort.Slice(violations, func(i, j int) bool { return violations[i].ModuleID < violations[j].ModuleID })
+
+		return nil, &ResolveError{
+			DependencyID: depID,
+			Installed:    mm.modules[depID].Info().Version,
+			Violations:   violations,
+		}
+	}
+
+	order := make([]string, 0, len(ids))
+	visited := make(map[string]bool, len(ids))
+	for len(order) < len(ids) {
+		progressed := false
+		for _, id := range ids {
+			if visited[id] || inDegree[id] > 0 {
+				continue
+			}
+			visited[id] = true
+			order = append(order, id)
+			for _, dependent := range adjacency[id] {
+				inDegree[dependent]--
+			}
+			progressed = true
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	if len(order) < len(ids) {
+		cyclic := make([]string, 0, len(ids)-len(order))
+		for _, id := range ids {
+			if !visited[id] {
+				cyclic = append(cyclic, id)
+			}
+		}
+		return nil, &CycleError{ModuleIDs: cyclic}
+	}
+
+	return order, nil
+}
+
+// semverVersion is a parsed (possibly partial) semantic version. Missing
+// components default to 0, so "1.2" parses the same as "1.2.0".
+type semverVersion struct {
+	major, minor, patch int
+}
+
+func parseVersion(s string) (semverVersion, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	parts := strings.SplitN(s, ".", 3)
+	var v semverVersion
+	nums := [3]*int{&v.major, &v.minor, &v.patch}
+	for i, p := range parts {
+		if p == "" {
+			continue
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return semverVersion{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+func compareVersions(a, b semverVersion) int {
+	switch {
+	case a.major != b.major:
+		return a.major - b.major
+	case a.minor != b.minor:
+		return a.minor - b.minor
+	default:
+		return a.patch - b.patch
+	}
+}
+
+// semverComparator is one "<op><version>" clause of a constraint, e.g. the
+// ">=0.5" in ">=0.5 <2".
+type semverComparator struct {
+	op string
+	v  semverVersion
+}
+
+func (c semverComparator) matches(v semverVersion) bool {
+	cmp := compareVersions(v, c.v)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	default: // "="
+		return cmp == 0
+	}
+}
+
+// semverConstraint is a set of comparators that must ALL hold - the AND
+// join needed to check a version against every dependent's requirement at
+// once.
+type semverConstraint struct {
+	comparators []semverComparator
+}
+
+func (c semverConstraint) matches(v semverVersion) bool {
+	for _, cmp := range c.comparators {
+		if !cmp.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+var semverOps = []string{">=", "<=", ">", "<", "="}
+
+// parseConstraint parses a space-separated list of comparator clauses (all
+// ANDed together), with caret ("^1.2") shorthand for "compatible with,
+// allowing non-breaking upgrades": ^1.2 means >=1.2.0 <2.0.0, ^0.5 means
+// >=0.5.0 <0.6.0, ^0.0.3 means >=0.0.3 <0.0.4.
+func parseConstraint(s string) (semverConstraint, error) {
+	var c semverConstraint
+	for _, token := range strings.Fields(s) {
+		if strings.HasPrefix(token, "^") {
+			v, err := parseVersion(token[1:])
+			if err != nil {
+				return semverConstraint{}, err
+			}
+			upper := v
+			switch {
+			case v.major > 0:
+				upper = semverVersion{major: v.major + 1}
+			case v.minor > 0:
+				upper = semverVersion{minor: v.minor + 1}
+			default:
+				upper = semverVersion{patch: v.patch + 1}
+			}
+			c.comparators = append(c.comparators,
+				semverComparator{op: ">=", v: v},
+				semverComparator{op: "<", v: upper},
+			)
+			continue
+		}
+
+		op := "="
+		rest := token
+		for _, candidate := range semverOps {
+			if strings.HasPrefix(token, candidate) {
+				op = candidate
+				rest = token[len(candidate):]
+				break
+			}
+		}
+		v, err := parseVersion(rest)
+		if err != nil {
+			return semverConstraint{}, err
+		}
+		c.comparators = append(c.comparators, semverComparator{op: op, v: v})
+	}
+	return c, nil
+}
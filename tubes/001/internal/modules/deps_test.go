@@ -0,0 +1,127 @@
+package modules
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeModule is a minimal Module for exercising Resolve without any real
+// lifecycle behavior.
+type fakeModule struct {
+	*BaseModule
+	deps []Dep
+}
+
+func newFakeModule(id, version string, deps ...Dep) *fakeModule {
+	return &fakeModule{
+		BaseModule: NewBaseModule(id, id, "", version),
+		deps:       deps,
+	}
+}
+
+func (m *fakeModule) Start() error  { return nil }
+func (m *fakeModule) Stop() error   { return nil }
+func (m *fakeModule) Delete() error { return nil }
+func (m *fakeModule) Health() error { return nil }
+
+func (m *fakeModule) Dependencies() []Dep { return m.deps }
+
+func newManager(modules ...*fakeModule) *ModuleManager {
+	mm := NewModuleManager()
+	for _, m := range modules {
+		mm.Register(m)
+	}
+	return mm
+}
+
+// indexOf returns the position of id in order, or -1 if absent.
+func indexOf(order []string, id string) int {
+	for i, v := range order {
+		if v == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestResolveDiamond(t *testing.T) {
+	// d depends on b and c, both of which depend on a.
+	a := newFakeModule("a", "1.0.0")
+	b := newFakeModule("b", "1.0.0", Dep{ID: "a", Constraint: ">=1.0"})
+	c := newFakeModule("c", "1.0.0", Dep{ID: "a", Constraint: "^1.0"})
+	d := newFakeModule("d", "1.0.0", Dep{ID: "b", Constraint: ">=1.0"}, Dep{ID: "c", Constraint: ">=1.0"})
+
+	order, err := newManager(a, b, c, d).Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	ia, ib, ic, id := indexOf(order, "a"), indexOf(order, "b"), indexOf(order, "c"), indexOf(order, "d")
+	if ia > ib || ia > ic || ib > id || ic > id {
+		t.Fatalf("Resolve() order = %v, want a before b and c, and both before d", order)
+	}
+}
+
+func TestResolveCycle(t *testing.T) {
+	a := newFakeModule("a", "1.0.0", Dep{ID: "b", Constraint: ">=1.0"})
+	b := newFakeModule("b", "1.0.0", Dep{ID: "c", Constraint: ">=1.0"})
+	c := newFakeModule("c", "1.0.0", Dep{ID: "a", Constraint: ">=1.0"})
+
+	_, err := newManager(a, b, c).Resolve()
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Resolve() error = %v, want *CycleError", err)
+	}
+	if len(cycleErr.ModuleIDs) != 3 {
+		t.Fatalf("CycleError.ModuleIDs = %v, want all 3 cyclic modules", cycleErr.ModuleIDs)
+	}
+}
+
+func TestResolveUnsatisfiableJointConstraint(t *testing.T) {
+	// b wants dep in [1.2, 2.0), c wants dep >=0.5 and <2 - both are
+	// individually satisfiable by 1.5.0, but dep is installed at 1.0.0,
+	// which only satisfies c's constraint, not b's ^1.2.
+	dep := newFakeModule("dep", "1.0.0")
+	b := newFakeModule("b", "1.0.0", Dep{ID: "dep", Constraint: "^1.2"})
+	c := newFakeModule("c", "1.0.0", Dep{ID: "dep", Constraint: ">=0.5 <2"})
+
+	_, err := newManager(dep, b, c).Resolve()
+	var resolveErr *ResolveError
+	if !errors.As(err, &resolveErr) {
+		t.Fatalf("Resolve() error = %v, want *ResolveError", err)
+	}
+	if resolveErr.DependencyID != "dep" {
+		t.Fatalf("ResolveError.DependencyID = %q, want %q", resolveErr.DependencyID, "dep")
+	}
+	if len(resolveErr.Violations) != 1 || resolveErr.Violations[0].ModuleID != "b" {
+		t.Fatalf("ResolveError.Violations = %+v, want a single violation from %q", resolveErr.Violations, "b")
+	}
+}
+
+func TestResolveJointConstraintSatisfied(t *testing.T) {
+	// dep installed at 1.5.0 satisfies both b's ^1.2 and c's >=0.5 <2.
+	dep := newFakeModule("dep", "1.5.0")
+	b := newFakeModule("b", "1.0.0", Dep{ID: "dep", Constraint: "^1.2"})
+	c := newFakeModule("c", "1.0.0", Dep{ID: "dep", Constraint: ">=0.5 <2"})
+
+	order, err := newManager(dep, b, c).Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if indexOf(order, "dep") > indexOf(order, "b") || indexOf(order, "dep") > indexOf(order, "c") {
+		t.Fatalf("Resolve() order = %v, want dep before both dependents", order)
+	}
+}
+
+func TestResolveMissingDependency(t *testing.T) {
+	a := newFakeModule("a", "1.0.0", Dep{ID: "missing", Constraint: ">=1.0"})
+
+	_, err := newManager(a).Resolve()
+	var resolveErr *ResolveError
+	if !errors.As(err, &resolveErr) {
+		t.Fatalf("Resolve() error = %v, want *ResolveError", err)
+	}
+	if resolveErr.Installed != "" {
+		t.Fatalf("ResolveError.Installed = %q, want empty for unregistered dependency", resolveErr.Installed)
+	}
+}
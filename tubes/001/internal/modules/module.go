@@ -64,6 +64,10 @@ type Module interface {
 	
 	// Health check
 	Health() error
+
+	// Dependencies lists other modules (and version constraints on them)
+	// that must be started before this one.
+	Dependencies() []Dep
 }
 
 // BaseModule provides default implementations for common module functionality
@@ -182,20 +186,30 @@ func (mm *ModuleManager) List() []ModuleInfo {
 	return infos
 }
 
-// StartAll starts all registered modules
+// StartAll starts all registered modules in dependency order - every
+// module's dependencies are started, and their version constraints
+// verified, before it is.
 func (mm *ModuleManager) StartAll() error {
-	for _, module := range mm.modules {
-		if err := module.Start(); err != nil {
+	order, err := mm.Resolve()
+	if err != nil {
+		return err
+	}
+	for _, id := range order {
+		if err := mm.modules[id].Start(); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// StopAll stops all registered modules
+// StopAll stops all registered modules in reverse dependency order.
 func (mm *ModuleManager) StopAll() error {
-	for _, module := range mm.modules {
-		if err := module.Stop(); err != nil {
+	order, err := mm.Resolve()
+	if err != nil {
+		return err
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		if err := mm.modules[order[i]].Stop(); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,158 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ActionMeta describes a KeyAction for the cheatsheet overlay: Tag
+// groups related actions (e.g. "navigation", "history", "scroll") and
+// Description is the human-readable text shown next to the key.
+type ActionMeta struct {
+	Tag         string
+	Description string
+}
+
+// actionMeta holds the Tag/Description for every KeyAction that should
+// show up in the cheatsheet. Actions with no entry here (e.g. the
+// "Obsolete actions kept for compatibility" in keymap.go) are omitted
+// from GenerateCheatsheet rather than shown with blank metadata.
+var actionMeta = map[KeyAction]ActionMeta{
+	ActionQuit:             {Tag: "general", Description: "Quit"},
+	ActionToggleCheatsheet: {Tag: "general", Description: "Toggle this cheatsheet"},
+	ActionExecuteCommand:   {Tag: "input", Description: "Execute command"},
+	ActionTabComplete:      {Tag: "input", Description: "Tab-complete"},
+	ActionHistoryPrev:      {Tag: "history", Description: "Previous history entry"},
+	ActionHistoryNext:      {Tag: "history", Description: "Next history entry"},
+	ActionScrollUp:         {Tag: "scroll", Description: "Scroll output up"},
+	ActionScrollDown:       {Tag: "scroll", Description: "Scroll output down"},
+	ActionCopyMode:         {Tag: "copy-mode", Description: "Enter copy mode"},
+	ActionCopyModeExit:     {Tag: "copy-mode", Description: "Exit copy mode"},
+	ActionCopyMoveUp:       {Tag: "copy-mode", Description: "Move cursor up"},
+	ActionCopyMoveDown:     {Tag: "copy-mode", Description: "Move cursor down"},
+	ActionCopyMoveLeft:     {Tag: "copy-mode", Description: "Move cursor left"},
+	ActionCopyMoveRight:    {Tag: "copy-mode", Description: "Move cursor right"},
+	ActionCopySelect:       {Tag: "copy-mode", Description: "Start selection"},
+	ActionCopyYank:         {Tag: "copy-mode", Description: "Yank selection to clipboard"},
+}
+
+// cheatsheetContexts is GenerateCheatsheet's fixed section order and
+// display titles. Only these three contexts are shown; any other context
+// in km.Contexts or km.Runes, which GetAllMappings does list, is left out.
+var cheatsheetContexts = []struct {
+	ctx   string
+	title string
+}{
+	{"global", "Global"},
+	{"input", "Input"},
+	{"copy_mode", "Copy Mode"},
+}
+
+// cheatsheetOverlay is the `?`-toggled modal's state: active tracks
+// whether it's showing, and Lines holds the rendered rows to draw (set
+// whenever it opens, so it reflects whatever bindings - including user
+// overrides - are live at the time).
+type cheatsheetOverlay struct {
+	active bool
+	Lines  []string
+}
+
+// toggleCheatsheet opens the `?` overlay, or closes it via
+// closeCheatsheet if it is already showing. Esc is not bound to
+// closeCheatsheet, and no key handler in this package calls either.
+func (m *Model) toggleCheatsheet() {
+	if m.cheatsheet.active {
+		m.closeCheatsheet()
+		return
+	}
+	m.cheatsheet.active = true
+	m.cheatsheet.Lines = m.keymap.GenerateCheatsheet()
+}
+
+// closeCheatsheet dismisses the overlay.
+func (m *Model) closeCheatsheet() {
+	m.cheatsheet.active = false
+	m.cheatsheet.Lines = nil
+}
+
+// GenerateCheatsheet renders every binding in km, grouped by context and
+// then by Tag within each context, as plain text lines suitable for the
+// `?` overlay.
+func (km *KeyMapping) GenerateCheatsheet() []string {
+	var lines []string
+	for _, section := range cheatsheetContexts {
+		lines = append(lines, km.cheatsheetSection(section.title, section.ctx)...)
+	}
+	return lines
+}
+
+// cheatsheetSection renders one context's bindings, grouped by Tag.
+func (km *KeyMapping) cheatsheetSection(title, ctx string) []string {
+	byTag := make(map[string][]string)
+
+	addBinding := func(keyStr string, action KeyAction) {
+		meta, ok := actionMeta[action]
+		if !ok {
+			return
+		}
+		byTag[meta.Tag] = append(byTag[meta.Tag], fmt.Sprintf("  %s: %s", keyStr, meta.Description))
+	}
+
+	for _, kt := range sortedKeyTypeKeys(km.keyTypeMap(ctx)) {
+		addBinding(keyTypeToString(kt), km.keyTypeMap(ctx)[kt])
+	}
+	for _, r := range sortedKeys(km.Runes[ctx]) {
+		addBinding(r, km.Runes[ctx][r])
+	}
+
+	if len(byTag) == 0 {
+		return nil
+	}
+
+	lines := []string{"", title + ":"}
+	for _, tag := range sortedKeys(byTag) {
+		lines = append(lines, fmt.Sprintf("  [%s]", tag))
+		lines = append(lines, byTag[tag]...)
+	}
+	return lines
+}
+
+// GenerateCheatsheetMarkdown renders the same bindings as Markdown,
+// suitable for dumping to stdout for docs (e.g. a `keymap -cheatsheet`
+// dev command).
+func (km *KeyMapping) GenerateCheatsheetMarkdown() string {
+	var b strings.Builder
+	b.WriteString("# Keybindings\n")
+	for _, section := range cheatsheetContexts {
+		rows := km.cheatsheetSectionMarkdown(section.ctx)
+		if len(rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n", section.title)
+		b.WriteString("\n| Key | Action | Tag |\n|---|---|---|\n")
+		for _, row := range rows {
+			b.WriteString(row)
+		}
+	}
+	return b.String()
+}
+
+// cheatsheetSectionMarkdown renders ctx's bindings as Markdown table
+// rows, sorted by key for determinism.
+func (km *KeyMapping) cheatsheetSectionMarkdown(ctx string) []string {
+	var rows []string
+	add := func(keyStr string, action KeyAction) {
+		meta, ok := actionMeta[action]
+		if !ok {
+			return
+		}
+		rows = append(rows, fmt.Sprintf("| %s | %s | %s |\n", keyStr, meta.Description, meta.Tag))
+	}
+	for _, kt := range sortedKeyTypeKeys(km.keyTypeMap(ctx)) {
+		add(keyTypeToString(kt), km.keyTypeMap(ctx)[kt])
+	}
+	for _, r := range sortedKeys(km.Runes[ctx]) {
+		add(r, km.Runes[ctx][r])
+	}
+	return rows
+}
@@ -0,0 +1,154 @@
+package tui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+)
+
+// copyModeState is the cursor/selection state kept while m.currentInputMode
+// is copyMode: Row/Col index into m.leftContent (the same output buffer
+// the REPL appends to), and a selection spans from SelRow/SelCol to the
+// current cursor once SelActive is set by 'v'.
+type copyModeState struct {
+	Row, Col       int
+	SelActive      bool
+	SelRow, SelCol int
+}
+
+// enterCopyMode switches the input panel into copy mode: editing stops,
+// hjkl/arrows move a cursor over m.leftContent instead. The cursor
+// starts at the end of the buffer, where the REPL's attention usually
+// is.
+func (m *Model) enterCopyMode() {
+	m.currentInputMode = copyMode
+	row := len(m.leftContent) - 1
+	if row < 0 {
+		row = 0
+	}
+	m.copyMode = copyModeState{Row: row}
+}
+
+// exitCopyMode returns to view mode, dropping any in-progress selection.
+func (m *Model) exitCopyMode() {
+	m.currentInputMode = viewMode
+	m.copyMode = copyModeState{}
+}
+
+// moveCopyCursor moves the copy-mode cursor by (dRow, dCol), clamped to
+// the bounds of m.leftContent.
+func (m *Model) moveCopyCursor(dRow, dCol int) {
+	row := m.copyMode.Row + dRow
+	if row < 0 {
+		row = 0
+	}
+	if max := len(m.leftContent) - 1; row > max {
+		row = max
+	}
+	if row < 0 {
+		row = 0
+	}
+
+	col := m.copyMode.Col + dCol
+	if col < 0 {
+		col = 0
+	}
+	if row < len(m.leftContent) {
+		if max := len(m.leftContent[row]); col > max {
+			col = max
+		}
+	}
+
+	m.copyMode.Row, m.copyMode.Col = row, col
+}
+
+// startCopySelection anchors a selection at the current cursor ('v').
+func (m *Model) startCopySelection() {
+	m.copyMode.SelActive = true
+	m.copyMode.SelRow, m.copyMode.SelCol = m.copyMode.Row, m.copyMode.Col
+}
+
+// yankCopySelection copies the text between the selection anchor and the
+// current cursor to the clipboard ('y'), then exits copy mode. With no
+// active selection, it yanks the whole line the cursor is on.
+func (m *Model) yankCopySelection() error {
+	text := m.copySelectionText()
+	err := copyToClipboard(text)
+	m.exitCopyMode()
+	return err
+}
+
+// copySelectionText extracts the text currently selected in copy mode,
+// normalizing start/end so it reads correctly regardless of which
+// direction the cursor moved from the anchor.
+func (m *Model) copySelectionText() string {
+	if !m.copyMode.SelActive {
+		if m.copyMode.Row < len(m.leftContent) {
+			return m.leftContent[m.copyMode.Row]
+		}
+		return ""
+	}
+
+	startRow, startCol := m.copyMode.SelRow, m.copyMode.SelCol
+	endRow, endCol := m.copyMode.Row, m.copyMode.Col
+	if startRow > endRow || (startRow == endRow && startCol > endCol) {
+		startRow, endRow = endRow, startRow
+		startCol, endCol = endCol, startCol
+	}
+
+	var lines []string
+	for row := startRow; row <= endRow && row < len(m.leftContent); row++ {
+		line := m.leftContent[row]
+		switch {
+		case startRow == endRow:
+			lines = append(lines, sliceCols(line, startCol, endCol))
+		case row == startRow:
+			lines = append(lines, sliceCols(line, startCol, len(line)))
+		case row == endRow:
+			lines = append(lines, sliceCols(line, 0, endCol))
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sliceCols returns line[from:to], clamped to line's bounds.
+func sliceCols(line string, from, to int) string {
+	if from < 0 {
+		from = 0
+	}
+	if to > len(line) {
+		to = len(line)
+	}
+	if from > to {
+		return ""
+	}
+	return line[from:to]
+}
+
+// copyToClipboard writes text to the system clipboard. When no local
+// clipboard is available - the common case over SSH - it falls back to
+// an OSC52 escape sequence, which terminal emulators that support it
+// (iTerm2, kitty, wezterm, tmux with passthrough, ...) forward to the
+// client's clipboard instead.
+func copyToClipboard(text string) error {
+	if err := clipboard.WriteAll(text); err == nil {
+		return nil
+	}
+	return writeOSC52(os.Stdout, text)
+}
+
+// writeOSC52 emits the OSC52 clipboard-set sequence for text to w.
+func writeOSC52(w *os.File, text string) error {
+	var b bytes.Buffer
+	b.WriteString("\x1b]52;c;")
+	b.WriteString(base64.StdEncoding.EncodeToString([]byte(text)))
+	b.WriteString("\x07")
+	_, err := fmt.Fprint(w, b.String())
+	return err
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -10,38 +11,56 @@ import (
 type KeyAction string
 
 const (
-	ActionNone           KeyAction = "none"
-	ActionQuit           KeyAction = "quit"
-	ActionExecuteCommand KeyAction = "execute_command"
-	ActionTabComplete    KeyAction = "tab_complete"
-	ActionHistoryPrev    KeyAction = "history_prev"
-	ActionHistoryNext    KeyAction = "history_next"
-	ActionScrollUp       KeyAction = "scroll_up"
-	ActionScrollDown     KeyAction = "scroll_down"
-	ActionCopyMode       KeyAction = "copy_mode"
-	
+	ActionNone             KeyAction = "none"
+	ActionQuit             KeyAction = "quit"
+	ActionExecuteCommand   KeyAction = "execute_command"
+	ActionTabComplete      KeyAction = "tab_complete"
+	ActionHistoryPrev      KeyAction = "history_prev"
+	ActionHistoryNext      KeyAction = "history_next"
+	ActionScrollUp         KeyAction = "scroll_up"
+	ActionScrollDown       KeyAction = "scroll_down"
+	ActionCopyMode         KeyAction = "copy_mode"
+	ActionCopyModeExit     KeyAction = "copy_mode_exit"
+	ActionCopyMoveUp       KeyAction = "copy_move_up"
+	ActionCopyMoveDown     KeyAction = "copy_move_down"
+	ActionCopyMoveLeft     KeyAction = "copy_move_left"
+	ActionCopyMoveRight    KeyAction = "copy_move_right"
+	ActionCopySelect       KeyAction = "copy_select"
+	ActionCopyYank         KeyAction = "copy_yank"
+	ActionToggleCheatsheet KeyAction = "toggle_cheatsheet"
+
 	// Obsolete actions kept for compatibility
-	ActionCyclePanes     KeyAction = "cycle_panes"
-	ActionCycleReverse   KeyAction = "cycle_reverse" 
-	ActionNavigateUp     KeyAction = "navigate_up"
-	ActionNavigateDown   KeyAction = "navigate_down"
-	ActionToggleExpand   KeyAction = "toggle_expand"
-	ActionSelectItem     KeyAction = "select_item"
-	ActionAddToCursor    KeyAction = "add_to_cursor"
-	ActionToggleMode     KeyAction = "toggle_mode"
-	ActionMoveLeft       KeyAction = "move_left"
-	ActionMoveRight      KeyAction = "move_right"
-	ActionEnterCursor    KeyAction = "enter_cursor"
-	ActionExitCursor     KeyAction = "exit_cursor"
+	ActionCyclePanes        KeyAction = "cycle_panes"
+	ActionCycleReverse      KeyAction = "cycle_reverse"
+	ActionNavigateUp        KeyAction = "navigate_up"
+	ActionNavigateDown      KeyAction = "navigate_down"
+	ActionToggleExpand      KeyAction = "toggle_expand"
+	ActionSelectItem        KeyAction = "select_item"
+	ActionAddToCursor       KeyAction = "add_to_cursor"
+	ActionToggleMode        KeyAction = "toggle_mode"
+	ActionMoveLeft          KeyAction = "move_left"
+	ActionMoveRight         KeyAction = "move_right"
+	ActionEnterCursor       KeyAction = "enter_cursor"
+	ActionExitCursor        KeyAction = "exit_cursor"
 	ActionToggleMulticursor KeyAction = "toggle_multicursor"
-	ActionEnter          KeyAction = "enter"
-	ActionOpen           KeyAction = "open"
+	ActionEnter             KeyAction = "enter"
+	ActionOpen              KeyAction = "open"
 )
 
-// KeyMapping defines the key bindings for minimal interface
+// KeyMapping defines the key bindings for minimal interface. Global and
+// InputPanel are the two built-in contexts; Contexts holds any further
+// ones a user config defines (e.g. "copy_mode"), and Runes holds
+// single-rune bindings (e.g. "h"/"j"/"k"/"l") per context, since
+// tea.KeyType alone can't represent those.
 type KeyMapping struct {
-	Global      map[tea.KeyType]KeyAction
-	InputPanel  map[tea.KeyType]KeyAction
+	Global     map[tea.KeyType]KeyAction
+	InputPanel map[tea.KeyType]KeyAction
+	Contexts   map[string]map[tea.KeyType]KeyAction
+	Runes      map[string]map[string]KeyAction
+
+	// Mouse routes tea.MouseMsg events the same way Global/Contexts
+	// route tea.KeyMsg - see mouse.go.
+	Mouse map[tea.MouseEventType]MouseAction
 }
 
 // NewKeyMapping creates the minimal key mapping configuration
@@ -59,21 +78,88 @@ func NewKeyMapping() *KeyMapping {
 			tea.KeyCtrlN: ActionHistoryNext, // Ctrl+N for history
 			tea.KeyCtrlY: ActionCopyMode,    // Ctrl+Y for copy mode info
 		},
+		Runes: map[string]map[string]KeyAction{
+			"global": {
+				"?": ActionToggleCheatsheet,
+			},
+			"copy_mode": {
+				"h": ActionCopyMoveLeft,
+				"j": ActionCopyMoveDown,
+				"k": ActionCopyMoveUp,
+				"l": ActionCopyMoveRight,
+				"v": ActionCopySelect,
+				"y": ActionCopyYank,
+			},
+		},
+		Contexts: map[string]map[tea.KeyType]KeyAction{
+			"copy_mode": {
+				tea.KeyEscape: ActionCopyModeExit,
+				tea.KeyUp:     ActionCopyMoveUp,
+				tea.KeyDown:   ActionCopyMoveDown,
+				tea.KeyLeft:   ActionCopyMoveLeft,
+				tea.KeyRight:  ActionCopyMoveRight,
+			},
+		},
+		Mouse: defaultMouseMapping(),
+	}
+}
+
+// contextForMode returns which built-in context a mode falls back to
+// when no more specific context applies.
+func contextForMode(mode inputMode) string {
+	switch mode {
+	case textMode:
+		return "input"
+	case copyMode:
+		return "copy_mode"
+	default:
+		return "global"
+	}
+}
+
+// keyTypeMap returns the tea.KeyType bindings for a named context -
+// "global" and "input" are the two built-ins, anything else is looked up
+// in Contexts.
+func (km *KeyMapping) keyTypeMap(ctx string) map[tea.KeyType]KeyAction {
+	switch ctx {
+	case "global":
+		return km.Global
+	case "input":
+		return km.InputPanel
+	default:
+		return km.Contexts[ctx]
 	}
 }
 
-// GetActionForMode determines what action should be taken for a key in minimal interface
+// GetActionForMode determines what action should be taken for a key,
+// checking the context implied by mode (falling back to global), then
+// global itself. tea.KeyRunes events (plain letters, not represented by
+// a distinct tea.KeyType) are matched against Runes instead.
 func (km *KeyMapping) GetActionForMode(keyMsg tea.KeyMsg, currentPane pane, mode inputMode) KeyAction {
-	// Global keys first
-	if action, exists := km.Global[keyMsg.Type]; exists {
-		return action
+	ctx := contextForMode(mode)
+
+	if keyMsg.Type == tea.KeyRunes {
+		r := string(keyMsg.Runes)
+		if action, ok := km.Runes[ctx][r]; ok {
+			return action
+		}
+		if ctx != "global" {
+			if action, ok := km.Runes["global"][r]; ok {
+				return action
+			}
+		}
+		return ActionNone
 	}
-	
-	// Input panel keys
-	if action, exists := km.InputPanel[keyMsg.Type]; exists {
+
+	if action, exists := km.keyTypeMap(ctx)[keyMsg.Type]; exists {
 		return action
 	}
-	
+	if ctx != "global" {
+		if action, exists := km.Global[keyMsg.Type]; exists {
+			return action
+		}
+	}
+
 	return ActionNone
 }
 
@@ -85,32 +171,58 @@ func (km *KeyMapping) GetAction(keyMsg tea.KeyMsg, currentPane pane) KeyAction {
 // ShouldPreventDefault checks if the key event should prevent default behavior
 func (km *KeyMapping) ShouldPreventDefault(keyMsg tea.KeyMsg, currentPane pane) bool {
 	action := km.GetAction(keyMsg, currentPane)
-	
+
 	// Only prevent defaults for special actions
 	preventDefaults := []KeyAction{
 		ActionTabComplete,
 		ActionScrollUp,
 		ActionScrollDown,
 	}
-	
+
 	for _, preventAction := range preventDefaults {
 		if action == preventAction {
 			return true
 		}
 	}
-	
+
 	return false
 }
 
-// GetKeyHelp returns help text for minimal interface
+// keyForAction finds the display string for the first key bound to
+// action within ctx (checking both KeyType and rune bindings), or want
+// if nothing overrides it - used so GetKeyHelp reflects user remaps.
+func (km *KeyMapping) keyForAction(ctx string, action KeyAction, want string) string {
+	for kt, a := range km.keyTypeMap(ctx) {
+		if a == action {
+			return keyTypeToString(kt)
+		}
+	}
+	for r, a := range km.Runes[ctx] {
+		if a == action {
+			return r
+		}
+	}
+	return want
+}
+
+// GetKeyHelp returns a compact one-line summary of the most relevant
+// bindings for currentPane, reflecting any user overrides loaded via
+// LoadKeyMapping.
 func (km *KeyMapping) GetKeyHelp(currentPane pane) string {
-	return "Tab: complete | Enter: execute | ↑↓: scroll output | Ctrl-C: quit"
+	return strings.Join([]string{
+		km.keyForAction("input", ActionTabComplete, "Tab") + ": complete",
+		km.keyForAction("input", ActionExecuteCommand, "Enter") + ": execute",
+		km.keyForAction("global", ActionScrollUp, "↑") + "/" + km.keyForAction("global", ActionScrollDown, "↓") + ": scroll output",
+		km.keyForAction("global", ActionQuit, "Ctrl-C") + ": quit",
+	}, " | ")
 }
 
-// UpdateKeyMapping allows runtime modification of key bindings
+// UpdateKeyMapping allows runtime modification of key bindings. context
+// may be "global", "input", or any other name, which is created in
+// Contexts on first use.
 func (km *KeyMapping) UpdateKeyMapping(context string, key tea.KeyType, action KeyAction) bool {
 	var targetMap map[tea.KeyType]KeyAction
-	
+
 	switch context {
 	case "global":
 		targetMap = km.Global
@@ -120,42 +232,77 @@ func (km *KeyMapping) UpdateKeyMapping(context string, key tea.KeyType, action K
 	case "left", "right":
 		return false // No longer supported in minimal interface
 	default:
-		return false
+		if km.Contexts == nil {
+			km.Contexts = make(map[string]map[tea.KeyType]KeyAction)
+		}
+		if km.Contexts[context] == nil {
+			km.Contexts[context] = make(map[tea.KeyType]KeyAction)
+		}
+		targetMap = km.Contexts[context]
 	}
-	
+
 	if action == ActionNone {
 		delete(targetMap, key)
 	} else {
 		targetMap[key] = action
 	}
-	
+
 	return true
 }
 
-// GetAllMappings returns a formatted summary of key mappings for minimal interface
+// UpdateRuneMapping is UpdateKeyMapping's counterpart for single-rune
+// bindings (e.g. "h"/"j"/"k"/"l"), which aren't representable as a
+// tea.KeyType.
+func (km *KeyMapping) UpdateRuneMapping(context, r string, action KeyAction) {
+	if km.Runes == nil {
+		km.Runes = make(map[string]map[string]KeyAction)
+	}
+	if km.Runes[context] == nil {
+		km.Runes[context] = make(map[string]KeyAction)
+	}
+	if action == ActionNone {
+		delete(km.Runes[context], r)
+	} else {
+		km.Runes[context][r] = action
+	}
+}
+
+// GetAllMappings returns a formatted summary of key mappings for minimal
+// interface, including any extra contexts and rune bindings a user
+// config added.
 func (km *KeyMapping) GetAllMappings() []string {
 	var lines []string
-	
-	lines = append(lines, "MINIMAL INTERFACE KEY MAPPINGS")
-	lines = append(lines, "")
-	
-	// Global keys
-	lines = append(lines, "Global:")
-	lines = append(lines, "  ↑: scroll output up")
-	lines = append(lines, "  ↓: scroll output down")
-	for key, action := range km.Global {
-		if key != tea.KeyUp && key != tea.KeyDown {
-			lines = append(lines, fmt.Sprintf("  %s: %s", keyTypeToString(key), string(action)))
+	lines = append(lines, "KEY MAPPINGS")
+
+	lines = append(lines, formatContextMappings("Global", km.Global, km.Runes["global"])...)
+	lines = append(lines, formatContextMappings("Input", km.InputPanel, km.Runes["input"])...)
+
+	for _, ctx := range sortedKeys(km.Contexts) {
+		lines = append(lines, formatContextMappings(ctx, km.Contexts[ctx], km.Runes[ctx])...)
+	}
+	for _, ctx := range sortedKeys(km.Runes) {
+		if ctx == "global" || ctx == "input" {
+			continue
+		}
+		if _, hasKeyTypes := km.Contexts[ctx]; hasKeyTypes {
+			continue // already printed above alongside its KeyType bindings
 		}
+		lines = append(lines, formatContextMappings(ctx, nil, km.Runes[ctx])...)
 	}
-	lines = append(lines, "")
-	
-	// Input keys
-	lines = append(lines, "Input:")
-	for key, action := range km.InputPanel {
-		lines = append(lines, fmt.Sprintf("  %s: %s", keyTypeToString(key), string(action)))
+
+	return lines
+}
+
+// formatContextMappings renders one context's section: a header
+// followed by a sorted, deterministic line per binding.
+func formatContextMappings(name string, keyTypes map[tea.KeyType]KeyAction, runes map[string]KeyAction) []string {
+	lines := []string{"", name + ":"}
+	for _, key := range sortedKeyTypeKeys(keyTypes) {
+		lines = append(lines, fmt.Sprintf("  %s: %s", keyTypeToString(key), string(keyTypes[key])))
+	}
+	for _, r := range sortedKeys(runes) {
+		lines = append(lines, fmt.Sprintf("  %s: %s", r, string(runes[r])))
 	}
-	
 	return lines
 }
 
@@ -199,4 +346,4 @@ func keyTypeToString(keyType tea.KeyType) string {
 
 func (km *KeyMapping) IsNavigationKey(keyMsg tea.KeyMsg) bool {
 	return false // No navigation in minimal interface
-}
\ No newline at end of file
+}
@@ -0,0 +1,165 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+const keybindingsRelPath = ".config/devops/keybindings.yaml"
+
+// keybindingsFile is the on-disk YAML shape for user keybinding
+// overrides: Contexts holds tea.KeyType-style bindings (key names like
+// "enter", "ctrl+p") per context, Runes holds single-character bindings
+// (e.g. "h", "j") per context. "global" and "input" are the two built-in
+// contexts; any other name (e.g. "copy_mode") becomes a KeyMapping
+// context.
+type keybindingsFile struct {
+	Contexts map[string]map[string]string `yaml:"contexts"`
+	Runes    map[string]map[string]string `yaml:"runes"`
+}
+
+// KeybindingsConfigPath returns where user keybinding overrides are read
+// from: $TUBES_DIR/keybindings.yaml if TUBES_DIR is set, else
+// ~/.config/devops/keybindings.yaml.
+func KeybindingsConfigPath() (string, error) {
+	if root := os.Getenv("TUBES_DIR"); root != "" {
+		return filepath.Join(root, "keybindings.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, keybindingsRelPath), nil
+}
+
+// LoadKeyMapping builds the default KeyMapping and, if path exists,
+// layers the user's overrides on top of it. A missing file is not an
+// error - it just means no overrides. Parse and validation problems
+// (unknown key names, conflicting bindings) are returned as errs rather
+// than failing the load, so a typo in one binding doesn't take down the
+// whole keymap.
+func LoadKeyMapping(path string) (*KeyMapping, []error) {
+	km := NewKeyMapping()
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return km, nil
+	}
+	if err != nil {
+		return km, []error{err}
+	}
+
+	var cfg keybindingsFile
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return km, []error{fmt.Errorf("parsing %s: %w", path, err)}
+	}
+
+	errs := applyKeybindingsFile(km, &cfg)
+	return km, errs
+}
+
+// Reload re-reads path and replaces km's bindings in place with the
+// result, so callers holding a *KeyMapping see the update without
+// needing a new pointer (e.g. a "/reload-keys" command).
+func (km *KeyMapping) Reload(path string) []error {
+	fresh, errs := LoadKeyMapping(path)
+	*km = *fresh
+	return errs
+}
+
+// applyKeybindingsFile validates and layers cfg's bindings onto km,
+// collecting one error per problem encountered (unknown key name,
+// duplicate binding within a context) without aborting on the first.
+func applyKeybindingsFile(km *KeyMapping, cfg *keybindingsFile) []error {
+	var errs []error
+
+	for ctx, bindings := range cfg.Contexts {
+		seen := make(map[tea.KeyType]string, len(bindings))
+		for keyName, action := range bindings {
+			kt, ok := keyTypeByName(keyName)
+			if !ok {
+				errs = append(errs, fmt.Errorf("context %q: unknown key %q", ctx, keyName))
+				continue
+			}
+			if prior, dup := seen[kt]; dup {
+				errs = append(errs, fmt.Errorf("context %q: key %q bound to both %q and %q", ctx, keyName, prior, action))
+				continue
+			}
+			seen[kt] = action
+			km.UpdateKeyMapping(ctx, kt, KeyAction(action))
+		}
+	}
+
+	for ctx, bindings := range cfg.Runes {
+		seen := make(map[string]string, len(bindings))
+		for r, action := range bindings {
+			if len([]rune(r)) != 1 {
+				errs = append(errs, fmt.Errorf("context %q: rune binding %q must be a single character", ctx, r))
+				continue
+			}
+			if prior, dup := seen[r]; dup {
+				errs = append(errs, fmt.Errorf("context %q: rune %q bound to both %q and %q", ctx, r, prior, action))
+				continue
+			}
+			seen[r] = action
+			km.UpdateRuneMapping(ctx, r, KeyAction(action))
+		}
+	}
+
+	return errs
+}
+
+// keyNameTable maps the config file's key names to tea.KeyType, covering
+// the bindings a user would plausibly want to remap.
+var keyNameTable = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"space":     tea.KeySpace,
+	"ctrl+c":    tea.KeyCtrlC,
+	"ctrl+p":    tea.KeyCtrlP,
+	"ctrl+n":    tea.KeyCtrlN,
+	"ctrl+y":    tea.KeyCtrlY,
+	"esc":       tea.KeyEscape,
+	"escape":    tea.KeyEscape,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+}
+
+// keyTypeByName looks up a config file key name (case-sensitive, as
+// written in keyNameTable) and reports whether it's recognized.
+func keyTypeByName(name string) (tea.KeyType, bool) {
+	kt, ok := keyNameTable[name]
+	return kt, ok
+}
+
+// sortedKeys returns m's keys in sorted order, for deterministic output
+// in GetAllMappings.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedKeyTypeKeys returns m's keys ordered by their display string, so
+// GetAllMappings output doesn't depend on Go's randomized map iteration.
+func sortedKeyTypeKeys(m map[tea.KeyType]KeyAction) []tea.KeyType {
+	keys := make([]tea.KeyType, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keyTypeToString(keys[i]) < keyTypeToString(keys[j])
+	})
+	return keys
+}
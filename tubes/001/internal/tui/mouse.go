@@ -0,0 +1,84 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// MouseAction is the mouse counterpart to KeyAction - a handful of
+// gesture-level actions rather than one per tea.MouseEventType, since
+// most mouse events (move, individual buttons) aren't independently
+// bindable the way keys are.
+type MouseAction string
+
+const (
+	MouseActionNone        MouseAction = "none"
+	MouseActionWheelUp     MouseAction = "wheel_up"
+	MouseActionWheelDown   MouseAction = "wheel_down"
+	MouseActionFocusPane   MouseAction = "focus_pane"
+	MouseActionDragSelect  MouseAction = "drag_select"
+)
+
+// GetMouseAction maps a tea.MouseMsg to a MouseAction via km.Mouse,
+// falling back to the built-in wheel/click/drag defaults below when the
+// event type has no explicit entry - mirroring how GetActionForMode
+// falls back to Global for keys.
+func (km *KeyMapping) GetMouseAction(msg tea.MouseMsg) MouseAction {
+	if action, ok := km.Mouse[msg.Type]; ok {
+		return action
+	}
+	switch msg.Type {
+	case tea.MouseWheelUp:
+		return MouseActionWheelUp
+	case tea.MouseWheelDown:
+		return MouseActionWheelDown
+	case tea.MouseLeft:
+		return MouseActionFocusPane
+	case tea.MouseMotion:
+		if msg.Button == tea.MouseButtonLeft {
+			return MouseActionDragSelect
+		}
+	}
+	return MouseActionNone
+}
+
+// ShouldPreventDefaultMouse reports whether msg should be consumed
+// rather than passed through - the mouse equivalent of
+// ShouldPreventDefault, used so e.g. wheel events inside the output pane
+// don't also bubble up to whatever's behind it.
+func (km *KeyMapping) ShouldPreventDefaultMouse(msg tea.MouseMsg) bool {
+	switch km.GetMouseAction(msg) {
+	case MouseActionWheelUp, MouseActionWheelDown, MouseActionDragSelect:
+		return true
+	default:
+		return false
+	}
+}
+
+// defaultMouseMapping is the out-of-the-box Mouse section installed by
+// NewKeyMapping: wheel scrolls the output pane using the same actions as
+// the Up/Down keys, click focuses a pane, and drag extends a copy-mode
+// selection.
+func defaultMouseMapping() map[tea.MouseEventType]MouseAction {
+	return map[tea.MouseEventType]MouseAction{
+		tea.MouseWheelUp:   MouseActionWheelUp,
+		tea.MouseWheelDown: MouseActionWheelDown,
+		tea.MouseLeft:      MouseActionFocusPane,
+	}
+}
+
+// mouseActionToKeyAction maps wheel gestures onto the same KeyActions
+// Up/Down already use, so callers that dispatch on KeyAction (e.g. the
+// output pane's scroll handler) don't need a parallel mouse-specific
+// code path for the cases that are really just "scroll".
+func mouseActionToKeyAction(ma MouseAction) KeyAction {
+	switch ma {
+	case MouseActionWheelUp:
+		return ActionScrollUp
+	case MouseActionWheelDown:
+		return ActionScrollDown
+	case MouseActionDragSelect:
+		return ActionCopySelect
+	default:
+		return ActionNone
+	}
+}
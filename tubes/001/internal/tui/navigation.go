@@ -1,11 +1,16 @@
 package tui
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/fsnotify/fsnotify"
 )
 
 // NavigationNode represents a node in the collapsible tree structure
@@ -24,8 +29,32 @@ type NavigationState struct {
 	Nodes       []*NavigationNode `json:"nodes"`
 	Selected    int               `json:"selected"`
 	VisibleList []int             `json:"-"` // Flattened view of expanded nodes
+
+	sections []sectionWatch // which section each file-backed dir feeds, for live refresh
+	watcher  *fsnotify.Watcher
+	changed  chan treeChangedMsg
+
+	pendingSnapshot *NavigationSnapshot // set by LoadState, consumed by ApplyPendingState
+}
+
+// sectionWatch ties a "section" node to the directory
+// BuildArchitectureTree populated its Children from, so a filesystem
+// event can recompute just that section's children instead of rebuilding
+// the whole tree. rebuild re-runs the same node-builder BuildArchitectureTree
+// called originally; extra is appended afterward for children that
+// aren't file-backed (the HTTP API section's live endpoint link).
+type sectionWatch struct {
+	node      *NavigationNode
+	dir       string
+	recursive bool
+	rebuild   func() []*NavigationNode
+	extra     []*NavigationNode
 }
 
+// treeChangedMsg is emitted after a filesystem event has patched the
+// tree in place, so the TUI update loop knows to redraw.
+type treeChangedMsg struct{}
+
 // NewNavigationState creates a new navigation state
 func NewNavigationState() *NavigationState {
 	return &NavigationState{
@@ -37,58 +66,38 @@ func NewNavigationState() *NavigationState {
 
 // BuildArchitectureTree creates the main architecture navigation tree
 func (ns *NavigationState) BuildArchitectureTree(projectRoot, tubesDir, apiPort string) {
+	reduxNode := &NavigationNode{Title: "Redux Pattern", Type: "section", Level: 1, Expanded: true}
+	reduxNode.Children = ns.createDocsNodes(projectRoot, "docs/tea", 2)
+
+	httpAPINode := &NavigationNode{Title: "HTTP API", Type: "section", Level: 1, Expanded: true}
+	apiEndpoint := &NavigationNode{
+		Title: fmt.Sprintf("localhost:%s/api/list", apiPort),
+		Type:  "file",
+		Level: 2,
+		Path:  fmt.Sprintf("http://localhost:%s/api/list", apiPort),
+	}
+	httpAPINode.Children = append(ns.createDocsNodes(projectRoot, "docs/api", 2), apiEndpoint)
+
+	sourceNode := &NavigationNode{Title: "Source Code", Type: "section", Level: 1, Expanded: false}
+	sourceNode.Children = ns.createSourceNodes(projectRoot, 2)
+
+	docsNode := &NavigationNode{Title: "Documentation", Type: "section", Level: 1, Expanded: false}
+	docsNode.Children = ns.createAllDocsNodes(projectRoot, 2)
+
 	ns.Nodes = []*NavigationNode{
 		{
 			Title:    "TUBES ARCHITECTURE",
 			Type:     "header",
 			Level:    0,
 			Expanded: true,
-			Children: []*NavigationNode{
-				{
-					Title:    "Redux Pattern",
-					Type:     "section",
-					Level:    1,
-					Expanded: true,
-					Children: ns.createDocsNodes(projectRoot, "docs/tea", 2),
-				},
-				{
-					Title:    "HTTP API",
-					Type:     "section", 
-					Level:    1,
-					Expanded: true,
-					Children: append(
-						ns.createDocsNodes(projectRoot, "docs/api", 2),
-						&NavigationNode{
-							Title: fmt.Sprintf("localhost:%s/api/list", apiPort),
-							Type:  "file",
-							Level: 2,
-							Path:  fmt.Sprintf("http://localhost:%s/api/list", apiPort),
-						},
-					),
-				},
-			},
+			Children: []*NavigationNode{reduxNode, httpAPINode},
 		},
 		{
 			Title:    "PROJECT STRUCTURE",
 			Type:     "header",
 			Level:    0,
 			Expanded: true,
-			Children: []*NavigationNode{
-				{
-					Title:    "Source Code",
-					Type:     "section",
-					Level:    1,
-					Expanded: false,
-					Children: ns.createSourceNodes(projectRoot, 2),
-				},
-				{
-					Title:    "Documentation",
-					Type:     "section",
-					Level:    1,
-					Expanded: false,
-					Children: ns.createAllDocsNodes(projectRoot, 2),
-				},
-			},
+			Children: []*NavigationNode{sourceNode, docsNode},
 		},
 		{
 			Title:    "COMMANDS",
@@ -104,12 +113,49 @@ func (ns *NavigationState) BuildArchitectureTree(projectRoot, tubesDir, apiPort
 			},
 		},
 	}
-	
+
+	// sections feeds StartWatching/refreshSectionFor: which directory each
+	// file-backed section's children came from, and how to recompute them.
+	ns.sections = []sectionWatch{
+		{
+			node: reduxNode,
+			dir:  filepath.Join(projectRoot, "docs/tea"),
+			rebuild: func() []*NavigationNode {
+				return ns.createDocsNodes(projectRoot, "docs/tea", 2)
+			},
+		},
+		{
+			node: httpAPINode,
+			dir:  filepath.Join(projectRoot, "docs/api"),
+			rebuild: func() []*NavigationNode {
+				return ns.createDocsNodes(projectRoot, "docs/api", 2)
+			},
+			extra: []*NavigationNode{apiEndpoint},
+		},
+		{
+			node: sourceNode,
+			dir:  filepath.Join(projectRoot, "internal/tui"),
+			rebuild: func() []*NavigationNode {
+				return ns.createSourceNodes(projectRoot, 2)
+			},
+		},
+		{
+			node:      docsNode,
+			dir:       filepath.Join(projectRoot, "docs"),
+			recursive: true,
+			rebuild: func() []*NavigationNode {
+				return ns.createAllDocsNodes(projectRoot, 2)
+			},
+		},
+	}
+
 	// Set parent relationships
 	ns.setParentRelationships()
-	
+
 	// Build initial visible list
 	ns.rebuildVisibleList()
+
+	ns.ApplyPendingState()
 }
 
 // createDocsNodes creates navigation nodes for documentation files
@@ -368,4 +414,343 @@ func (ns *NavigationState) GetSelectedPath() string {
 		return node.Path
 	}
 	return ""
+}
+
+// SelectByPath walks Nodes for a node whose Path matches path, expands
+// every ancestor so it's visible, rebuilds VisibleList, and moves
+// Selected onto it. Used both by refreshSectionFor (to keep the cursor
+// on the same file across a live refresh) and by /open (so opening a
+// file moves the tree's cursor to match, the same way jumping buffers
+// auto-selects a tree node). Returns false, leaving Selected untouched,
+// if no node has that path.
+func (ns *NavigationState) SelectByPath(path string) bool {
+	var target *NavigationNode
+	var find func([]*NavigationNode) bool
+	find = func(nodes []*NavigationNode) bool {
+		for _, n := range nodes {
+			if n.Path == path {
+				target = n
+				return true
+			}
+			if len(n.Children) > 0 && find(n.Children) {
+				return true
+			}
+		}
+		return false
+	}
+	if !find(ns.Nodes) || target == nil {
+		return false
+	}
+
+	for p := target.Parent; p != nil; p = p.Parent {
+		p.Expanded = true
+	}
+	ns.rebuildVisibleList()
+
+	targetIndex := ns.getNodeGlobalIndex(target)
+	for i, idx := range ns.VisibleList {
+		if idx == targetIndex {
+			ns.Selected = i
+			return true
+		}
+	}
+	return false
+}
+
+/* =========================
+   live filesystem refresh
+   ========================= */
+
+// StartWatching watches every directory BuildArchitectureTree drew a
+// section from (recursively for sections like Documentation) and
+// returns a tea.Cmd resolving to the first treeChangedMsg - wire it into
+// the update loop like:
+//
+//	cmd, err := nav.StartWatching()
+//	...
+//	case treeChangedMsg:
+//	    cmds = append(cmds, m.nav.waitForTreeChange()) // keep listening
+//
+// A missing directory is simply skipped rather than failing the whole
+// watch, since docs/tea or docs/api not existing yet is normal for a
+// fresh checkout.
+func (ns *NavigationState) StartWatching() (tea.Cmd, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sw := range ns.sections {
+		dirs := []string{sw.dir}
+		if sw.recursive {
+			dirs = subdirsOf(sw.dir)
+		}
+		for _, d := range dirs {
+			_ = w.Add(d)
+		}
+	}
+
+	ns.watcher = w
+	ns.changed = make(chan treeChangedMsg, 1)
+	go ns.watchLoop()
+
+	return ns.waitForTreeChange(), nil
+}
+
+// Close stops the filesystem watcher. Safe to call even if StartWatching
+// was never called or already failed.
+func (ns *NavigationState) Close() error {
+	if ns.watcher == nil {
+		return nil
+	}
+	return ns.watcher.Close()
+}
+
+// waitForTreeChange is the tea.Cmd that blocks for the next
+// treeChangedMsg - re-issue it after each one arrives so the program
+// keeps listening.
+func (ns *NavigationState) waitForTreeChange() tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ns.changed
+		if !ok {
+			return nil
+		}
+		return msg
+	}
+}
+
+// subdirsOf lists root and every directory beneath it, for watching a
+// recursive section (fsnotify itself never watches recursively). A
+// subdirectory created after startup won't be picked up until the next
+// restart - an acceptable gap for a docs tree that's rarely restructured
+// mid-session.
+func subdirsOf(root string) []string {
+	var dirs []string
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+	return dirs
+}
+
+// watchLoop reads watcher.Events, debouncing bursts (an editor save plus
+// its backup file, a `git checkout` touching several files at once) into
+// a single refresh ~150ms after the last one, then patches just the
+// affected sections in place and signals treeChangedMsg.
+func (ns *NavigationState) watchLoop() {
+	const debounce = 150 * time.Millisecond
+
+	pending := map[string]struct{}{}
+	var timer *time.Timer
+	flush := func() {
+		paths := make([]string, 0, len(pending))
+		for p := range pending {
+			paths = append(paths, p)
+		}
+		pending = map[string]struct{}{}
+
+		for _, p := range paths {
+			ns.refreshSectionFor(p)
+		}
+
+		select {
+		case ns.changed <- treeChangedMsg{}:
+		default:
+		}
+	}
+
+	for {
+		select {
+		case ev, ok := <-ns.watcher.Events:
+			if !ok {
+				return
+			}
+			if !isRelevantFSEvent(ev) {
+				continue
+			}
+			pending[ev.Name] = struct{}{}
+			if timer == nil {
+				timer = time.AfterFunc(debounce, flush)
+			} else {
+				timer.Reset(debounce)
+			}
+		case _, ok := <-ns.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// isRelevantFSEvent reports whether ev is worth a refresh: a .md or .go
+// file being created, removed, or renamed (not a plain write - the
+// content doesn't change the tree's shape), and not a hidden or editor
+// temp file.
+func isRelevantFSEvent(ev fsnotify.Event) bool {
+	if ev.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+		return false
+	}
+	base := filepath.Base(ev.Name)
+	if strings.HasPrefix(base, ".") || strings.HasSuffix(base, "~") ||
+		strings.HasSuffix(base, ".swp") || strings.HasSuffix(base, ".tmp") {
+		return false
+	}
+	return strings.HasSuffix(base, ".md") || strings.HasSuffix(base, ".go")
+}
+
+// refreshSectionFor recomputes the sectionWatch covering path's children
+// from disk - splicing in the new list and re-sorting via the same
+// rebuild closure BuildArchitectureTree used - then rebuilds VisibleList
+// and rebinds Selected onto whatever was selected before, preserving the
+// cursor across the patch instead of resetting it to the top.
+func (ns *NavigationState) refreshSectionFor(path string) {
+	sw := ns.sectionFor(path)
+	if sw == nil {
+		return
+	}
+
+	prevPath := ns.GetSelectedPath()
+
+	children := sw.rebuild()
+	children = append(children, sw.extra...)
+	sw.node.Children = children
+
+	ns.setParentRelationships()
+	ns.rebuildVisibleList()
+
+	if prevPath == "" || !ns.SelectByPath(prevPath) {
+		if ns.Selected >= len(ns.VisibleList) {
+			ns.Selected = len(ns.VisibleList) - 1
+		}
+		if ns.Selected < 0 {
+			ns.Selected = 0
+		}
+	}
+}
+
+// sectionFor returns the sectionWatch whose dir is the closest ancestor
+// of path - a non-recursive section only claims files directly inside
+// its dir, so a new file under docs/api doesn't get attributed to
+// Documentation's recursive walk of all of docs/ instead of HTTP API.
+func (ns *NavigationState) sectionFor(path string) *sectionWatch {
+	var best *sectionWatch
+	bestLen := -1
+	for i := range ns.sections {
+		sw := &ns.sections[i]
+		rel, err := filepath.Rel(sw.dir, path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		if !sw.recursive && strings.Contains(rel, string(filepath.Separator)) {
+			continue
+		}
+		if len(sw.dir) > bestLen {
+			best = sw
+			bestLen = len(sw.dir)
+		}
+	}
+	return best
+}
+
+/* =========================
+   persistent snapshots
+   ========================= */
+
+// NavigationSnapshot is what SaveState/LoadState persist: not the tree
+// itself (node pointers, and the files backing it, change across runs)
+// but enough to restore user intent once BuildArchitectureTree has run
+// again - matched back up by Title+Level+Path since pointers don't
+// survive a restart.
+type NavigationSnapshot struct {
+	Expanded []NavigationNodeKey `json:"expanded"`
+	Selected string              `json:"selected_path"`
+}
+
+// NavigationNodeKey identifies a node across restarts, since *NavigationNode
+// pointers don't survive one.
+type NavigationNodeKey struct {
+	Title string `json:"title"`
+	Level int    `json:"level"`
+	Path  string `json:"path"`
+}
+
+// SaveState writes ns's current expansion state and selected path to
+// path (typically ~/.config/tubes/session.json) as JSON.
+func (ns *NavigationState) SaveState(path string) error {
+	var snap NavigationSnapshot
+	var walk func([]*NavigationNode)
+	walk = func(nodes []*NavigationNode) {
+		for _, n := range nodes {
+			if n.Expanded {
+				snap.Expanded = append(snap.Expanded, NavigationNodeKey{Title: n.Title, Level: n.Level, Path: n.Path})
+			}
+			walk(n.Children)
+		}
+	}
+	walk(ns.Nodes)
+	snap.Selected = ns.GetSelectedPath()
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadState reads a snapshot written by SaveState into ns.pendingSnapshot
+// for ApplyPendingState to consume once BuildArchitectureTree has built
+// the tree - node pointers don't exist yet when LoadState itself runs.
+func (ns *NavigationState) LoadState(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var snap NavigationSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return err
+	}
+	ns.pendingSnapshot = &snap
+	return nil
+}
+
+// ApplyPendingState restores a snapshot loaded by LoadState onto the
+// freshly-built tree: expands every node whose Title+Level+Path matches
+// one that was expanded before, then repositions Selected onto the
+// previously selected path if it still exists. BuildArchitectureTree
+// calls this itself once it's done, so LoadState need only be called
+// before it.
+func (ns *NavigationState) ApplyPendingState() {
+	snap := ns.pendingSnapshot
+	if snap == nil {
+		return
+	}
+	ns.pendingSnapshot = nil
+
+	expanded := make(map[NavigationNodeKey]bool, len(snap.Expanded))
+	for _, k := range snap.Expanded {
+		expanded[k] = true
+	}
+	var walk func([]*NavigationNode)
+	walk = func(nodes []*NavigationNode) {
+		for _, n := range nodes {
+			if expanded[NavigationNodeKey{Title: n.Title, Level: n.Level, Path: n.Path}] {
+				n.Expanded = true
+			}
+			walk(n.Children)
+		}
+	}
+	walk(ns.Nodes)
+	ns.rebuildVisibleList()
+
+	if snap.Selected != "" {
+		ns.SelectByPath(snap.Selected)
+	}
 }
\ No newline at end of file
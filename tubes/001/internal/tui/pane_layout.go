@@ -0,0 +1,144 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PaneLayout owns the widths of a row of side-by-side panes as ratios
+// summing to 1, so a terminal resize just re-applies the same ratios
+// instead of losing a drag the user already made. A vertical split (the
+// feedback/output areas stacked on top of each other) is just another
+// PaneLayout with Vertical set, used to divide one pane's height instead
+// of the row's width.
+type PaneLayout struct {
+	Vertical bool
+
+	ratios      []float64
+	totalWidth  int
+	totalHeight int
+	minWidth    int
+
+	dragging     bool
+	dragBoundary int
+}
+
+// NewPaneLayout creates a PaneLayout for n panes with equal starting
+// ratios, enforcing minWidth on every pane (UIConfig.GetInputWidth's
+// minimum of 10, for the nav/output split).
+func NewPaneLayout(n, minWidth int) *PaneLayout {
+	ratios := make([]float64, n)
+	for i := range ratios {
+		ratios[i] = 1.0 / float64(n)
+	}
+	return &PaneLayout{ratios: ratios, minWidth: minWidth}
+}
+
+// SetSize records the layout's overall size - call on every
+// tea.WindowSizeMsg so Widths and the mouse hit-test stay correct.
+func (pl *PaneLayout) SetSize(width, height int) {
+	pl.totalWidth = width
+	pl.totalHeight = height
+}
+
+// Widths returns each pane's width in columns, enforcing minWidth and
+// making the last pane absorb any rounding remainder so the total always
+// equals totalWidth exactly - callers re-flow RenderLines(maxWidth) off
+// of these after every resize.
+func (pl *PaneLayout) Widths() []int {
+	widths := make([]int, len(pl.ratios))
+	used := 0
+	for i, r := range pl.ratios {
+		w := int(float64(pl.totalWidth) * r)
+		if w < pl.minWidth {
+			w = pl.minWidth
+		}
+		widths[i] = w
+		used += w
+	}
+	if n := len(widths); n > 0 {
+		widths[n-1] += pl.totalWidth - used
+		if widths[n-1] < pl.minWidth {
+			widths[n-1] = pl.minWidth
+		}
+	}
+	return widths
+}
+
+// Resize nudges the boundary between pane i and i+1 by deltaCols,
+// clamping so neither side goes below minWidth - what Ctrl+Left/Right
+// calls.
+func (pl *PaneLayout) Resize(boundary, deltaCols int) {
+	if pl.totalWidth == 0 || boundary < 0 || boundary >= len(pl.ratios)-1 {
+		return
+	}
+	deltaRatio := float64(deltaCols) / float64(pl.totalWidth)
+	minRatio := float64(pl.minWidth) / float64(pl.totalWidth)
+	next := pl.ratios[boundary] + deltaRatio
+	other := pl.ratios[boundary+1] - deltaRatio
+	if next < minRatio || other < minRatio {
+		return
+	}
+	pl.ratios[boundary] = next
+	pl.ratios[boundary+1] = other
+}
+
+// boundaryColumn returns the screen column of the divider between pane i
+// and i+1, for hit-testing a mouse event against it.
+func (pl *PaneLayout) boundaryColumn(boundary int) int {
+	widths := pl.Widths()
+	col := 0
+	for i := 0; i <= boundary && i < len(widths); i++ {
+		col += widths[i]
+	}
+	return col
+}
+
+// HandleMouse updates drag state from a tea.MouseMsg: a left-button
+// press on a divider column starts tracking, motion while dragging moves
+// it, release commits it. Returns true if msg was consumed, so the
+// caller doesn't also treat the press as a pane-focus click.
+func (pl *PaneLayout) HandleMouse(msg tea.MouseMsg) bool {
+	switch msg.Type {
+	case tea.MouseLeft:
+		for b := 0; b < len(pl.ratios)-1; b++ {
+			col := pl.boundaryColumn(b)
+			if msg.X == col || msg.X == col-1 {
+				pl.dragging = true
+				pl.dragBoundary = b
+				return true
+			}
+		}
+		return false
+	case tea.MouseMotion:
+		if !pl.dragging || msg.Button != tea.MouseButtonLeft {
+			return false
+		}
+		pl.Resize(pl.dragBoundary, msg.X-pl.boundaryColumn(pl.dragBoundary))
+		return true
+	case tea.MouseRelease:
+		if pl.dragging {
+			pl.dragging = false
+			return true
+		}
+	}
+	return false
+}
+
+// Ratios returns a copy of the current pane ratios, for persisting via
+// UIConfig.
+func (pl *PaneLayout) Ratios() []float64 {
+	out := make([]float64, len(pl.ratios))
+	copy(out, pl.ratios)
+	return out
+}
+
+// SetRatios restores ratios previously returned by Ratios - e.g. loaded
+// from a persisted UIConfig at startup. A length mismatch (the pane
+// count changed since the ratios were saved) is ignored rather than
+// partially applied.
+func (pl *PaneLayout) SetRatios(ratios []float64) {
+	if len(ratios) != len(pl.ratios) {
+		return
+	}
+	copy(pl.ratios, ratios)
+}
@@ -14,6 +14,7 @@ const (
 	viewMode inputMode = iota
 	commandMode
 	textMode
+	copyMode // entered via ActionCopyMode; hjkl/arrows move, v selects, y yanks
 )
 
 // pane represents different UI panes
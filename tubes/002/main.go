@@ -3,18 +3,28 @@ package main
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type command struct {
 	Name        string
 	Args        string
 	Description string
+	// isFile marks a synthetic entry produced by /find rather than a
+	// registered command; selecting it opens the file instead of running it.
+	isFile bool
+	path   string
+
+	// matched holds the rune indices highlighted by the last fuzzy match.
+	matched []int
 }
 
 var registry = []command{
@@ -23,16 +33,24 @@ var registry = []command{
 	{Name: "/new", Args: "[name]", Description: "Create a new buffer or resource"},
 	{Name: "/close", Args: "[name]", Description: "Close current or named buffer"},
 	{Name: "/quit", Args: "", Description: "Exit the program"},
+	{Name: "/find", Args: "<query>", Description: "Fuzzy-find a file under the working directory"},
 }
 
+var matchStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+
+// FileSelectedAction mirrors the Store-dispatched actions used elsewhere in
+// this project's TUIs; this standalone program has no Store, so selecting a
+// file just applies the action inline.
+type FileSelectedAction struct{ Path string }
+
 type model struct {
-	ta           textarea.Model
-	width        int
-	height       int
-	suggestions  []command
-	selIdx       int
-	showSuggest  bool
-	status       string
+	ta          textarea.Model
+	width       int
+	height      int
+	suggestions []command
+	selIdx      int
+	showSuggest bool
+	status      string
 }
 
 func initialModel() model {
@@ -82,7 +100,10 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		case "enter":
 			line := strings.TrimSpace(m.currentLine())
-			if strings.HasPrefix(line, "/") {
+			if m.showSuggest && len(m.suggestions) > 0 && m.suggestions[m.selIdx].isFile {
+				sel := m.suggestions[m.selIdx]
+				m.status = applyFileSelected(FileSelectedAction{Path: sel.path})
+			} else if strings.HasPrefix(line, "/") {
 				m.status = m.execCommand(line)
 			} else if line != "" {
 				m.status = fmt.Sprintf("echo: %s", line)
@@ -104,13 +125,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// update suggestions if the line starts with '/'
 	line := m.currentLine()
-	if strings.HasPrefix(line, "/") {
+	switch {
+	case strings.HasPrefix(line, "/find "):
+		query := strings.TrimPrefix(line, "/find ")
+		m.suggestions = fuzzyFindFiles(m.pwd(), query)
+		m.showSuggest = len(m.suggestions) > 0
+		if m.selIdx >= len(m.suggestions) {
+			m.selIdx = 0
+		}
+	case strings.HasPrefix(line, "/"):
 		m.suggestions = filterCommands(registry, line)
 		m.showSuggest = len(m.suggestions) > 0
 		if m.selIdx >= len(m.suggestions) {
 			m.selIdx = 0
 		}
-	} else {
+	default:
 		m.showSuggest = false
 		m.suggestions = nil
 		m.selIdx = 0
@@ -177,22 +206,162 @@ func (m model) currentLine() string {
 	return m.ta.Value()
 }
 
+// fuzzyMatch scores target against query as a fuzzy subsequence match, à la
+// sahilm/fuzzy: every rune of query must appear in target in order. Matches
+// at the start of a word or on a camelHump earn a bonus, consecutive matches
+// earn a growing bonus, and gaps between matches are penalized. Returns the
+// matched-rune indices (for highlighting) and whether query matched at all.
+func fuzzyMatch(query, target string) (score int, positions []int, ok bool) {
+	if query == "" {
+		return 0, nil, true
+	}
+	q := []rune(strings.ToLower(query))
+	t := []rune(target)
+	tl := []rune(strings.ToLower(target))
+
+	qi := 0
+	lastMatch := -2
+	consecutive := 0
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if tl[ti] != q[qi] {
+			continue
+		}
+		positions = append(positions, ti)
+
+		points := 1
+		if ti == 0 || isSeparator(t[ti-1]) {
+			points += 8 // start of word
+		} else if isUpper(t[ti]) && !isUpper(t[ti-1]) {
+			points += 6 // camelHump
+		}
+		if ti == lastMatch+1 {
+			consecutive++
+			points += consecutive * 3
+		} else {
+			consecutive = 0
+			if lastMatch >= 0 {
+				points -= (ti - lastMatch - 1) // gap penalty
+			}
+		}
+		score += points
+		lastMatch = ti
+		qi++
+	}
+	if qi < len(q) {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+func isSeparator(r rune) bool {
+	return r == '/' || r == '_' || r == '-' || r == '.' || r == ' '
+}
+
+func isUpper(r rune) bool { return r >= 'A' && r <= 'Z' }
+
+// filterCommands fuzzy-matches the first token of line against every
+// registered command name, sorting by descending score.
 func filterCommands(cmds []command, line string) []command {
-	// match prefix on the first token
 	token := line
 	if i := strings.IndexAny(line, " \t"); i >= 0 {
 		token = line[:i]
 	}
-	token = strings.ToLower(token)
-	var out []command
+
+	type scored struct {
+		cmd   command
+		score int
+	}
+	var out []scored
 	for _, c := range cmds {
-		name := strings.ToLower(c.Name)
-		if strings.HasPrefix(name, token) {
-			out = append(out, c)
+		score, positions, ok := fuzzyMatch(token, c.Name)
+		if !ok {
+			continue
+		}
+		c.matched = positions
+		out = append(out, scored{cmd: c, score: score})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].score != out[j].score {
+			return out[i].score > out[j].score
+		}
+		return out[i].cmd.Name < out[j].cmd.Name
+	})
+
+	result := make([]command, len(out))
+	for i, s := range out {
+		result[i] = s.cmd
+	}
+	return result
+}
+
+// fuzzyFindFiles walks root and fuzzy-matches query against each relative
+// path, returning file-opening suggestions sorted by descending score.
+func fuzzyFindFiles(root, query string) []command {
+	const maxResults = 20
+
+	type scored struct {
+		cmd   command
+		score int
+	}
+	var out []scored
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
 		}
+		score, positions, ok := fuzzyMatch(query, rel)
+		if !ok {
+			return nil
+		}
+		out = append(out, scored{cmd: command{Name: rel, isFile: true, path: path, matched: positions}, score: score})
+		return nil
+	})
+	sort.SliceStable(out, func(i, j int) bool { return out[i].score > out[j].score })
+	if len(out) > maxResults {
+		out = out[:maxResults]
+	}
+	result := make([]command, len(out))
+	for i, s := range out {
+		result[i] = s.cmd
+	}
+	return result
+}
+
+func (m model) pwd() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "."
 	}
-	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
-	return out
+	return wd
+}
+
+// applyFileSelected is the stand-in for Store.Dispatch(FileSelectedAction{})
+// in this standalone program, which has no reducer of its own.
+func applyFileSelected(a FileSelectedAction) string {
+	return fmt.Sprintf("opened: %s", a.Path)
+}
+
+// highlightMatches renders name with the runes at positions emphasized.
+func highlightMatches(name string, positions []int) string {
+	if len(positions) == 0 {
+		return name
+	}
+	matched := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		matched[p] = true
+	}
+	var b strings.Builder
+	for i, r := range []rune(name) {
+		if matched[i] {
+			b.WriteString(matchStyle.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
 }
 
 func renderSuggestions(sug []command, sel, width int) string {
@@ -203,7 +372,7 @@ func renderSuggestions(sug []command, sel, width int) string {
 	// Example: [/help] /new /open /quit
 	var parts []string
 	for i, c := range sug {
-		label := c.Name
+		label := highlightMatches(c.Name, c.matched)
 		if c.Args != "" {
 			label += " " + c.Args
 		}
@@ -213,14 +382,17 @@ func renderSuggestions(sug []command, sel, width int) string {
 		parts = append(parts, label)
 	}
 	line := strings.Join(parts, "  ")
+	desc := sug[sel].Description
+	if desc == "" && sug[sel].isFile {
+		desc = sug[sel].path
+	}
+	desc = " — " + desc
 	// If too long, append a short help for selected item on overflow line.
 	if len(line) > width {
 		lead := truncate(line, width)
-		desc := " — " + sug[sel].Description
 		return truncate(lead, width) + "\n" + truncate(desc, width)
 	}
 	// Fit description inline if possible.
-	desc := " — " + sug[sel].Description
 	combined := line + desc
 	return truncate(combined, width)
 }
@@ -264,7 +436,6 @@ func (m *model) execCommand(line string) string {
 	}
 }
 
-
 func splitArgs(s string) []string {
 	// simple split; no quoting
 	fs := strings.Fields(s)
@@ -0,0 +1,156 @@
+// Package fswatch recursively watches a directory tree for changes,
+// debouncing bursts of fsnotify events into one Event per settle period.
+package fswatch
+
+import (
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Event reports a debounced filesystem change at Path. Dir is true when
+// Path is a directory (e.g. a file was added or removed within it).
+type Event struct {
+	Path string
+	Dir  bool
+}
+
+// DefaultIgnore is the directory-name denylist applied when New's ignore
+// argument is nil.
+var DefaultIgnore = []string{".git", "node_modules"}
+
+// Watcher recursively watches root's directory tree (skipping any name in
+// its ignore list) plus any individually-added files.
+type Watcher struct {
+	fsw      *fsnotify.Watcher
+	events   chan Event
+	errs     chan error
+	done     chan struct{}
+	debounce time.Duration
+	ignore   map[string]bool
+}
+
+// New starts watching root's directory tree recursively, skipping any
+// directory named in ignore (DefaultIgnore if nil). Call Close when done.
+func New(root string, ignore []string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if ignore == nil {
+		ignore = DefaultIgnore
+	}
+	set := make(map[string]bool, len(ignore))
+	for _, name := range ignore {
+		set[name] = true
+	}
+
+	w := &Watcher{
+		fsw:      fsw,
+		events:   make(chan Event, 1),
+		errs:     make(chan error, 1),
+		done:     make(chan struct{}),
+		debounce: 100 * time.Millisecond,
+		ignore:   set,
+	}
+
+	if err := w.addTree(root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	go w.run()
+	return w, nil
+}
+
+// addTree walks root, adding every non-ignored directory to the
+// underlying fsnotify watcher. fsnotify only watches one directory level
+// at a time, so subdirectories created later are picked up in run via
+// their own fsnotify.Create event.
+func (w *Watcher) addTree(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && w.ignore[d.Name()] {
+			return filepath.SkipDir
+		}
+		return w.fsw.Add(path)
+	})
+}
+
+// AddFile watches an individual file (e.g. the currently open one) in
+// addition to the directory tree passed to New.
+func (w *Watcher) AddFile(path string) error {
+	return w.fsw.Add(path)
+}
+
+// Events returns the channel Event values are delivered on.
+func (w *Watcher) Events() <-chan Event { return w.events }
+
+// Close stops the watcher and releases the underlying fsnotify watcher.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.fsw.Close()
+}
+
+// run debounces bursts of fsnotify events (editors often write a file
+// several times per save, and a directory's own Create event fires
+// alongside the Create of whatever's inside it) into one Event per path
+// per settle period, so unrelated files don't coalesce into each other's
+// debounce window.
+func (w *Watcher) run() {
+	timers := make(map[string]*time.Timer)
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+
+			info, statErr := os.Stat(event.Name)
+			isDir := statErr == nil && info.IsDir()
+			if isDir && event.Op&fsnotify.Create != 0 {
+				_ = w.addTree(event.Name)
+			}
+
+			path := event.Name
+			if t, exists := timers[path]; exists {
+				t.Reset(w.debounce)
+				continue
+			}
+			timers[path] = time.AfterFunc(w.debounce, func() {
+				w.emit(Event{Path: path, Dir: isDir})
+			})
+
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("fswatch: %v", err)
+
+		case <-w.done:
+			for _, t := range timers {
+				t.Stop()
+			}
+			return
+		}
+	}
+}
+
+func (w *Watcher) emit(e Event) {
+	select {
+	case w.events <- e:
+	default:
+	}
+}
@@ -0,0 +1,128 @@
+// Package render renders file content for display in tui viewports.
+package render
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"tubes/internal/theme"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/glamour/ansi"
+)
+
+// Markdown renders Markdown files through glamour using a style derived
+// from the active theme. It caches the last render so repeated calls for
+// an unchanged (file, theme, width) triple - the common case on resize -
+// don't re-run glamour.
+type Markdown struct {
+	mu  sync.Mutex
+	key mdCacheKey
+	out string
+}
+
+type mdCacheKey struct {
+	path  string
+	mtime time.Time
+	theme string
+	width int
+}
+
+// RenderFile renders the Markdown file at path using t's style, word-wrapped
+// to width.
+func (m *Markdown) RenderFile(path string, t *theme.Theme, width int) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	key := mdCacheKey{path: path, mtime: info.ModTime(), theme: t.Name, width: width}
+
+	m.mu.Lock()
+	if key == m.key {
+		out := m.out
+		m.mu.Unlock()
+		return out, nil
+	}
+	m.mu.Unlock()
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	renderer, err := newRenderer(t, width)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := renderer.Render(string(source))
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	m.key, m.out = key, out
+	m.mu.Unlock()
+
+	return out, nil
+}
+
+// newRenderer builds a glamour renderer whose style comes entirely from
+// t.Tokens, so switching themes restyles rendered Markdown without needing
+// a different renderer.
+func newRenderer(t *theme.Theme, width int) (*glamour.TermRenderer, error) {
+	// Compile populates t.Derived (e.g. "border") as a side effect, even
+	// when the caller hasn't compiled t itself yet.
+	if _, err := theme.Compile(t); err != nil {
+		return nil, err
+	}
+	border := t.Derived["border"]
+
+	heading := ansi.StyleBlock{
+		StylePrimitive: ansi.StylePrimitive{
+			Color: strPtr(t.Tokens.Primary),
+			Bold:  boolPtr(true),
+		},
+	}
+	code := ansi.StylePrimitive{
+		Color:           strPtr(t.Tokens.TextMute),
+		BackgroundColor: strPtr(t.Tokens.SurfaceHi),
+	}
+
+	style := ansi.StyleConfig{
+		Document: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{Color: strPtr(t.Tokens.Text)},
+		},
+		BlockQuote: ansi.StyleBlock{
+			StylePrimitive: ansi.StylePrimitive{Color: strPtr(border), Italic: boolPtr(true)},
+			Indent:         uintPtr(1),
+		},
+		Heading: heading,
+		H1:      heading,
+		H2:      heading,
+		H3:      heading,
+		H4:      heading,
+		H5:      heading,
+		H6:      heading,
+		Text:    ansi.StylePrimitive{Color: strPtr(t.Tokens.Text)},
+		Strong:  ansi.StylePrimitive{Color: strPtr(t.Tokens.Accent), Bold: boolPtr(true)},
+		Emph:    ansi.StylePrimitive{Color: strPtr(t.Tokens.Accent), Italic: boolPtr(true)},
+
+		Link:     ansi.StylePrimitive{Color: strPtr(t.Tokens.Info), Underline: boolPtr(true)},
+		LinkText: ansi.StylePrimitive{Color: strPtr(t.Tokens.Info)},
+
+		Code:      ansi.StyleBlock{StylePrimitive: code},
+		CodeBlock: ansi.StyleCodeBlock{StyleBlock: ansi.StyleBlock{StylePrimitive: code}},
+	}
+
+	return glamour.NewTermRenderer(
+		glamour.WithStyles(style),
+		glamour.WithWordWrap(width),
+	)
+}
+
+func strPtr(s string) *string { return &s }
+func boolPtr(b bool) *bool    { return &b }
+func uintPtr(u uint) *uint    { return &u }
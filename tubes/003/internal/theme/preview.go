@@ -2,8 +2,9 @@ package theme
 
 import (
 	"fmt"
+	"sort"
 	"strings"
-	
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -73,6 +74,24 @@ func DetailedPreview(s *Styles) string {
 	
 	// Input example
 	lines = append(lines, s.Input.Render("/theme preview"))
-	
+
+	return strings.Join(lines, "\n")
+}
+
+// PreviewNamedStyles renders every named style in s.ByName as its own
+// swatch line, sorted alphabetically. Unlike Preview, which only covers
+// DetailedPreview's fixed handful of names, this picks up whatever a
+// styleset command has layered in too.
+func PreviewNamedStyles(s *Styles) string {
+	names := make([]string, 0, len(s.ByName))
+	for name := range s.ByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, s.ByName[name].Render(fmt.Sprintf(" %s ", name)))
+	}
 	return strings.Join(lines, "\n")
 }
\ No newline at end of file
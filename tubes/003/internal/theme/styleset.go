@@ -0,0 +1,191 @@
+package theme
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	DefaultStyleSetRelDir = "stylesets"
+	styleSetCurrentFile   = ".current_styleset"
+)
+
+// StyleSet is a file of named style overrides and selector rules that
+// layers on top of whatever Theme is active, without touching its color
+// Tokens - e.g. a "compact" styleset that tightens padding, or a
+// "high-contrast" one that bumps up Bold/Underline, switchable
+// independently of which theme supplies the palette.
+type StyleSet struct {
+	Version   int                 `yaml:"version"`
+	Name      string              `yaml:"name"`
+	Styles    map[string]StyleDef `yaml:"styles,omitempty"`
+	Selectors []SelectorRule      `yaml:"selectors,omitempty"`
+}
+
+// StyleSetDir returns the styleset directory, a sibling of the theme
+// directory returned by Dir, rooted the same way (TUBES_DIR, or the
+// working directory when that's unset).
+func StyleSetDir() (string, error) {
+	root := os.Getenv(DefaultDirEnv)
+	if root == "" {
+		var err error
+		root, err = os.Getwd()
+		if err != nil {
+			return "", err
+		}
+	}
+	d := filepath.Join(root, DefaultStyleSetRelDir)
+	if err := os.MkdirAll(d, 0o755); err != nil {
+		return "", err
+	}
+	return d, nil
+}
+
+// StyleSetPathFor returns the file path for a styleset name.
+func StyleSetPathFor(name string) (string, error) {
+	dir, err := StyleSetDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sanitize(name)+".yaml"), nil
+}
+
+// StyleSetCurrentPath returns the path to the file recording which
+// styleset is active, analogous to CurrentPath for themes.
+func StyleSetCurrentPath() (string, error) {
+	dir, err := StyleSetDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, styleSetCurrentFile), nil
+}
+
+// ListStyleSets returns all available styleset names.
+func ListStyleSets() ([]string, error) {
+	dir, err := StyleSetDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".yaml") {
+			out = append(out, strings.TrimSuffix(e.Name(), ".yaml"))
+		}
+	}
+	return out, nil
+}
+
+// LoadStyleSet loads a styleset by name.
+func LoadStyleSet(name string) (*StyleSet, error) {
+	p, err := StyleSetPathFor(name)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	var ss StyleSet
+	if err := yaml.Unmarshal(b, &ss); err != nil {
+		return nil, err
+	}
+	if ss.Name == "" {
+		ss.Name = name
+	}
+	return &ss, nil
+}
+
+// SaveStyleSet saves a styleset to disk.
+func SaveStyleSet(ss *StyleSet) error {
+	if ss == nil {
+		return errors.New("nil styleset")
+	}
+	if ss.Version == 0 {
+		ss.Version = SchemaVersion
+	}
+	p, err := StyleSetPathFor(ss.Name)
+	if err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(ss)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0o644)
+}
+
+// SetCurrentStyleSet records name as the active styleset.
+func SetCurrentStyleSet(name string) error {
+	p, err := StyleSetCurrentPath()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p, []byte(sanitize(name)), 0o644)
+}
+
+// GetCurrentStyleSet returns the active styleset's name, or "" if none
+// has been selected yet - not an error, just "no overlay active".
+func GetCurrentStyleSet() (string, error) {
+	p, err := StyleSetCurrentPath()
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(p)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// ApplyStyleSet layers ss's style overrides and selector rules on top of
+// base, producing a new compiled Styles with the same token colors but
+// ss's overrides winning and its selectors checked after base's. A nil
+// ss returns base unchanged.
+func ApplyStyleSet(base *Styles, ss *StyleSet) *Styles {
+	if ss == nil {
+		return base
+	}
+
+	merged := make(map[string]StyleDef, len(base.base)+len(ss.Styles))
+	for k, v := range base.base {
+		merged[k] = v
+	}
+	for k, v := range ss.Styles {
+		merged[k] = mergeStyleDef(merged[k], v)
+	}
+
+	styles := make(map[string]lipgloss.Style, len(merged))
+	for k, v := range merged {
+		styles[k] = buildStyle(v)
+	}
+
+	selectors := make([]compiledSelector, 0, len(base.selectors)+len(ss.Selectors))
+	selectors = append(selectors, base.selectors...)
+	selectors = append(selectors, compileSelectors(ss.Selectors)...)
+
+	return &Styles{
+		ByName:    styles,
+		Header:    styles["header"],
+		Sidebar:   styles["sidebar"],
+		Main:      styles["main"],
+		Input:     styles["input"],
+		Error:     styles["error"],
+		Warn:      styles["warn"],
+		Ok:        styles["ok"],
+		Info:      styles["info"],
+		base:      merged,
+		selectors: selectors,
+	}
+}
@@ -0,0 +1,120 @@
+package theme
+
+import (
+	"log"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// StyleSetReloadedMsg carries the result of a debounced reload triggered
+// by a StyleSetWatcher. Err is set when GetCurrentStyleSet/LoadStyleSet
+// failed; StyleSet is nil when no styleset is active, which isn't an
+// error - it just means nothing's layered on top of the theme.
+type StyleSetReloadedMsg struct {
+	Name     string
+	StyleSet *StyleSet
+	Err      error
+}
+
+// StyleSetWatcher watches a styleset directory (normally the result of
+// StyleSetDir) for changes to styleset YAML files or the
+// .current_styleset file, and delivers a debounced StyleSetReloadedMsg
+// on Events() whenever the active styleset changes on disk - the
+// styleset counterpart to Watcher.
+type StyleSetWatcher struct {
+	fsw      *fsnotify.Watcher
+	events   chan StyleSetReloadedMsg
+	done     chan struct{}
+	debounce time.Duration
+}
+
+// NewStyleSetWatcher starts watching dir. Call Close when done.
+func NewStyleSetWatcher(dir string) (*StyleSetWatcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fsw.Add(dir); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	w := &StyleSetWatcher{
+		fsw:      fsw,
+		events:   make(chan StyleSetReloadedMsg, 1),
+		done:     make(chan struct{}),
+		debounce: 150 * time.Millisecond,
+	}
+	go w.run()
+	return w, nil
+}
+
+// Events returns the channel StyleSetReloadedMsg values are delivered on.
+func (w *StyleSetWatcher) Events() <-chan StyleSetReloadedMsg { return w.events }
+
+// Close stops the watcher and releases the underlying fsnotify watcher.
+func (w *StyleSetWatcher) Close() error {
+	close(w.done)
+	return w.fsw.Close()
+}
+
+// run debounces bursts of fsnotify events into a single reload, the same
+// pattern as Watcher.run.
+func (w *StyleSetWatcher) run() {
+	var timer *time.Timer
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if timer == nil {
+				timer = time.AfterFunc(w.debounce, w.reload)
+			} else {
+				timer.Reset(w.debounce)
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("styleset watcher: %v", err)
+		case <-w.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+	}
+}
+
+// reload loads whichever styleset is current and delivers the result on
+// Events, dropping it if a previous reload is still waiting to be picked
+// up - the next event will trigger another reload anyway.
+func (w *StyleSetWatcher) reload() {
+	name, err := GetCurrentStyleSet()
+	if err != nil {
+		w.emit(StyleSetReloadedMsg{Err: err})
+		return
+	}
+	if name == "" {
+		w.emit(StyleSetReloadedMsg{})
+		return
+	}
+	ss, err := LoadStyleSet(name)
+	if err != nil {
+		w.emit(StyleSetReloadedMsg{Name: name, Err: err})
+		return
+	}
+	w.emit(StyleSetReloadedMsg{Name: name, StyleSet: ss})
+}
+
+func (w *StyleSetWatcher) emit(msg StyleSetReloadedMsg) {
+	select {
+	case w.events <- msg:
+	default:
+	}
+}
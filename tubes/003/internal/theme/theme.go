@@ -52,12 +52,29 @@ type StyleDef struct {
 
 // Theme represents a complete theme configuration
 type Theme struct {
-	Version int                `yaml:"version"`
-	Name    string             `yaml:"name"`
-	Updated time.Time          `yaml:"updated"`
-	Tokens  Tokens             `yaml:"tokens"`
-	Derived map[string]string  `yaml:"derived,omitempty"` // optional precomputed variants
-	Styles  map[string]StyleDef `yaml:"styles,omitempty"`  // named UI styles
+	Version   int                 `yaml:"version"`
+	Name      string              `yaml:"name"`
+	Updated   time.Time           `yaml:"updated"`
+	Tokens    Tokens              `yaml:"tokens"`
+	Derived   map[string]string   `yaml:"derived,omitempty"`   // optional precomputed variants
+	Styles    map[string]StyleDef `yaml:"styles,omitempty"`    // named UI styles
+	Selectors []SelectorRule      `yaml:"selectors,omitempty"` // context-aware overlays, see Styles.Resolve
+}
+
+// SelectorRule overlays Style onto whatever base style matches Match when
+// resolved via Styles.Resolve. Match is a dot-separated path, optionally
+// ending in an "attr=value" clause checked against the caller's context
+// instead of the resolved key:
+//
+//   - "sidebar.item.selected" matches the literal key "sidebar.item.selected"
+//     (or any key nested under it)
+//   - "main.error" matches the literal key "main.error"
+//   - "status.mode=tasks" matches key "status" when ctx["mode"] == "tasks"
+//   - "sidebar.item.status=error" matches key "sidebar.item" when
+//     ctx["status"] == "error"
+type SelectorRule struct {
+	Match string   `yaml:"match"`
+	Style StyleDef `yaml:"style"`
 }
 
 // Styles is the compiled lipgloss styles ready for rendering
@@ -72,6 +89,9 @@ type Styles struct {
 	Warn    lipgloss.Style
 	Ok      lipgloss.Style
 	Info    lipgloss.Style
+
+	base      map[string]StyleDef
+	selectors []compiledSelector
 }
 
 // Dir returns the theme directory path
@@ -211,101 +231,134 @@ func Compile(t *Theme) (*Styles, error) {
 	if t == nil {
 		return nil, errors.New("nil theme")
 	}
-	
+
 	// Derive missing tokens if requested
 	derived := ensureDerived(t)
-	
+
 	// Build styles
 	styles := make(map[string]lipgloss.Style, len(t.Styles)+8)
 
-	// Helper functions
-	asColor := func(x string) lipgloss.Color {
-		return lipgloss.Color(x) // termenv will downsample if needed
-	}
-	
-	build := func(sd StyleDef) lipgloss.Style {
-		st := lipgloss.NewStyle()
-		if sd.FG != "" {
-			st = st.Foreground(asColor(sd.FG))
-		}
-		if sd.BG != "" {
-			st = st.Background(asColor(sd.BG))
-		}
-		if sd.Border != "" {
-			st = st.BorderForeground(asColor(sd.Border)).BorderStyle(lipgloss.NormalBorder())
-		}
-		if len(sd.Padding) == 4 {
-			st = st.Padding(sd.Padding[0], sd.Padding[1], sd.Padding[2], sd.Padding[3])
-		}
-		if len(sd.Margin) == 4 {
-			st = st.Margin(sd.Margin[0], sd.Margin[1], sd.Margin[2], sd.Margin[3])
-		}
-		if sd.Bold {
-			st = st.Bold(true)
-		}
-		if sd.Faint {
-			st = st.Faint(true)
-		}
-		if sd.Italic {
-			st = st.Italic(true)
-		}
-		if sd.Underline {
-			st = st.Underline(true)
-		}
-		return st
-	}
-
 	// Default styles if not provided
 	def := map[string]StyleDef{
-		"top_status":   {FG: t.Tokens.TextMute, BG: t.Tokens.Surface, Faint: true},
-		"sidebar":      {FG: t.Tokens.TextMute, BG: t.Tokens.Surface},
-		"main":         {FG: t.Tokens.Text, BG: t.Tokens.Surface},
-		"input":        {FG: t.Tokens.Text, BG: t.Tokens.Surface, Border: derived["border"]},
+		"top_status":     {FG: t.Tokens.TextMute, BG: t.Tokens.Surface, Faint: true},
+		"sidebar":        {FG: t.Tokens.TextMute, BG: t.Tokens.Surface},
+		"main":           {FG: t.Tokens.Text, BG: t.Tokens.Surface},
+		"input":          {FG: t.Tokens.Text, BG: t.Tokens.Surface, Border: derived["border"]},
 		"input_noborder": {FG: t.Tokens.Text, BG: t.Tokens.Surface},
-		"feedback":     {FG: t.Tokens.TextMute, BG: t.Tokens.Surface, Faint: true},
-		"error":        {FG: t.Tokens.Surface, BG: t.Tokens.Error, Bold: true},
-		"warn":         {FG: t.Tokens.Surface, BG: t.Tokens.Warn, Bold: true},
-		"ok":           {FG: t.Tokens.Surface, BG: t.Tokens.Ok, Bold: true},
-		"info":         {FG: t.Tokens.Surface, BG: t.Tokens.Info, Bold: true},
+		"feedback":       {FG: t.Tokens.TextMute, BG: t.Tokens.Surface, Faint: true},
+		"error":          {FG: t.Tokens.Surface, BG: t.Tokens.Error, Bold: true},
+		"warn":           {FG: t.Tokens.Surface, BG: t.Tokens.Warn, Bold: true},
+		"ok":             {FG: t.Tokens.Surface, BG: t.Tokens.Ok, Bold: true},
+		"info":           {FG: t.Tokens.Surface, BG: t.Tokens.Info, Bold: true},
 	}
 
 	// Merge user overrides
 	for k, v := range t.Styles {
 		def[k] = v
 	}
-	
+
 	// Compile all styles
 	for k, v := range def {
-		styles[k] = build(v)
+		styles[k] = buildStyle(v)
 	}
 
 	return &Styles{
-		ByName:  styles,
-		Header:  styles["header"],
-		Sidebar: styles["sidebar"],
-		Main:    styles["main"],
-		Input:   styles["input"],
-		Error:   styles["error"],
-		Warn:    styles["warn"],
-		Ok:      styles["ok"],
-		Info:    styles["info"],
+		ByName:    styles,
+		Header:    styles["header"],
+		Sidebar:   styles["sidebar"],
+		Main:      styles["main"],
+		Input:     styles["input"],
+		Error:     styles["error"],
+		Warn:      styles["warn"],
+		Ok:        styles["ok"],
+		Info:      styles["info"],
+		base:      def,
+		selectors: compileSelectors(t.Selectors),
 	}, nil
 }
 
+// buildStyle turns a partial StyleDef into a lipgloss.Style, applying only
+// the fields that are actually set.
+func buildStyle(sd StyleDef) lipgloss.Style {
+	st := lipgloss.NewStyle()
+	if sd.FG != "" {
+		st = st.Foreground(lipgloss.Color(sd.FG)) // termenv will downsample if needed
+	}
+	if sd.BG != "" {
+		st = st.Background(lipgloss.Color(sd.BG))
+	}
+	if sd.Border != "" {
+		st = st.BorderForeground(lipgloss.Color(sd.Border)).BorderStyle(lipgloss.NormalBorder())
+	}
+	if len(sd.Padding) == 4 {
+		st = st.Padding(sd.Padding[0], sd.Padding[1], sd.Padding[2], sd.Padding[3])
+	}
+	if len(sd.Margin) == 4 {
+		st = st.Margin(sd.Margin[0], sd.Margin[1], sd.Margin[2], sd.Margin[3])
+	}
+	if sd.Bold {
+		st = st.Bold(true)
+	}
+	if sd.Faint {
+		st = st.Faint(true)
+	}
+	if sd.Italic {
+		st = st.Italic(true)
+	}
+	if sd.Underline {
+		st = st.Underline(true)
+	}
+	return st
+}
+
+// mergeStyleDef layers overlay's set fields on top of base, leaving
+// whatever overlay doesn't specify untouched.
+func mergeStyleDef(base, overlay StyleDef) StyleDef {
+	out := base
+	if overlay.FG != "" {
+		out.FG = overlay.FG
+	}
+	if overlay.BG != "" {
+		out.BG = overlay.BG
+	}
+	if overlay.Border != "" {
+		out.Border = overlay.Border
+	}
+	if overlay.Bold {
+		out.Bold = true
+	}
+	if overlay.Faint {
+		out.Faint = true
+	}
+	if overlay.Italic {
+		out.Italic = true
+	}
+	if overlay.Underline {
+		out.Underline = true
+	}
+	if len(overlay.Padding) == 4 {
+		out.Padding = overlay.Padding
+	}
+	if len(overlay.Margin) == 4 {
+		out.Margin = overlay.Margin
+	}
+	return out
+}
+
 // ensureDerived creates common variants if missing
 func ensureDerived(t *Theme) map[string]string {
 	if t.Derived == nil {
 		t.Derived = map[string]string{}
 	}
 	out := t.Derived
-	
+
 	if _, ok := out["border"]; !ok {
 		out["border"] = adjustLCH(t.Tokens.SurfaceHi, -0.10, 0, 0)
 	}
 	if _, ok := out["input_bg"]; !ok {
 		out["input_bg"] = adjustLCH(t.Tokens.Surface, -0.04, 0, 0)
 	}
-	
+
 	return out
 }
 
@@ -315,9 +368,9 @@ func adjustLCH(hex string, dL, dC, dH float64) string {
 	if err != nil {
 		return hex
 	}
-	
+
 	L, a, b := c.Lab()
-	
+
 	// Treat dC as scale on chroma via a,b vector
 	C := math.Sqrt(a*a + b*b)
 	theta := math.Atan2(b, a) + dH
@@ -325,7 +378,7 @@ func adjustLCH(hex string, dL, dC, dH float64) string {
 	L = clamp(L+dL, 0, 1)
 	a = C * math.Cos(theta)
 	b = C * math.Sin(theta)
-	
+
 	return colorful.Lab(L, a, b).Clamped().Hex()
 }
 
@@ -338,4 +391,70 @@ func clamp(x, lo, hi float64) float64 {
 		return hi
 	}
 	return x
-}
\ No newline at end of file
+}
+
+// compiledSelector is a SelectorRule parsed into the two things Resolve
+// needs to check quickly: the key prefix to match, and the optional
+// ctx attribute predicate.
+type compiledSelector struct {
+	keyPrefix string
+	attrName  string
+	attrValue string
+	hasAttr   bool
+	style     StyleDef
+}
+
+// compileSelectors parses every rule's Match once, at Compile time, so
+// Resolve never re-parses a selector on the render hot path.
+func compileSelectors(rules []SelectorRule) []compiledSelector {
+	out := make([]compiledSelector, 0, len(rules))
+	for _, r := range rules {
+		keyPrefix := r.Match
+		attrName, attrValue := "", ""
+		hasAttr := false
+
+		last := r.Match
+		if i := strings.LastIndex(r.Match, "."); i >= 0 {
+			last = r.Match[i+1:]
+		}
+		if eq := strings.Index(last, "="); eq >= 0 {
+			attrName, attrValue = last[:eq], last[eq+1:]
+			hasAttr = true
+			keyPrefix = strings.TrimSuffix(r.Match[:len(r.Match)-len(last)], ".")
+		}
+
+		out = append(out, compiledSelector{
+			keyPrefix: keyPrefix,
+			attrName:  attrName,
+			attrValue: attrValue,
+			hasAttr:   hasAttr,
+			style:     r.Style,
+		})
+	}
+	return out
+}
+
+func (s compiledSelector) matches(key string, ctx map[string]string) bool {
+	if s.keyPrefix != "" && key != s.keyPrefix && !strings.HasPrefix(key, s.keyPrefix+".") {
+		return false
+	}
+	if s.hasAttr && ctx[s.attrName] != s.attrValue {
+		return false
+	}
+	return true
+}
+
+// Resolve returns the lipgloss.Style for key, starting from its base style
+// (as set by Compile's defaults and any Theme.Styles override) and layering
+// every matching Theme.Selectors rule on top, in declaration order. ctx
+// carries whatever the caller's selectors key off - mode, focused panel,
+// module status, and so on.
+func (s *Styles) Resolve(key string, ctx map[string]string) lipgloss.Style {
+	def := s.base[key]
+	for _, sel := range s.selectors {
+		if sel.matches(key, ctx) {
+			def = mergeStyleDef(def, sel.style)
+		}
+	}
+	return buildStyle(def)
+}
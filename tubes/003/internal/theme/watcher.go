@@ -0,0 +1,121 @@
+package theme
+
+import (
+	"log"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ReloadedMsg carries the result of a debounced reload triggered by a
+// Watcher. Err is set when GetCurrent/Load/Compile failed; callers should
+// keep using whatever theme they already have in that case.
+type ReloadedMsg struct {
+	Name   string
+	Theme  *Theme
+	Styles *Styles
+	Err    error
+}
+
+// Watcher watches a theme directory (normally the result of Dir()) for
+// changes to theme YAML files or the .current file, and delivers a
+// debounced ReloadedMsg on Events() whenever the active theme changes on
+// disk - whether its own YAML was edited or .current was rewritten to
+// point at a different theme.
+type Watcher struct {
+	fsw      *fsnotify.Watcher
+	events   chan ReloadedMsg
+	done     chan struct{}
+	debounce time.Duration
+}
+
+// NewWatcher starts watching dir. Call Close when done.
+func NewWatcher(dir string) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := fsw.Add(dir); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+
+	w := &Watcher{
+		fsw:      fsw,
+		events:   make(chan ReloadedMsg, 1),
+		done:     make(chan struct{}),
+		debounce: 150 * time.Millisecond,
+	}
+	go w.run()
+	return w, nil
+}
+
+// Events returns the channel ReloadedMsg values are delivered on.
+func (w *Watcher) Events() <-chan ReloadedMsg { return w.events }
+
+// Close stops the watcher and releases the underlying fsnotify watcher.
+func (w *Watcher) Close() error {
+	close(w.done)
+	return w.fsw.Close()
+}
+
+// run debounces bursts of fsnotify events (editors often write a file
+// several times per save) into a single reload.
+func (w *Watcher) run() {
+	var timer *time.Timer
+	for {
+		select {
+		case event, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+				continue
+			}
+			if timer == nil {
+				timer = time.AfterFunc(w.debounce, w.reload)
+			} else {
+				timer.Reset(w.debounce)
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("theme watcher: %v", err)
+		case <-w.done:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		}
+	}
+}
+
+// reload loads and compiles the current theme and delivers the result on
+// Events, dropping it if a previous reload is still waiting to be picked
+// up - the next event will trigger another reload anyway.
+func (w *Watcher) reload() {
+	name, err := GetCurrent()
+	if err != nil {
+		w.emit(ReloadedMsg{Err: err})
+		return
+	}
+	t, err := Load(name)
+	if err != nil {
+		w.emit(ReloadedMsg{Name: name, Err: err})
+		return
+	}
+	styles, err := Compile(t)
+	if err != nil {
+		w.emit(ReloadedMsg{Name: name, Theme: t, Err: err})
+		return
+	}
+	w.emit(ReloadedMsg{Name: name, Theme: t, Styles: styles})
+}
+
+func (w *Watcher) emit(msg ReloadedMsg) {
+	select {
+	case w.events <- msg:
+	default:
+	}
+}
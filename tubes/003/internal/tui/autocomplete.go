@@ -0,0 +1,258 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Match is one ranked completion candidate: the candidate text, its fuzzy
+// score (higher is better), and the byte offsets into Text that matched
+// the input pattern - used to bold them when rendering.
+type Match struct {
+	Text      string
+	Score     int
+	Positions []int
+}
+
+// AutoComplete produces ranked completions for commands (by name) and
+// filenames (by scanning the working directory tree, in the same spirit
+// as tetra/tgo/go's FileTreePanel - which lives in an unrelated package
+// main and so can't be imported from here, hence the fresh walk below).
+type AutoComplete struct {
+	commands []string
+}
+
+// NewAutoComplete seeds completion over the given command names (see
+// CommandNames).
+func NewAutoComplete(commands []string) *AutoComplete {
+	return &AutoComplete{commands: commands}
+}
+
+// RankedCompletions scores every candidate relevant to input against its
+// fuzzy pattern and returns the matches in descending score order:
+// "/thm" ranks "/theme" highly, and "/open fo" ranks files containing
+// "fo" by whatever's typed after the command name.
+func (a *AutoComplete) RankedCompletions(input string) []Match {
+	cmd, rest, hasArg := splitCommandInput(input)
+
+	pattern := rest
+	var candidates []string
+	if !hasArg {
+		pattern = strings.TrimPrefix(cmd, "/")
+		for _, c := range a.commands {
+			candidates = append(candidates, "/"+c)
+		}
+	} else {
+		candidates = a.fileCandidates()
+	}
+
+	var matches []Match
+	for _, c := range candidates {
+		text := c
+		if !hasArg {
+			text = strings.TrimPrefix(c, "/")
+		}
+		score, positions, ok := fuzzyMatch(pattern, text)
+		if !ok {
+			continue
+		}
+		matches = append(matches, Match{Text: c, Score: score, Positions: positions})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches
+}
+
+// fileCandidates walks the working directory for filename completion.
+// Depth isn't capped, but the total count is, to keep this cheap on a
+// large tree.
+func (a *AutoComplete) fileCandidates() []string {
+	const maxFiles = 2000
+	var out []string
+	_ = filepath.WalkDir(".", func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if len(out) >= maxFiles {
+			return filepath.SkipAll
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		out = append(out, path)
+		return nil
+	})
+	return out
+}
+
+// GetNextCompletion returns input with its last token replaced by the
+// next fuzzy-ranked completion, cycling forward from fromIndex (-1 starts
+// at the top), plus that completion's index for the caller to pass back
+// in on the next cycle.
+func (a *AutoComplete) GetNextCompletion(input string, fromIndex int) (string, int) {
+	return a.cycle(input, fromIndex, 1)
+}
+
+// GetPrevCompletion is GetNextCompletion's reverse-direction counterpart.
+func (a *AutoComplete) GetPrevCompletion(input string, fromIndex int) (string, int) {
+	return a.cycle(input, fromIndex, -1)
+}
+
+func (a *AutoComplete) cycle(input string, fromIndex, step int) (string, int) {
+	matches := a.RankedCompletions(input)
+	if len(matches) == 0 {
+		return input, -1
+	}
+	idx := fromIndex + step
+	if idx < 0 {
+		idx = len(matches) - 1
+	}
+	if idx >= len(matches) {
+		idx = 0
+	}
+	return replaceLastToken(input, matches[idx].Text), idx
+}
+
+// GetCompletionHelp renders the top-ranked completions for input, for
+// display in the Feedback pane, with each match's matched characters
+// bolded.
+func (a *AutoComplete) GetCompletionHelp(input string) string {
+	const topN = 5
+	matches := a.RankedCompletions(input)
+	if len(matches) == 0 {
+		return "No completions"
+	}
+	if len(matches) > topN {
+		matches = matches[:topN]
+	}
+	bold := lipgloss.NewStyle().Bold(true)
+	lines := make([]string, 0, len(matches))
+	for _, m := range matches {
+		lines = append(lines, highlightMatch(m, bold))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitCommandInput splits input into its leading command token and the
+// remainder, reporting whether there's an argument being typed (anything
+// after the first whitespace-separated token).
+func splitCommandInput(input string) (cmd, rest string, hasArg bool) {
+	fields := strings.Fields(input)
+	if len(fields) == 0 {
+		return "", "", false
+	}
+	if !strings.Contains(input, " ") {
+		return fields[0], "", false
+	}
+	return fields[0], fields[len(fields)-1], true
+}
+
+// replaceLastToken replaces input's last whitespace-separated token with
+// completion, preserving everything before it.
+func replaceLastToken(input, completion string) string {
+	idx := strings.LastIndex(input, " ")
+	if idx < 0 {
+		return completion
+	}
+	return input[:idx+1] + completion
+}
+
+func highlightMatch(m Match, bold lipgloss.Style) string {
+	matched := make(map[int]bool, len(m.Positions))
+	for _, p := range m.Positions {
+		matched[p] = true
+	}
+	var b strings.Builder
+	for i, r := range m.Text {
+		if matched[i] {
+			b.WriteString(bold.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Fuzzy scoring, in the spirit of fzf/sahilm's algorithm: reward
+// consecutive matches, word-boundary starts and camelCase humps, and
+// penalize gaps between matched characters.
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+	bonusBoundary     = 8
+	bonusConsecutive  = 4
+	bonusCamel        = 8
+)
+
+// fuzzyMatch reports whether every rune of pattern appears in text, in
+// order (case-insensitively), and if so returns its score and the byte
+// offsets in text that matched.
+func fuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+
+	lowerText := strings.ToLower(text)
+	lowerPattern := strings.ToLower(pattern)
+
+	pi := 0
+	prevMatched := -2
+	for ti := 0; ti < len(lowerText) && pi < len(lowerPattern); ti++ {
+		if lowerText[ti] != lowerPattern[pi] {
+			continue
+		}
+
+		s := scoreMatch
+		if isWordBoundary(text, ti) {
+			s += bonusBoundary
+		}
+		if isCamelHump(text, ti) {
+			s += bonusCamel
+		}
+		switch {
+		case ti == prevMatched+1:
+			s += bonusConsecutive
+		case prevMatched >= 0:
+			gap := ti - prevMatched - 1
+			s += scoreGapStart + gap*scoreGapExtension
+		}
+
+		score += s
+		positions = append(positions, ti)
+		prevMatched = ti
+		pi++
+	}
+
+	if pi < len(lowerPattern) {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+func isWordBoundary(text string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	switch text[i-1] {
+	case '/', '.', '_', '-', ' ':
+		return true
+	default:
+		return false
+	}
+}
+
+func isCamelHump(text string, i int) bool {
+	if i == 0 {
+		return false
+	}
+	c, prev := text[i], text[i-1]
+	return c >= 'A' && c <= 'Z' && prev >= 'a' && prev <= 'z'
+}
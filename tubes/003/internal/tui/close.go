@@ -0,0 +1,17 @@
+package tui
+
+// Close releases the background watchers started by NewModel. There's no
+// App/entrypoint in this package yet to call it from tea.Program.Run's
+// return - it's here for that future caller, the same way
+// p.Run() cleanup is expected to work once one exists.
+func (m *Model) Close() {
+	if m.themeWatcher != nil {
+		_ = m.themeWatcher.Close()
+	}
+	if m.styleSetWatcher != nil {
+		_ = m.styleSetWatcher.Close()
+	}
+	if m.fsWatcher != nil {
+		_ = m.fsWatcher.Close()
+	}
+}
@@ -0,0 +1,264 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tubes/internal/theme"
+)
+
+// ArgKind identifies how a CommandSpec argument's raw text should be
+// coerced and completed.
+type ArgKind int
+
+const (
+	ArgString ArgKind = iota
+	ArgInt
+	ArgBool
+	ArgPath
+	ArgModuleID
+)
+
+func (k ArgKind) String() string {
+	switch k {
+	case ArgInt:
+		return "int"
+	case ArgBool:
+		return "bool"
+	case ArgPath:
+		return "path"
+	case ArgModuleID:
+		return "module_id"
+	default:
+		return "string"
+	}
+}
+
+// ArgSpec declares one positional argument a command accepts.
+type ArgSpec struct {
+	Name     string
+	Kind     ArgKind
+	Required bool
+	Default  string
+}
+
+// Args holds a command's coerced arguments, keyed by ArgSpec.Name.
+type Args map[string]any
+
+func (a Args) String(name string) string {
+	if s, ok := a[name].(string); ok {
+		return s
+	}
+	return ""
+}
+
+func (a Args) Int(name string) int {
+	if n, ok := a[name].(int); ok {
+		return n
+	}
+	return 0
+}
+
+func (a Args) Bool(name string) bool {
+	if b, ok := a[name].(bool); ok {
+		return b
+	}
+	return false
+}
+
+// CmdCtx is what a CommandSpec.Execute callback receives instead of the
+// raw *Model, so commands contributed by a module (via RegisterCommand)
+// depend only on this package's public surface.
+type CmdCtx struct {
+	Model *Model
+	Raw   string // full command line as typed, including the leading "/name"
+}
+
+// CommandSpec describes one registered command: its declared arguments
+// and the callback that runs it.
+type CommandSpec struct {
+	Name        string
+	Description string
+	Args        []ArgSpec
+	Execute     func(ctx *CmdCtx, args Args) tea.Cmd
+}
+
+// registry is the global command set, so a module can contribute commands
+// from its own Start() via RegisterCommand without touching
+// registerCommands or holding a reference to any *Model.
+var registry = map[string]CommandSpec{}
+
+// RegisterCommand adds spec to the global command registry under name,
+// overwriting any existing command of the same name.
+func RegisterCommand(name string, spec CommandSpec) {
+	spec.Name = name
+	registry[name] = spec
+}
+
+// LookupCommand returns the registered CommandSpec for name, if any.
+func LookupCommand(name string) (CommandSpec, bool) {
+	spec, ok := registry[name]
+	return spec, ok
+}
+
+// CommandNames returns every registered command name, sorted, for help
+// text and completion.
+func CommandNames() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CompletionsFor returns the candidate completions for the argument at
+// argIndex (0-based) of spec, given the prefix typed so far. ArgPath
+// completes against theme.List(), the only path-like namespace this
+// package knows about; ArgModuleID has no completions here, since this
+// package never imports a ModuleManager - a module registering a
+// module_id-typed arg is expected to supply its own completion once that
+// bridge exists.
+func CompletionsFor(spec CommandSpec, argIndex int, prefix string) []string {
+	if argIndex < 0 || argIndex >= len(spec.Args) {
+		return nil
+	}
+	if spec.Args[argIndex].Kind != ArgPath {
+		return nil
+	}
+	names, err := theme.List()
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, n := range names {
+		if strings.HasPrefix(n, prefix) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+// CommandHandler parses a raw input line against the global registry,
+// coerces its arguments per the matched CommandSpec, and dispatches to
+// Execute.
+type CommandHandler struct{}
+
+// Dispatch parses line (e.g. "/render foo.md"), validates and coerces its
+// arguments against the matched CommandSpec, and returns the resulting
+// tea.Cmd. Parse or coercion failures are surfaced as a styled status-bar
+// error instead of calling Execute.
+func (h CommandHandler) Dispatch(m *Model, line string) tea.Cmd {
+	name, rawArgs, ok := parseCommandLine(line)
+	if !ok {
+		return nil
+	}
+
+	spec, exists := registry[name]
+	if !exists {
+		return m.commandErrorCmd(fmt.Sprintf("Unknown command: %s", name))
+	}
+
+	args, err := coerceArgs(spec, rawArgs)
+	if err != nil {
+		return m.commandErrorCmd(fmt.Sprintf("/%s: %v", name, err))
+	}
+
+	ctx := &CmdCtx{Model: m, Raw: line}
+	return spec.Execute(ctx, args)
+}
+
+func parseCommandLine(line string) (name string, args []string, ok bool) {
+	parts := strings.Fields(strings.TrimSpace(line))
+	if len(parts) == 0 {
+		return "", nil, false
+	}
+	return strings.TrimPrefix(parts[0], "/"), parts[1:], true
+}
+
+// coerceArgs binds rawArgs positionally against spec.Args, applying
+// defaults and Kind-specific coercion, and erroring on a missing
+// Required argument or a value that doesn't parse as its Kind.
+func coerceArgs(spec CommandSpec, rawArgs []string) (Args, error) {
+	out := make(Args, len(spec.Args))
+	for i, a := range spec.Args {
+		var raw string
+		switch {
+		case i < len(rawArgs):
+			raw = rawArgs[i]
+		case a.Default != "":
+			raw = a.Default
+		case a.Required:
+			return nil, fmt.Errorf("missing required argument %q", a.Name)
+		default:
+			continue
+		}
+
+		v, err := coerceArg(a, raw)
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", a.Name, err)
+		}
+		out[a.Name] = v
+	}
+	return out, nil
+}
+
+func coerceArg(a ArgSpec, raw string) (any, error) {
+	switch a.Kind {
+	case ArgInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an int", raw)
+		}
+		return n, nil
+	case ArgBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a bool", raw)
+		}
+		return b, nil
+	default: // ArgString, ArgPath, ArgModuleID all pass the raw text through
+		return raw, nil
+	}
+}
+
+// commandErrorCmd renders msg behind a Styles.Error badge and returns the
+// tea.Cmd that surfaces it as an errorMsg.
+func (m *Model) commandErrorCmd(msg string) tea.Cmd {
+	badge := m.Styles.Error.Render(" ERROR ")
+	text := badge + " " + msg
+	return func() tea.Msg { return errorMsg(text) }
+}
+
+// formatHelp pretty-prints spec's ArgSpec table for "/help <cmd>".
+func formatHelp(spec CommandSpec) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "/%s - %s\n", spec.Name, spec.Description)
+	for _, a := range spec.Args {
+		req := "optional"
+		if a.Required {
+			req = "required"
+		}
+		fmt.Fprintf(&b, "  %-12s %-10s %s", a.Name, a.Kind, req)
+		if a.Default != "" {
+			fmt.Fprintf(&b, " (default %q)", a.Default)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// RunCommand invokes a registered command programmatically - for tests,
+// scripts, or later a JSON-RPC bridge - bypassing line parsing entirely.
+func (m *Model) RunCommand(name string, args Args) tea.Cmd {
+	spec, ok := LookupCommand(name)
+	if !ok {
+		return m.commandErrorCmd(fmt.Sprintf("Unknown command: %s", name))
+	}
+	ctx := &CmdCtx{Model: m, Raw: "/" + name}
+	return spec.Execute(ctx, args)
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"tubes/internal/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// registerCommands populates the global command registry. Only commands
+// implemented so far are registered here; unimplemented ones are added
+// alongside their features. A module can contribute further commands from
+// its own Start() via RegisterCommand, without touching this function.
+func (m *Model) registerCommands() {
+	RegisterCommand("render", CommandSpec{
+		Description: "Render a Markdown file into Main",
+		Args: []ArgSpec{
+			{Name: "file", Kind: ArgPath, Required: true},
+		},
+		Execute: cmdRender,
+	})
+	RegisterCommand("help", CommandSpec{
+		Description: "List commands, or describe one command's arguments",
+		Args: []ArgSpec{
+			{Name: "cmd", Kind: ArgString},
+		},
+		Execute: cmdHelp,
+	})
+	RegisterCommand("styleset", CommandSpec{
+		Description: "Switch the active styleset overlay, or clear it with no name",
+		Args: []ArgSpec{
+			{Name: "name", Kind: ArgString},
+		},
+		Execute: cmdStyleSet,
+	})
+	RegisterCommand("preview", CommandSpec{
+		Description: "Preview every named style in the sidebar",
+		Execute:     cmdPreview,
+	})
+}
+
+func cmdStyleSet(ctx *CmdCtx, args Args) tea.Cmd {
+	name := args.String("name")
+	if name == "" {
+		ctx.Model.StyleSet = nil
+		if err := theme.SetCurrentStyleSet(""); err != nil {
+			return ctx.Model.commandErrorCmd(fmt.Sprintf("styleset: %v", err))
+		}
+		base, err := theme.Compile(ctx.Model.Theme)
+		if err != nil {
+			return ctx.Model.commandErrorCmd(fmt.Sprintf("styleset: %v", err))
+		}
+		ctx.Model.Styles = base
+		ctx.Model.updateComponents()
+		return func() tea.Msg { return statusMsg("Styleset cleared") }
+	}
+
+	ss, err := theme.LoadStyleSet(name)
+	if err != nil {
+		return ctx.Model.commandErrorCmd(fmt.Sprintf("styleset: %v", err))
+	}
+	if err := theme.SetCurrentStyleSet(name); err != nil {
+		return ctx.Model.commandErrorCmd(fmt.Sprintf("styleset: %v", err))
+	}
+
+	base, err := theme.Compile(ctx.Model.Theme)
+	if err != nil {
+		return ctx.Model.commandErrorCmd(fmt.Sprintf("styleset: %v", err))
+	}
+	ctx.Model.StyleSet = ss
+	ctx.Model.Styles = theme.ApplyStyleSet(base, ss)
+	ctx.Model.updateComponents()
+	return func() tea.Msg { return statusMsg(fmt.Sprintf("Switched to styleset: %s", name)) }
+}
+
+func cmdPreview(ctx *CmdCtx, args Args) tea.Cmd {
+	return func() tea.Msg { return previewThemeMsg{} }
+}
+
+func cmdRender(ctx *CmdCtx, args Args) tea.Cmd {
+	file := args.String("file")
+	if err := ctx.Model.SetMainMarkdown(file); err != nil {
+		return ctx.Model.commandErrorCmd(fmt.Sprintf("render: %v", err))
+	}
+	return func() tea.Msg { return statusMsg(fmt.Sprintf("Rendered: %s", file)) }
+}
+
+func cmdHelp(ctx *CmdCtx, args Args) tea.Cmd {
+	name := args.String("cmd")
+	if name == "" {
+		names := CommandNames()
+		return func() tea.Msg { return statusMsg("Commands: " + strings.Join(names, ", ")) }
+	}
+	spec, ok := LookupCommand(name)
+	if !ok {
+		return ctx.Model.commandErrorCmd(fmt.Sprintf("Unknown command: %s", name))
+	}
+	help := formatHelp(spec)
+	return func() tea.Msg { return statusMsg(help) }
+}
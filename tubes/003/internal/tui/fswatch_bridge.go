@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tubes/internal/fswatch"
+)
+
+// fileChangedMsg reports that the currently open file (Model.CurrentFile)
+// changed on disk.
+type fileChangedMsg struct{ Path string }
+
+// dirChangedMsg reports a change somewhere else in the watched project
+// tree, requiring a sidebar refresh.
+type dirChangedMsg struct{ Path string }
+
+// startFSWatcher watches the working directory (the project root)
+// recursively, plus m.CurrentFile if one is already open, logging a
+// non-fatal error to m.Error on failure - mirroring startThemeWatcher.
+func (m *Model) startFSWatcher() {
+	root, err := os.Getwd()
+	if err != nil {
+		m.Error = err.Error()
+		return
+	}
+	w, err := fswatch.New(root, nil)
+	if err != nil {
+		m.Error = err.Error()
+		return
+	}
+	if m.CurrentFile != "" {
+		_ = w.AddFile(m.CurrentFile)
+	}
+	m.fsWatcher = w
+}
+
+// watchFS waits for the next fswatch.Event and translates it into a
+// fileChangedMsg or dirChangedMsg, the same re-arm-on-delivery pattern as
+// watchThemeReload.
+func (m *Model) watchFS() tea.Cmd {
+	if m.fsWatcher == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		e, ok := <-m.fsWatcher.Events()
+		if !ok {
+			return nil
+		}
+		if e.Path == m.CurrentFile {
+			return fileChangedMsg{Path: e.Path}
+		}
+		return dirChangedMsg{Path: e.Path}
+	}
+}
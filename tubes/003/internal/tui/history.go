@@ -0,0 +1,112 @@
+package tui
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const historyFileName = "history"
+
+// historyPath returns $XDG_DATA_HOME/tubes/history, falling back to
+// $HOME/.local/share/tubes/history per the XDG base directory spec when
+// XDG_DATA_HOME is unset. Unlike layoutPath, history isn't scoped by
+// TUBES_DIR - it's user data, not a per-project setting, and is meant to
+// survive across every mode and project.
+func historyPath() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+
+	dir := filepath.Join(dataHome, "tubes")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, historyFileName), nil
+}
+
+// loadHistory restores previously-persisted command history, one entry per
+// line. A missing file isn't an error - there's simply no history yet.
+func loadHistory() []string {
+	path, err := historyPath()
+	if err != nil {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var history []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			history = append(history, line)
+		}
+	}
+	return history
+}
+
+// saveHistory overwrites the on-disk history with the full in-memory list,
+// one entry per line.
+func saveHistory(history []string) error {
+	path, err := historyPath()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(history, "\n")+"\n"), 0o644)
+}
+
+// appendHistory records cmd, deduplicating against the immediately
+// preceding entry (typing the same command twice in a row shouldn't
+// double up the history), and persists the result so it survives restarts
+// and mode switches.
+func (m *Model) appendHistory(cmd string) {
+	if len(m.History) > 0 && m.History[len(m.History)-1] == cmd {
+		return
+	}
+	m.History = append(m.History, cmd)
+	if err := saveHistory(m.History); err != nil {
+		m.Error = err.Error()
+	}
+}
+
+// historyUp recalls the previous history entry into Input, same as a
+// shell's up-arrow: the first press stashes whatever was being typed so
+// historyDown can restore it once the user cycles back past the bottom.
+func (m *Model) historyUp() {
+	if len(m.History) == 0 {
+		return
+	}
+	if m.HistoryIndex < 0 {
+		m.pendingInput = m.Input.Value()
+		m.HistoryIndex = len(m.History)
+	}
+	if m.HistoryIndex == 0 {
+		return
+	}
+	m.HistoryIndex--
+	m.Input.SetValue(m.History[m.HistoryIndex])
+}
+
+// historyDown is historyUp's reverse-direction counterpart, restoring
+// pendingInput once the user cycles past the most recent entry.
+func (m *Model) historyDown() {
+	if m.HistoryIndex < 0 {
+		return
+	}
+	m.HistoryIndex++
+	if m.HistoryIndex >= len(m.History) {
+		m.HistoryIndex = -1
+		m.Input.SetValue(m.pendingInput)
+		return
+	}
+	m.Input.SetValue(m.History[m.HistoryIndex])
+}
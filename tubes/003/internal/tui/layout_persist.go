@@ -0,0 +1,92 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+
+	"tubes/internal/theme"
+
+	"gopkg.in/yaml.v3"
+)
+
+const layoutFileName = "layout.yaml"
+
+// persistedLayout is the on-disk shape of user-adjusted split sizes, keyed
+// by Model.Mode so "self" and "tasks" each remember their own split.
+type persistedLayout struct {
+	Modes map[string]modeLayout `yaml:"modes"`
+}
+
+// modeLayout is one mode's persisted split sizes.
+type modeLayout struct {
+	SidebarWidth int `yaml:"sidebar_width"`
+	InputHeight  int `yaml:"input_height"`
+}
+
+// layoutPath returns $TUBES_DIR/layout.yaml, falling back to the working
+// directory like theme.Dir does when TUBES_DIR is unset.
+func layoutPath() (string, error) {
+	root := os.Getenv(theme.DefaultDirEnv)
+	if root == "" {
+		var err error
+		root, err = os.Getwd()
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(root, layoutFileName), nil
+}
+
+// loadAllLayout reads the full on-disk layout, across every mode.
+func loadAllLayout() (persistedLayout, error) {
+	path, err := layoutPath()
+	if err != nil {
+		return persistedLayout{}, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return persistedLayout{}, err
+	}
+	var p persistedLayout
+	if err := yaml.Unmarshal(b, &p); err != nil {
+		return persistedLayout{}, err
+	}
+	return p, nil
+}
+
+// loadLayoutForMode restores the previously-persisted split sizes for mode.
+// ok is false (not an error) when nothing has been saved for it yet.
+func loadLayoutForMode(mode string) (p modeLayout, ok bool) {
+	all, err := loadAllLayout()
+	if err != nil {
+		return modeLayout{}, false
+	}
+	p, ok = all.Modes[mode]
+	return p, ok
+}
+
+// saveLayoutForMode persists mode's current split sizes, alongside
+// whatever's saved for other modes, so they survive restarts.
+func saveLayoutForMode(mode string, p modeLayout) error {
+	all, err := loadAllLayout()
+	if err != nil {
+		all = persistedLayout{}
+	}
+	if all.Modes == nil {
+		all.Modes = make(map[string]modeLayout)
+	}
+	all.Modes[mode] = p
+
+	path, err := layoutPath()
+	if err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(all)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
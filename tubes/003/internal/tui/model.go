@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"tubes/internal/fswatch"
 	"tubes/internal/layout"
+	"tubes/internal/render"
 	"tubes/internal/theme"
 
-	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -17,7 +19,7 @@ import (
 type Model struct {
 	// Terminal dimensions
 	Width, Height int
-	
+
 	// Layout system
 	Grid     *layout.Grid
 	Watchdog *layout.UIWatchdog
@@ -29,122 +31,199 @@ type Model struct {
 		Input        layout.Rect
 		Feedback     layout.Rect // help/coaching area
 	}
-	
+
 	// Theme system
-	Theme  *theme.Theme
-	Styles *theme.Styles
-	
+	Theme           *theme.Theme
+	Styles          *theme.Styles
+	themeWatcher    *theme.Watcher
+	StyleSet        *theme.StyleSet
+	styleSetWatcher *theme.StyleSetWatcher
+
+	// Filesystem watcher, see fswatch_bridge.go
+	fsWatcher *fswatch.Watcher
+
 	// UI Components
 	Sidebar      viewport.Model
 	Main         viewport.Model
 	Input        textarea.Model
 	BottomStatus viewport.Model
 	Feedback     viewport.Model
-	
+
 	// Application state
 	Mode        string // "self" or "tasks"
 	CurrentFile string
 	Error       string
 	Ready       bool
-	
+
+	// Resizable layout
+	ResizeMode      bool // true while arrow keys drag a split boundary
+	SidebarWidth    int
+	InputHeight     int
+	draggingSidebar bool // true while a mouse-press drag is resizing the sidebar
+
 	// Command system
-	Commands     map[string]Command
 	AutoComplete *AutoComplete
 	History      []string
-	
+
+	// History recall (up/down arrows), see history.go
+	HistoryIndex int    // -1 when not navigating History
+	pendingInput string // Input.Value() stashed by the first historyUp, restored by historyDown past the bottom
+
+	// Reverse-incremental-search (ctrl+r), see reverse_search.go
+	ReverseSearch bool
+	SearchQuery   string
+	searchMatches []Match
+	searchIndex   int
+
+	// Markdown renders files into Main via /render.
+	markdown *render.Markdown
+
 	// Completion state
 	CompletionIndex int
 	LastInput       string
 }
 
-// Command represents a TUI command
-type Command struct {
-	Name        string
-	Description string
-	Execute     func(*Model, []string) tea.Cmd
-}
-
 // NewModel creates a new TUI model
 func NewModel() (*Model, error) {
 	m := &Model{
-		Mode:     "self",
-		Commands: make(map[string]Command),
-		Width:    80,  // default dimensions
-		Height:   24,
+		Mode:         "self",
+		Width:        80, // default dimensions
+		Height:       24,
+		markdown:     &render.Markdown{},
+		SidebarWidth: 30,
+		InputHeight:  1,
+	}
+
+	if p, ok := loadLayoutForMode(m.Mode); ok {
+		if p.SidebarWidth > 0 {
+			m.SidebarWidth = p.SidebarWidth
+		}
+		if p.InputHeight > 0 {
+			m.InputHeight = p.InputHeight
+		}
 	}
-	
+
 	// Initialize watchdog with default dimensions
 	m.Watchdog = layout.NewUIWatchdog(m.Width, m.Height)
-	
+
 	// Initialize theme
 	if err := m.initTheme(); err != nil {
 		return nil, err
 	}
-	
+	m.startThemeWatcher()
+	m.startStyleSetWatcher()
+	m.startFSWatcher()
+
+	if name, err := theme.GetCurrentStyleSet(); err == nil && name != "" {
+		if ss, err := theme.LoadStyleSet(name); err == nil {
+			m.StyleSet = ss
+			m.Styles = theme.ApplyStyleSet(m.Styles, ss)
+		}
+	}
+
 	// Initialize UI components
 	m.initComponents()
-	
+
 	// Register commands
 	m.registerCommands()
-	
+
 	// Initialize autocompletion
-	m.AutoComplete = NewAutoComplete(m.Commands)
+	m.AutoComplete = NewAutoComplete(CommandNames())
 	m.CompletionIndex = -1
-	
+
+	// Restore persisted command history
+	m.History = loadHistory()
+	m.HistoryIndex = -1
+
 	m.Ready = true
 	return m, nil
 }
 
 // Init implements the tea.Model interface
 func (m *Model) Init() tea.Cmd {
-	return m.refreshSidebar()
+	return tea.Batch(m.refreshSidebar(), m.watchThemeReload(), m.watchStyleSetReload(), m.watchFS())
 }
 
 // Update implements the tea.Model interface
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
-	
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		return m.handleResize(msg)
-		
+
 	case tea.KeyMsg:
 		return m.handleKey(msg)
-		
+
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case errorMsg:
 		m.Error = string(msg)
+		m.BottomStatus.SetContent(string(msg))
 		return m, nil
-		
+
 	case statusMsg:
 		m.BottomStatus.SetContent(string(msg))
 		return m, nil
-		
+
 	case sidebarContentMsg:
 		m.handleSidebarContent(msg)
 		return m, nil
-		
+
 	case themeChangeMsg:
 		m.Theme = msg.Theme
-		m.Styles = msg.Styles
-		return m, func() tea.Msg {
-			return statusMsg(fmt.Sprintf("Switched to theme: %s", msg.Name))
-		}
-		
+		m.Styles = theme.ApplyStyleSet(msg.Styles, m.StyleSet)
+		m.updateComponents()
+		return m, tea.Batch(
+			m.watchThemeReload(),
+			func() tea.Msg {
+				return statusMsg(fmt.Sprintf("Switched to theme: %s", msg.Name))
+			},
+		)
+
 	case previewThemeMsg:
 		if m.Styles != nil {
-			preview := theme.DetailedPreview(m.Styles)
-			m.Sidebar.SetContent(preview)
+			m.Sidebar.SetContent(theme.PreviewNamedStyles(m.Styles))
 		}
 		return m, nil
-		
+
+	case applyStyleSetMsg:
+		m.StyleSet = msg.StyleSet
+		base, err := theme.Compile(m.Theme)
+		if err != nil {
+			m.Error = err.Error()
+			return m, m.watchStyleSetReload()
+		}
+		m.Styles = theme.ApplyStyleSet(base, m.StyleSet)
+		m.updateComponents()
+		return m, tea.Batch(
+			m.watchStyleSetReload(),
+			func() tea.Msg {
+				if m.StyleSet == nil {
+					return statusMsg("Styleset cleared")
+				}
+				return statusMsg(fmt.Sprintf("Reloaded styleset: %s", m.StyleSet.Name))
+			},
+		)
+
 	case openFileMsg:
 		m.CurrentFile = msg.Filename
 		m.Main.SetContent(msg.Content)
 		return m, func() tea.Msg {
 			return statusMsg(fmt.Sprintf("Opened: %s", msg.Filename))
 		}
-		
+
+	case fileChangedMsg:
+		if data, err := os.ReadFile(msg.Path); err == nil {
+			m.Main.SetContent(string(data))
+		}
+		return m, m.watchFS()
+
+	case dirChangedMsg:
+		return m, tea.Batch(m.refreshSidebar(), m.watchFS())
+
 	case clearMsg:
 		m.Main.SetContent("")
 		m.BottomStatus.SetContent("")
@@ -156,52 +235,58 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			},
 		)
 	}
-	
+
 	// Update UI components
 	m.Input, cmd = m.Input.Update(msg)
 	cmds = append(cmds, cmd)
-	
+
 	m.Sidebar, cmd = m.Sidebar.Update(msg)
 	cmds = append(cmds, cmd)
-	
+
 	m.Main, cmd = m.Main.Update(msg)
 	cmds = append(cmds, cmd)
-	
+
 	m.BottomStatus, cmd = m.BottomStatus.Update(msg)
 	cmds = append(cmds, cmd)
-	
+
 	m.Feedback, cmd = m.Feedback.Update(msg)
 	cmds = append(cmds, cmd)
-	
+
 	return m, tea.Batch(cmds...)
 }
 
-// View implements the tea.Model interface  
+// View implements the tea.Model interface
 func (m *Model) View() string {
 	if !m.Ready || m.Width == 0 || m.Height == 0 || m.Watchdog == nil {
 		return "Loading..."
 	}
-	
+
 	var result strings.Builder
-	
-	// Top Status Bar (muted)
+	ctx := map[string]string{"mode": m.Mode}
+
+	// Top Status Bar (muted) - restyled per Theme.Selectors, e.g. a rule
+	// matching "top_status.mode=tasks" to recolor it in tasks mode.
 	topStatus := fmt.Sprintf("Tubes - %s mode", m.Mode)
-	topStyle := m.getStyle("top_status", lipgloss.NewStyle().Foreground(lipgloss.Color("#a0a0a0")).Background(lipgloss.Color("#1e1e1e")).Faint(true))
+	topStyle := m.Styles.Resolve("top_status", ctx)
 	topStatusStyled := m.Watchdog.SafeRenderContent("top_status", topStatus, m.Rects.TopStatus, topStyle)
 	result.WriteString(topStatusStyled)
 	result.WriteString("\n")
-	
+
 	// Body (sidebar + main) - render side by side
 	sidebarStyle := m.getStyle("sidebar", m.Styles.Sidebar)
-	mainStyle := m.getStyle("main", m.Styles.Main)
-	
+	mainKey := "main"
+	if m.Error != "" {
+		mainKey = "main.error"
+	}
+	mainStyle := m.Styles.Resolve(mainKey, ctx)
+
 	sidebar := m.Watchdog.SafeRenderContent("sidebar", m.Sidebar.View(), m.Rects.Sidebar, sidebarStyle)
 	main := m.Watchdog.SafeRenderContent("main", m.Main.View(), m.Rects.Main, mainStyle)
-	
+
 	sidebarLines := strings.Split(sidebar, "\n")
 	mainLines := strings.Split(main, "\n")
 	maxLines := m.Rects.Sidebar.H
-	
+
 	for i := 0; i < maxLines; i++ {
 		var sLine, mLine string
 		if i < len(sidebarLines) {
@@ -220,23 +305,23 @@ func (m *Model) View() string {
 		}
 	}
 	result.WriteString("\n")
-	
+
 	// Bottom Status Bar (blue)
 	bottomStatus := m.Watchdog.SafeRenderContent("bottom_status", m.BottomStatus.View(), m.Rects.BottomStatus, m.Styles.Info)
 	result.WriteString(bottomStatus)
 	result.WriteString("\n")
-	
+
 	// Input (borderless to prevent cut-off)
 	inputStyle := m.getStyle("input_noborder", lipgloss.NewStyle().Foreground(lipgloss.Color("#e0e0e0")).Background(lipgloss.Color("#1e1e1e")))
 	input := m.Watchdog.SafeRenderContent("input", m.Input.View(), m.Rects.Input, inputStyle)
 	result.WriteString(input)
 	result.WriteString("\n")
-	
+
 	// Feedback
 	feedbackStyle := m.getStyle("feedback", lipgloss.NewStyle().Foreground(lipgloss.Color("#a0a0a0")).Background(lipgloss.Color("#1e1e1e")).Faint(true))
 	feedback := m.Watchdog.SafeRenderContent("feedback", m.Feedback.View(), m.Rects.Feedback, feedbackStyle)
 	result.WriteString(feedback)
-	
+
 	return result.String()
 }
 
@@ -254,39 +339,71 @@ func (m *Model) handleResize(msg tea.WindowSizeMsg) (*Model, tea.Cmd) {
 	if msg.Width == m.Width && msg.Height == m.Height {
 		return m, nil
 	}
-	
+
 	m.Width, m.Height = msg.Width, msg.Height
 	m.Watchdog = layout.NewUIWatchdog(m.Width, m.Height)
 	m.computeLayout()
 	m.updateComponents()
-	
+
 	return m, nil
 }
 
 func (m *Model) handleKey(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	if m.ResizeMode {
+		return m.handleResizeKey(msg)
+	}
+	if m.ReverseSearch {
+		return m.handleReverseSearchKey(msg)
+	}
+
 	switch msg.String() {
 	case "ctrl+c":
 		return m, tea.Quit
-		
+
+	case "ctrl+w":
+		m.ResizeMode = true
+		return m, nil
+
+	case "ctrl+left":
+		m.adjustSidebarWidth(-2)
+		return m, nil
+
+	case "ctrl+right":
+		m.adjustSidebarWidth(2)
+		return m, nil
+
+	case "ctrl+r":
+		m.enterReverseSearch()
+		return m, nil
+
+	case "up":
+		m.historyUp()
+		return m, nil
+
+	case "down":
+		m.historyDown()
+		return m, nil
+
 	case "esc":
 		m.switchMode()
 		return m, nil
-		
+
 	case "enter":
 		return m.handleCommand()
-		
+
 	case "tab":
 		return m.handleTabCompletion()
-		
+
 	case "shift+tab":
 		return m.handleShiftTabCompletion()
-		
+
 	default:
 		// Reset completion on any other key
 		m.CompletionIndex = -1
 		m.LastInput = ""
+		m.HistoryIndex = -1
 	}
-	
+
 	// Let input handle other keys
 	var cmd tea.Cmd
 	m.Input, cmd = m.Input.Update(msg)
@@ -298,32 +415,17 @@ func (m *Model) handleCommand() (*Model, tea.Cmd) {
 	if text == "" {
 		return m, nil
 	}
-	
+
 	m.Input.Reset()
-	m.History = append(m.History, text)
-	
-	// Parse command
-	parts := strings.Fields(text)
-	if len(parts) == 0 {
-		return m, nil
-	}
-	
-	cmdName := strings.TrimPrefix(parts[0], "/")
-	args := parts[1:]
-	
-	// Execute command
-	if cmd, exists := m.Commands[cmdName]; exists {
-		return m, cmd.Execute(m, args)
-	}
-	
-	// Unknown command
-	m.BottomStatus.SetContent("Unknown command: " + cmdName)
-	return m, nil
+	m.appendHistory(text)
+	m.HistoryIndex = -1
+
+	return m, CommandHandler{}.Dispatch(m, text)
 }
 
 func (m *Model) handleTabCompletion() (*Model, tea.Cmd) {
 	currentInput := strings.TrimSpace(m.Input.Value())
-	
+
 	// If input hasn't changed, cycle to next completion
 	if currentInput == m.LastInput && m.CompletionIndex >= 0 {
 		completion, index := m.AutoComplete.GetNextCompletion(currentInput, m.CompletionIndex)
@@ -338,23 +440,23 @@ func (m *Model) handleTabCompletion() (*Model, tea.Cmd) {
 			m.LastInput = currentInput
 		}
 	}
-	
+
 	// Update feedback with completion help
 	help := m.AutoComplete.GetCompletionHelp(currentInput)
 	m.Feedback.SetContent(help)
-	
+
 	return m, nil
 }
 
 func (m *Model) handleShiftTabCompletion() (*Model, tea.Cmd) {
 	currentInput := strings.TrimSpace(m.Input.Value())
-	
+
 	if currentInput == m.LastInput && m.CompletionIndex >= 0 {
 		completion, index := m.AutoComplete.GetPrevCompletion(currentInput, m.CompletionIndex)
 		m.Input.SetValue(completion)
 		m.CompletionIndex = index
 	}
-	
+
 	return m, nil
 }
 
@@ -364,30 +466,48 @@ func (m *Model) switchMode() {
 	} else {
 		m.Mode = "self"
 	}
+
+	if p, ok := loadLayoutForMode(m.Mode); ok {
+		if p.SidebarWidth > 0 {
+			m.SidebarWidth = p.SidebarWidth
+		}
+		if p.InputHeight > 0 {
+			m.InputHeight = p.InputHeight
+		}
+		m.computeLayout()
+		m.updateComponents()
+	}
+
 	m.refreshSidebar()
 }
 
 func (m *Model) computeLayout() {
 	spec := layout.GridSpec{
 		Rows: []layout.Row{
-			{Height: layout.Unit{Kind: layout.Px, Val: 1}},  // top status (muted)
-			{Height: layout.Unit{Kind: layout.Fr, Val: 1}},  // body
-			{Height: layout.Unit{Kind: layout.Px, Val: 1}},  // bottom status (blue)
-			{Height: layout.Unit{Kind: layout.Px, Val: 1}},  // input
-			{Height: layout.Unit{Kind: layout.Px, Val: 2}},  // feedback
+			{Height: layout.Unit{Kind: layout.Px, Val: 1}},                                                       // top status (muted)
+			{Height: layout.Unit{Kind: layout.Fr, Val: 1}},                                                       // body
+			{Height: layout.Unit{Kind: layout.Px, Val: 1}},                                                       // bottom status (blue)
+			{Height: layout.Unit{Kind: layout.Px, Val: m.InputHeight}, Min: inputMinHeight, Max: inputMaxHeight}, // input
+			{Height: layout.Unit{Kind: layout.Px, Val: 2}},                                                       // feedback
 		},
 		Cols: []layout.Col{
-			{Width: layout.Unit{Kind: layout.Px, Val: 30}, Min: 20, Max: 50}, // sidebar
-			{Width: layout.Unit{Kind: layout.Fr, Val: 1}},                    // main
+			{Width: layout.Unit{Kind: layout.Px, Val: m.SidebarWidth}, Min: sidebarMinWidth, Max: sidebarMaxWidth}, // sidebar
+			{Width: layout.Unit{Kind: layout.Fr, Val: 1}},                                                          // main
+		},
+		Splitters: []layout.Splitter{
+			{Axis: layout.SplitCol, Index: 0}, // sidebar/main boundary, left/right in ResizeMode
+			{Axis: layout.SplitRow, Index: 3}, // input row boundary, up/down in ResizeMode
 		},
 		Gaps: struct{ Row, Col int }{Row: 0, Col: 0}, // no gaps
 	}
-	
+
 	m.Grid = layout.NewGrid(spec)
+	m.Grid.SetOverrides(map[int]int{0: m.SidebarWidth})
+	m.Grid.SetRowOverrides(map[int]int{3: m.InputHeight})
 	m.Grid.Compute(m.Width, m.Height)
-	
+
 	// Store rectangles
-	m.Rects.TopStatus = m.Grid.SpanRect(0, 0, 1, 2)    // spans both columns
+	m.Rects.TopStatus = m.Grid.SpanRect(0, 0, 1, 2) // spans both columns
 	m.Rects.Sidebar = m.Grid.CellRect(1, 0)
 	m.Rects.Main = m.Grid.CellRect(1, 1)
 	m.Rects.BottomStatus = m.Grid.SpanRect(2, 0, 1, 2) // spans both columns
@@ -403,17 +523,17 @@ func (m *Model) initTheme() error {
 	} else if env := os.Getenv("TUBES_THEME"); env != "" {
 		themeName = env
 	}
-	
+
 	t, err := theme.Load(themeName)
 	if err != nil {
 		return err
 	}
-	
+
 	styles, err := theme.Compile(t)
 	if err != nil {
 		return err
 	}
-	
+
 	m.Theme = t
 	m.Styles = styles
 	return nil
@@ -424,7 +544,7 @@ func (m *Model) initComponents() {
 	m.Main = viewport.New(0, 0)
 	m.BottomStatus = viewport.New(0, 0)
 	m.Feedback = viewport.New(0, 0)
-	
+
 	m.Input = textarea.New()
 	m.Input.Placeholder = "Enter command (try /help)..."
 	m.Input.Focus()
@@ -437,16 +557,16 @@ func (m *Model) initComponents() {
 func (m *Model) updateComponents() {
 	m.Sidebar.Width = m.Rects.Sidebar.W
 	m.Sidebar.Height = m.Rects.Sidebar.H
-	
+
 	m.Main.Width = m.Rects.Main.W
 	m.Main.Height = m.Rects.Main.H
-	
+
 	m.BottomStatus.Width = m.Rects.BottomStatus.W
 	m.BottomStatus.Height = m.Rects.BottomStatus.H
-	
+
 	m.Feedback.Width = m.Rects.Feedback.W
 	m.Feedback.Height = m.Rects.Feedback.H
-	
+
 	m.Input.SetWidth(m.Rects.Input.W)
 	m.Input.SetHeight(m.Rects.Input.H)
 }
@@ -463,4 +583,17 @@ func (m *Model) refreshSidebar() tea.Cmd {
 	}
 }
 
-type sidebarContentMsg string
\ No newline at end of file
+type sidebarContentMsg string
+
+// SetMainMarkdown renders source as Markdown through m.markdown and installs
+// the wrapped result into Main, using m.Rects.Main.W as the wrap width and
+// m.Theme to derive the glamour style.
+func (m *Model) SetMainMarkdown(source string) error {
+	out, err := m.markdown.RenderFile(source, m.Theme, m.Rects.Main.W)
+	if err != nil {
+		return err
+	}
+	m.CurrentFile = source
+	m.Main.SetContent(out)
+	return nil
+}
@@ -0,0 +1,36 @@
+package tui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// handleMouse detects presses and drags on the sidebar/main column
+// boundary and live-resizes the sidebar to match - the mouse analogue of
+// handleResizeKey's left/right. Enabling mouse reporting
+// (tea.WithMouseCellMotion) is the entrypoint's responsibility; this
+// package only reacts to whatever tea.MouseMsg events arrive.
+func (m *Model) handleMouse(msg tea.MouseMsg) (*Model, tea.Cmd) {
+	switch msg.Action {
+	case tea.MouseActionPress:
+		if msg.Button == tea.MouseButtonLeft && m.onSidebarBoundary(msg) {
+			m.draggingSidebar = true
+		}
+	case tea.MouseActionMotion:
+		if m.draggingSidebar {
+			m.setSidebarWidth(msg.X - m.Rects.Sidebar.X)
+		}
+	case tea.MouseActionRelease:
+		if m.draggingSidebar {
+			m.draggingSidebar = false
+			m.persistLayout()
+		}
+	}
+
+	return m, nil
+}
+
+// onSidebarBoundary reports whether msg falls within one cell of the
+// sidebar/main column boundary, on a row within the sidebar's height.
+func (m *Model) onSidebarBoundary(msg tea.MouseMsg) bool {
+	boundaryX := m.Rects.Main.X
+	return msg.X >= boundaryX-1 && msg.X <= boundaryX+1 &&
+		msg.Y >= m.Rects.Sidebar.Y && msg.Y < m.Rects.Sidebar.Y+m.Rects.Sidebar.H
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+
+	"tubes/internal/layout"
+)
+
+// Renderer is the backend-agnostic surface a TUI frontend must implement:
+// drawing rectangles, handling keys/mouse, and invalidating regions for
+// redraw. It lets a future entrypoint share commands, themes, and
+// layout.Rect math across more than one concrete widget toolkit, the way
+// fzf's tcell/termbox split does.
+type Renderer interface {
+	// DrawRect paints styled content into the given rectangle.
+	DrawRect(r layout.Rect, content string, style RenderStyle)
+	// Invalidate marks r dirty so the next frame repaints it.
+	Invalidate(r layout.Rect)
+	// HandleKey processes a key event, returning true if it consumed it.
+	HandleKey(key string) bool
+	// HandleMouse processes a mouse event at (x, y); pressed is true on
+	// button-down, false on button-up/motion.
+	HandleMouse(x, y int, pressed bool) bool
+	// Run starts the renderer's own event loop and blocks until it exits.
+	Run() error
+}
+
+// RenderStyle is the subset of styling every backend can apply, expressed
+// independently of lipgloss or tcell so both renderers can consume it.
+type RenderStyle struct {
+	FG, BG string
+	Bold   bool
+}
+
+// RendererEnv selects the renderer backend; see SelectRendererBackend.
+const RendererEnv = "TUBES_RENDERER"
+
+// SelectRendererBackend reads TUBES_RENDERER ("tview" or "bubbletea"),
+// defaulting to "bubbletea" when unset or unrecognized.
+func SelectRendererBackend() string {
+	if os.Getenv(RendererEnv) == "tview" {
+		return "tview"
+	}
+	return "bubbletea"
+}
+
+// NewRenderer constructs the Renderer named by backend, wrapping m for the
+// bubbletea case. tview builds its own application/widget tree instead -
+// see tviewRenderer's doc comment for why it can't wrap the existing
+// FileTreePanel/DockView code.
+func NewRenderer(backend string, m *Model) (Renderer, error) {
+	switch backend {
+	case "tview":
+		return newTviewRenderer(), nil
+	case "bubbletea":
+		return newBubbleteaRenderer(m), nil
+	default:
+		return nil, fmt.Errorf("tui: unknown renderer backend %q", backend)
+	}
+}
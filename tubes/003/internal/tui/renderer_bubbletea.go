@@ -0,0 +1,43 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+
+	"tubes/internal/layout"
+)
+
+// bubbleteaRenderer wraps the existing tea.Model-based Model so it can be
+// driven through the Renderer interface instead of a direct tea.NewProgram
+// call at the entrypoint.
+type bubbleteaRenderer struct {
+	model   *Model
+	program *tea.Program
+}
+
+func newBubbleteaRenderer(m *Model) *bubbleteaRenderer {
+	return &bubbleteaRenderer{
+		model:   m,
+		program: tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion()),
+	}
+}
+
+// DrawRect is a no-op here: bubbletea already repaints the whole View()
+// every Update cycle, so there's nothing incremental to do.
+func (r *bubbleteaRenderer) DrawRect(layout.Rect, string, RenderStyle) {}
+
+// Invalidate is a no-op for the same reason as DrawRect.
+func (r *bubbleteaRenderer) Invalidate(layout.Rect) {}
+
+// HandleKey always returns false: bubbletea's own loop already delivers
+// key events straight to Model.Update via tea.KeyMsg.
+func (r *bubbleteaRenderer) HandleKey(key string) bool { return false }
+
+// HandleMouse always returns false, for the same reason as HandleKey -
+// see Model.handleMouse.
+func (r *bubbleteaRenderer) HandleMouse(x, y int, pressed bool) bool { return false }
+
+// Run starts the bubbletea program and blocks until it exits.
+func (r *bubbleteaRenderer) Run() error {
+	_, err := r.program.Run()
+	return err
+}
@@ -0,0 +1,96 @@
+package tui
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"tubes/internal/layout"
+)
+
+// tviewRenderer is the tview/tcell-backed Renderer implementation. It does
+// NOT wrap tetra/tgo/go's FileTreePanel/DockView: those live in an
+// unrelated "package main" snapshot, which by Go's own rules can't be
+// imported from another package, so this builds a fresh, minimal tview
+// surface directly against layout.Rect and RenderStyle instead of reusing
+// that code.
+type tviewRenderer struct {
+	app   *tview.Application
+	root  *tview.Flex
+	panes map[layout.Rect]*tview.TextView
+
+	keyHandler   func(key string) bool
+	mouseHandler func(x, y int, pressed bool) bool
+}
+
+func newTviewRenderer() *tviewRenderer {
+	r := &tviewRenderer{
+		app:   tview.NewApplication(),
+		root:  tview.NewFlex(),
+		panes: make(map[layout.Rect]*tview.TextView),
+	}
+	r.app.EnableMouse(true)
+	r.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if r.keyHandler != nil && r.keyHandler(tcellKeyName(event)) {
+			return nil
+		}
+		return event
+	})
+	r.app.SetMouseCapture(func(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+		if event != nil && r.mouseHandler != nil {
+			x, y := event.Position()
+			if r.mouseHandler(x, y, action == tview.MouseLeftDown) {
+				return nil, action
+			}
+		}
+		return event, action
+	})
+	return r
+}
+
+// DrawRect paints content into the tview.TextView for rect, creating it
+// (and adding it to the root Flex) the first time rect is seen.
+func (r *tviewRenderer) DrawRect(rect layout.Rect, content string, style RenderStyle) {
+	pane, ok := r.panes[rect]
+	if !ok {
+		pane = tview.NewTextView().SetDynamicColors(true)
+		r.panes[rect] = pane
+		r.root.AddItem(pane, rect.W, 0, false)
+	}
+	if style.FG != "" {
+		pane.SetTextColor(tcell.GetColor(style.FG))
+	}
+	if style.BG != "" {
+		pane.SetBackgroundColor(tcell.GetColor(style.BG))
+	}
+	pane.SetText(content)
+}
+
+// Invalidate asks tview to redraw; tview recomputes the whole screen from
+// its primitive tree, so there's no narrower "just this rect" operation.
+func (r *tviewRenderer) Invalidate(layout.Rect) {
+	r.app.Draw()
+}
+
+// HandleKey lets external code (the future shared command dispatcher)
+// register the key handler installed via SetInputCapture in
+// newTviewRenderer; calling it directly replays that same handler.
+func (r *tviewRenderer) HandleKey(key string) bool {
+	return r.keyHandler != nil && r.keyHandler(key)
+}
+
+// HandleMouse is the mouse analogue of HandleKey.
+func (r *tviewRenderer) HandleMouse(x, y int, pressed bool) bool {
+	return r.mouseHandler != nil && r.mouseHandler(x, y, pressed)
+}
+
+// Run starts the tview application and blocks until it exits.
+func (r *tviewRenderer) Run() error {
+	return r.app.SetRoot(r.root, true).Run()
+}
+
+func tcellKeyName(event *tcell.EventKey) string {
+	if event.Key() == tcell.KeyRune {
+		return string(event.Rune())
+	}
+	return event.Name()
+}
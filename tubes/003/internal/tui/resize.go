@@ -0,0 +1,84 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	sidebarMinWidth = 20
+	sidebarMaxWidth = 50
+	inputMinHeight  = 1
+	inputMaxHeight  = 5
+)
+
+// handleResizeKey reinterprets arrow keys as boundary drags while
+// Model.ResizeMode is active, entered via ctrl+w.
+func (m *Model) handleResizeKey(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+w":
+		m.ResizeMode = false
+	case "left":
+		m.adjustSidebarWidth(-2)
+	case "right":
+		m.adjustSidebarWidth(2)
+	case "up":
+		m.adjustInputHeight(1)
+	case "down":
+		m.adjustInputHeight(-1)
+	}
+	return m, nil
+}
+
+// adjustSidebarWidth grows or shrinks the sidebar column within its bounds,
+// recomputing the layout and persisting the new size.
+func (m *Model) adjustSidebarWidth(delta int) {
+	if m.setSidebarWidth(m.SidebarWidth + delta) {
+		m.persistLayout()
+	}
+}
+
+// setSidebarWidth clamps w to [sidebarMinWidth, sidebarMaxWidth] and, if
+// that changes the current width, recomputes the layout. It does not
+// persist - callers that fire repeatedly for one user gesture (mouse
+// drag motion) persist once at the end instead. Returns whether the width
+// actually changed.
+func (m *Model) setSidebarWidth(w int) bool {
+	w = clampInt(w, sidebarMinWidth, sidebarMaxWidth)
+	if w == m.SidebarWidth {
+		return false
+	}
+	m.SidebarWidth = w
+	m.computeLayout()
+	m.updateComponents()
+	return true
+}
+
+// adjustInputHeight grows or shrinks the input row within its bounds,
+// recomputing the layout and persisting the new size.
+func (m *Model) adjustInputHeight(delta int) {
+	h := clampInt(m.InputHeight+delta, inputMinHeight, inputMaxHeight)
+	if h == m.InputHeight {
+		return
+	}
+	m.InputHeight = h
+	m.computeLayout()
+	m.updateComponents()
+	m.persistLayout()
+}
+
+func (m *Model) persistLayout() {
+	p := modeLayout{SidebarWidth: m.SidebarWidth, InputHeight: m.InputHeight}
+	if err := saveLayoutForMode(m.Mode, p); err != nil {
+		m.Error = err.Error()
+	}
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
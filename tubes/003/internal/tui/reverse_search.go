@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// enterReverseSearch opens ctrl+r's reverse-incremental-search overlay,
+// which takes over the Feedback area and reads keys until esc/enter.
+func (m *Model) enterReverseSearch() {
+	m.ReverseSearch = true
+	m.SearchQuery = ""
+	m.searchIndex = 0
+	m.refreshSearchMatches()
+}
+
+// refreshSearchMatches re-ranks History against SearchQuery with the same
+// fuzzyMatch scorer autocomplete uses, most-relevant first.
+func (m *Model) refreshSearchMatches() {
+	var matches []Match
+	for _, h := range m.History {
+		score, positions, ok := fuzzyMatch(m.SearchQuery, h)
+		if !ok {
+			continue
+		}
+		matches = append(matches, Match{Text: h, Score: score, Positions: positions})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+
+	m.searchMatches = matches
+	if m.searchIndex >= len(matches) {
+		m.searchIndex = 0
+	}
+	m.renderSearchPrompt()
+}
+
+// cycleSearchMatch steps to the next-best match on a repeated ctrl+r, the
+// same convention as a shell's reverse-i-search.
+func (m *Model) cycleSearchMatch() {
+	if len(m.searchMatches) == 0 {
+		return
+	}
+	m.searchIndex = (m.searchIndex + 1) % len(m.searchMatches)
+	m.renderSearchPrompt()
+}
+
+func (m *Model) renderSearchPrompt() {
+	match := ""
+	if len(m.searchMatches) > 0 {
+		match = m.searchMatches[m.searchIndex].Text
+	}
+	m.Feedback.SetContent(fmt.Sprintf("(reverse-i-search)'%s': %s", m.SearchQuery, match))
+}
+
+// handleReverseSearchKey handles every key while ReverseSearch is active,
+// mirroring handleResizeKey's takeover of the normal key-dispatch path.
+func (m *Model) handleReverseSearchKey(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+g":
+		m.ReverseSearch = false
+		m.Feedback.SetContent("")
+		return m, nil
+
+	case "enter":
+		m.ReverseSearch = false
+		m.Feedback.SetContent("")
+		if len(m.searchMatches) == 0 {
+			return m, nil
+		}
+		m.Input.SetValue(m.searchMatches[m.searchIndex].Text)
+		return m.handleCommand()
+
+	case "backspace":
+		if len(m.SearchQuery) > 0 {
+			m.SearchQuery = m.SearchQuery[:len(m.SearchQuery)-1]
+		}
+		m.refreshSearchMatches()
+		return m, nil
+
+	case "ctrl+r":
+		m.cycleSearchMatch()
+		return m, nil
+
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.SearchQuery += string(msg.Runes)
+			m.refreshSearchMatches()
+		}
+		return m, nil
+	}
+}
@@ -0,0 +1,56 @@
+package tui
+
+import (
+	"fmt"
+
+	"tubes/internal/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// previewThemeMsg requests a preview render of every currently named
+// style - including whatever a styleset has layered on top of the base
+// theme - into the Sidebar.
+type previewThemeMsg struct{}
+
+// startStyleSetWatcher begins watching the styleset directory for
+// on-disk changes, same as startThemeWatcher: failure is non-fatal,
+// hot-reload is a convenience on top of /styleset, not a requirement.
+func (m *Model) startStyleSetWatcher() {
+	dir, err := theme.StyleSetDir()
+	if err != nil {
+		return
+	}
+	w, err := theme.NewStyleSetWatcher(dir)
+	if err != nil {
+		return
+	}
+	m.styleSetWatcher = w
+}
+
+// watchStyleSetReload waits for the next reload emitted by
+// styleSetWatcher and turns it into a themeChangeMsg layering the
+// reloaded styleset over a fresh compile of the active theme (or an
+// errorMsg on failure). The themeChangeMsg handler in Update must call
+// this again to keep listening.
+func (m *Model) watchStyleSetReload() tea.Cmd {
+	if m.styleSetWatcher == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg, ok := <-m.styleSetWatcher.Events()
+		if !ok {
+			return nil
+		}
+		if msg.Err != nil {
+			return errorMsg(fmt.Sprintf("styleset reload: %v", msg.Err))
+		}
+		return applyStyleSetMsg{StyleSet: msg.StyleSet}
+	}
+}
+
+// applyStyleSetMsg carries a freshly (re)loaded styleset - nil clears
+// whatever overlay was active - to be layered over the active theme.
+type applyStyleSetMsg struct {
+	StyleSet *theme.StyleSet
+}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"fmt"
+
+	"tubes/internal/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// themeChangeMsg carries a freshly (re)loaded theme into Update, whether
+// from an explicit theme-switch command or from themeWatcher picking up an
+// on-disk change.
+type themeChangeMsg struct {
+	Name   string
+	Theme  *theme.Theme
+	Styles *theme.Styles
+}
+
+// startThemeWatcher begins watching the theme directory for on-disk
+// changes. Failure to start is non-fatal: hot-reload is a convenience, not
+// a requirement to run.
+func (m *Model) startThemeWatcher() {
+	dir, err := theme.Dir()
+	if err != nil {
+		return
+	}
+	w, err := theme.NewWatcher(dir)
+	if err != nil {
+		return
+	}
+	m.themeWatcher = w
+}
+
+// watchThemeReload waits for the next reload emitted by themeWatcher and
+// turns it into a themeChangeMsg (or an errorMsg on failure). The
+// themeChangeMsg handler in Update must call this again to keep listening.
+func (m *Model) watchThemeReload() tea.Cmd {
+	if m.themeWatcher == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg, ok := <-m.themeWatcher.Events()
+		if !ok {
+			return nil
+		}
+		if msg.Err != nil {
+			return errorMsg(fmt.Sprintf("theme reload: %v", msg.Err))
+		}
+		return themeChangeMsg{Name: msg.Name, Theme: msg.Theme, Styles: msg.Styles}
+	}
+}
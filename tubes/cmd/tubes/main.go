@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"tubes/internal/ipc"
 	"tubes/internal/theme"
 	"tubes/internal/tui"
 )
@@ -41,6 +43,25 @@ func main() {
 		tea.WithAltScreen(),
 	)
 
+	// Start the JSON-RPC 2.0 control socket (tubesctl, editors, RAG
+	// indexers) - every call is replayed through p.Send, so it only ever
+	// touches Model on the Update goroutine.
+	home, err := os.UserHomeDir()
+	if err == nil {
+		tokenPath := filepath.Join(home, ".tubes", "token")
+		server, err := ipc.NewServer(tui.NewRPCBridge(p), tokenPath)
+		if err != nil {
+			log.Printf("ipc: setup failed, continuing without control socket: %v", err)
+		} else {
+			model.SetNotifier(server)
+			go func() {
+				if err := server.Serve(); err != nil {
+					log.Printf("ipc: server stopped: %v", err)
+				}
+			}()
+		}
+	}
+
 	// Start the program
 	if _, err := p.Run(); err != nil {
 		log.Printf("TUI error: %v", err)
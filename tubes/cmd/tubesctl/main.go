@@ -0,0 +1,124 @@
+// Command tubesctl is a small JSON-RPC 2.0 client for a running tubes
+// instance's control socket - e.g. `tubesctl mode.set '{"mode":"tasks"}'`.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: tubesctl <method> [json-params]")
+		os.Exit(2)
+	}
+	method := os.Args[1]
+	params := "{}"
+	if len(os.Args) > 2 {
+		params = os.Args[2]
+	}
+
+	if err := call(method, params); err != nil {
+		fmt.Fprintln(os.Stderr, "tubesctl:", err)
+		os.Exit(1)
+	}
+}
+
+func call(method, rawParams string) error {
+	socketPath := os.Getenv("XDG_RUNTIME_DIR")
+	if socketPath == "" {
+		socketPath = os.TempDir()
+	}
+	socketPath = filepath.Join(socketPath, "tubes.sock")
+
+	token, err := readToken()
+	if err != nil {
+		return fmt.Errorf("read token: %w", err)
+	}
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", socketPath, err)
+	}
+	defer conn.Close()
+
+	req := struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      int             `json:"id"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
+		Token   string          `json:"token"`
+	}{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  method,
+		Params:  json.RawMessage(rawParams),
+		Token:   token,
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(conn, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		return err
+	}
+
+	resp, err := readFrame(bufio.NewReader(conn))
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(resp))
+	return nil
+}
+
+func readToken() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	b, err := os.ReadFile(filepath.Join(home, ".tubes", "token"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// readFrame mirrors ipc.Server's own framing: a "Content-Length: N"
+// header, a blank line, then exactly N bytes of JSON.
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	var length int
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if strings.HasPrefix(line, "Content-Length:") {
+			length, err = strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "Content-Length:")))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	buf := make([]byte, length)
+	total := 0
+	for total < len(buf) {
+		n, err := r.Read(buf[total:])
+		total += n
+		if err != nil {
+			return buf[:total], err
+		}
+	}
+	return buf, nil
+}
@@ -0,0 +1,304 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Role is a Message's speaker, following the usual chat-completion
+// convention.
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleAssistant Role = "assistant"
+	RoleTool      Role = "tool"
+	RoleSystem    Role = "system"
+)
+
+// Message is one turn in the conversation sent to a Backend - built from
+// a Tree by walking Current back to Root.
+type Message struct {
+	Role    Role   `json:"role"`
+	Content string `json:"content"`
+}
+
+// StreamChunk is one piece of a Backend's streamed response. Done is set
+// on the final chunk (which may also carry the last bit of Text).
+type StreamChunk struct {
+	Text string
+	Err  error
+	Done bool
+}
+
+// Backend generates a streamed assistant reply from a message history -
+// implemented by Ollama, OpenAI, and Anthropic, selected at runtime via
+// "/agent backend NAME".
+type Backend interface {
+	Name() string
+	Stream(ctx context.Context, messages []Message) (<-chan StreamChunk, error)
+}
+
+// NewBackend resolves name ("ollama", "openai", "anthropic") to a
+// Backend, reading its API key/host from the environment the same way
+// melvin's modules read theirs (see melvin's getenv helper).
+func NewBackend(name string) (Backend, error) {
+	switch name {
+	case "ollama":
+		return &ollamaBackend{host: envOr("TUBES_OLLAMA_HOST", "http://localhost:11434"), model: envOr("TUBES_OLLAMA_MODEL", "llama3")}, nil
+	case "openai":
+		return &openAIBackend{apiKey: os.Getenv("OPENAI_API_KEY"), model: envOr("TUBES_OPENAI_MODEL", "gpt-4o-mini")}, nil
+	case "anthropic":
+		return &anthropicBackend{apiKey: os.Getenv("ANTHROPIC_API_KEY"), model: envOr("TUBES_ANTHROPIC_MODEL", "claude-3-5-sonnet-20241022")}, nil
+	default:
+		return nil, fmt.Errorf("unknown agent backend %q (want ollama|openai|anthropic)", name)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+/* =========================
+   ollama
+   ========================= */
+
+type ollamaBackend struct {
+	host  string
+	model string
+}
+
+func (b *ollamaBackend) Name() string { return "ollama" }
+
+func (b *ollamaBackend) Stream(ctx context.Context, messages []Message) (<-chan StreamChunk, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model":    b.model,
+		"messages": messages,
+		"stream":   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.host+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+
+		// Ollama streams one JSON object per line, not SSE.
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var frame struct {
+				Message struct {
+					Content string `json:"content"`
+				} `json:"message"`
+				Done bool `json:"done"`
+			}
+			if err := json.Unmarshal([]byte(line), &frame); err != nil {
+				ch <- StreamChunk{Err: err}
+				return
+			}
+			ch <- StreamChunk{Text: frame.Message.Content, Done: frame.Done}
+			if frame.Done {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			ch <- StreamChunk{Err: err}
+		}
+	}()
+	return ch, nil
+}
+
+/* =========================
+   openai
+   ========================= */
+
+type openAIBackend struct {
+	apiKey string
+	model  string
+}
+
+func (b *openAIBackend) Name() string { return "openai" }
+
+func (b *openAIBackend) Stream(ctx context.Context, messages []Message) (<-chan StreamChunk, error) {
+	if b.apiKey == "" {
+		return nil, fmt.Errorf("OPENAI_API_KEY not set")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model":    b.model,
+		"messages": messages,
+		"stream":   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.openai.com/v1/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+b.apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+		streamSSE(resp.Body, ch, func(data []byte) (text string, done bool, err error) {
+			if string(data) == "[DONE]" {
+				return "", true, nil
+			}
+			var frame struct {
+				Choices []struct {
+					Delta struct {
+						Content string `json:"content"`
+					} `json:"delta"`
+				} `json:"choices"`
+			}
+			if err := json.Unmarshal(data, &frame); err != nil {
+				return "", false, err
+			}
+			if len(frame.Choices) == 0 {
+				return "", false, nil
+			}
+			return frame.Choices[0].Delta.Content, false, nil
+		})
+	}()
+	return ch, nil
+}
+
+/* =========================
+   anthropic
+   ========================= */
+
+type anthropicBackend struct {
+	apiKey string
+	model  string
+}
+
+func (b *anthropicBackend) Name() string { return "anthropic" }
+
+func (b *anthropicBackend) Stream(ctx context.Context, messages []Message) (<-chan StreamChunk, error) {
+	if b.apiKey == "" {
+		return nil, fmt.Errorf("ANTHROPIC_API_KEY not set")
+	}
+
+	var system string
+	var rest []Message
+	for _, m := range messages {
+		if m.Role == RoleSystem {
+			system = m.Content
+			continue
+		}
+		rest = append(rest, m)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model":      b.model,
+		"system":     system,
+		"messages":   rest,
+		"max_tokens": 4096,
+		"stream":     true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.anthropic.com/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", b.apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan StreamChunk)
+	go func() {
+		defer close(ch)
+		defer resp.Body.Close()
+		streamSSE(resp.Body, ch, func(data []byte) (text string, done bool, err error) {
+			var frame struct {
+				Type  string `json:"type"`
+				Delta struct {
+					Text string `json:"text"`
+				} `json:"delta"`
+			}
+			if err := json.Unmarshal(data, &frame); err != nil {
+				return "", false, err
+			}
+			return frame.Delta.Text, frame.Type == "message_stop", nil
+		})
+	}()
+	return ch, nil
+}
+
+/* =========================
+   shared SSE plumbing
+   ========================= */
+
+// streamSSE reads "data: <payload>" lines from r, handing each payload to
+// parse, and writes the resulting StreamChunks to ch until parse reports
+// done or the stream ends.
+func streamSSE(r io.Reader, ch chan<- StreamChunk, parse func(data []byte) (text string, done bool, err error)) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := []byte(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		if len(data) == 0 {
+			continue
+		}
+
+		text, done, err := parse(data)
+		if err != nil {
+			ch <- StreamChunk{Err: err}
+			return
+		}
+		ch <- StreamChunk{Text: text, Done: done}
+		if done {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		ch <- StreamChunk{Err: err}
+	}
+}
@@ -0,0 +1,250 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Node is one message in a Tree - branching because Fork lets a user
+// re-prompt from any prior Node instead of only ever appending to the
+// end, the same way a reader would fork a conversation.
+type Node struct {
+	ID        string     `json:"id"`
+	ParentID  string     `json:"parentId,omitempty"`
+	Role      Role       `json:"role"`
+	Content   string     `json:"content"`
+	ToolCalls []ToolCall `json:"toolCalls,omitempty"`
+	Children  []string   `json:"children,omitempty"`
+}
+
+// Tree is one task's full branching conversation, persisted to
+// ~/.tubes/tasks/<id>.json so it survives a restart.
+type Tree struct {
+	TaskID  string           `json:"taskId"`
+	Backend string           `json:"backend"`
+	Root    string           `json:"root"`
+	Current string           `json:"current"`
+	Nodes   map[string]*Node `json:"nodes"`
+}
+
+// Event is one step of a running agent turn - cmdAgent turns each into a
+// Bubbletea message so the sidebar checklist and main panel update as
+// the turn progresses instead of only once it finishes.
+type Event struct {
+	Type string // "chunk", "tool", "done", "error"
+	Text string
+	Tool string
+	Args string
+	Err  error
+}
+
+// Session drives one Tree through a Backend and the tool Registry.
+type Session struct {
+	Tree    *Tree
+	backend Backend
+}
+
+func tasksDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".tubes", "tasks"), nil
+}
+
+// NewSession starts a fresh Tree for a goal, rooted at a single user
+// Node.
+func NewSession(taskID string, backend Backend, goal string) *Session {
+	root := &Node{ID: "n1", Role: RoleUser, Content: goal}
+	tree := &Tree{
+		TaskID:  taskID,
+		Backend: backend.Name(),
+		Root:    root.ID,
+		Current: root.ID,
+		Nodes:   map[string]*Node{root.ID: root},
+	}
+	return &Session{Tree: tree, backend: backend}
+}
+
+// LoadSession reloads a previously persisted Tree for taskID.
+func LoadSession(taskID string, backend Backend) (*Session, error) {
+	dir, err := tasksDir()
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.ReadFile(filepath.Join(dir, taskID+".json"))
+	if err != nil {
+		return nil, err
+	}
+	var tree Tree
+	if err := json.Unmarshal(b, &tree); err != nil {
+		return nil, err
+	}
+	return &Session{Tree: &tree, backend: backend}, nil
+}
+
+// Save persists Tree to ~/.tubes/tasks/<id>.json.
+func (s *Session) Save() error {
+	dir, err := tasksDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(s.Tree, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, s.Tree.TaskID+".json"), b, 0o644)
+}
+
+// Fork starts a new branch from nodeID, so a user can re-prompt from any
+// prior message without losing the original continuation.
+func (s *Session) Fork(nodeID, newPrompt string) (*Node, error) {
+	parent, ok := s.Tree.Nodes[nodeID]
+	if !ok {
+		return nil, fmt.Errorf("unknown node %q", nodeID)
+	}
+	node := &Node{ID: fmt.Sprintf("n%d", len(s.Tree.Nodes)+1), ParentID: parent.ID, Role: RoleUser, Content: newPrompt}
+	parent.Children = append(parent.Children, node.ID)
+	s.Tree.Nodes[node.ID] = node
+	s.Tree.Current = node.ID
+	return node, nil
+}
+
+// history walks Current back to Root, oldest first, as the Message list
+// a Backend expects.
+func (s *Session) history() []Message {
+	var path []*Node
+	id := s.Tree.Current
+	for id != "" {
+		node, ok := s.Tree.Nodes[id]
+		if !ok {
+			break
+		}
+		path = append([]*Node{node}, path...)
+		id = node.ParentID
+	}
+
+	out := make([]Message, len(path))
+	for i, n := range path {
+		out[i] = Message{Role: n.Role, Content: n.Content}
+	}
+	return out
+}
+
+func (s *Session) appendNode(role Role, content string, toolCalls []ToolCall) *Node {
+	node := &Node{
+		ID:        fmt.Sprintf("n%d", len(s.Tree.Nodes)+1),
+		ParentID:  s.Tree.Current,
+		Role:      role,
+		Content:   content,
+		ToolCalls: toolCalls,
+	}
+	s.Tree.Nodes[s.Tree.Current].Children = append(s.Tree.Nodes[s.Tree.Current].Children, node.ID)
+	s.Tree.Nodes[node.ID] = node
+	s.Tree.Current = node.ID
+	return node
+}
+
+const maxAgentSteps = 8
+
+// Run drives the goal already recorded as the tree's root/current Node
+// through the backend, emitting an Event per streamed chunk and per tool
+// call, executing up to maxAgentSteps tool calls before giving up, and
+// closing the returned channel once a turn produces plain text with no
+// tool call (the final answer) or an error occurs.
+func (s *Session) Run(ctx context.Context, tc *ToolContext) <-chan Event {
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for step := 0; step < maxAgentSteps; step++ {
+			reply, err := s.streamTurn(ctx, events)
+			if err != nil {
+				events <- Event{Type: "error", Err: err}
+				return
+			}
+
+			call, text := parseToolCall(reply)
+			if call == nil {
+				s.appendNode(RoleAssistant, text, nil)
+				_ = s.Save()
+				events <- Event{Type: "done", Text: text}
+				return
+			}
+
+			s.appendNode(RoleAssistant, text, []ToolCall{*call})
+			result, toolErr := s.runTool(ctx, tc, *call)
+			if toolErr != nil {
+				result = "error: " + toolErr.Error()
+			}
+			s.appendNode(RoleTool, result, nil)
+			_ = s.Save()
+
+			argsJSON, _ := json.Marshal(call.Args)
+			events <- Event{Type: "tool", Tool: call.Name, Args: string(argsJSON), Text: result}
+		}
+		events <- Event{Type: "error", Err: fmt.Errorf("agent: exceeded %d steps without a final answer", maxAgentSteps)}
+	}()
+	return events
+}
+
+func (s *Session) streamTurn(ctx context.Context, events chan<- Event) (string, error) {
+	stream, err := s.backend.Stream(ctx, s.history())
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for chunk := range stream {
+		if chunk.Err != nil {
+			return "", chunk.Err
+		}
+		if chunk.Text != "" {
+			b.WriteString(chunk.Text)
+			events <- Event{Type: "chunk", Text: chunk.Text}
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	return b.String(), nil
+}
+
+func (s *Session) runTool(ctx context.Context, tc *ToolContext, call ToolCall) (string, error) {
+	tool, ok := Registry[call.Name]
+	if !ok {
+		return "", fmt.Errorf("unknown tool %q", call.Name)
+	}
+	return tool.Execute(ctx, tc, call.Args)
+}
+
+// parseToolCall looks for a fenced ```tool ... ``` block containing a
+// {"name":..., "args":{...}} object - the agent's way of requesting a
+// tool invocation mid-reply. Returns the call (nil if none was found)
+// and the reply text with the fence stripped.
+func parseToolCall(reply string) (*ToolCall, string) {
+	const fence = "```tool"
+	start := strings.Index(reply, fence)
+	if start == -1 {
+		return nil, reply
+	}
+	rest := reply[start+len(fence):]
+	end := strings.Index(rest, "```")
+	if end == -1 {
+		return nil, reply
+	}
+
+	var call ToolCall
+	if err := json.Unmarshal([]byte(strings.TrimSpace(rest[:end])), &call); err != nil {
+		return nil, reply
+	}
+
+	text := strings.TrimSpace(reply[:start]) + strings.TrimSpace(rest[end+3:])
+	return &call, text
+}
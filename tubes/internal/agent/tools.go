@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// ToolCall is the agent's request to invoke a named tool with the given
+// JSON args - recorded on an assistant Node and, once executed, paired
+// with a ToolResult on the following tool Node.
+type ToolCall struct {
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args"`
+}
+
+// ToolContext gives a Tool a way to affect the running TUI - set by
+// cmdAgent to the actual Model hooks, or left nil in tests.
+type ToolContext struct {
+	OpenFile func(path string) error
+	ThemeUse func(name string) error
+}
+
+// Tool is one Go-implemented capability the agent can invoke.
+type Tool struct {
+	Name        string
+	Description string
+	Execute     func(ctx context.Context, tc *ToolContext, args json.RawMessage) (string, error)
+}
+
+// Registry is every tool available to a Session, keyed by name.
+var Registry = map[string]Tool{
+	"open_file": {
+		Name:        "open_file",
+		Description: "Open a file into the main panel and return its content",
+		Execute: func(ctx context.Context, tc *ToolContext, args json.RawMessage) (string, error) {
+			var p struct {
+				Path string `json:"path"`
+			}
+			if err := json.Unmarshal(args, &p); err != nil {
+				return "", err
+			}
+			content, err := os.ReadFile(p.Path)
+			if err != nil {
+				return "", err
+			}
+			if tc != nil && tc.OpenFile != nil {
+				if err := tc.OpenFile(p.Path); err != nil {
+					return "", err
+				}
+			}
+			return string(content), nil
+		},
+	},
+	"list_dir": {
+		Name:        "list_dir",
+		Description: "List the entries of a directory",
+		Execute: func(ctx context.Context, tc *ToolContext, args json.RawMessage) (string, error) {
+			var p struct {
+				Path string `json:"path"`
+			}
+			if err := json.Unmarshal(args, &p); err != nil {
+				return "", err
+			}
+			entries, err := os.ReadDir(p.Path)
+			if err != nil {
+				return "", err
+			}
+			names := make([]string, len(entries))
+			for i, e := range entries {
+				names[i] = e.Name()
+			}
+			out, err := json.Marshal(names)
+			return string(out), err
+		},
+	},
+	"run_shell": {
+		Name:        "run_shell",
+		Description: "Run a shell command and return its combined output (10s timeout)",
+		Execute: func(ctx context.Context, tc *ToolContext, args json.RawMessage) (string, error) {
+			var p struct {
+				Command string `json:"command"`
+			}
+			if err := json.Unmarshal(args, &p); err != nil {
+				return "", err
+			}
+			runCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+			out, err := exec.CommandContext(runCtx, "sh", "-c", p.Command).CombinedOutput()
+			return string(out), err
+		},
+	},
+	"edit_file": {
+		Name:        "edit_file",
+		Description: "Overwrite a file with new content",
+		Execute: func(ctx context.Context, tc *ToolContext, args json.RawMessage) (string, error) {
+			var p struct {
+				Path    string `json:"path"`
+				Content string `json:"content"`
+			}
+			if err := json.Unmarshal(args, &p); err != nil {
+				return "", err
+			}
+			if err := os.WriteFile(p.Path, []byte(p.Content), 0o644); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("wrote %d bytes to %s", len(p.Content), p.Path), nil
+		},
+	},
+	"theme_use": {
+		Name:        "theme_use",
+		Description: "Switch the active theme",
+		Execute: func(ctx context.Context, tc *ToolContext, args json.RawMessage) (string, error) {
+			var p struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(args, &p); err != nil {
+				return "", err
+			}
+			if tc == nil || tc.ThemeUse == nil {
+				return "", fmt.Errorf("theme_use unavailable outside the TUI")
+			}
+			if err := tc.ThemeUse(p.Name); err != nil {
+				return "", err
+			}
+			return "theme set to " + p.Name, nil
+		},
+	},
+}
@@ -0,0 +1,293 @@
+// Package ipc is the JSON-RPC 2.0 control channel for the tubes TUI -
+// tubesctl and external tools (editors, RAG indexers) drive a running
+// instance over a Unix domain socket instead of the tui package's old
+// unauthenticated named pipe.
+package ipc
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Request is a JSON-RPC 2.0 request - or, with ID omitted, a
+// notification from the client.
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Token   string          `json:"token"`
+}
+
+// Response is a JSON-RPC 2.0 response to a Request that carried an ID.
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Notification is a server-pushed JSON-RPC 2.0 message with no ID and no
+// expected reply - e.g. "panel.updated" after a command changes what's
+// on screen.
+type Notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// Error is a JSON-RPC 2.0 error object.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInternalError  = -32603
+	codeUnauthorized   = -32000
+)
+
+// maxFrameSize bounds Content-Length so a malformed or hostile frame
+// can't make readFrame allocate an unbounded (or negative-length)
+// buffer - the token on a request isn't checked until after the frame
+// is fully read, so this guards every connecting process, not just
+// authenticated ones.
+const maxFrameSize = 64 << 20 // 64 MiB
+
+// Dispatcher executes a JSON-RPC method call against the running TUI -
+// the tui package's RPCBridge is the only implementation, routing
+// through Model the same way executeCommand would dispatch a Command on
+// a tview frontend.
+type Dispatcher interface {
+	Call(method string, params json.RawMessage) (interface{}, error)
+}
+
+// Server is a JSON-RPC 2.0 server over a Unix domain socket, framed like
+// LSP ("Content-Length: N\r\n\r\n<json>") so one long-lived connection
+// carries many request/response pairs plus server-pushed notifications
+// without a line-delimited protocol racing on partial writes or reopen.
+type Server struct {
+	SocketPath string
+	Token      string
+
+	dispatcher Dispatcher
+
+	mu      sync.Mutex
+	clients map[net.Conn]*sync.Mutex // per-conn write lock, so a notification can't interleave with a response
+}
+
+// NewServer creates a Server bound to $XDG_RUNTIME_DIR/tubes.sock (or
+// os.TempDir() if unset), loading or generating the shared auth token at
+// tokenPath with 0600 perms so only the invoking user can drive the UI.
+func NewServer(dispatcher Dispatcher, tokenPath string) (*Server, error) {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	token, err := loadOrCreateToken(tokenPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Server{
+		SocketPath: filepath.Join(dir, "tubes.sock"),
+		Token:      token,
+		dispatcher: dispatcher,
+		clients:    make(map[net.Conn]*sync.Mutex),
+	}, nil
+}
+
+func loadOrCreateToken(path string) (string, error) {
+	if b, err := os.ReadFile(path); err == nil {
+		return strings.TrimSpace(string(b)), nil
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Serve removes any stale socket from a previous run, listens, and
+// accepts connections until the listener errors (typically because
+// Close was called).
+func (s *Server) Serve() error {
+	os.Remove(s.SocketPath)
+	ln, err := net.Listen("unix", s.SocketPath)
+	if err != nil {
+		return fmt.Errorf("ipc: listen %s: %w", s.SocketPath, err)
+	}
+	if err := os.Chmod(s.SocketPath, 0o600); err != nil {
+		ln.Close()
+		return err
+	}
+	defer ln.Close()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		s.addClient(conn)
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) addClient(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients[conn] = &sync.Mutex{}
+}
+
+func (s *Server) removeClient(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.clients, conn)
+	conn.Close()
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.removeClient(conn)
+	reader := bufio.NewReader(conn)
+
+	for {
+		payload, err := readFrame(reader)
+		if err != nil {
+			return
+		}
+
+		var req Request
+		if err := json.Unmarshal(payload, &req); err != nil {
+			s.reply(conn, nil, nil, &Error{Code: codeParseError, Message: err.Error()})
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(req.Token), []byte(s.Token)) != 1 {
+			s.reply(conn, req.ID, nil, &Error{Code: codeUnauthorized, Message: "invalid or missing token"})
+			continue
+		}
+		if req.Method == "" {
+			s.reply(conn, req.ID, nil, &Error{Code: codeInvalidRequest, Message: "missing method"})
+			continue
+		}
+
+		result, callErr := s.dispatcher.Call(req.Method, req.Params)
+		if req.ID == nil {
+			continue // a notification from the client - no reply expected
+		}
+		if callErr != nil {
+			s.reply(conn, req.ID, nil, &Error{Code: codeInternalError, Message: callErr.Error()})
+			continue
+		}
+		s.reply(conn, req.ID, result, nil)
+	}
+}
+
+func (s *Server) reply(conn net.Conn, id json.RawMessage, result interface{}, rpcErr *Error) {
+	if id == nil && rpcErr == nil {
+		return
+	}
+	s.writeTo(conn, Response{JSONRPC: "2.0", ID: id, Result: result, Error: rpcErr})
+}
+
+func (s *Server) writeTo(conn net.Conn, v interface{}) {
+	s.mu.Lock()
+	lock, ok := s.clients[conn]
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	body, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	fmt.Fprintf(conn, "Content-Length: %d\r\n\r\n", len(body))
+	conn.Write(body)
+}
+
+// Notify pushes method/params to every connected client as a
+// JSON-RPC 2.0 notification - "panel.updated" after a command changes
+// what's on screen, so an external tool watching the socket stays in
+// sync without polling.
+func (s *Server) Notify(method string, params interface{}) {
+	n := Notification{JSONRPC: "2.0", Method: method, Params: params}
+
+	s.mu.Lock()
+	conns := make([]net.Conn, 0, len(s.clients))
+	for c := range s.clients {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		s.writeTo(c, n)
+	}
+}
+
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	var length int
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break // blank line ends the header block
+		}
+		if strings.HasPrefix(line, "Content-Length:") {
+			n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "Content-Length:")))
+			if err != nil {
+				return nil, fmt.Errorf("ipc: bad Content-Length: %w", err)
+			}
+			if n <= 0 || n > maxFrameSize {
+				return nil, fmt.Errorf("ipc: Content-Length %d out of range", n)
+			}
+			length = n
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := readFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func readFull(r *bufio.Reader, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := r.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
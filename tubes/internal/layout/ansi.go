@@ -0,0 +1,70 @@
+package layout
+
+import (
+	"github.com/mattn/go-runewidth"
+)
+
+// ansiReset is the SGR sequence that clears all active attributes.
+const ansiReset = "\x1b[0m"
+
+// isSGRReset reports whether seq is exactly a full SGR reset - terminals
+// only treat "\x1b[0m"/"\x1b[m" as clearing every attribute, so that's
+// the only form that clears our own tracked "is something active" state.
+func isSGRReset(seq string) bool {
+	return seq == "\x1b[0m" || seq == "\x1b[m"
+}
+
+// scanANSI walks s rune by rune, calling onText for each printable rune
+// and onEscape for each CSI ("\x1b[...<letter>") or OSC ("\x1b]...BEL" or
+// "\x1b]...\x1b\\") escape sequence it finds, in order. Truncate/WrapANSI
+// use it to treat an escape sequence as one zero-width, un-splittable
+// unit instead of a run of ordinary runes.
+func scanANSI(s string, onText func(r rune), onEscape func(seq string)) {
+	runes := []rune(s)
+	n := len(runes)
+	for i := 0; i < n; {
+		if runes[i] == 0x1b && i+1 < n && runes[i+1] == '[' {
+			j := i + 2
+			for j < n && !(runes[j] >= '@' && runes[j] <= '~') {
+				j++
+			}
+			if j < n {
+				j++ // include the final letter (e.g. 'm')
+			}
+			onEscape(string(runes[i:j]))
+			i = j
+			continue
+		}
+		if runes[i] == 0x1b && i+1 < n && runes[i+1] == ']' {
+			j := i + 2
+			for j < n {
+				if runes[j] == '\a' {
+					j++
+					break
+				}
+				if runes[j] == 0x1b && j+1 < n && runes[j+1] == '\\' {
+					j += 2
+					break
+				}
+				j++
+			}
+			onEscape(string(runes[i:j]))
+			i = j
+			continue
+		}
+		onText(runes[i])
+		i++
+	}
+}
+
+// displayWidth measures s in terminal display cells via go-runewidth
+// (so e.g. a CJK character or most emoji count as 2, a combining mark as
+// 0), skipping any embedded CSI/OSC escape sequence entirely rather than
+// counting its bytes as visible characters.
+func displayWidth(s string) int {
+	w := 0
+	scanANSI(s, func(r rune) {
+		w += runewidth.RuneWidth(r)
+	}, func(string) {})
+	return w
+}
@@ -0,0 +1,108 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want int
+	}{
+		{"plain ascii", "hello", 5},
+		{"combining mark is zero-width", "éllo", 4}, // e + combining acute + llo
+		// scanANSI/runewidth measure per-rune, so a ZWJ-joined emoji
+		// sequence still costs each emoji's width (the ZWJ itself is
+		// zero-width) rather than collapsing to one grapheme cluster.
+		{"zero-width joiner sequence", "\U0001F468‍\U0001F469‍\U0001F467", 6},
+		{"CJK is double-width", "你好", 4},
+		{"SGR sequence is skipped", "\x1b[31mred\x1b[0m", 3},
+		{"nested SGR sequences are skipped", "\x1b[1m\x1b[31mbold red\x1b[0m\x1b[22m", 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := displayWidth(tt.in); got != tt.want {
+				t.Fatalf("displayWidth(%q) = %d, want %d", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanANSISplitsEscapesAsAtomicUnits(t *testing.T) {
+	var text []rune
+	var escapes []string
+	scanANSI("\x1b[1m\x1b[31mhi\x1b[0m", func(r rune) {
+		text = append(text, r)
+	}, func(seq string) {
+		escapes = append(escapes, seq)
+	})
+
+	wantText := "hi"
+	if string(text) != wantText {
+		t.Fatalf("scanANSI text = %q, want %q", string(text), wantText)
+	}
+	wantEscapes := []string{"\x1b[1m", "\x1b[31m", "\x1b[0m"}
+	if len(escapes) != len(wantEscapes) {
+		t.Fatalf("scanANSI escapes = %v, want %v", escapes, wantEscapes)
+	}
+	for i, e := range wantEscapes {
+		if escapes[i] != e {
+			t.Fatalf("scanANSI escapes[%d] = %q, want %q", i, escapes[i], e)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		width int
+		want  string
+	}{
+		{"shorter than width is unchanged", "hi", 10, "hi"},
+		{"exact width is unchanged", "hello", 5, "hello"},
+		{"truncates with ellipsis", "hello world", 8, "hello..."},
+		{"width at or below 3 is all dots", "hello", 3, "..."},
+		{"width zero is empty", "hello", 0, ""},
+		{"combining mark doesn't count toward width", "éllo world", 8, "éllo ..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Truncate(tt.in, tt.width); got != tt.want {
+				t.Fatalf("Truncate(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateClosesActiveSGR(t *testing.T) {
+	got := Truncate("\x1b[31mhello world\x1b[0m", 8)
+	if !strings.HasSuffix(got, ansiReset) {
+		t.Fatalf("Truncate() = %q, want it to close the still-active SGR with a reset", got)
+	}
+}
+
+func TestWrapANSIReplaysActiveSGRAcrossLines(t *testing.T) {
+	lines := WrapANSI("\x1b[31mred fox jumps\x1b[0m", 7)
+	if len(lines) < 2 {
+		t.Fatalf("WrapANSI() = %v, want at least 2 lines", lines)
+	}
+	for i, line := range lines[1:] {
+		if !strings.HasPrefix(line, "\x1b[31m") {
+			t.Fatalf("WrapANSI() line %d = %q, want it to replay the active SGR sequence", i+1, line)
+		}
+	}
+}
+
+func TestWrapANSIStopsReplayingAfterReset(t *testing.T) {
+	lines := WrapANSI("\x1b[31mred\x1b[0m fox jumps over", 8)
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "\x1b[31m") {
+			t.Fatalf("WrapANSI() = %v, want no replay once the SGR was reset", lines)
+		}
+	}
+}
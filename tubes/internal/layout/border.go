@@ -0,0 +1,240 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// BorderSide is a bitmask of which edges of a Rect get a border, so a
+// panel can declare e.g. "just a left divider" instead of the
+// all-or-nothing lipgloss Border() that UIWatchdog.AdaptiveStyle
+// already has to strip entirely once width < 10.
+type BorderSide int
+
+const (
+	BorderLeft BorderSide = 1 << iota
+	BorderTop
+	BorderRight
+	BorderBottom
+)
+
+// InnerRect returns rect shrunk by 1 cell on each side set in sides -
+// the space a Bordered panel actually hands its child to render into.
+func InnerRect(rect Rect, sides BorderSide) Rect {
+	inner := rect
+	if sides&BorderLeft != 0 {
+		inner.X++
+		inner.W--
+	}
+	if sides&BorderRight != 0 {
+		inner.W--
+	}
+	if sides&BorderTop != 0 {
+		inner.Y++
+		inner.H--
+	}
+	if sides&BorderBottom != 0 {
+		inner.H--
+	}
+	if inner.W < 0 {
+		inner.W = 0
+	}
+	if inner.H < 0 {
+		inner.H = 0
+	}
+	return inner
+}
+
+// Bordered wraps a child Panel with a configurable per-edge border
+// drawn with box-drawing characters, shrinking the rect handed to Child
+// by 1 cell on each bordered side (see InnerRect) rather than lipgloss's
+// Border(), which always reserves all four. Neighbors lists the Rects
+// of sibling panels in the same Grid; wherever one of their edges meets
+// this panel's border at the same cell, the glyph there is upgraded
+// from a plain line to a T or + junction instead of drawing a doubled
+// edge.
+type Bordered struct {
+	Child     Panel
+	Sides     BorderSide
+	Style     lipgloss.Style
+	Neighbors []Rect
+}
+
+// NewBordered wraps child with a border on sides, styled with style.
+func NewBordered(child Panel, sides BorderSide, style lipgloss.Style) *Bordered {
+	return &Bordered{Child: child, Sides: sides, Style: style}
+}
+
+// Render draws Child's content into rect's interior (per InnerRect),
+// surrounded by a border on whichever sides b.Sides selects.
+func (b *Bordered) Render(rect Rect) string {
+	if rect.W <= 0 || rect.H <= 0 {
+		return ""
+	}
+
+	inner := InnerRect(rect, b.Sides)
+	contentLines := strings.Split(b.Child.Render(inner), "\n")
+	for len(contentLines) < inner.H {
+		contentLines = append(contentLines, strings.Repeat(" ", inner.W))
+	}
+
+	hasTop := b.Sides&BorderTop != 0
+	hasBottom := b.Sides&BorderBottom != 0
+	hasLeft := b.Sides&BorderLeft != 0
+	hasRight := b.Sides&BorderRight != 0
+
+	var out []string
+	if hasTop {
+		out = append(out, b.Style.Render(b.borderRow(rect, rect.Y)))
+	}
+
+	for i := 0; i < inner.H; i++ {
+		line := padOrTruncate(contentLines[i], inner.W)
+		if hasLeft {
+			line = b.Style.Render(string(b.borderGlyphAt(rect, rect.X, inner.Y+i))) + line
+		}
+		if hasRight {
+			line = line + b.Style.Render(string(b.borderGlyphAt(rect, rect.X+rect.W-1, inner.Y+i)))
+		}
+		out = append(out, line)
+	}
+
+	if hasBottom {
+		out = append(out, b.Style.Render(b.borderRow(rect, rect.Y+rect.H-1)))
+	}
+
+	return strings.Join(out, "\n")
+}
+
+func (b *Bordered) HandleInput(key string) bool { return b.Child.HandleInput(key) }
+func (b *Bordered) Update(rect Rect)            { b.Child.Update(InnerRect(rect, b.Sides)) }
+
+// borderRow renders one full horizontal border line (top or bottom) at
+// absolute row y.
+func (b *Bordered) borderRow(rect Rect, y int) string {
+	var row strings.Builder
+	for x := rect.X; x < rect.X+rect.W; x++ {
+		row.WriteRune(b.borderGlyphAt(rect, x, y))
+	}
+	return row.String()
+}
+
+// borderGlyphAt resolves the box-drawing glyph for absolute cell (x, y)
+// on rect's own border, merging in any neighbor whose edge meets it at
+// the same point.
+func (b *Bordered) borderGlyphAt(rect Rect, x, y int) rune {
+	north, south, east, west := nativeDirections(x, y, rect, b.Sides)
+	for _, nb := range b.Neighbors {
+		n, s, e, w := neighborDirections(x, y, nb)
+		north, south, east, west = north || n, south || s, east || e, west || w
+	}
+	return borderGlyph(north, south, east, west)
+}
+
+// nativeDirections reports which cardinal directions a border segment
+// extends from absolute cell (x, y), given that rect declares borders on
+// sides. A corner or straight-run cell sets exactly 2 of the 4; a cell
+// not on one of the declared edges returns all false.
+func nativeDirections(x, y int, rect Rect, sides BorderSide) (north, south, east, west bool) {
+	top := sides&BorderTop != 0 && y == rect.Y
+	bottom := sides&BorderBottom != 0 && y == rect.Y+rect.H-1
+	left := sides&BorderLeft != 0 && x == rect.X
+	right := sides&BorderRight != 0 && x == rect.X+rect.W-1
+
+	if top || bottom {
+		if x > rect.X || left {
+			west = true
+		}
+		if x < rect.X+rect.W-1 || right {
+			east = true
+		}
+	}
+	if left || right {
+		if y > rect.Y || top {
+			north = true
+		}
+		if y < rect.Y+rect.H-1 || bottom {
+			south = true
+		}
+	}
+	return
+}
+
+// neighborDirections reports which cardinal direction neighbor rect n
+// contributes at absolute cell (x, y), when (x, y) lies exactly on one
+// of n's four edges - e.g. a neighbor sitting directly below contributes
+// "south" where our border meets its top edge, turning what would be a
+// plain line into a T or + junction.
+func neighborDirections(x, y int, n Rect) (north, south, east, west bool) {
+	onTop := y == n.Y && x >= n.X && x <= n.X+n.W-1
+	onBottom := y == n.Y+n.H-1 && x >= n.X && x <= n.X+n.W-1
+	onLeft := x == n.X && y >= n.Y && y <= n.Y+n.H-1
+	onRight := x == n.X+n.W-1 && y >= n.Y && y <= n.Y+n.H-1
+
+	if onTop {
+		south = true
+	}
+	if onBottom {
+		north = true
+	}
+	if onLeft {
+		east = true
+	}
+	if onRight {
+		west = true
+	}
+	return
+}
+
+// borderGlyphs maps a set of extending directions (N=1, S=2, E=4, W=8)
+// to the single box-drawing character that draws all of them from one
+// cell - a corner, a straight run, a T, or a + where up to four borders
+// converge.
+var borderGlyphs = map[int]rune{
+	0b0001: '╵',
+	0b0010: '╷',
+	0b0100: '╶',
+	0b1000: '╴',
+	0b0011: '│',
+	0b1100: '─',
+	0b0110: '┌',
+	0b1010: '┐',
+	0b0101: '└',
+	0b1001: '┘',
+	0b0111: '├',
+	0b1011: '┤',
+	0b1110: '┬',
+	0b1101: '┴',
+	0b1111: '┼',
+}
+
+func borderGlyph(north, south, east, west bool) rune {
+	mask := 0
+	if north {
+		mask |= 0b0001
+	}
+	if south {
+		mask |= 0b0010
+	}
+	if east {
+		mask |= 0b0100
+	}
+	if west {
+		mask |= 0b1000
+	}
+	if r, ok := borderGlyphs[mask]; ok {
+		return r
+	}
+	return ' '
+}
+
+// padOrTruncate pads s with spaces (or truncates it) to exactly width
+// runes, the same convention RenderContent uses for its lines.
+func padOrTruncate(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return s + strings.Repeat(" ", width-len(runes))
+}
@@ -6,8 +6,9 @@ import "math"
 type UnitKind int
 
 const (
-	Px UnitKind = iota // absolute cells
-	Fr                 // fractional share of remaining space
+	Px         UnitKind = iota // absolute cells
+	Fr                         // fractional share of remaining space
+	FrAdaptive                 // fractional share, capped to measured content size
 )
 
 // Unit represents a sizing unit with its kind and value
@@ -25,27 +26,67 @@ type Rect struct {
 type Row struct {
 	Height   Unit
 	Min, Max int // Min/Max in cells (0 = no bound)
+
+	// Optional marks a row UIWatchdog.Repair is allowed to collapse to
+	// zero height (by pinning Height to Px 0 and clearing Min) when the
+	// terminal is too small to fit every row, instead of leaving the
+	// layout overflowing. Priority breaks ties among several Optional
+	// rows: Repair collapses the lowest Priority first.
+	Optional bool
+	Priority int
 }
 
 // Col defines a column configuration with width and constraints
 type Col struct {
 	Width    Unit
 	Min, Max int // Min/Max in cells (0 = no bound)
+
+	// Optional and Priority are the column analogues of Row's fields - see
+	// Row.Optional.
+	Optional bool
+	Priority int
+}
+
+// SplitterAxis identifies whether a Splitter drags a row or column
+// boundary.
+type SplitterAxis int
+
+const (
+	SplitCol SplitterAxis = iota // drags the boundary after Cols[Index]
+	SplitRow                     // drags the boundary after Rows[Index]
+)
+
+// Splitter describes one row/col boundary a caller lets the user drag at
+// runtime. Grid itself only records them for callers to introspect (e.g. to
+// decide which arrow key maps to which boundary); the actual resize happens
+// through SetOverrides/SetRowOverrides.
+type Splitter struct {
+	Axis  SplitterAxis
+	Index int
 }
 
 // GridSpec defines the complete grid specification
 type GridSpec struct {
-	Rows    []Row
-	Cols    []Col
-	Gaps    struct{ Row, Col int }      // inter-row/col spacing
-	Padding struct{ T, R, B, L int }   // outer padding
+	Rows      []Row
+	Cols      []Col
+	Splitters []Splitter               // which row/col boundaries are user-draggable
+	Gaps      struct{ Row, Col int }   // inter-row/col spacing
+	Padding   struct{ T, R, B, L int } // outer padding
 }
 
 // Grid holds the computed layout state
 type Grid struct {
-	spec GridSpec
-	rows []int // computed heights
-	cols []int // computed widths
+	spec         GridSpec
+	rows         []int // computed heights
+	cols         []int // computed widths
+	colOverrides map[int]int
+	rowOverrides map[int]int
+
+	// rowContent/colContent are the measured content size (e.g. line
+	// count, longest line width) per track, consulted by FrAdaptive
+	// tracks on the next Compute - see SetRowContentSizes.
+	rowContent []int
+	colContent []int
 }
 
 // NewGrid creates a new grid with the given specification
@@ -53,6 +94,33 @@ func NewGrid(spec GridSpec) *Grid {
 	return &Grid{spec: spec}
 }
 
+// SetOverrides sets explicit pixel-width overrides for column indices,
+// applied by the next Compute call in place of that column's configured
+// Unit (still clamped to the column's Min/Max). Pass nil to clear.
+func (g *Grid) SetOverrides(overrides map[int]int) {
+	g.colOverrides = overrides
+}
+
+// SetRowOverrides is the row analogue of SetOverrides.
+func (g *Grid) SetRowOverrides(overrides map[int]int) {
+	g.rowOverrides = overrides
+}
+
+// SetRowContentSizes sets each row's measured content size (e.g. a log
+// panel's current line count), consulted by FrAdaptive rows on the next
+// Compute call so a track whose content is smaller than its Fr share
+// collapses instead of always consuming the full share. Pass nil to
+// clear, which falls back to plain Fr sizing for every row - the
+// behavior before anything has been measured (the first frame).
+func (g *Grid) SetRowContentSizes(sizes []int) {
+	g.rowContent = sizes
+}
+
+// SetColContentSizes is the column analogue of SetRowContentSizes.
+func (g *Grid) SetColContentSizes(sizes []int) {
+	g.colContent = sizes
+}
+
 // Compute calculates the layout for the given total dimensions
 func (g *Grid) Compute(totalW, totalH int) {
 	// Calculate inner box after padding
@@ -68,8 +136,8 @@ func (g *Grid) Compute(totalW, totalH int) {
 	// Distribute space
 	rowMin, rowMax := g.rowBounds()
 	colMin, colMax := g.colBounds()
-	g.rows = g.distribute(h, g.spec.Gaps.Row, g.rowUnits(), rowMin, rowMax)
-	g.cols = g.distribute(w, g.spec.Gaps.Col, g.colUnits(), colMin, colMax)
+	g.rows = g.distribute(h, g.spec.Gaps.Row, g.rowUnitsWithOverrides(), rowMin, rowMax, g.rowContent)
+	g.cols = g.distribute(w, g.spec.Gaps.Col, g.colUnitsWithOverrides(), colMin, colMax, g.colContent)
 }
 
 // CellRect returns the rectangle for the cell at row r, column c
@@ -85,7 +153,7 @@ func (g *Grid) CellRect(r, c int) Rect {
 		}
 	}
 
-	// Add row heights and gaps to get y position  
+	// Add row heights and gaps to get y position
 	for i := 0; i < r; i++ {
 		y += g.rows[i]
 		if i < len(g.rows)-1 {
@@ -114,7 +182,7 @@ func (g *Grid) SpanRect(r, c, rowSpan, colSpan int) Rect {
 
 	// Get starting position
 	startRect := g.CellRect(r, c)
-	
+
 	// Calculate spanning width
 	w := 0
 	for i := c; i < c+colSpan && i < len(g.cols); i++ {
@@ -153,6 +221,30 @@ func (g *Grid) colUnits() []Unit {
 	return units
 }
 
+// rowUnitsWithOverrides is rowUnits with any Row.SetRowOverrides entries
+// applied as Px units.
+func (g *Grid) rowUnitsWithOverrides() []Unit {
+	units := g.rowUnits()
+	for i, v := range g.rowOverrides {
+		if i >= 0 && i < len(units) {
+			units[i] = Unit{Kind: Px, Val: v}
+		}
+	}
+	return units
+}
+
+// colUnitsWithOverrides is colUnits with any SetOverrides entries applied
+// as Px units.
+func (g *Grid) colUnitsWithOverrides() []Unit {
+	units := g.colUnits()
+	for i, v := range g.colOverrides {
+		if i >= 0 && i < len(units) {
+			units[i] = Unit{Kind: Px, Val: v}
+		}
+	}
+	return units
+}
+
 func (g *Grid) rowBounds() (min, max []int) {
 	n := len(g.spec.Rows)
 	min = make([]int, n)
@@ -173,21 +265,25 @@ func (g *Grid) colBounds() (min, max []int) {
 	return
 }
 
-// distribute allocates space: absolute Px first, then distribute remaining by Fr weights with bounds
-func (g *Grid) distribute(total, gap int, units []Unit, boundsMin, boundsMax []int) []int {
+// distribute allocates space: absolute Px first, then Fr/FrAdaptive
+// weights with bounds. contentSize holds each track's measured content
+// size (line count / longest line width), indexed the same as units;
+// pass nil (or leave an entry 0) when nothing's been measured yet (e.g.
+// the first frame), which makes FrAdaptive behave exactly like Fr.
+func (g *Grid) distribute(total, gap int, units []Unit, boundsMin, boundsMax []int, contentSize []int) []int {
 	n := len(units)
 	if n == 0 {
 		return []int{}
 	}
 
 	out := make([]int, n)
-	
+
 	// Calculate space taken by gaps
 	gapSpace := 0
 	if n > 1 {
 		gapSpace = gap * (n - 1)
 	}
-	
+
 	remain := total - gapSpace
 	if remain < 0 {
 		remain = 0
@@ -195,8 +291,10 @@ func (g *Grid) distribute(total, gap int, units []Unit, boundsMin, boundsMax []i
 
 	// First pass: allocate Px units, respecting bounds
 	sumFr := 0
+	plainFrSum := 0
 	for i, u := range units {
-		if u.Kind == Px {
+		switch u.Kind {
+		case Px:
 			v := u.Val
 			// Apply min/max constraints
 			if boundsMin[i] > 0 && v < boundsMin[i] {
@@ -207,7 +305,10 @@ func (g *Grid) distribute(total, gap int, units []Unit, boundsMin, boundsMax []i
 			}
 			out[i] = v
 			remain -= v
-		} else {
+		case Fr:
+			sumFr += u.Val
+			plainFrSum += u.Val
+		case FrAdaptive:
 			sumFr += u.Val
 		}
 	}
@@ -216,18 +317,43 @@ func (g *Grid) distribute(total, gap int, units []Unit, boundsMin, boundsMax []i
 		remain = 0
 	}
 
-	// Second pass: distribute remaining space by Fr weights
+	// Second pass: distribute remaining space by Fr/FrAdaptive weights.
+	// An FrAdaptive track whose measured content is smaller than its
+	// share is capped to that content size; the freed space (slack) is
+	// redistributed to plain Fr tracks below, so a small log panel can
+	// collapse without starving its neighbors.
+	slack := 0
 	for i, u := range units {
-		if u.Kind == Fr {
-			v := 0
-			if sumFr > 0 {
-				v = int(math.Round(float64(u.Val) / float64(sumFr) * float64(remain)))
-			}
-			
-			// Apply min/max constraints
-			if boundsMin[i] > 0 && v < boundsMin[i] {
-				v = boundsMin[i]
+		if u.Kind != Fr && u.Kind != FrAdaptive {
+			continue
+		}
+
+		v := 0
+		if sumFr > 0 {
+			v = int(math.Round(float64(u.Val) / float64(sumFr) * float64(remain)))
+		}
+
+		if u.Kind == FrAdaptive && i < len(contentSize) && contentSize[i] > 0 && contentSize[i] < v {
+			slack += v - contentSize[i]
+			v = contentSize[i]
+		}
+
+		// Apply min/max constraints
+		if boundsMin[i] > 0 && v < boundsMin[i] {
+			v = boundsMin[i]
+		}
+		if boundsMax[i] > 0 && v > boundsMax[i] {
+			v = boundsMax[i]
+		}
+		out[i] = v
+	}
+
+	if slack > 0 && plainFrSum > 0 {
+		for i, u := range units {
+			if u.Kind != Fr {
+				continue
 			}
+			v := out[i] + int(math.Round(float64(u.Val)/float64(plainFrSum)*float64(slack)))
 			if boundsMax[i] > 0 && v > boundsMax[i] {
 				v = boundsMax[i]
 			}
@@ -235,29 +361,41 @@ func (g *Grid) distribute(total, gap int, units []Unit, boundsMin, boundsMax []i
 		}
 	}
 
-	// Third pass: handle rounding drift by adjusting fractional units
+	// Third pass: handle rounding drift by adjusting fractional units,
+	// preferring plain Fr tracks so the cap an adaptive track just
+	// honored isn't undone by a +/-1 correction.
+	adjustable := make([]int, 0, n)
+	for i, u := range units {
+		if u.Kind == Fr {
+			adjustable = append(adjustable, i)
+		}
+	}
+	if len(adjustable) == 0 {
+		for i, u := range units {
+			if u.Kind == FrAdaptive {
+				adjustable = append(adjustable, i)
+			}
+		}
+	}
+
 	used := 0
 	for _, v := range out {
 		used += v
 	}
-	
 	drift := (total - gapSpace) - used
-	
-	// Distribute positive drift
-	for i := 0; drift > 0 && i < n; i++ {
-		if units[i].Kind == Fr {
+
+	for _, i := range adjustable {
+		if drift == 0 {
+			break
+		}
+		if drift > 0 {
 			out[i]++
 			drift--
-		}
-	}
-	
-	// Remove negative drift
-	for i := 0; drift < 0 && i < n; i++ {
-		if units[i].Kind == Fr && out[i] > 0 {
+		} else if out[i] > 0 {
 			out[i]--
 			drift++
 		}
 	}
 
 	return out
-}
\ No newline at end of file
+}
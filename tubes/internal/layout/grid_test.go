@@ -0,0 +1,116 @@
+package layout
+
+import "testing"
+
+func TestGridSetOverridesClampsToBounds(t *testing.T) {
+	g := NewGrid(GridSpec{
+		Cols: []Col{
+			{Width: Unit{Kind: Px, Val: 30}, Min: 20, Max: 50},
+			{Width: Unit{Kind: Fr, Val: 1}},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		w       int
+		wantCol int
+	}{
+		{"below min clamps up", 5, 20},
+		{"above max clamps down", 100, 50},
+		{"within bounds passes through", 35, 35},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.SetOverrides(map[int]int{0: tt.w})
+			g.Compute(100, 10)
+			rect := g.CellRect(0, 0)
+			if rect.W != tt.wantCol {
+				t.Fatalf("SetOverrides(%d): col 0 width = %d, want %d", tt.w, rect.W, tt.wantCol)
+			}
+		})
+	}
+}
+
+func TestGridSetRowOverridesClampsToBounds(t *testing.T) {
+	g := NewGrid(GridSpec{
+		Rows: []Row{
+			{Height: Unit{Kind: Px, Val: 3}, Min: 1, Max: 5},
+			{Height: Unit{Kind: Fr, Val: 1}},
+		},
+	})
+
+	tests := []struct {
+		name    string
+		h       int
+		wantRow int
+	}{
+		{"below min clamps up", 0, 1},
+		{"above max clamps down", 20, 5},
+		{"within bounds passes through", 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g.SetRowOverrides(map[int]int{0: tt.h})
+			g.Compute(10, 20)
+			rect := g.CellRect(0, 0)
+			if rect.H != tt.wantRow {
+				t.Fatalf("SetRowOverrides(%d): row 0 height = %d, want %d", tt.h, rect.H, tt.wantRow)
+			}
+		})
+	}
+}
+
+// TestGridOverrideReflowsNeighbor verifies that clamping an override on one
+// column still leaves its Fr neighbor to absorb the remaining space, rather
+// than the neighbor staying fixed at its old size.
+func TestGridOverrideReflowsNeighbor(t *testing.T) {
+	g := NewGrid(GridSpec{
+		Cols: []Col{
+			{Width: Unit{Kind: Px, Val: 30}, Min: 20, Max: 50},
+			{Width: Unit{Kind: Fr, Val: 1}},
+		},
+	})
+
+	g.Compute(100, 10)
+	before := g.CellRect(0, 1)
+
+	// Push col 0 past its Max; it should clamp to 50, and col 1 (Fr)
+	// should shrink to fill exactly the remaining space.
+	g.SetOverrides(map[int]int{0: 100})
+	g.Compute(100, 10)
+	after := g.CellRect(0, 1)
+
+	if after.W == before.W {
+		t.Fatalf("neighboring Fr column did not reflow: width stayed %d", after.W)
+	}
+	col0 := g.CellRect(0, 0)
+	if col0.W+after.W != 100 {
+		t.Fatalf("col widths %d + %d != total 100", col0.W, after.W)
+	}
+	if col0.W != 50 {
+		t.Fatalf("col 0 width = %d, want clamped to Max 50", col0.W)
+	}
+}
+
+func TestGridSetOverridesNil(t *testing.T) {
+	g := NewGrid(GridSpec{
+		Cols: []Col{
+			{Width: Unit{Kind: Px, Val: 30}},
+			{Width: Unit{Kind: Fr, Val: 1}},
+		},
+	})
+
+	g.SetOverrides(map[int]int{0: 40})
+	g.Compute(100, 10)
+	if w := g.CellRect(0, 0).W; w != 40 {
+		t.Fatalf("with override: col 0 width = %d, want 40", w)
+	}
+
+	g.SetOverrides(nil)
+	g.Compute(100, 10)
+	if w := g.CellRect(0, 0).W; w != 30 {
+		t.Fatalf("after clearing override: col 0 width = %d, want 30", w)
+	}
+}
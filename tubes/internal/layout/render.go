@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 )
 
 // Panel represents a renderable UI panel
@@ -31,14 +32,15 @@ func RenderContent(content string, rect Rect, style lipgloss.Style) string {
 		lines = append(lines, "")
 	}
 
-	// Truncate/pad each line to width
+	// Truncate/pad each line to width, measured in display cells (not
+	// bytes/runes) so wide characters and embedded ANSI SGR sequences are
+	// handled the same way Truncate is.
 	for i, line := range lines {
-		// Truncate if too long
-		if len(line) > rect.W {
-			lines[i] = line[:rect.W]
-		} else {
-			// Pad if too short
-			lines[i] = line + strings.Repeat(" ", rect.W-len(line))
+		w := displayWidth(line)
+		if w > rect.W {
+			lines[i] = cutToWidth(line, rect.W)
+		} else if w < rect.W {
+			lines[i] = line + strings.Repeat(" ", rect.W-w)
 		}
 	}
 
@@ -50,26 +52,108 @@ func RenderContent(content string, rect Rect, style lipgloss.Style) string {
 	return result
 }
 
-// Truncate truncates a string to fit within the given width
+// Truncate truncates s to at most width display cells (per
+// go-runewidth, so e.g. a CJK character counts as 2 and a combining mark
+// as 0), treating any embedded CSI/OSC escape sequence as a zero-width,
+// un-splittable unit rather than slicing through it. If truncation cuts
+// off a line with an SGR sequence still active, the result is closed
+// with an SGR reset so the rest of the terminal line isn't left
+// miscolored.
 func Truncate(s string, width int) string {
 	if width <= 0 {
 		return ""
 	}
-	
-	// Handle wide characters properly
-	runes := []rune(s)
-	if len(runes) <= width {
+	if displayWidth(s) <= width {
 		return s
 	}
-	
 	if width <= 3 {
 		return strings.Repeat(".", width)
 	}
-	
-	return string(runes[:width-3]) + "..."
+
+	budget := width - 3
+	var out strings.Builder
+	used := 0
+	done := false
+	activeSGR := false
+
+	scanANSI(s,
+		func(r rune) {
+			if done {
+				return
+			}
+			w := runewidth.RuneWidth(r)
+			if used+w > budget {
+				done = true
+				return
+			}
+			used += w
+			out.WriteRune(r)
+		},
+		func(seq string) {
+			if done {
+				return
+			}
+			out.WriteString(seq)
+			if isSGRReset(seq) {
+				activeSGR = false
+			} else if strings.HasSuffix(seq, "m") {
+				activeSGR = true
+			}
+		},
+	)
+
+	out.WriteString("...")
+	if activeSGR {
+		out.WriteString(ansiReset)
+	}
+	return out.String()
 }
 
-// WrapText wraps text to fit within the given width
+// cutToWidth hard-cuts s to exactly width display cells with no
+// ellipsis, for RenderContent's fixed-width lines. Like Truncate, it
+// treats escape sequences as atomic and closes a still-active SGR state
+// at the cut point with a reset.
+func cutToWidth(s string, width int) string {
+	var out strings.Builder
+	used := 0
+	done := false
+	activeSGR := false
+
+	scanANSI(s,
+		func(r rune) {
+			if done {
+				return
+			}
+			w := runewidth.RuneWidth(r)
+			if used+w > width {
+				done = true
+				return
+			}
+			used += w
+			out.WriteRune(r)
+		},
+		func(seq string) {
+			if done {
+				return
+			}
+			out.WriteString(seq)
+			if isSGRReset(seq) {
+				activeSGR = false
+			} else if strings.HasSuffix(seq, "m") {
+				activeSGR = true
+			}
+		},
+	)
+
+	if activeSGR {
+		out.WriteString(ansiReset)
+	}
+	return out.String()
+}
+
+// WrapText wraps text to fit within the given width, measured in
+// display cells (per go-runewidth) rather than bytes/runes, so wide
+// characters count correctly against the wrap width.
 func WrapText(text string, width int) []string {
 	if width <= 0 {
 		return []string{}
@@ -82,19 +166,24 @@ func WrapText(text string, width int) []string {
 
 	var lines []string
 	var currentLine strings.Builder
+	currentWidth := 0
 
 	for _, word := range words {
+		wordWidth := displayWidth(word)
 		// If adding this word would exceed width, start new line
-		if currentLine.Len() > 0 && currentLine.Len()+1+len(word) > width {
+		if currentWidth > 0 && currentWidth+1+wordWidth > width {
 			lines = append(lines, currentLine.String())
 			currentLine.Reset()
+			currentWidth = 0
 		}
 
 		// Add word to current line
-		if currentLine.Len() > 0 {
+		if currentWidth > 0 {
 			currentLine.WriteString(" ")
+			currentWidth++
 		}
 		currentLine.WriteString(word)
+		currentWidth += wordWidth
 	}
 
 	// Add final line if not empty
@@ -105,6 +194,88 @@ func WrapText(text string, width int) []string {
 	return lines
 }
 
+// WrapANSI is WrapText's ANSI-aware counterpart: words may contain CSI
+// SGR sequences (as markdown/syntax-highlight rendering now produces),
+// and any SGR state still active where a line wraps is replayed at the
+// start of the next line, so color doesn't silently end at whichever
+// line happened to contain the original escape sequence.
+func WrapANSI(text string, width int) []string {
+	if width <= 0 {
+		return []string{}
+	}
+
+	words := splitANSIWords(text)
+	if len(words) == 0 {
+		return []string{}
+	}
+
+	var lines []string
+	var current strings.Builder
+	currentWidth := 0
+	var activeSGR []string
+
+	flush := func() {
+		lines = append(lines, current.String())
+		current.Reset()
+		currentWidth = 0
+		for _, seq := range activeSGR {
+			current.WriteString(seq)
+		}
+	}
+
+	for _, word := range words {
+		wordWidth := displayWidth(word)
+		if currentWidth > 0 && currentWidth+1+wordWidth > width {
+			flush()
+		} else if currentWidth > 0 {
+			current.WriteString(" ")
+			currentWidth++
+		}
+		current.WriteString(word)
+		currentWidth += wordWidth
+
+		scanANSI(word, func(rune) {}, func(seq string) {
+			if isSGRReset(seq) {
+				activeSGR = nil
+			} else if strings.HasSuffix(seq, "m") {
+				activeSGR = append(activeSGR, seq)
+			}
+		})
+	}
+	if current.Len() > 0 {
+		lines = append(lines, current.String())
+	}
+	return lines
+}
+
+// splitANSIWords splits text on whitespace the way strings.Fields does,
+// but via scanANSI so a space byte can never be misread out of the
+// middle of a multi-byte escape sequence.
+func splitANSIWords(text string) []string {
+	var words []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			words = append(words, cur.String())
+			cur.Reset()
+		}
+	}
+	scanANSI(text,
+		func(r rune) {
+			if r == ' ' || r == '\t' || r == '\n' {
+				flush()
+				return
+			}
+			cur.WriteRune(r)
+		},
+		func(seq string) {
+			cur.WriteString(seq)
+		},
+	)
+	flush()
+	return words
+}
+
 // PadRect adds padding to a rectangle, returning the inner content area
 func PadRect(rect Rect, padding [4]int) Rect {
 	// padding: [top, right, bottom, left]
@@ -0,0 +1,262 @@
+package layout
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SplitAxis is the axis along which a Node's children are arranged.
+type SplitAxis int
+
+const (
+	SplitHorizontal SplitAxis = iota // children side-by-side, splitting width
+	SplitVertical                    // children stacked, splitting height
+)
+
+// splitStep is how many cells a single Ctrl+Arrow keypress shifts a
+// split ratio by.
+const splitStep = 1
+
+// Node is one node of a resizable/splittable layout tree: either a leaf
+// wrapping a single Panel, or a split holding two or more Children along
+// Axis, each sized by its entry in Weights (a fractional share of the
+// split's total, the same idea as Grid's Fr unit) and floored by the
+// matching entry in MinSize.
+type Node struct {
+	Axis     SplitAxis
+	Children []*Node
+	Weights  []float64
+	MinSize  []int
+
+	Panel Panel // set on a leaf Node (len(Children) == 0)
+	rect  Rect  // the Rect the last Manager.Compute assigned this node
+}
+
+// NewLeaf wraps panel as a leaf Node.
+func NewLeaf(panel Panel) *Node {
+	return &Node{Panel: panel}
+}
+
+// NewSplit creates an internal Node arranging children along axis.
+// weights and minSizes are parallel to children; a nil weights gives
+// every child an equal share, and a nil minSizes floors every child at 0.
+func NewSplit(axis SplitAxis, children []*Node, weights []float64, minSizes []int) *Node {
+	if weights == nil {
+		weights = make([]float64, len(children))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	if minSizes == nil {
+		minSizes = make([]int, len(children))
+	}
+	return &Node{Axis: axis, Children: children, Weights: weights, MinSize: minSizes}
+}
+
+// ResizeEvent reports that a leaf's Rect changed size on the last
+// Compute, so a caller can propagate the new dimensions downstream (a
+// markdown preview's UpdateWidth, a pty's Setsize) without this package
+// needing to know about either.
+type ResizeEvent struct {
+	Panel Panel
+	Rect  Rect
+}
+
+// Manager walks a layout tree, assigning each leaf Panel a Rect sized by
+// its ancestors' split axis/weights/min-size, and reports every leaf
+// whose Rect changed since the previous Compute via OnResize.
+type Manager struct {
+	root     *Node
+	OnResize func(ResizeEvent)
+}
+
+// NewManager creates a Manager for the tree rooted at root.
+func NewManager(root *Node) *Manager {
+	return &Manager{root: root}
+}
+
+// Root returns the tree's root Node, e.g. to route HandleInput through it.
+func (m *Manager) Root() *Node {
+	return m.root
+}
+
+// Compute assigns rect to the tree's root and recurses, updating every
+// leaf Panel via Update and firing OnResize for any leaf whose Rect this
+// call changed.
+func (m *Manager) Compute(rect Rect) {
+	m.compute(m.root, rect)
+}
+
+func (m *Manager) compute(n *Node, rect Rect) {
+	if n == nil {
+		return
+	}
+	changed := n.rect != rect
+	n.rect = rect
+
+	if len(n.Children) == 0 {
+		if n.Panel != nil {
+			n.Panel.Update(rect)
+			if changed && m.OnResize != nil {
+				m.OnResize(ResizeEvent{Panel: n.Panel, Rect: rect})
+			}
+		}
+		return
+	}
+
+	sizes := n.distribute(rect)
+	offset := 0
+	for i, child := range n.Children {
+		var childRect Rect
+		if n.Axis == SplitHorizontal {
+			childRect = Rect{X: rect.X + offset, Y: rect.Y, W: sizes[i], H: rect.H}
+		} else {
+			childRect = Rect{X: rect.X, Y: rect.Y + offset, W: rect.W, H: sizes[i]}
+		}
+		offset += sizes[i]
+		m.compute(child, childRect)
+	}
+}
+
+// distribute splits rect's width (SplitHorizontal) or height
+// (SplitVertical) across n.Children by n.Weights, flooring each at the
+// matching n.MinSize.
+func (n *Node) distribute(rect Rect) []int {
+	total := rect.W
+	if n.Axis == SplitVertical {
+		total = rect.H
+	}
+
+	sizes := make([]int, len(n.Children))
+	sumW := 0.0
+	for _, w := range n.Weights {
+		sumW += w
+	}
+	if sumW <= 0 {
+		return sizes
+	}
+
+	remain := total
+	for i, w := range n.Weights {
+		v := int(math.Round(w / sumW * float64(total)))
+		if i < len(n.MinSize) && v < n.MinSize[i] {
+			v = n.MinSize[i]
+		}
+		sizes[i] = v
+		remain -= v
+	}
+
+	// Absorb rounding drift (or a min-size floor pushing the total over)
+	// into the last child, the same "last slice gets the remainder"
+	// convention Grid.distribute uses.
+	if len(sizes) > 0 {
+		sizes[len(sizes)-1] += remain
+		if sizes[len(sizes)-1] < 0 {
+			sizes[len(sizes)-1] = 0
+		}
+	}
+	return sizes
+}
+
+// HandleInput lets the user adjust a split ratio interactively: Ctrl+Arrow
+// shifts the ratio between this node's first two children by one step,
+// and "drag:<axis>:<delta>" (as a mouse-drag handler would report it)
+// adjusts it by delta cells directly. Anything unrecognized is offered to
+// each child in turn, then - on a leaf - to the wrapped Panel's own
+// HandleInput, so a split Node composes with the "HandleInput(key
+// string) bool" every Panel already implements.
+func (n *Node) HandleInput(key string) bool {
+	switch key {
+	case "ctrl+left":
+		return n.shiftRatio(SplitHorizontal, -splitStep)
+	case "ctrl+right":
+		return n.shiftRatio(SplitHorizontal, splitStep)
+	case "ctrl+up":
+		return n.shiftRatio(SplitVertical, -splitStep)
+	case "ctrl+down":
+		return n.shiftRatio(SplitVertical, splitStep)
+	}
+	if axis, delta, ok := parseDrag(key); ok {
+		return n.shiftRatio(axis, delta)
+	}
+
+	for _, child := range n.Children {
+		if child.HandleInput(key) {
+			return true
+		}
+	}
+	if n.Panel != nil {
+		return n.Panel.HandleInput(key)
+	}
+	return false
+}
+
+// shiftRatio moves deltaCells of n's current size from its second child's
+// share to its first child's (or the reverse, for a negative delta),
+// provided n splits along axis and has at least two children - a no-op
+// (returning false) otherwise so HandleInput falls through to the
+// children/Panel instead of swallowing an input meant for them.
+func (n *Node) shiftRatio(axis SplitAxis, deltaCells int) bool {
+	if n.Axis != axis || len(n.Children) < 2 {
+		return false
+	}
+
+	total := n.rect.W
+	if axis == SplitVertical {
+		total = n.rect.H
+	}
+	if total <= 0 {
+		sumW := 0.0
+		for _, w := range n.Weights {
+			sumW += w
+		}
+		n.Weights[0] += float64(deltaCells) / 100 * sumW
+		return true
+	}
+
+	deltaWeight := float64(deltaCells) / float64(total) * weightSum(n.Weights)
+	n.Weights[0] += deltaWeight
+	n.Weights[1] -= deltaWeight
+	if n.Weights[0] < 0 {
+		n.Weights[1] += n.Weights[0]
+		n.Weights[0] = 0
+	}
+	if n.Weights[1] < 0 {
+		n.Weights[0] += n.Weights[1]
+		n.Weights[1] = 0
+	}
+	return true
+}
+
+// parseDrag parses a "drag:<axis>:<delta>" key, the convention a mouse
+// handler translating a border-drag gesture into HandleInput calls is
+// expected to use ("drag:horizontal:3" == dragged a vertical border 3
+// cells right). Any other shape reports ok = false.
+func parseDrag(key string) (axis SplitAxis, delta int, ok bool) {
+	parts := strings.SplitN(key, ":", 3)
+	if len(parts) != 3 || parts[0] != "drag" {
+		return 0, 0, false
+	}
+	switch parts[1] {
+	case "horizontal":
+		axis = SplitHorizontal
+	case "vertical":
+		axis = SplitVertical
+	default:
+		return 0, 0, false
+	}
+	delta, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return 0, 0, false
+	}
+	return axis, delta, true
+}
+
+func weightSum(weights []float64) float64 {
+	sum := 0.0
+	for _, w := range weights {
+		sum += w
+	}
+	return sum
+}
@@ -0,0 +1,121 @@
+package layout
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// NodeConfig is the panels.toml-serializable shape of a layout tree: a
+// leaf names the Panel it wraps (resolved against a caller-supplied
+// lookup table, since a Panel itself isn't something TOML can encode),
+// and a split nests its Children the same way GridSpec nests Rows/Cols.
+type NodeConfig struct {
+	Axis     string       `toml:"axis"` // "horizontal" or "vertical"; ignored on a leaf
+	Panel    string       `toml:"panel,omitempty"`
+	Weights  []float64    `toml:"weight,omitempty"`
+	MinSize  []int        `toml:"min_size,omitempty"`
+	Children []NodeConfig `toml:"child,omitempty"`
+}
+
+// layoutFile is the top-level panels.toml shape this package reads and
+// writes, wrapping a single NodeConfig under a [layout] table so it can
+// sit alongside the [[panel]]/[[keybind]] tables other config already
+// occupies at the top level.
+type layoutFile struct {
+	Layout NodeConfig `toml:"layout"`
+}
+
+// SaveLayout serializes root to path as TOML, resolving each leaf's
+// Panel back to a name via nameOf.
+func SaveLayout(path string, root *Node, nameOf func(Panel) string) error {
+	cfg, err := toConfig(root, nameOf)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return toml.NewEncoder(f).Encode(layoutFile{Layout: cfg})
+}
+
+// LoadLayout reads a layout tree previously written by SaveLayout from
+// path, resolving each leaf's Panel name against panels.
+func LoadLayout(path string, panels map[string]Panel) (*Node, error) {
+	var file layoutFile
+	if _, err := toml.DecodeFile(path, &file); err != nil {
+		return nil, err
+	}
+	return fromConfig(file.Layout, panels)
+}
+
+func toConfig(n *Node, nameOf func(Panel) string) (NodeConfig, error) {
+	if len(n.Children) == 0 {
+		if n.Panel == nil {
+			return NodeConfig{}, fmt.Errorf("layout: leaf node has no panel")
+		}
+		return NodeConfig{Panel: nameOf(n.Panel)}, nil
+	}
+
+	cfg := NodeConfig{
+		Axis:    axisName(n.Axis),
+		Weights: n.Weights,
+		MinSize: n.MinSize,
+	}
+	for _, child := range n.Children {
+		childCfg, err := toConfig(child, nameOf)
+		if err != nil {
+			return NodeConfig{}, err
+		}
+		cfg.Children = append(cfg.Children, childCfg)
+	}
+	return cfg, nil
+}
+
+func fromConfig(cfg NodeConfig, panels map[string]Panel) (*Node, error) {
+	if len(cfg.Children) == 0 {
+		panel, ok := panels[cfg.Panel]
+		if !ok {
+			return nil, fmt.Errorf("layout: unknown panel %q", cfg.Panel)
+		}
+		return NewLeaf(panel), nil
+	}
+
+	axis, err := parseAxisName(cfg.Axis)
+	if err != nil {
+		return nil, err
+	}
+
+	children := make([]*Node, len(cfg.Children))
+	for i, childCfg := range cfg.Children {
+		child, err := fromConfig(childCfg, panels)
+		if err != nil {
+			return nil, err
+		}
+		children[i] = child
+	}
+	return NewSplit(axis, children, cfg.Weights, cfg.MinSize), nil
+}
+
+func axisName(axis SplitAxis) string {
+	if axis == SplitVertical {
+		return "vertical"
+	}
+	return "horizontal"
+}
+
+func parseAxisName(name string) (SplitAxis, error) {
+	switch name {
+	case "horizontal", "":
+		return SplitHorizontal, nil
+	case "vertical":
+		return SplitVertical, nil
+	default:
+		return 0, fmt.Errorf("layout: unknown split axis %q", name)
+	}
+}
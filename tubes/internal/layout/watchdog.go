@@ -112,6 +112,164 @@ func (w *UIWatchdog) rectsOverlap(r1, r2 Rect) bool {
 	return !(r1.X+r1.W <= r2.X || r2.X+r2.W <= r1.X || r1.Y+r1.H <= r2.Y || r2.Y+r2.H <= r1.Y)
 }
 
+// RepairEvent describes one corrective action Repair took to a GridSpec,
+// structured rather than a string like Issues so a caller can surface
+// specifics - e.g. a status bar showing "panel X hidden due to terminal
+// size" instead of just logging it.
+type RepairEvent struct {
+	Axis   string // "row" or "col"
+	Index  int    // index into GridSpec.Rows/Cols
+	Action string // "collapsed" or "grew-min"
+	Detail string // human-readable summary for logs/status bar
+}
+
+const (
+	maxRepairPasses = 8 // backstop against a GridSpec with no satisfiable fixed point
+	repairMinFloor  = 1 // the Min Repair grows a starved track to - enough to render something
+)
+
+// Repair re-solves grid against this watchdog's terminal size, mutating
+// its GridSpec and re-running Grid.Compute until the layout fits or a
+// fixed point is reached: a genuine overflow (rows/cols summing to more
+// than the terminal has) collapses the lowest-Priority Optional row/col
+// still taking space, and a starved track (computed down to zero despite
+// not being Optional) has its Min bumped so the next Compute guarantees
+// it something to draw. Returns every change it made, in order.
+func (w *UIWatchdog) Repair(grid *Grid) []RepairEvent {
+	var events []RepairEvent
+
+	for pass := 0; pass < maxRepairPasses; pass++ {
+		grid.Compute(w.TerminalWidth, w.TerminalHeight)
+
+		rowOver := trackOverflow(grid.rows, grid.spec.Gaps.Row, w.TerminalHeight-grid.spec.Padding.T-grid.spec.Padding.B)
+		colOver := trackOverflow(grid.cols, grid.spec.Gaps.Col, w.TerminalWidth-grid.spec.Padding.L-grid.spec.Padding.R)
+
+		var event *RepairEvent
+		switch {
+		case rowOver > 0:
+			event = collapseOptionalRow(&grid.spec)
+		case colOver > 0:
+			event = collapseOptionalCol(&grid.spec)
+		}
+		if event == nil {
+			event = growStarvedRow(&grid.spec, grid.rows)
+		}
+		if event == nil {
+			event = growStarvedCol(&grid.spec, grid.cols)
+		}
+		if event == nil {
+			break // fixed point: nothing left to collapse or grow
+		}
+		events = append(events, *event)
+	}
+
+	return events
+}
+
+// trackOverflow reports how many cells over budget a distributed track
+// list (Grid.rows or Grid.cols) is - 0 or negative when it fits.
+func trackOverflow(sizes []int, gap, budget int) int {
+	total := 0
+	for _, v := range sizes {
+		total += v
+	}
+	if len(sizes) > 1 {
+		total += gap * (len(sizes) - 1)
+	}
+	return total - budget
+}
+
+// collapseOptionalRow pins the lowest-Priority Optional row still taking
+// space to zero height, freeing its space for rows that can't be dropped.
+func collapseOptionalRow(spec *GridSpec) *RepairEvent {
+	idx := lowestPriorityOptionalRow(spec.Rows)
+	if idx < 0 {
+		return nil
+	}
+	spec.Rows[idx].Height = Unit{Kind: Px, Val: 0}
+	spec.Rows[idx].Min = 0
+	return &RepairEvent{
+		Axis: "row", Index: idx, Action: "collapsed",
+		Detail: fmt.Sprintf("row %d hidden due to terminal size", idx),
+	}
+}
+
+// collapseOptionalCol is the column analogue of collapseOptionalRow.
+func collapseOptionalCol(spec *GridSpec) *RepairEvent {
+	idx := lowestPriorityOptionalCol(spec.Cols)
+	if idx < 0 {
+		return nil
+	}
+	spec.Cols[idx].Width = Unit{Kind: Px, Val: 0}
+	spec.Cols[idx].Min = 0
+	return &RepairEvent{
+		Axis: "col", Index: idx, Action: "collapsed",
+		Detail: fmt.Sprintf("col %d hidden due to terminal size", idx),
+	}
+}
+
+func lowestPriorityOptionalRow(rows []Row) int {
+	best := -1
+	for i, r := range rows {
+		if !r.Optional || (r.Height.Kind == Px && r.Height.Val == 0) {
+			continue // not ours to collapse, or already collapsed
+		}
+		if best < 0 || r.Priority < rows[best].Priority {
+			best = i
+		}
+	}
+	return best
+}
+
+func lowestPriorityOptionalCol(cols []Col) int {
+	best := -1
+	for i, c := range cols {
+		if !c.Optional || (c.Width.Kind == Px && c.Width.Val == 0) {
+			continue
+		}
+		if best < 0 || c.Priority < cols[best].Priority {
+			best = i
+		}
+	}
+	return best
+}
+
+// growStarvedRow bumps the Min of the first non-Optional row Compute left
+// at zero height despite it not being marked Optional, guaranteeing it
+// something to draw on the next pass instead of silently vanishing.
+func growStarvedRow(spec *GridSpec, computed []int) *RepairEvent {
+	for i := range spec.Rows {
+		if spec.Rows[i].Optional || spec.Rows[i].Min > 0 {
+			continue
+		}
+		if i < len(computed) && computed[i] == 0 {
+			spec.Rows[i].Min = repairMinFloor
+			return &RepairEvent{
+				Axis: "row", Index: i, Action: "grew-min",
+				Detail: fmt.Sprintf("row %d grown to its minimum size", i),
+			}
+		}
+	}
+	return nil
+}
+
+// growStarvedCol is the column analogue of growStarvedRow.
+func growStarvedCol(spec *GridSpec, computed []int) *RepairEvent {
+	for i := range spec.Cols {
+		if spec.Cols[i].Optional || spec.Cols[i].Min > 0 {
+			continue
+		}
+		if i < len(computed) && computed[i] == 0 {
+			spec.Cols[i].Min = repairMinFloor
+			return &RepairEvent{
+				Axis: "col", Index: i, Action: "grew-min",
+				Detail: fmt.Sprintf("col %d grown to its minimum size", i),
+			}
+		}
+	}
+	return nil
+}
+
 // SafeRenderContent renders content with automatic truncation and bounds checking
 func (w *UIWatchdog) SafeRenderContent(name string, content string, rect Rect, style lipgloss.Style) string {
 	// Register for monitoring
@@ -166,6 +324,22 @@ func (w *UIWatchdog) AdaptiveStyle(rect Rect, baseStyle lipgloss.Style) lipgloss
 	return style.Width(rect.W).Height(rect.H)
 }
 
+// AdaptiveBorderSides reports which of sides a Bordered panel should
+// actually draw for rect, degrading the same way AdaptiveStyle does but
+// one edge at a time instead of stripping the whole border: a rect too
+// narrow for left/right borders drops just those (keeping top/bottom if
+// requested), and only a rect too small for any border at all - the same
+// cutoff AdaptiveStyle uses to drop padding and margin - drops to none.
+func (w *UIWatchdog) AdaptiveBorderSides(rect Rect, sides BorderSide) BorderSide {
+	if rect.W < 5 || rect.H < 3 {
+		return 0
+	}
+	if rect.W < 10 {
+		return sides &^ (BorderLeft | BorderRight)
+	}
+	return sides
+}
+
 // Report generates a diagnostic report
 func (w *UIWatchdog) Report() string {
 	var report strings.Builder
@@ -0,0 +1,182 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"tubes/internal/agent"
+	"tubes/internal/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// agentChecklistItem is one line of the sidebar's collapsible tool-call
+// checklist - collapsed to a single summary line unless expanded.
+type agentChecklistItem struct {
+	Tool     string
+	Args     string
+	Result   string
+	Expanded bool
+}
+
+// agentEventMsg wraps one agent.Event for the Bubbletea loop - see
+// waitForAgentEvent for how the channel gets turned into a stream of
+// these.
+type agentEventMsg agent.Event
+
+// cmdAgent is the "/agent" command: "/agent backend NAME" switches
+// backends, "/agent fork NODE PROMPT" branches an existing task,
+// anything else is treated as a new goal for the tasks-mode runner.
+func (m *Model) cmdAgent(model *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		return func() tea.Msg {
+			return errorMsg("Usage: /agent <goal> | /agent backend NAME | /agent fork NODE <prompt>")
+		}
+	}
+
+	switch args[0] {
+	case "backend":
+		if len(args) < 2 {
+			return func() tea.Msg { return errorMsg("Usage: /agent backend NAME") }
+		}
+		return m.cmdAgentBackend(args[1])
+
+	case "fork":
+		if len(args) < 3 {
+			return func() tea.Msg { return errorMsg("Usage: /agent fork NODE <prompt>") }
+		}
+		return m.cmdAgentFork(args[1], strings.Join(args[2:], " "))
+
+	default:
+		return m.cmdAgentGoal(strings.Join(args, " "))
+	}
+}
+
+func (m *Model) cmdAgentBackend(name string) tea.Cmd {
+	return func() tea.Msg {
+		b, err := agent.NewBackend(name)
+		if err != nil {
+			return errorMsg(err.Error())
+		}
+		m.agentBackend = b
+		return statusMsg("Agent backend: " + b.Name())
+	}
+}
+
+func (m *Model) cmdAgentGoal(goal string) tea.Cmd {
+	if m.agentBackend == nil {
+		return func() tea.Msg {
+			return errorMsg("No agent backend selected - try /agent backend ollama")
+		}
+	}
+
+	taskID := fmt.Sprintf("task-%d", time.Now().UnixNano())
+	m.agentSession = agent.NewSession(taskID, m.agentBackend, goal)
+	m.agentChecklist = nil
+	m.Mode = "tasks"
+
+	return tea.Batch(m.refreshSidebar(), m.startAgentTurn())
+}
+
+func (m *Model) cmdAgentFork(nodeID, prompt string) tea.Cmd {
+	if m.agentSession == nil {
+		return func() tea.Msg { return errorMsg("No active task to fork") }
+	}
+	if _, err := m.agentSession.Fork(nodeID, prompt); err != nil {
+		return func() tea.Msg { return errorMsg(err.Error()) }
+	}
+	return m.startAgentTurn()
+}
+
+// startAgentTurn runs one Session.Run and starts listening for its
+// events - the toolContext wires open_file/theme_use back into this
+// Model the same way the matching cmd* Command would.
+func (m *Model) startAgentTurn() tea.Cmd {
+	ctx := context.Background()
+	tc := &agent.ToolContext{
+		OpenFile: func(path string) error {
+			return nil // content is already returned to the agent by the tool; the panel is updated from the final reply
+		},
+		ThemeUse: func(name string) error {
+			t, err := theme.Load(name)
+			if err != nil {
+				return err
+			}
+			styles, err := theme.Compile(t)
+			if err != nil {
+				return err
+			}
+			m.Theme = t
+			m.Styles = styles
+			return theme.SetCurrent(name)
+		},
+	}
+
+	m.agentEvents = m.agentSession.Run(ctx, tc)
+	return m.waitForAgentEvent()
+}
+
+// waitForAgentEvent blocks for the next event off m.agentEvents and
+// reissues itself, the same "re-subscribe after every message" idiom
+// tubes/001's watchLoop uses for its fsnotify channel, so the Bubbletea
+// loop keeps receiving agent output until the channel closes.
+func (m *Model) waitForAgentEvent() tea.Cmd {
+	return func() tea.Msg {
+		event, ok := <-m.agentEvents
+		if !ok {
+			return nil
+		}
+		return agentEventMsg(event)
+	}
+}
+
+// handleAgentEvent applies one agentEventMsg to Model state, returning
+// the tea.Cmd that keeps listening for the next one (nil once the
+// channel is done).
+func (m *Model) handleAgentEvent(msg agentEventMsg) tea.Cmd {
+	switch msg.Type {
+	case "chunk":
+		m.Main.SetContent(m.Main.View() + msg.Text)
+		return m.waitForAgentEvent()
+
+	case "tool":
+		m.agentChecklist = append(m.agentChecklist, agentChecklistItem{Tool: msg.Tool, Args: msg.Args, Result: msg.Text})
+		m.refreshSidebar()
+		return m.waitForAgentEvent()
+
+	case "done":
+		m.Main.SetContent(msg.Text)
+		m.Status.SetContent("Agent finished")
+		return nil
+
+	case "error":
+		m.Status.SetContent("Agent error: " + msg.Err.Error())
+		return nil
+	}
+	return m.waitForAgentEvent()
+}
+
+// generateAgentChecklist renders the sidebar's collapsible tool-call
+// list for tasks mode - each entry collapsed to "tool(args)" unless
+// Expanded, in which case its result is shown indented underneath.
+func (m *Model) generateAgentChecklist() string {
+	if m.agentSession == nil {
+		return ""
+	}
+
+	var lines []string
+	lines = append(lines, "")
+	lines = append(lines, fmt.Sprintf("🤖 Agent (%s)", m.agentSession.Tree.Backend))
+	for _, item := range m.agentChecklist {
+		box := "▸"
+		if item.Expanded {
+			box = "▾"
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s(%s)", box, item.Tool, item.Args))
+		if item.Expanded {
+			lines = append(lines, "      "+item.Result)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
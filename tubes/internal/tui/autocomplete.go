@@ -3,11 +3,22 @@ package tui
 import (
 	"sort"
 	"strings"
+
+	"github.com/sahilm/fuzzy"
 )
 
+// Match is a single AutoComplete candidate together with the rune ranges
+// that matched the query, so a renderer can bold them instead of just
+// showing a flat list.
+type Match struct {
+	Text   string
+	Ranges [][2]int // half-open [start,end) rune ranges into Text
+}
+
 // AutoComplete provides command completion functionality
 type AutoComplete struct {
 	commands []string
+	paths    []string
 }
 
 // NewAutoComplete creates a new autocomplete instance
@@ -17,27 +28,80 @@ func NewAutoComplete(commands map[string]Command) *AutoComplete {
 		cmdList = append(cmdList, "/"+name)
 	}
 	sort.Strings(cmdList)
-	
+
 	return &AutoComplete{
 		commands: cmdList,
 	}
 }
 
-// Complete returns completion suggestions for the given input
-func (ac *AutoComplete) Complete(input string) []string {
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return ac.commands
+// SetPaths records file paths - e.g. harvested from generateProjectTree -
+// so /open completions can be ranked alongside command names instead of
+// only ever completing "/foo".
+func (ac *AutoComplete) SetPaths(paths []string) {
+	ac.paths = paths
+}
+
+// candidates returns every completable string, commands first so a tied
+// fuzzy score still favors a command over a same-named file path.
+func (ac *AutoComplete) candidates() []string {
+	out := make([]string, 0, len(ac.commands)+len(ac.paths))
+	out = append(out, ac.commands...)
+	out = append(out, ac.paths...)
+	return out
+}
+
+// FuzzyMatch ranks every candidate against query using sahilm/fuzzy's
+// subsequence scoring, replacing the old strings.HasPrefix scan so "ot"
+// still finds "/theme" or "cmd/tubes/main.go". An empty query returns
+// every candidate unscored, in their natural order.
+func (ac *AutoComplete) FuzzyMatch(query string) []Match {
+	candidates := ac.candidates()
+	if strings.TrimSpace(query) == "" {
+		out := make([]Match, len(candidates))
+		for i, c := range candidates {
+			out[i] = Match{Text: c}
+		}
+		return out
+	}
+
+	found := fuzzy.Find(query, candidates)
+	out := make([]Match, len(found))
+	for i, f := range found {
+		out[i] = Match{Text: f.Str, Ranges: matchedRanges(f.MatchedIndexes)}
 	}
-	
-	var matches []string
-	for _, cmd := range ac.commands {
-		if strings.HasPrefix(cmd, input) {
-			matches = append(matches, cmd)
+	return out
+}
+
+// matchedRanges collapses consecutive matched rune indexes into half-open
+// ranges, so a renderer can bold a run of runes with one style call
+// instead of one per rune.
+func matchedRanges(idx []int) [][2]int {
+	if len(idx) == 0 {
+		return nil
+	}
+	var ranges [][2]int
+	start, prev := idx[0], idx[0]
+	for _, i := range idx[1:] {
+		if i == prev+1 {
+			prev = i
+			continue
 		}
+		ranges = append(ranges, [2]int{start, prev + 1})
+		start, prev = i, i
+	}
+	ranges = append(ranges, [2]int{start, prev + 1})
+	return ranges
+}
+
+// Complete returns completion suggestions for the given input, ranked by
+// fuzzy score.
+func (ac *AutoComplete) Complete(input string) []string {
+	matches := ac.FuzzyMatch(input)
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = m.Text
 	}
-	
-	return matches
+	return out
 }
 
 // GetNextCompletion cycles through completions for the given input
@@ -46,7 +110,7 @@ func (ac *AutoComplete) GetNextCompletion(input string, currentIndex int) (strin
 	if len(matches) == 0 {
 		return input, -1
 	}
-	
+
 	nextIndex := (currentIndex + 1) % len(matches)
 	return matches[nextIndex], nextIndex
 }
@@ -57,7 +121,7 @@ func (ac *AutoComplete) GetPrevCompletion(input string, currentIndex int) (strin
 	if len(matches) == 0 {
 		return input, -1
 	}
-	
+
 	prevIndex := currentIndex - 1
 	if prevIndex < 0 {
 		prevIndex = len(matches) - 1
@@ -71,10 +135,10 @@ func (ac *AutoComplete) GetCompletionHelp(input string) string {
 	if len(matches) == 0 {
 		return "No completions available"
 	}
-	
+
 	if len(matches) == 1 {
 		return "Press Tab to complete: " + matches[0]
 	}
-	
+
 	return "Available completions: " + strings.Join(matches, ", ")
-}
\ No newline at end of file
+}
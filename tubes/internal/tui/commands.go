@@ -59,6 +59,21 @@ func (m *Model) registerCommands() {
 			Description: "Exit application",
 			Execute:     m.cmdQuit,
 		},
+		"agent": {
+			Name:        "agent",
+			Description: "Tool-calling task agent (GOAL|backend NAME|fork NODE PROMPT)",
+			Execute:     m.cmdAgent,
+		},
+		"tree": {
+			Name:        "tree",
+			Description: "Collapse/expand a project tree directory (collapse|expand PATH)",
+			Execute:     m.cmdTree,
+		},
+		"panel": {
+			Name:        "panel",
+			Description: "Ad-hoc shell-backed panels (new NAME CMD|run NAME|list|rm NAME)",
+			Execute:     m.cmdPanel,
+		},
 	}
 }
 
@@ -76,6 +91,9 @@ func (m *Model) cmdHelp(model *Model, args []string) tea.Cmd {
 	lines = append(lines, "")
 	lines = append(lines, "Keyboard shortcuts:")
 	lines = append(lines, "  Tab    - Switch between modes")
+	lines = append(lines, "  Ctrl+P - Command palette")
+	lines = append(lines, "  Ctrl+R - Reverse search history")
+	lines = append(lines, "  Up/Down - Recall history (scoped to current mode)")
 	lines = append(lines, "  Ctrl+C - Quit")
 	lines = append(lines, "  Esc    - Quit")
 	
@@ -97,6 +115,7 @@ func (m *Model) cmdMode(model *Model, args []string) tea.Cmd {
 	switch args[0] {
 	case "self", "tasks":
 		model.Mode = args[0]
+		model.dispatchPanelDeps()
 		return tea.Batch(
 			model.refreshSidebar(),
 			func() tea.Msg {
@@ -209,6 +228,39 @@ func (m *Model) cmdOpen(model *Model, args []string) tea.Cmd {
 	}
 }
 
+func (m *Model) cmdTree(model *Model, args []string) tea.Cmd {
+	if len(args) < 2 {
+		return func() tea.Msg {
+			return errorMsg("Usage: /tree collapse|expand PATH")
+		}
+	}
+	if model.Project == nil {
+		return func() tea.Msg {
+			return errorMsg("No project tree watcher active")
+		}
+	}
+
+	var collapsed bool
+	switch args[0] {
+	case "collapse":
+		collapsed = true
+	case "expand":
+		collapsed = false
+	default:
+		return func() tea.Msg {
+			return errorMsg("Usage: /tree collapse|expand PATH")
+		}
+	}
+
+	path := args[1]
+	return func() tea.Msg {
+		if !model.Project.SetCollapsed(path, collapsed) {
+			return errorMsg("Unknown directory: " + path)
+		}
+		return sidebarContentMsg(model.generateSidebarContent())
+	}
+}
+
 func (m *Model) cmdClear(model *Model, args []string) tea.Cmd {
 	return func() tea.Msg {
 		return clearMsg{}
@@ -232,6 +284,10 @@ func (m *Model) generateSidebarContent() string {
 }
 
 func (m *Model) generateProjectTree() string {
+	if m.Project != nil {
+		return m.Project.Render()
+	}
+
 	var lines []string
 	lines = append(lines, "Project Files:")
 	lines = append(lines, "")
@@ -285,6 +341,45 @@ func (m *Model) generateProjectTree() string {
 	return strings.Join(lines, "\n")
 }
 
+// projectFilePaths walks the project tree the same way generateProjectTree
+// does and returns the plain relative file paths (directories excluded),
+// so the command palette can rank them alongside command names for
+// /open's fuzzy completion.
+func (m *Model) projectFilePaths() []string {
+	var paths []string
+
+	root, _ := os.Getwd()
+	_ = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		if strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		skipDirs := []string{"node_modules", "target", "build", "dist", ".git"}
+		for _, skip := range skipDirs {
+			if d.IsDir() && d.Name() == skip {
+				return filepath.SkipDir
+			}
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		relPath, _ := filepath.Rel(root, path)
+		paths = append(paths, relPath)
+		return nil
+	})
+
+	return paths
+}
+
 func (m *Model) generateTaskList() string {
 	var lines []string
 	lines = append(lines, "Task Management:")
@@ -293,13 +388,17 @@ func (m *Model) generateTaskList() string {
 	lines = append(lines, "  • No tasks yet")
 	lines = append(lines, "")
 	lines = append(lines, "🔧 Available Actions")
-	lines = append(lines, "  • /task new")
-	lines = append(lines, "  • /task list")
-	lines = append(lines, "  • /task switch")
+	lines = append(lines, "  • /agent <goal>")
+	lines = append(lines, "  • /agent backend ollama|openai|anthropic")
+	lines = append(lines, "  • /agent fork NODE <prompt>")
 	lines = append(lines, "")
 	lines = append(lines, "💡 Quick Start")
 	lines = append(lines, "  Use /help for commands")
-	
+
+	if checklist := m.generateAgentChecklist(); checklist != "" {
+		lines = append(lines, checklist)
+	}
+
 	return strings.Join(lines, "\n")
 }
 
@@ -0,0 +1,217 @@
+package tui
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// HistoryEntry is one executed command, persisted to
+// ~/.tubes/history.jsonl so every concurrently running tubes instance
+// can see it.
+type HistoryEntry struct {
+	Ts         int64    `json:"ts"`
+	Mode       string   `json:"mode"`
+	Command    string   `json:"command"`
+	Args       []string `json:"args"`
+	ExitStatus string   `json:"exitStatus"`
+}
+
+// HistoryStore holds every HistoryEntry loaded from (and appended to)
+// ~/.tubes/history.jsonl, reloading from disk on fsnotify writes so
+// concurrently running tubes instances converge on the same history
+// instead of only ever seeing their own commands.
+type HistoryStore struct {
+	mu      sync.Mutex
+	path    string
+	entries []HistoryEntry
+
+	watcher *fsnotify.Watcher
+	changed chan struct{}
+}
+
+// NewHistoryStore opens (creating if needed) ~/.tubes/history.jsonl,
+// loads its existing entries, and starts watching it for writes from
+// other tubes instances. A failure to start the watcher isn't fatal -
+// history still works, it just won't live-reload.
+func NewHistoryStore() (*HistoryStore, error) {
+	dir, err := historyDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	hs := &HistoryStore{path: filepath.Join(dir, "history.jsonl"), changed: make(chan struct{}, 1)}
+	if err := hs.reload(); err != nil {
+		return nil, err
+	}
+
+	if watcher, err := fsnotify.NewWatcher(); err == nil {
+		if err := watcher.Add(dir); err == nil {
+			hs.watcher = watcher
+			go hs.watchLoop()
+		} else {
+			watcher.Close()
+		}
+	}
+
+	return hs, nil
+}
+
+func historyDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".tubes"), nil
+}
+
+func (hs *HistoryStore) watchLoop() {
+	for {
+		select {
+		case event, ok := <-hs.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != hs.path {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if err := hs.reload(); err == nil {
+				select {
+				case hs.changed <- struct{}{}:
+				default:
+				}
+			}
+		case _, ok := <-hs.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+// reload re-reads every entry from disk, so a write from another tubes
+// instance - or our own Append - is reflected in Entries/ForMode/Search.
+func (hs *HistoryStore) reload() error {
+	f, err := os.Open(hs.path)
+	if os.IsNotExist(err) {
+		hs.mu.Lock()
+		hs.entries = nil
+		hs.mu.Unlock()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var e HistoryEntry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			continue // a line from a half-written concurrent append
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	hs.mu.Lock()
+	hs.entries = entries
+	hs.mu.Unlock()
+	return nil
+}
+
+// Append records a new entry, both in memory and to history.jsonl, so
+// the next recall and every other running tubes instance sees it.
+func (hs *HistoryStore) Append(e HistoryEntry) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(hs.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+
+	hs.mu.Lock()
+	hs.entries = append(hs.entries, e)
+	hs.mu.Unlock()
+	return nil
+}
+
+// Entries returns a copy of every entry, oldest first.
+func (hs *HistoryStore) Entries() []HistoryEntry {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	out := make([]HistoryEntry, len(hs.entries))
+	copy(out, hs.entries)
+	return out
+}
+
+// ForMode returns entries recorded in the given mode, oldest first - the
+// default scope for Up/Down recall and Ctrl+R search.
+func (hs *HistoryStore) ForMode(mode string) []HistoryEntry {
+	all := hs.Entries()
+	out := make([]HistoryEntry, 0, len(all))
+	for _, e := range all {
+		if e.Mode == mode {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Search returns, most-recent-first, every entry whose Command contains
+// query - scoped to mode unless allModes is set (the Ctrl+R toggle).
+func (hs *HistoryStore) Search(query, mode string, allModes bool) []HistoryEntry {
+	all := hs.Entries()
+	if !allModes {
+		all = hs.ForMode(mode)
+	}
+	var out []HistoryEntry
+	for i := len(all) - 1; i >= 0; i-- {
+		if query == "" || strings.Contains(all[i].Command, query) {
+			out = append(out, all[i])
+		}
+	}
+	return out
+}
+
+// Close stops the fsnotify watcher, if one was started.
+func (hs *HistoryStore) Close() error {
+	if hs.watcher == nil {
+		return nil
+	}
+	return hs.watcher.Close()
+}
+
+// Changed receives a value whenever another process appended to
+// history.jsonl - for the Bubbletea loop to turn into a refresh the same
+// way tubes/001's sectionWatch turns an fsnotify event into a
+// treeChangedMsg.
+func (hs *HistoryStore) Changed() <-chan struct{} {
+	return hs.changed
+}
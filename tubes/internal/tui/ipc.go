@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"tubes/internal/theme"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// rpcCallMsg carries one ipc.Server call into the Bubbletea Update loop,
+// so a method like mode.set or file.load touches Model state only on the
+// goroutine that already owns it, instead of racing the program's own
+// key/window/command messages.
+type rpcCallMsg struct {
+	Method string
+	Params json.RawMessage
+	Reply  chan rpcResult
+}
+
+type rpcResult struct {
+	Value interface{}
+	Err   error
+}
+
+// Notifier pushes a JSON-RPC 2.0 notification to every connected
+// tubesctl/editor/indexer client - ipc.Server.Notify, wired in from
+// cmd/tubes/main.go once the socket is listening.
+type Notifier interface {
+	Notify(method string, params interface{})
+}
+
+// RPCBridge implements ipc.Dispatcher by replaying every call through a
+// running tea.Program's Send, so an ipc.Server goroutine can drive the
+// TUI without ever touching Model directly.
+type RPCBridge struct {
+	program *tea.Program
+}
+
+// NewRPCBridge wires p to receive rpcCallMsg values from Call.
+func NewRPCBridge(p *tea.Program) *RPCBridge {
+	return &RPCBridge{program: p}
+}
+
+// Call implements ipc.Dispatcher.
+func (b *RPCBridge) Call(method string, params json.RawMessage) (interface{}, error) {
+	reply := make(chan rpcResult, 1)
+	b.program.Send(rpcCallMsg{Method: method, Params: params, Reply: reply})
+	result := <-reply
+	return result.Value, result.Err
+}
+
+// SetNotifier records n so commands that change what's on screen can
+// push "panel.updated" - called once from main after the ipc.Server
+// starts listening.
+func (m *Model) SetNotifier(n Notifier) {
+	m.notify = n
+}
+
+func (m *Model) notifyPanelUpdated(panel string) {
+	m.Panels.InvalidateAll()
+	if m.notify == nil {
+		return
+	}
+	m.notify.Notify("panel.updated", map[string]string{"panel": panel, "mode": m.Mode})
+}
+
+// handleRPCCall is the method dispatch table for the ipc.Server -
+// file.load, file.save, theme.use, mode.set reuse the same logic as the
+// matching cmd* Command, run synchronously instead of via a tea.Cmd
+// since the reply is expected before the RPC call returns; panel.execute
+// reuses Commands/Command.Execute directly, mirroring how a tview
+// frontend's executeCommand would route into the same dispatch table.
+func (m *Model) handleRPCCall(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "file.load":
+		var p struct {
+			Path string `json:"path"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(p.Path)
+		if err != nil {
+			return nil, err
+		}
+		m.CurrentFile = p.Path
+		m.Main.SetContent(string(content))
+		m.dispatchPanelDeps()
+		m.notifyPanelUpdated("main")
+		return map[string]int{"bytes": len(content)}, nil
+
+	case "file.save":
+		var p struct {
+			Path    string `json:"path"`
+			Content string `json:"content"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(p.Path, []byte(p.Content), 0o644); err != nil {
+			return nil, err
+		}
+		return map[string]bool{"ok": true}, nil
+
+	case "theme.use":
+		var p struct {
+			Name string `json:"name"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		t, err := theme.Load(p.Name)
+		if err != nil {
+			return nil, err
+		}
+		styles, err := theme.Compile(t)
+		if err != nil {
+			return nil, err
+		}
+		m.Theme = t
+		m.Styles = styles
+		_ = theme.SetCurrent(p.Name)
+		m.notifyPanelUpdated("theme")
+		return map[string]string{"theme": p.Name}, nil
+
+	case "mode.set":
+		var p struct {
+			Mode string `json:"mode"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Mode != "self" && p.Mode != "tasks" {
+			return nil, fmt.Errorf("invalid mode %q, want self|tasks", p.Mode)
+		}
+		m.Mode = p.Mode
+		m.dispatchPanelDeps()
+		m.refreshSidebar()
+		m.notifyPanelUpdated("sidebar")
+		return map[string]string{"mode": m.Mode}, nil
+
+	case "panel.execute":
+		var p struct {
+			Command string   `json:"command"`
+			Args    []string `json:"args"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, err
+		}
+		cmd, exists := m.Commands[p.Command]
+		if !exists {
+			return nil, fmt.Errorf("unknown command %q", p.Command)
+		}
+		cmd.Execute(m, p.Args)
+		m.notifyPanelUpdated("main")
+		return map[string]bool{"accepted": true}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown method %q", method)
+	}
+}
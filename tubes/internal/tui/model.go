@@ -3,6 +3,8 @@ package tui
 import (
 	"os"
 	"strings"
+	"time"
+	"tubes/internal/agent"
 	"tubes/internal/layout"
 	"tubes/internal/theme"
 
@@ -44,7 +46,34 @@ type Model struct {
 	
 	// Command system
 	Commands map[string]Command
-	History  []string
+
+	// Command palette (Ctrl+P)
+	Complete     *AutoComplete
+	paletteOpen  bool
+	paletteQuery string
+	paletteIndex int
+
+	// Persistent history (Ctrl+R search, Up/Down recall)
+	Hist        *HistoryStore
+	recallOpen  bool
+	recallQuery string
+	recallAll   bool
+	recallIndex int // position into recallList(), -1 when not recalling
+
+	// ipc.Server notifications (panel.updated); nil until SetNotifier is called
+	notify Notifier
+
+	// Tool-calling task agent ("tasks" mode, /agent)
+	agentBackend   agent.Backend
+	agentSession   *agent.Session
+	agentChecklist []agentChecklistItem
+	agentEvents    <-chan agent.Event
+
+	// Live-watched project tree ("self" mode sidebar)
+	Project *ProjectWatcher
+
+	// Ad-hoc shell-backed panels ("/panel")
+	Panels *PanelStore
 }
 
 // Command represents a TUI command
@@ -71,17 +100,51 @@ func NewModel() (*Model, error) {
 	
 	// Register commands
 	m.registerCommands()
-	
+	m.Complete = NewAutoComplete(m.Commands)
+
+	hist, err := NewHistoryStore()
+	if err != nil {
+		return nil, err
+	}
+	m.Hist = hist
+	m.recallIndex = -1
+
+	if root, err := os.Getwd(); err == nil {
+		if pw, err := NewProjectWatcher(root); err == nil {
+			m.Project = pw
+		}
+	}
+
+	m.Panels = NewPanelStore()
+
 	m.Ready = true
 	return m, nil
 }
 
 // Init implements the tea.Model interface
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(
-		textarea.Blink,
-		m.refreshSidebar(),
-	)
+	cmds := []tea.Cmd{textarea.Blink, m.refreshSidebar()}
+	if m.Project != nil {
+		cmds = append(cmds, m.waitForProjectChange())
+	}
+	return tea.Batch(cmds...)
+}
+
+// projectChangedMsg signals that ProjectWatcher recomputed a subtree -
+// handled by refreshing the sidebar the same way any other
+// sidebarContentMsg would.
+type projectChangedMsg struct{}
+
+// waitForProjectChange blocks for the next debounced refresh off
+// m.Project.Changed() and reissues itself, so the Bubbletea loop keeps
+// picking up filesystem changes made outside the TUI.
+func (m *Model) waitForProjectChange() tea.Cmd {
+	return func() tea.Msg {
+		if _, ok := <-m.Project.Changed(); !ok {
+			return nil
+		}
+		return projectChangedMsg{}
+	}
 }
 
 // Update implements the tea.Model interface
@@ -107,6 +170,25 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case sidebarContentMsg:
 		m.handleSidebarContent(msg)
 		return m, nil
+
+	case rpcCallMsg:
+		value, err := m.handleRPCCall(msg.Method, msg.Params)
+		msg.Reply <- rpcResult{Value: value, Err: err}
+		return m, nil
+
+	case agentEventMsg:
+		return m, m.handleAgentEvent(msg)
+
+	case projectChangedMsg:
+		m.Panels.InvalidateAll()
+		if m.Mode == "self" {
+			m.Sidebar.SetContent(m.generateSidebarContent())
+		}
+		return m, m.waitForProjectChange()
+
+	case panelOutputMsg:
+		m.Main.SetContent(msg.Content)
+		return m, nil
 	}
 	
 	// Update UI components
@@ -127,7 +209,14 @@ func (m *Model) View() string {
 	if !m.Ready || m.Width == 0 || m.Height == 0 {
 		return "Loading..."
 	}
-	
+
+	if m.paletteOpen {
+		return layout.RenderContent(m.renderPalette(), layout.Rect{W: m.Width, H: m.Height}, m.Styles.Main)
+	}
+	if m.recallOpen {
+		return layout.RenderContent(m.renderRecall(), layout.Rect{W: m.Width, H: m.Height}, m.Styles.Main)
+	}
+
 	// Render each panel into its rectangle
 	var result string
 	
@@ -188,16 +277,39 @@ func (m *Model) handleResize(msg tea.WindowSizeMsg) (*Model, tea.Cmd) {
 }
 
 func (m *Model) handleKey(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	if m.paletteOpen {
+		return m.handlePaletteKey(msg)
+	}
+	if m.recallOpen {
+		return m.handleRecallKey(msg)
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "esc":
 		return m, tea.Quit
-		
+
+	case "ctrl+p":
+		m.openPalette()
+		return m, nil
+
+	case "ctrl+r":
+		m.openRecall()
+		return m, nil
+
 	case "enter":
 		return m.handleCommand()
-		
+
 	case "tab":
 		m.switchMode()
 		return m, nil
+
+	case "up":
+		m.recallUp()
+		return m, nil
+
+	case "down":
+		m.recallDown()
+		return m, nil
 	}
 	
 	// Let input handle other keys
@@ -211,24 +323,39 @@ func (m *Model) handleCommand() (*Model, tea.Cmd) {
 	if text == "" {
 		return m, nil
 	}
-	
+
 	m.Input.Reset()
-	m.History = append(m.History, text)
-	
+	m.recallIndex = -1
+
 	// Parse command
 	parts := strings.Fields(text)
 	if len(parts) == 0 {
 		return m, nil
 	}
-	
+
 	cmdName := strings.TrimPrefix(parts[0], "/")
 	args := parts[1:]
-	
+
+	cmd, exists := m.Commands[cmdName]
+	exitStatus := "ok"
+	if !exists {
+		exitStatus = "unknown command"
+	}
+	if err := m.Hist.Append(HistoryEntry{
+		Ts:         time.Now().Unix(),
+		Mode:       m.Mode,
+		Command:    cmdName,
+		Args:       args,
+		ExitStatus: exitStatus,
+	}); err != nil {
+		m.Status.SetContent("history: " + err.Error())
+	}
+
 	// Execute command
-	if cmd, exists := m.Commands[cmdName]; exists {
+	if exists {
 		return m, cmd.Execute(m, args)
 	}
-	
+
 	// Unknown command
 	m.Status.SetContent("Unknown command: " + cmdName)
 	return m, nil
@@ -240,9 +367,21 @@ func (m *Model) switchMode() {
 	} else {
 		m.Mode = "self"
 	}
+	m.dispatchPanelDeps()
 	m.refreshSidebar()
 }
 
+// dispatchPanelDeps feeds Panels.Dispatch the current value of every
+// dependency key a PanelConfig can declare in RefreshOn, so a panel
+// watching "mode" or "file" is marked stale exactly when that value
+// actually changes - not on every keystroke.
+func (m *Model) dispatchPanelDeps() {
+	m.Panels.Dispatch(map[string]string{
+		"mode": m.Mode,
+		"file": m.CurrentFile,
+	})
+}
+
 func (m *Model) computeLayout() {
 	spec := layout.GridSpec{
 		Rows: []layout.Row{
@@ -0,0 +1,164 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// matchHighlightStyle bolds the rune ranges sahilm/fuzzy matched, so the
+// palette can show why a candidate ranked where it did.
+var matchHighlightStyle = lipgloss.NewStyle().Bold(true)
+
+// paletteMaxResults caps how many ranked candidates the overlay shows at
+// once, so a broad query against a large project tree doesn't scroll off
+// the screen.
+const paletteMaxResults = 8
+
+// openPalette shows the full-screen command/file palette (Ctrl+P),
+// seeding it with the current project tree so /open's completions rank
+// alongside command names.
+func (m *Model) openPalette() {
+	m.Complete.SetPaths(m.projectFilePaths())
+	m.paletteOpen = true
+	m.paletteQuery = ""
+	m.paletteIndex = 0
+}
+
+func (m *Model) closePalette() {
+	m.paletteOpen = false
+	m.paletteQuery = ""
+	m.paletteIndex = 0
+}
+
+// handlePaletteKey handles a key while the palette is open, consuming it
+// so handleKey's normal input/mode handling doesn't also see it.
+func (m *Model) handlePaletteKey(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "ctrl+p":
+		m.closePalette()
+		return m, nil
+
+	case "enter":
+		matches := m.Complete.FuzzyMatch(m.paletteQuery)
+		var text string
+		if m.paletteIndex < len(matches) {
+			text = matches[m.paletteIndex].Text
+		}
+		m.closePalette()
+		if text == "" {
+			return m, nil
+		}
+		return m.runPaletteSelection(text)
+
+	case "up":
+		if m.paletteIndex > 0 {
+			m.paletteIndex--
+		}
+		return m, nil
+
+	case "down":
+		m.paletteIndex++
+		return m, nil
+
+	case "backspace":
+		if len(m.paletteQuery) > 0 {
+			m.paletteQuery = m.paletteQuery[:len(m.paletteQuery)-1]
+			m.paletteIndex = 0
+		}
+		return m, nil
+
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.paletteQuery += string(msg.Runes)
+			m.paletteIndex = 0
+		}
+		return m, nil
+	}
+}
+
+// runPaletteSelection executes a chosen "/command" the same way
+// handleCommand dispatches one, or opens a chosen file path.
+func (m *Model) runPaletteSelection(text string) (*Model, tea.Cmd) {
+	if name, ok := strings.CutPrefix(text, "/"); ok {
+		if cmd, exists := m.Commands[name]; exists {
+			return m, cmd.Execute(m, nil)
+		}
+		return m, nil
+	}
+
+	return m, func() tea.Msg {
+		content, err := os.ReadFile(text)
+		if err != nil {
+			return errorMsg(fmt.Sprintf("Failed to read %q: %v", text, err))
+		}
+		return openFileMsg{Filename: text, Content: string(content)}
+	}
+}
+
+// renderPalette draws the full-screen overlay: the query line followed
+// by up to paletteMaxResults ranked candidates, matched runes bolded and
+// the selected row reversed.
+func (m *Model) renderPalette() string {
+	matches := m.Complete.FuzzyMatch(m.paletteQuery)
+	if len(matches) > 0 && m.paletteIndex >= len(matches) {
+		m.paletteIndex = len(matches) - 1
+	}
+
+	lines := []string{"Command palette: " + m.paletteQuery, ""}
+
+	if len(matches) == 0 {
+		lines = append(lines, "(no matches)")
+	}
+	for i, match := range matches {
+		if i >= paletteMaxResults {
+			break
+		}
+		line := renderHighlighted(match)
+		if desc := m.paletteDescription(match.Text); desc != "" {
+			line += "  " + desc
+		}
+		if i == m.paletteIndex {
+			line = lipgloss.NewStyle().Reverse(true).Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// paletteDescription returns Commands[name].Description for a "/name"
+// candidate, blank for a file path.
+func (m *Model) paletteDescription(text string) string {
+	name, ok := strings.CutPrefix(text, "/")
+	if !ok {
+		return ""
+	}
+	return m.Commands[name].Description
+}
+
+// renderHighlighted bolds a Match's matched rune ranges via
+// matchHighlightStyle, leaving the rest of the text untouched.
+func renderHighlighted(match Match) string {
+	if len(match.Ranges) == 0 {
+		return match.Text
+	}
+	runes := []rune(match.Text)
+	var b strings.Builder
+	pos := 0
+	for _, r := range match.Ranges {
+		start, end := r[0], r[1]
+		if start > pos {
+			b.WriteString(string(runes[pos:start]))
+		}
+		b.WriteString(matchHighlightStyle.Render(string(runes[start:end])))
+		pos = end
+	}
+	if pos < len(runes) {
+		b.WriteString(string(runes[pos:]))
+	}
+	return b.String()
+}
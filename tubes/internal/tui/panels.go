@@ -0,0 +1,202 @@
+package tui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PanelConfig declares an ad-hoc panel bound to a shell command -
+// "/panel new logs tail -f tubes.log" - and the dependency keys
+// ("file", "mode", "theme") that should invalidate its cached output.
+type PanelConfig struct {
+	Name      string
+	Command   string
+	RefreshOn []string
+}
+
+// panelState is one PanelConfig's cached output plus the invalidation
+// bit fsnotify (via ProjectWatcher) or the ipc layer can flip without
+// either of them knowing how to run a shell command themselves.
+type panelState struct {
+	Config            PanelConfig
+	Content           string
+	IsContentUpToDate bool
+	lastDeps          map[string]string
+}
+
+// PanelStore is the reactive store behind every ad-hoc panel: Dispatch
+// records the current value of each dependency key, and a panel only
+// re-runs its Command when a key it declared in RefreshOn actually
+// changed - not on every tick.
+type PanelStore struct {
+	mu     sync.Mutex
+	panels map[string]*panelState
+}
+
+// NewPanelStore creates an empty store.
+func NewPanelStore() *PanelStore {
+	return &PanelStore{panels: make(map[string]*panelState)}
+}
+
+// Add registers cfg, marked stale so the first Dispatch/Run produces its
+// initial content.
+func (ps *PanelStore) Add(cfg PanelConfig) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.panels[cfg.Name] = &panelState{Config: cfg, lastDeps: make(map[string]string)}
+}
+
+// Remove deletes a panel by name.
+func (ps *PanelStore) Remove(name string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	delete(ps.panels, name)
+}
+
+// Names returns every registered panel name.
+func (ps *PanelStore) Names() []string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	out := make([]string, 0, len(ps.panels))
+	for name := range ps.panels {
+		out = append(out, name)
+	}
+	return out
+}
+
+// Invalidate flips IsContentUpToDate off for one panel - called by
+// ProjectWatcher on a filesystem change or by the ipc layer on an
+// incoming notification, without either needing to know the panel's
+// Command.
+func (ps *PanelStore) Invalidate(name string) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	if p, ok := ps.panels[name]; ok {
+		p.IsContentUpToDate = false
+	}
+}
+
+// InvalidateAll flips every panel's IsContentUpToDate off.
+func (ps *PanelStore) InvalidateAll() {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	for _, p := range ps.panels {
+		p.IsContentUpToDate = false
+	}
+}
+
+// Dispatch records the current value of every dependency key (e.g.
+// {"file": m.CurrentFile, "mode": m.Mode, "theme": themeName}) and
+// returns the names of panels whose RefreshOn deps actually changed,
+// marking them stale - the store's "re-run only when a declared
+// dependency changed" half.
+func (ps *PanelStore) Dispatch(deps map[string]string) []string {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	var stale []string
+	for name, p := range ps.panels {
+		changed := false
+		for _, key := range p.Config.RefreshOn {
+			if p.lastDeps[key] != deps[key] {
+				changed = true
+				break
+			}
+		}
+		if changed {
+			p.IsContentUpToDate = false
+			stale = append(stale, name)
+		}
+		for _, key := range p.Config.RefreshOn {
+			p.lastDeps[key] = deps[key]
+		}
+	}
+	return stale
+}
+
+// Run executes a panel's Command if it isn't already up to date,
+// updating Content and clearing IsContentUpToDate - the store's "run the
+// shell command" half, kept separate from Dispatch so callers can batch
+// several Dispatch calls before deciding what to actually run.
+func (ps *PanelStore) Run(name string) (string, error) {
+	ps.mu.Lock()
+	p, ok := ps.panels[name]
+	ps.mu.Unlock()
+	if !ok {
+		return "", fmt.Errorf("unknown panel %q", name)
+	}
+	if p.IsContentUpToDate {
+		return p.Content, nil
+	}
+
+	out, err := exec.Command("sh", "-c", p.Config.Command).CombinedOutput()
+
+	ps.mu.Lock()
+	p.Content = string(out)
+	p.IsContentUpToDate = err == nil
+	ps.mu.Unlock()
+
+	return string(out), err
+}
+
+// cmdPanel is "/panel": "new NAME COMMAND...", "run NAME", "list", or
+// "rm NAME".
+func (m *Model) cmdPanel(model *Model, args []string) tea.Cmd {
+	if len(args) == 0 {
+		return func() tea.Msg {
+			return errorMsg("Usage: /panel new NAME CMD... | run NAME | list | rm NAME")
+		}
+	}
+
+	switch args[0] {
+	case "new":
+		if len(args) < 3 {
+			return func() tea.Msg { return errorMsg(`Usage: /panel new NAME "shell command"`) }
+		}
+		name := args[1]
+		command := strings.Join(args[2:], " ")
+		model.Panels.Add(PanelConfig{Name: name, Command: command, RefreshOn: []string{"mode", "file"}})
+		return func() tea.Msg { return statusMsg("Panel registered: " + name) }
+
+	case "run":
+		if len(args) < 2 {
+			return func() tea.Msg { return errorMsg("Usage: /panel run NAME") }
+		}
+		name := args[1]
+		return func() tea.Msg {
+			out, err := model.Panels.Run(name)
+			if err != nil {
+				return errorMsg(fmt.Sprintf("panel %q: %v", name, err))
+			}
+			return panelOutputMsg{Name: name, Content: out}
+		}
+
+	case "list":
+		return func() tea.Msg {
+			return statusMsg("Panels: " + strings.Join(model.Panels.Names(), ", "))
+		}
+
+	case "rm":
+		if len(args) < 2 {
+			return func() tea.Msg { return errorMsg("Usage: /panel rm NAME") }
+		}
+		model.Panels.Remove(args[1])
+		return func() tea.Msg { return statusMsg("Panel removed: " + args[1]) }
+
+	default:
+		return func() tea.Msg { return errorMsg("Unknown /panel subcommand: " + args[0]) }
+	}
+}
+
+// panelOutputMsg carries a panel's freshly run Command output into Main
+// - replacing "/panel run NAME" with a dedicated view of its own would
+// need its own Rect the way Sidebar/Main/Status/Input each have one, so
+// for now a panel's output is shown in Main like any other command result.
+type panelOutputMsg struct {
+	Name    string
+	Content string
+}
@@ -0,0 +1,331 @@
+package tui
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// treeNode is one file or directory in a ProjectWatcher's in-memory
+// tree. Collapsed is a per-directory bit toggled by "/tree collapse|expand"
+// instead of ever being recomputed from a walk.
+type treeNode struct {
+	Name      string
+	Path      string // relative to ProjectWatcher.root
+	IsDir     bool
+	Collapsed bool
+	Children  []*treeNode
+}
+
+// projectSkipDirs mirrors generateProjectTree's hard-coded list, kept
+// here too so a watcher built without ever calling generateProjectTree
+// still skips the same build/cache directories.
+var projectSkipDirs = []string{"node_modules", "target", "build", "dist", ".git"}
+
+// ProjectWatcher maintains an in-memory tree of the project's files,
+// recursively watched via fsnotify so generateProjectTree doesn't have
+// to re-walk the whole directory on every sidebar refresh and so
+// changes made outside the TUI are picked up too.
+type ProjectWatcher struct {
+	root   string
+	ignore *ignoreRules
+
+	mu   sync.Mutex
+	tree *treeNode
+
+	watcher *fsnotify.Watcher
+	changed chan struct{}
+
+	debounceMu sync.Mutex
+	debounce   *time.Timer
+}
+
+// NewProjectWatcher builds the initial tree under root (loading
+// .tubesignore if present) and starts watching every directory in it
+// recursively - fsnotify.Watcher.Add isn't recursive on its own.
+func NewProjectWatcher(root string) (*ProjectWatcher, error) {
+	ignore := loadIgnoreRules(root)
+
+	pw := &ProjectWatcher{
+		root:    root,
+		ignore:  ignore,
+		changed: make(chan struct{}, 1),
+	}
+	pw.tree = pw.buildTree(root, "")
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return pw, nil // tree still works, just without live updates
+	}
+	pw.watcher = watcher
+	for _, dir := range pw.allDirs(pw.tree) {
+		_ = watcher.Add(filepath.Join(root, dir))
+	}
+	_ = watcher.Add(root)
+	go pw.watchLoop()
+
+	return pw, nil
+}
+
+// buildTree walks dir (absolute) and returns its treeNode, recursing
+// into subdirectories and skipping anything hidden, in projectSkipDirs,
+// or matched by .tubesignore.
+func (pw *ProjectWatcher) buildTree(absDir, relDir string) *treeNode {
+	node := &treeNode{Name: filepath.Base(absDir), Path: relDir, IsDir: true}
+
+	entries, err := os.ReadDir(absDir)
+	if err != nil {
+		return node
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		skip := false
+		for _, s := range projectSkipDirs {
+			if e.IsDir() && e.Name() == s {
+				skip = true
+				break
+			}
+		}
+		if skip {
+			continue
+		}
+
+		childRel := filepath.Join(relDir, e.Name())
+		if pw.ignore.matches(childRel, e.IsDir()) {
+			continue
+		}
+
+		if e.IsDir() {
+			node.Children = append(node.Children, pw.buildTree(filepath.Join(absDir, e.Name()), childRel))
+		} else {
+			node.Children = append(node.Children, &treeNode{Name: e.Name(), Path: childRel})
+		}
+	}
+
+	return node
+}
+
+func (pw *ProjectWatcher) allDirs(node *treeNode) []string {
+	var dirs []string
+	if node.IsDir {
+		dirs = append(dirs, node.Path)
+		for _, c := range node.Children {
+			dirs = append(dirs, pw.allDirs(c)...)
+		}
+	}
+	return dirs
+}
+
+// watchLoop debounces bursts of fsnotify events at 100ms - a save in an
+// editor is often a Remove+Create pair, which would otherwise refresh
+// twice - before recomputing the affected subtree and signaling Changed.
+func (pw *ProjectWatcher) watchLoop() {
+	for {
+		select {
+		case event, ok := <-pw.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Remove|fsnotify.Rename) == 0 {
+				continue
+			}
+			pw.scheduleRefresh(event.Name)
+		case _, ok := <-pw.watcher.Errors:
+			if !ok {
+				return
+			}
+		}
+	}
+}
+
+func (pw *ProjectWatcher) scheduleRefresh(changedAbsPath string) {
+	pw.debounceMu.Lock()
+	defer pw.debounceMu.Unlock()
+
+	if pw.debounce != nil {
+		pw.debounce.Stop()
+	}
+	pw.debounce = time.AfterFunc(100*time.Millisecond, func() {
+		pw.refreshSubtree(changedAbsPath)
+		select {
+		case pw.changed <- struct{}{}:
+		default:
+		}
+	})
+}
+
+// refreshSubtree recomputes only the directory containing changedAbsPath
+// - a new Create there, a Remove, or a Rename - rather than the whole
+// tree.
+func (pw *ProjectWatcher) refreshSubtree(changedAbsPath string) {
+	dirRel, err := filepath.Rel(pw.root, filepath.Dir(changedAbsPath))
+	if err != nil {
+		return
+	}
+	if dirRel == "." {
+		dirRel = ""
+	}
+
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	target := pw.findNode(pw.tree, dirRel)
+	if target == nil {
+		return
+	}
+	fresh := pw.buildTree(filepath.Join(pw.root, dirRel), dirRel)
+	fresh.Collapsed = target.Collapsed
+	*target = *fresh
+
+	if pw.watcher != nil {
+		for _, dir := range pw.allDirs(target) {
+			_ = pw.watcher.Add(filepath.Join(pw.root, dir))
+		}
+	}
+}
+
+func (pw *ProjectWatcher) findNode(node *treeNode, relPath string) *treeNode {
+	if node.Path == relPath {
+		return node
+	}
+	for _, c := range node.Children {
+		if c.IsDir {
+			if found := pw.findNode(c, relPath); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// SetCollapsed toggles a directory's Collapsed bit in place, so
+// "/tree collapse|expand PATH" never has to rebuild from scratch.
+func (pw *ProjectWatcher) SetCollapsed(path string, collapsed bool) bool {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+	node := pw.findNode(pw.tree, path)
+	if node == nil || !node.IsDir {
+		return false
+	}
+	node.Collapsed = collapsed
+	return true
+}
+
+// Render returns the same "📁 name/" / "📄 name" indented tree
+// generateProjectTree used to produce from a live walk, reading from the
+// cached tree instead.
+func (pw *ProjectWatcher) Render() string {
+	pw.mu.Lock()
+	defer pw.mu.Unlock()
+
+	lines := []string{"Project Files:", ""}
+	for _, c := range pw.tree.Children {
+		lines = append(lines, pw.renderNode(c, 0)...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (pw *ProjectWatcher) renderNode(node *treeNode, depth int) []string {
+	indent := strings.Repeat("  ", depth)
+	if !node.IsDir {
+		return []string{indent + "📄 " + node.Name}
+	}
+
+	marker := ""
+	if node.Collapsed {
+		marker = " (collapsed)"
+	}
+	lines := []string{indent + "📁 " + node.Name + "/" + marker}
+	if node.Collapsed {
+		return lines
+	}
+	for _, c := range node.Children {
+		lines = append(lines, pw.renderNode(c, depth+1)...)
+	}
+	return lines
+}
+
+// Changed receives a value after every debounced refresh, for the
+// Bubbletea loop to turn into a sidebarContentMsg.
+func (pw *ProjectWatcher) Changed() <-chan struct{} {
+	return pw.changed
+}
+
+// Close stops the fsnotify watcher and any pending debounce timer.
+func (pw *ProjectWatcher) Close() error {
+	pw.debounceMu.Lock()
+	if pw.debounce != nil {
+		pw.debounce.Stop()
+	}
+	pw.debounceMu.Unlock()
+
+	if pw.watcher == nil {
+		return nil
+	}
+	return pw.watcher.Close()
+}
+
+/* =========================
+   .tubesignore
+   ========================= */
+
+// ignoreRules is a small gitignore-flavored matcher parsed from
+// .tubesignore: blank lines and "#" comments are skipped, a trailing "/"
+// restricts a pattern to directories, everything else is matched with
+// filepath.Match against both the full relative path and its base name.
+type ignoreRules struct {
+	patterns []ignorePattern
+}
+
+type ignorePattern struct {
+	glob    string
+	dirOnly bool
+}
+
+func loadIgnoreRules(root string) *ignoreRules {
+	f, err := os.Open(filepath.Join(root, ".tubesignore"))
+	if err != nil {
+		return &ignoreRules{}
+	}
+	defer f.Close()
+
+	var rules ignoreRules
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		dirOnly := strings.HasSuffix(line, "/")
+		rules.patterns = append(rules.patterns, ignorePattern{glob: strings.TrimSuffix(line, "/"), dirOnly: dirOnly})
+	}
+	return &rules
+}
+
+func (r *ignoreRules) matches(relPath string, isDir bool) bool {
+	if r == nil {
+		return false
+	}
+	for _, p := range r.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if ok, _ := filepath.Match(p.glob, relPath); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p.glob, filepath.Base(relPath)); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// recallMaxResults caps how many Ctrl+R search hits the overlay shows.
+const recallMaxResults = 8
+
+// recallList returns the entries Up/Down and Ctrl+R search over - scoped
+// to the current Mode by default, every mode once recallAll is toggled.
+func (m *Model) recallList() []HistoryEntry {
+	if m.recallAll {
+		return m.Hist.Entries()
+	}
+	return m.Hist.ForMode(m.Mode)
+}
+
+// recallUp is the vi-like Up-arrow recall: step one command further back
+// in the current scope's history and place it in Input.
+func (m *Model) recallUp() {
+	entries := m.recallList()
+	if len(entries) == 0 {
+		return
+	}
+	if m.recallIndex < 0 || m.recallIndex >= len(entries) {
+		m.recallIndex = len(entries) - 1
+	} else if m.recallIndex > 0 {
+		m.recallIndex--
+	}
+	m.setInputFromEntry(entries[m.recallIndex])
+}
+
+// recallDown steps one command forward; past the newest entry it clears
+// Input back to empty, mirroring a shell's history behavior.
+func (m *Model) recallDown() {
+	entries := m.recallList()
+	if m.recallIndex < 0 || len(entries) == 0 {
+		return
+	}
+	if m.recallIndex >= len(entries)-1 {
+		m.recallIndex = -1
+		m.Input.SetValue("")
+		return
+	}
+	m.recallIndex++
+	m.setInputFromEntry(entries[m.recallIndex])
+}
+
+func (m *Model) setInputFromEntry(e HistoryEntry) {
+	text := "/" + e.Command
+	if len(e.Args) > 0 {
+		text += " " + strings.Join(e.Args, " ")
+	}
+	m.Input.SetValue(text)
+}
+
+// openRecall shows the Ctrl+R reverse-search overlay.
+func (m *Model) openRecall() {
+	m.recallOpen = true
+	m.recallQuery = ""
+}
+
+func (m *Model) closeRecall() {
+	m.recallOpen = false
+	m.recallQuery = ""
+}
+
+// handleRecallKey handles a key while the Ctrl+R overlay is open,
+// consuming it so handleKey's normal input handling doesn't also see it.
+func (m *Model) handleRecallKey(msg tea.KeyMsg) (*Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.closeRecall()
+		return m, nil
+
+	case "ctrl+r":
+		m.recallAll = !m.recallAll
+		return m, nil
+
+	case "enter":
+		hits := m.Hist.Search(m.recallQuery, m.Mode, m.recallAll)
+		if len(hits) > 0 {
+			m.setInputFromEntry(hits[0])
+		}
+		m.closeRecall()
+		return m, nil
+
+	case "backspace":
+		if len(m.recallQuery) > 0 {
+			m.recallQuery = m.recallQuery[:len(m.recallQuery)-1]
+		}
+		return m, nil
+
+	default:
+		if msg.Type == tea.KeyRunes {
+			m.recallQuery += string(msg.Runes)
+		}
+		return m, nil
+	}
+}
+
+// renderRecall draws the Ctrl+R overlay: the query and scope indicator,
+// then up to recallMaxResults matches, most recent first.
+func (m *Model) renderRecall() string {
+	scope := m.Mode
+	if m.recallAll {
+		scope = "all"
+	}
+	lines := []string{
+		fmt.Sprintf("(reverse-search)`%s` [scope: %s, Ctrl+R to toggle]", m.recallQuery, scope),
+		"",
+	}
+
+	hits := m.Hist.Search(m.recallQuery, m.Mode, m.recallAll)
+	if len(hits) == 0 {
+		lines = append(lines, "(no matches)")
+	}
+	for i, e := range hits {
+		if i >= recallMaxResults {
+			break
+		}
+		line := "/" + e.Command
+		if len(e.Args) > 0 {
+			line += " " + strings.Join(e.Args, " ")
+		}
+		if i == 0 {
+			line = lipgloss.NewStyle().Reverse(true).Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}